@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+// activateShells lists the shells 'gopher env activate' can write for
+var activateShells = []string{"bash", "zsh", "sh", "fish", "powershell", "pwsh"}
+
+// Activation stashes the value each variable had before the first
+// 'gopher env activate' in GOPHER_OLD_<NAME>, and lists the stashed names in
+// GOPHER_OLD_VARS so 'gopher env deactivate' knows what to restore. A name
+// listed without a GOPHER_OLD_ value was unset.
+const (
+	oldVarPrefix = "GOPHER_OLD_"
+	oldVarsList  = "GOPHER_OLD_VARS"
+)
+
+// envChange is one variable to set, or to unset, in the calling shell
+type envChange struct {
+	key   string
+	value string
+	unset bool
+}
+
+// activateVersion prints the commands that activate a Go version in the
+// calling shell, for use with eval:
+//
+//	eval "$(gopher env activate 1.21.0)"
+//
+// Nothing else is written to stdout, so the output can be evaluated as is.
+func activateVersion(manager *inruntime.Manager, args []string) error {
+	if len(args) < 1 {
+		return errors.NewMissingArgument("activate (requires version, e.g. 'gopher env activate 1.21.0')")
+	}
+
+	shell, err := activateShell()
+	if err != nil {
+		return err
+	}
+
+	_, vars, err := manager.ExecEnvironment(args[0])
+	if err != nil {
+		return err
+	}
+
+	return writeEnvChanges(os.Stdout, shell, activationChanges(vars, os.LookupEnv))
+}
+
+// deactivateVersion prints the commands that restore the environment from
+// before 'gopher env activate'. When nothing was activated it prints nothing
+// to stdout and succeeds, so it is always safe to eval.
+func deactivateVersion() error {
+	shell, err := activateShell()
+	if err != nil {
+		return err
+	}
+
+	changes, ok := deactivationChanges(os.LookupEnv)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Nothing to deactivate: no version was activated with 'gopher env activate' in this shell")
+		return nil
+	}
+	return writeEnvChanges(os.Stdout, shell, changes)
+}
+
+// activateShell returns the shell to write commands for: --shell when given,
+// otherwise the detected shell
+func activateShell() (string, error) {
+	shell := *shellFlag
+	if shell == "" {
+		shell = detectShell()
+	}
+	for _, supported := range activateShells {
+		if shell == supported {
+			return shell, nil
+		}
+	}
+	return "", errors.Newf(errors.ErrCodeInvalidArgument,
+		"unsupported shell %q for env activate (use --shell with one of: %s)", shell, strings.Join(activateShells, ", "))
+}
+
+// activationChanges returns the changes that set vars, sorted by name, after
+// stashing the current value of each variable not stashed yet. Activating
+// again keeps the stash from the first activation.
+func activationChanges(vars map[string]string, lookup func(string) (string, bool)) []envChange {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list, _ := lookup(oldVarsList)
+	stashed := strings.Fields(list)
+
+	var changes []envChange
+	for _, key := range keys {
+		if slices.Contains(stashed, key) {
+			continue
+		}
+		if old, ok := lookup(key); ok {
+			changes = append(changes, envChange{key: oldVarPrefix + key, value: old})
+		}
+		stashed = append(stashed, key)
+	}
+	changes = append(changes, envChange{key: oldVarsList, value: strings.Join(stashed, " ")})
+
+	for _, key := range keys {
+		changes = append(changes, envChange{key: key, value: vars[key]})
+	}
+	return changes
+}
+
+// deactivationChanges returns the changes that restore the stashed variables
+// and remove the stash, or false when nothing was activated
+func deactivationChanges(lookup func(string) (string, bool)) ([]envChange, bool) {
+	list, ok := lookup(oldVarsList)
+	if !ok {
+		return nil, false
+	}
+
+	var changes []envChange
+	for _, key := range strings.Fields(list) {
+		if old, ok := lookup(oldVarPrefix + key); ok {
+			changes = append(changes,
+				envChange{key: key, value: old},
+				envChange{key: oldVarPrefix + key, unset: true})
+		} else {
+			changes = append(changes, envChange{key: key, unset: true})
+		}
+	}
+	changes = append(changes, envChange{key: oldVarsList, unset: true})
+	return changes, true
+}
+
+// writeEnvChanges writes one shell command per change
+func writeEnvChanges(w io.Writer, shell string, changes []envChange) error {
+	for _, change := range changes {
+		command := exportCommand(shell, change.key, change.value)
+		if change.unset {
+			command = unsetCommand(shell, change.key)
+		}
+		if _, err := fmt.Fprintln(w, command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Escapes for a value inside single quotes in fish and PowerShell; POSIX
+// shells use inruntime.ShellQuote
+var (
+	fishQuoteReplacer       = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	powershellQuoteReplacer = strings.NewReplacer(`'`, `''`)
+)
+
+// exportCommand returns the command that sets an environment variable in
+// shell. Values are single-quoted so they are not expanded.
+func exportCommand(shell, key, value string) string {
+	switch shell {
+	case "fish":
+		return fmt.Sprintf("set -gx %s '%s';", key, fishQuoteReplacer.Replace(value))
+	case "powershell", "pwsh":
+		return fmt.Sprintf("$env:%s = '%s'", key, powershellQuoteReplacer.Replace(value))
+	default:
+		return fmt.Sprintf("export %s=%s", key, inruntime.ShellQuote(value))
+	}
+}
+
+// unsetCommand returns the command that removes an environment variable in
+// shell
+func unsetCommand(shell, key string) string {
+	switch shell {
+	case "fish":
+		return fmt.Sprintf("set -e %s;", key)
+	case "powershell", "pwsh":
+		return fmt.Sprintf("Remove-Item Env:%s -ErrorAction SilentlyContinue", key)
+	default:
+		return "unset " + key
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExportCommand(t *testing.T) {
+	tests := []struct {
+		shell string
+		value string
+		want  string
+	}{
+		{"bash", "/opt/go", "export GOROOT='/opt/go'"},
+		{"zsh", "it's", `export GOROOT='it'\''s'`},
+		{"fish", "/opt/go", "set -gx GOROOT '/opt/go';"},
+		{"fish", `it's \ here`, `set -gx GOROOT 'it\'s \\ here';`},
+		{"pwsh", `C:\Go`, `$env:GOROOT = 'C:\Go'`},
+		{"powershell", "it's", `$env:GOROOT = 'it''s'`},
+	}
+	for _, tt := range tests {
+		if got := exportCommand(tt.shell, "GOROOT", tt.value); got != tt.want {
+			t.Errorf("exportCommand(%q, %q) = %s, want %s", tt.shell, tt.value, got, tt.want)
+		}
+	}
+}
+
+// fakeEnv returns a lookup function over a fixed environment
+func fakeEnv(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+func TestActivationChanges(t *testing.T) {
+	vars := map[string]string{"PATH": "/go/bin:/usr/bin", "GOROOT": "/go", "GOPATH": "/home/me/go"}
+	env := fakeEnv(map[string]string{"PATH": "/usr/bin", "GOPATH": "/home/me/work"})
+
+	var buf bytes.Buffer
+	if err := writeEnvChanges(&buf, "bash", activationChanges(vars, env)); err != nil {
+		t.Fatal(err)
+	}
+	want := "export GOPHER_OLD_GOPATH='/home/me/work'\n" +
+		"export GOPHER_OLD_PATH='/usr/bin'\n" +
+		"export GOPHER_OLD_VARS='GOPATH GOROOT PATH'\n" +
+		"export GOPATH='/home/me/go'\n" +
+		"export GOROOT='/go'\n" +
+		"export PATH='/go/bin:/usr/bin'\n"
+	if buf.String() != want {
+		t.Errorf("activation =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestActivationChanges_KeepsFirstStash(t *testing.T) {
+	vars := map[string]string{"PATH": "/go2/bin:/go/bin:/usr/bin", "GOROOT": "/go2"}
+	env := fakeEnv(map[string]string{
+		"PATH":            "/go/bin:/usr/bin",
+		"GOROOT":          "/go",
+		"GOPHER_OLD_PATH": "/usr/bin",
+		"GOPHER_OLD_VARS": "GOROOT PATH",
+	})
+
+	for _, change := range activationChanges(vars, env) {
+		if change.key == "GOPHER_OLD_PATH" || change.key == "GOPHER_OLD_GOROOT" {
+			t.Errorf("re-activation overwrote the stash: %+v", change)
+		}
+		if change.key == oldVarsList && change.value != "GOROOT PATH" {
+			t.Errorf("GOPHER_OLD_VARS = %q, want %q", change.value, "GOROOT PATH")
+		}
+	}
+}
+
+func TestDeactivationChanges(t *testing.T) {
+	if _, ok := deactivationChanges(fakeEnv(nil)); ok {
+		t.Error("expected nothing to deactivate without a stash")
+	}
+
+	env := fakeEnv(map[string]string{
+		"PATH":            "/go/bin:/usr/bin",
+		"GOROOT":          "/go",
+		"GOPHER_OLD_PATH": "/usr/bin",
+		"GOPHER_OLD_VARS": "GOROOT PATH",
+	})
+	changes, ok := deactivationChanges(env)
+	if !ok {
+		t.Fatal("expected a stash to restore")
+	}
+
+	var buf bytes.Buffer
+	if err := writeEnvChanges(&buf, "fish", changes); err != nil {
+		t.Fatal(err)
+	}
+	want := "set -e GOROOT;\n" +
+		"set -gx PATH '/usr/bin';\n" +
+		"set -e GOPHER_OLD_PATH;\n" +
+		"set -e GOPHER_OLD_VARS;\n"
+	if buf.String() != want {
+		t.Errorf("deactivation =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestActivateShell(t *testing.T) {
+	saved := *shellFlag
+	defer func() { *shellFlag = saved }()
+
+	*shellFlag = "fish"
+	if shell, err := activateShell(); err != nil || shell != "fish" {
+		t.Errorf("activateShell() = %q, %v; want fish", shell, err)
+	}
+
+	*shellFlag = "cmd"
+	if _, err := activateShell(); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
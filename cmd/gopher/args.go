@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"strings"
+)
+
+// parseArgs parses global flags from anywhere in args and returns the
+// remaining positional arguments (command and its arguments) in order.
+//
+// The standard flag package stops at the first positional argument, so
+// "gopher list --json" would otherwise leave --json unparsed. Arguments are
+// split into flags and positionals first, then the flags are parsed in one go.
+// Everything after a "--" terminator is treated as positional. Unknown flags
+// that follow the command are left in place so commands can parse their own
+// options (e.g. "alias export file.json --tags a,b"). For exec and run,
+// everything after the version belongs to the child command and is passed
+// through unchanged, so "gopher exec 1.21.0 go test -v" keeps its -v.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var flagArgs, positional []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if len(positional) >= 2 && passThroughCommands[positional[0]] {
+			rest := args[i:]
+			if rest[0] == "--" {
+				rest = rest[1:]
+			}
+			positional = append(positional, rest...)
+			break
+		}
+
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		f := fs.Lookup(name)
+		if f == nil && len(positional) > 0 {
+			positional = append(positional, arg)
+			continue
+		}
+
+		flagArgs = append(flagArgs, arg)
+
+		// Non-boolean flags given as "--name value" consume the next argument
+		if f != nil && !hasValue && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			flagArgs = append(flagArgs, args[i])
+		}
+	}
+
+	if err := fs.Parse(flagArgs); err != nil {
+		return nil, err
+	}
+
+	return positional, nil
+}
+
+// passThroughCommands run a child command given after their version
+// argument; gopher does not parse flags there
+var passThroughCommands = map[string]bool{"exec": true, "run": true}
+
+// isBoolFlag reports whether f can be set without a value
+func isBoolFlag(f *flag.Flag) bool {
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// newTestFlagSet returns a flag set mirroring the shape of the global flags
+func newTestFlagSet() (*flag.FlagSet, *bool, *bool, *int, *string) {
+	fs := flag.NewFlagSet("gopher", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	jsonOut := fs.Bool("json", false, "")
+	verbose := fs.Bool("verbose", false, "")
+	pageSize := fs.Int("page-size", 10, "")
+	filter := fs.String("filter", "", "")
+	return fs, jsonOut, verbose, pageSize, filter
+}
+
+func TestParseArgs_FlagsBeforeCommand(t *testing.T) {
+	fs, jsonOut, _, pageSize, _ := newTestFlagSet()
+
+	args, err := parseArgs(fs, []string{"--json", "--page-size", "5", "list"})
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	if !reflect.DeepEqual(args, []string{"list"}) {
+		t.Errorf("args = %v, want [list]", args)
+	}
+	if !*jsonOut || *pageSize != 5 {
+		t.Errorf("json = %v, page-size = %d; want true, 5", *jsonOut, *pageSize)
+	}
+}
+
+func TestParseArgs_FlagsAfterCommand(t *testing.T) {
+	fs, jsonOut, verbose, _, filter := newTestFlagSet()
+
+	args, err := parseArgs(fs, []string{"install", "1.21.0", "--verbose", "--filter=1.21", "--json"})
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	if !reflect.DeepEqual(args, []string{"install", "1.21.0"}) {
+		t.Errorf("args = %v, want [install 1.21.0]", args)
+	}
+	if !*jsonOut || !*verbose || *filter != "1.21" {
+		t.Errorf("json = %v, verbose = %v, filter = %q", *jsonOut, *verbose, *filter)
+	}
+}
+
+func TestParseArgs_FlagsMixed(t *testing.T) {
+	fs, jsonOut, _, pageSize, _ := newTestFlagSet()
+
+	args, err := parseArgs(fs, []string{"-page-size", "3", "list-remote", "-json"})
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	if !reflect.DeepEqual(args, []string{"list-remote"}) {
+		t.Errorf("args = %v, want [list-remote]", args)
+	}
+	if !*jsonOut || *pageSize != 3 {
+		t.Errorf("json = %v, page-size = %d; want true, 3", *jsonOut, *pageSize)
+	}
+}
+
+func TestParseArgs_Terminator(t *testing.T) {
+	fs, jsonOut, _, _, _ := newTestFlagSet()
+
+	args, err := parseArgs(fs, []string{"exec", "go1.21.0", "--", "go", "test", "--json"})
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	want := []string{"exec", "go1.21.0", "go", "test", "--json"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+	if *jsonOut {
+		t.Error("flags after -- must not be parsed")
+	}
+}
+
+func TestParseArgs_UnknownFlags(t *testing.T) {
+	fs, _, _, _, _ := newTestFlagSet()
+
+	// Unknown flags after the command are passed through to the command
+	args, err := parseArgs(fs, []string{"alias", "export", "a.json", "--tags", "dev,prod"})
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	want := []string{"alias", "export", "a.json", "--tags", "dev,prod"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+
+	// Unknown flags before the command are an error
+	fs, _, _, _, _ = newTestFlagSet()
+	if _, err := parseArgs(fs, []string{"--bogus", "list"}); err == nil {
+		t.Error("expected error for unknown flag before command")
+	}
+}
+
+func TestParseArgs_ExecPassesChildFlags(t *testing.T) {
+	fs, jsonOut, verbose, _, _ := newTestFlagSet()
+
+	// Global flags before the version still apply to gopher
+	args, err := parseArgs(fs, []string{"exec", "--verbose", "go1.21.0", "go", "test", "--json", "-v", "./..."})
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	want := []string{"exec", "go1.21.0", "go", "test", "--json", "-v", "./..."}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+	if *jsonOut || !*verbose {
+		t.Errorf("json = %v, verbose = %v; want false, true", *jsonOut, *verbose)
+	}
+
+	fs, _, verbose, _, _ = newTestFlagSet()
+	args, err = parseArgs(fs, []string{"run", "go1.21.0", "--verbose"})
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	if want := []string{"run", "go1.21.0", "--verbose"}; !reflect.DeepEqual(args, want) || *verbose {
+		t.Errorf("args = %v (verbose %v), want %v", args, *verbose, want)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+// handleCacheCommand handles 'gopher cache' subcommands
+func handleCacheCommand(args []string, manager *inruntime.Manager) error {
+	if len(args) < 1 {
+		return showCacheHelp()
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		return listCache(manager)
+	case "size":
+		return showCacheSize(manager)
+	case "path":
+		return showCachePath(manager)
+	case "clean":
+		if len(args) > 2 {
+			return errors.Newf(errors.ErrCodeInvalidArgument, "cache clean takes at most one version")
+		}
+		if len(args) == 2 {
+			return cleanCachedVersion(manager, args[1])
+		}
+		return cleanDownloadCache(manager)
+	case "help":
+		return showCacheHelp()
+	default:
+		return errors.Newf(errors.ErrCodeInvalidArgument, "unknown cache subcommand: %s (use 'gopher cache help')", args[0])
+	}
+}
+
+// listCache shows the archives in the download cache
+func listCache(manager *inruntime.Manager) error {
+	archives, err := manager.ListCache()
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		return outputJSON(archives)
+	}
+
+	if len(archives) == 0 {
+		fmt.Printf("Download cache is empty (%s)\n", manager.CacheDir())
+		return nil
+	}
+
+	var total int64
+	fmt.Printf("Cached downloads in %s:\n\n", manager.CacheDir())
+	for _, archive := range archives {
+		version := archive.Version
+		if version == "" {
+			version = "-"
+		}
+		fmt.Printf("  %-12s %10s  %s\n", version, formatBytes(archive.Size), archive.File)
+		total += archive.Size
+	}
+	fmt.Printf("\nTotal: %s in %d files\n", formatBytes(total), len(archives))
+	return nil
+}
+
+// showCacheSize shows the total size of the download cache
+func showCacheSize(manager *inruntime.Manager) error {
+	size, err := manager.CacheSize()
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		return outputJSON(map[string]any{
+			"path": manager.CacheDir(),
+			"size": size,
+		})
+	}
+
+	fmt.Println(formatBytes(size))
+	return nil
+}
+
+// showCachePath prints the download cache directory
+func showCachePath(manager *inruntime.Manager) error {
+	if *jsonOutput {
+		return outputJSON(map[string]string{"path": manager.CacheDir()})
+	}
+
+	fmt.Println(manager.CacheDir())
+	return nil
+}
+
+// cleanCachedVersion removes the cached archives of one version
+func cleanCachedVersion(manager *inruntime.Manager, version string) error {
+	freed, err := manager.CleanCache(version)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		return outputJSON(map[string]any{
+			"version": inruntime.NormalizeVersion(version),
+			"freed":   freed,
+		})
+	}
+
+	fmt.Printf("✓ Removed cached download of %s\n", inruntime.NormalizeVersion(version))
+	fmt.Printf("  Freed: %s\n", formatBytes(freed))
+	return nil
+}
+
+// showCacheHelp shows help for 'gopher cache'
+func showCacheHelp() error {
+	fmt.Println(`gopher cache - Inspect and clean the download cache
+
+USAGE:
+    gopher cache <subcommand> [arguments]
+
+SUBCOMMANDS:
+    list                      List cached archives with their versions and sizes
+    size                      Show the total size of the cache
+    path                      Show the cache directory
+    clean [version]           Remove one version's archive, or the whole cache
+    help                      Show this help
+
+EXAMPLES:
+    gopher cache list
+    gopher cache size
+    gopher cache clean 1.21.0
+    gopher cache clean        # Same as 'gopher clean'
+    gopher --json cache list`)
+	return nil
+}
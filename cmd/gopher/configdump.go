@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+
+	"github.com/molmedoz/gopher/internal/config"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+// configDump is the configuration in effect for this run, after the config
+// file, command-line flags and environment variables are applied
+type configDump struct {
+	Config      *config.Config    `json:"config"`
+	Paths       *GopherPaths      `json:"paths"`
+	Channel     string            `json:"channel"`     // Release channel for list-remote and 'latest'
+	Offline     bool              `json:"offline"`     // Whether the network is used
+	GOTOOLCHAIN string            `json:"gotoolchain"` // What go commands see
+	Sources     map[string]string `json:"sources"`     // Where each setting came from
+}
+
+// dumpConfig prints the effective configuration as JSON (or YAML with
+// --format yaml), with the resolved paths and the source of every setting
+// that does not simply come from the config file
+func dumpConfig(manager *inruntime.Manager) error {
+	dump, err := effectiveConfig(manager)
+	if err != nil {
+		return err
+	}
+	return outputStructured(dump)
+}
+
+// effectiveConfig collects the configuration in effect for this run
+func effectiveConfig(manager *inruntime.Manager) (*configDump, error) {
+	cfg := manager.GetConfig()
+	paths, err := getGopherPaths(manager)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := activeChannel(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := map[string]string{
+		"config_path":  "default",
+		"install_dir":  "config file",
+		"download_dir": "config file",
+		"symlink_dir":  "config file",
+		"channel":      "default_channel",
+		"offline":      "default",
+		"gotoolchain":  "go default",
+	}
+	if *configPath != "" {
+		sources["config_path"] = "--config"
+	}
+	if *installDirFlag != "" {
+		sources["install_dir"] = "--install-dir"
+	}
+	if *downloadDirFlag != "" {
+		sources["download_dir"] = "--download-dir"
+	}
+	switch {
+	case *symlinkDirFlag != "":
+		sources["symlink_dir"] = "--symlink-dir"
+	case cfg.SymlinkDir == "":
+		sources["symlink_dir"] = "default"
+	}
+	switch {
+	case *stable:
+		sources["channel"] = "--stable"
+	case *channelFlag != "":
+		sources["channel"] = "--channel"
+	}
+	switch {
+	case *offline:
+		sources["offline"] = "--offline"
+	case offlineMode():
+		sources["offline"] = "GOPHER_OFFLINE"
+	}
+	switch {
+	case os.Getenv("GOTOOLCHAIN") != "":
+		sources["gotoolchain"] = "GOTOOLCHAIN environment variable"
+	case cfg.SetEnvironment && cfg.GOTOOLCHAIN != "":
+		sources["gotoolchain"] = "gotoolchain"
+	}
+
+	return &configDump{
+		Config:      cfg,
+		Paths:       paths,
+		Channel:     channel,
+		Offline:     offlineMode(),
+		GOTOOLCHAIN: manager.EffectiveGOTOOLCHAIN(),
+		Sources:     sources,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/env"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.InstallDir = filepath.Join(root, "versions")
+	cfg.DownloadDir = filepath.Join(root, "downloads")
+	cfg.GOTOOLCHAIN = "local"
+	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
+
+	savedInstallDir, savedChannel, savedStable, savedOffline := *installDirFlag, *channelFlag, *stable, *offline
+	defer func() {
+		*installDirFlag, *channelFlag, *stable, *offline = savedInstallDir, savedChannel, savedStable, savedOffline
+	}()
+	*installDirFlag, *channelFlag, *stable, *offline = cfg.InstallDir, "unstable", false, false
+	t.Setenv("GOPHER_OFFLINE", "1")
+	t.Setenv("GOTOOLCHAIN", "")
+
+	dump, err := effectiveConfig(manager)
+	if err != nil {
+		t.Fatalf("effectiveConfig failed: %v", err)
+	}
+
+	if dump.Config != cfg || dump.Paths.StateDir != filepath.Join(root, "state") {
+		t.Errorf("unexpected config or paths: %+v", dump)
+	}
+	if dump.Channel != "unstable" || !dump.Offline || dump.GOTOOLCHAIN != "local" {
+		t.Errorf("channel, offline, gotoolchain = %q, %v, %q; want unstable, true, local", dump.Channel, dump.Offline, dump.GOTOOLCHAIN)
+	}
+
+	want := map[string]string{
+		"config_path":  "default",
+		"install_dir":  "--install-dir",
+		"download_dir": "config file",
+		"channel":      "--channel",
+		"offline":      "GOPHER_OFFLINE",
+		"gotoolchain":  "gotoolchain",
+	}
+	for key, source := range want {
+		if got := dump.Sources[key]; got != source {
+			t.Errorf("sources[%s] = %q, want %q", key, got, source)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/log"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+// handleConfigCommand handles 'gopher config' subcommands
+func handleConfigCommand(args []string, manager *inruntime.Manager) error {
+	if len(args) < 1 {
+		return errors.NewMissingArgument("config (requires subcommand, e.g. 'gopher config edit' or 'gopher config dump')")
+	}
+
+	switch args[0] {
+	case "edit":
+		return editConfig(getConfigPath())
+	case "dump":
+		return dumpConfig(manager)
+	default:
+		return errors.Newf(errors.ErrCodeInvalidArgument, "unknown config subcommand: %s", args[0])
+	}
+}
+
+// editConfig opens the config file in the user's editor and keeps the result
+// only if it is a valid configuration; otherwise the previous file is
+// restored.
+func editConfig(configPath string) error {
+	editor, err := editorCommand(os.Getenv)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G304 -- configPath is gopher's own config file
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeConfigLoadFailed, "failed to read config file %s", configPath)
+	}
+
+	// #nosec G204 -- the editor is chosen by the user through $EDITOR
+	cmd := exec.Command(editor[0], append(editor[1:], configPath)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, errors.ErrCodeUnknown, "editor %s failed", editor[0])
+	}
+
+	// #nosec G304 -- configPath is gopher's own config file
+	edited, err := os.ReadFile(configPath)
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeConfigLoadFailed, "failed to read config file %s", configPath)
+	}
+	if bytes.Equal(original, edited) {
+		log.Info("Configuration unchanged")
+		return nil
+	}
+
+	if err := validateConfigData(edited); err != nil {
+		// #nosec G306 -- restores the file with the same permissions Config.Save uses
+		if restoreErr := os.WriteFile(configPath, original, 0644); restoreErr != nil {
+			return errors.NewConfigSaveFailed(configPath, restoreErr)
+		}
+		return errors.Wrapf(err, errors.ErrCodeInvalidConfigValue, "invalid configuration, previous config restored")
+	}
+
+	log.Debug("validated edited configuration at %s", configPath)
+	log.Info("✓ Configuration updated")
+	return nil
+}
+
+// validateConfigData checks that data is a valid configuration file
+func validateConfigData(data []byte) error {
+	var cfg config.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return errors.Wrapf(err, errors.ErrCodeInvalidFormat, "config file is not valid JSON")
+	}
+	return cfg.Validate()
+}
+
+// editorCommand returns the editor command line from $EDITOR, falling back
+// to notepad on Windows. $EDITOR may include arguments (e.g. "code --wait").
+func editorCommand(getenv func(string) string) ([]string, error) {
+	if fields := strings.Fields(getenv("EDITOR")); len(fields) > 0 {
+		return fields, nil
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}, nil
+	}
+	return nil, errors.New(errors.ErrCodeMissingArgument,
+		"no editor configured; set $EDITOR (e.g. 'export EDITOR=vim') or use 'gopher env set <key>=<value>'")
+}
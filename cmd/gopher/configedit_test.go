@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/config"
+)
+
+func TestEditorCommand(t *testing.T) {
+	env := map[string]string{"EDITOR": "code --wait"}
+	got, err := editorCommand(func(key string) string { return env[key] })
+	if err != nil || len(got) != 2 || got[0] != "code" || got[1] != "--wait" {
+		t.Fatalf("editorCommand = %v, %v; want [code --wait]", got, err)
+	}
+
+	delete(env, "EDITOR")
+	got, err = editorCommand(func(key string) string { return env[key] })
+	if runtime.GOOS == "windows" {
+		if err != nil || got[0] != "notepad" {
+			t.Errorf("editorCommand = %v, %v; want notepad", got, err)
+		}
+	} else if err == nil {
+		t.Errorf("expected an error when $EDITOR is unset, got %v", got)
+	}
+}
+
+func TestEditConfig_RestoresInvalidConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the editor")
+	}
+
+	tmp := t.TempDir()
+	configPath := filepath.Join(tmp, "config.json")
+	original, err := json.MarshalIndent(config.DefaultConfig(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// An "editor" that sets max_versions to an invalid value
+	editor := filepath.Join(tmp, "editor.sh")
+	script := "#!/bin/sh\nsed 's/\"max_versions\": [0-9]*/\"max_versions\": 0/' \"$1\" > \"$1.tmp\" && mv \"$1.tmp\" \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", editor)
+
+	if err := editConfig(configPath); err == nil {
+		t.Fatal("expected an error for an invalid edited config")
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(original) {
+		t.Errorf("config was not restored:\n%s", data)
+	}
+
+	if err := validateConfigData([]byte("{not json")); err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+	if err := validateConfigData(original); err != nil {
+		t.Errorf("default config should be valid: %v", err)
+	}
+}
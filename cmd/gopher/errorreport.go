@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/molmedoz/gopher/internal/errors"
+)
+
+// errorDetail is the error of a failed command with --json
+type errorDetail struct {
+	Code    errors.ErrorCode `json:"code"`
+	Message string           `json:"message"`
+}
+
+// exitCodes maps error codes to process exit codes so scripts can branch on
+// the reason a command failed. Codes that are not listed exit with 1.
+var exitCodes = map[errors.ErrorCode]int{
+	errors.ErrCodeMissingArgument:         2,
+	errors.ErrCodeInvalidArgument:         2,
+	errors.ErrCodeInvalidFormat:           2,
+	errors.ErrCodeVersionNotInstalled:     3,
+	errors.ErrCodeDownloadFailed:          4,
+	errors.ErrCodeNetworkUnavailable:      5,
+	errors.ErrCodeTimeoutExceeded:         5,
+	errors.ErrCodeServerUnavailable:       5,
+	errors.ErrCodeInvalidVersion:          6,
+	errors.ErrCodeInstallationFailed:      7,
+	errors.ErrCodeExtractionFailed:        7,
+	errors.ErrCodeVersionAlreadyInstalled: 8,
+	errors.ErrCodeAliasNotFound:           9,
+	errors.ErrCodePermissionDenied:        10,
+	errors.ErrCodeConfigLoadFailed:        11,
+	errors.ErrCodeConfigSaveFailed:        11,
+}
+
+// exitCodeInterrupted is the exit code of a command stopped with Ctrl-C, as
+// shells report for SIGINT
+const exitCodeInterrupted = 130
+
+// exitCodeFor returns the process exit code for a command error
+func exitCodeFor(err error) int {
+	if isInterrupted(err) {
+		return exitCodeInterrupted
+	}
+	if code, ok := exitCodes[errorCode(err)]; ok {
+		return code
+	}
+	return 1
+}
+
+// reportError prints a command error: as JSON on stdout with --json, so
+// scripts can branch on the code, otherwise as text on stderr
+func reportError(err error) {
+	if *jsonOutput {
+		envelope := newEnvelope(nil)
+		envelope.Error = &errorDetail{Code: errorCode(err), Message: errorMessage(err)}
+		if jsonErr := writeEnvelope(envelope); jsonErr == nil {
+			return
+		}
+	}
+	if isInterrupted(err) {
+		fmt.Fprintln(os.Stderr, "Interrupted")
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+}
+
+// isInterrupted reports whether err comes from a command canceled by
+// interruptContext
+func isInterrupted(err error) bool {
+	for err != nil {
+		if err == context.Canceled {
+			return true
+		}
+		err = unwrapError(err)
+	}
+	return false
+}
+
+// errorCode returns the most specific code in err's chain, which is the
+// innermost one other than UNKNOWN_ERROR. Commands wrap a specific error
+// (e.g. VERSION_NOT_INSTALLED) with a generic one such as
+// UNINSTALLATION_FAILED, so the outer codes only describe the command that
+// failed, not why.
+func errorCode(err error) errors.ErrorCode {
+	code := errors.ErrCodeUnknown
+	for err != nil {
+		if gopherErr, ok := err.(*errors.GopherError); ok && gopherErr.Code != errors.ErrCodeUnknown {
+			code = gopherErr.Code
+		}
+		err = unwrapError(err)
+	}
+	return code
+}
+
+// errorMessage joins the messages in err's chain without the code prefixes
+// that GopherError.Error adds
+func errorMessage(err error) string {
+	var parts []string
+	for err != nil {
+		gopherErr, ok := err.(*errors.GopherError)
+		if !ok {
+			parts = append(parts, err.Error())
+			break
+		}
+		parts = append(parts, gopherErr.Message)
+		err = gopherErr.WrappedErr
+	}
+	return strings.Join(parts, ": ")
+}
+
+// unwrapError returns the error wrapped by err, or nil
+func unwrapError(err error) error {
+	if wrapper, ok := err.(interface{ Unwrap() error }); ok {
+		return wrapper.Unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/env"
+	"github.com/molmedoz/gopher/internal/errors"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+func TestErrorCodeAndMessage(t *testing.T) {
+	notInstalled := errors.NewVersionNotInstalled("go1.21.0")
+	err := errors.Wrapf(fmt.Errorf("switch failed: %w", notInstalled), errors.ErrCodeUnknown, "failed to switch to version %s", "go1.21.0")
+
+	if got := errorCode(err); got != errors.ErrCodeVersionNotInstalled {
+		t.Errorf("errorCode = %s, want %s", got, errors.ErrCodeVersionNotInstalled)
+	}
+	want := "failed to switch to version go1.21.0: switch failed: " + notInstalled.Error()
+	if got := errorMessage(err); got != want {
+		t.Errorf("errorMessage = %q, want %q", got, want)
+	}
+
+	plain := fmt.Errorf("boom")
+	if got := errorCode(plain); got != errors.ErrCodeUnknown {
+		t.Errorf("errorCode of a plain error = %s, want %s", got, errors.ErrCodeUnknown)
+	}
+	if got := errorMessage(plain); got != "boom" {
+		t.Errorf("errorMessage of a plain error = %q", got)
+	}
+
+	missing := errors.NewMissingArgument("install")
+	if got := errorMessage(missing); got != missing.Message {
+		t.Errorf("errorMessage = %q, want %q", got, missing.Message)
+	}
+}
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"missing argument", errors.NewMissingArgument("install"), 2},
+		{"wrapped not installed", errors.Wrapf(errors.NewVersionNotInstalled("go1.21.0"), errors.ErrCodeUnknown, "failed to switch"), 3},
+		{"download failed", errors.NewDownloadFailed("go1.21.0", fmt.Errorf("404")), 4},
+		{"network", errors.NewNetworkUnavailable(fmt.Errorf("no route")), 5},
+		{"unknown", errors.New(errors.ErrCodeUnknown, "boom"), 1},
+		{"plain error", fmt.Errorf("boom"), 1},
+		{"not installed in uninstall", errors.Wrapf(errors.NewVersionNotInstalled("go1.21.0"), errors.ErrCodeUninstallationFailed, "failed to uninstall"), 3},
+		{"already installed in install", errors.Wrapf(errors.NewVersionAlreadyInstalled("go1.21.0"), errors.ErrCodeInstallationFailed, "failed to install"), 8},
+		{"download failed in install", errors.Wrapf(errors.NewDownloadFailed("go1.21.0", fmt.Errorf("404")), errors.ErrCodeInstallationFailed, "failed to install"), 4},
+		{"install failed", errors.Wrapf(fmt.Errorf("disk full"), errors.ErrCodeInstallationFailed, "failed to install"), 7},
+		{"interrupted", errors.Wrapf(errors.NewDownloadFailed("go1.21.0", fmt.Errorf("request: %w", context.Canceled)), errors.ErrCodeInstallationFailed, "failed to install"), 130},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeFor(tt.err); got != tt.want {
+				t.Errorf("exitCodeFor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// decodeOnlyDocument decodes out into v and fails unless out is exactly one
+// JSON document, with nothing before or after it
+func decodeOnlyDocument(t *testing.T, out []byte, v any) {
+	t.Helper()
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	if err := decoder.Decode(v); err != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", err, out)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		t.Fatalf("stdout has more than the JSON document:\n%s", out)
+	}
+}
+
+// captureStdout returns what fn writes to stdout
+func captureStdout(t *testing.T, fn func()) []byte {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = saved }()
+
+	fn()
+	_ = w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestOutputEnvelope(t *testing.T) {
+	savedJSON, savedCommand := *jsonOutput, outputCommand
+	defer func() { *jsonOutput, outputCommand = savedJSON, savedCommand }()
+	*jsonOutput, outputCommand = true, "use"
+
+	var envelope struct {
+		SchemaVersion int             `json:"schema_version"`
+		Command       string          `json:"command"`
+		Data          json.RawMessage `json:"data"`
+		Error         *errorDetail    `json:"error"`
+	}
+
+	out := captureStdout(t, func() {
+		if err := outputJSON(map[string]string{"version": "go1.21.0"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	decodeOnlyDocument(t, out, &envelope)
+	var data map[string]string
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		t.Fatalf("invalid data %s: %v", envelope.Data, err)
+	}
+	if envelope.SchemaVersion != jsonSchemaVersion || envelope.Command != "use" || data["version"] != "go1.21.0" || envelope.Error != nil {
+		t.Errorf("unexpected envelope: %s", out)
+	}
+
+	out = captureStdout(t, func() { reportError(errors.NewVersionNotInstalled("go1.99.0")) })
+	envelope.Error = nil
+	decodeOnlyDocument(t, out, &envelope)
+	if envelope.Command != "use" || string(envelope.Data) != "null" || envelope.Error == nil || envelope.Error.Code != errors.ErrCodeVersionNotInstalled {
+		t.Errorf("unexpected error envelope: %s", out)
+	}
+}
+
+// TestCommandErrorCodes checks that install and uninstall report the reason
+// they failed, not the generic code they wrap it in
+func TestCommandErrorCodes(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.InstallDir = filepath.Join(root, "versions")
+	cfg.DownloadDir = filepath.Join(root, "downloads")
+	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
+
+	versionDir := filepath.Join(cfg.InstallDir, "go1.21.0")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	metadata := "version=go1.21.0\nos=" + runtime.GOOS + "\narch=" + runtime.GOARCH + "\ninstalled_at=2023-01-01T00:00:00Z\ninstall_dir=" + versionDir + "\n"
+	if err := os.WriteFile(filepath.Join(versionDir, ".gopher-metadata"), []byte(metadata), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	savedJSON, savedCommand := *jsonOutput, outputCommand
+	defer func() { *jsonOutput, outputCommand = savedJSON, savedCommand }()
+	*jsonOutput = true
+
+	tests := []struct {
+		command  string
+		run      func() error
+		wantCode errors.ErrorCode
+		wantExit int
+	}{
+		{"install", func() error { return installVersion(context.Background(), manager, "1.21.0") }, errors.ErrCodeVersionAlreadyInstalled, 8},
+		{"uninstall", func() error { return uninstallVersion(manager, "1.22.0") }, errors.ErrCodeVersionNotInstalled, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			outputCommand = tt.command
+			err := tt.run()
+			if err == nil {
+				t.Fatalf("%s succeeded", tt.command)
+			}
+			if got := exitCodeFor(err); got != tt.wantExit {
+				t.Errorf("exitCodeFor() = %d, want %d", got, tt.wantExit)
+			}
+
+			var envelope outputEnvelope
+			decodeOnlyDocument(t, captureStdout(t, func() { reportError(err) }), &envelope)
+			if envelope.Error == nil || envelope.Error.Code != tt.wantCode {
+				t.Errorf("%s --json reported %+v, want code %s", tt.command, envelope.Error, tt.wantCode)
+			}
+		})
+	}
+}
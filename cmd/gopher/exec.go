@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/log"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+// exitCodeError reports that gopher should exit with a child process's exit
+// code without printing an error of its own
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// execWithVersion runs a command with a Go version activated only for that
+// command. args is the version (or alias) followed by the command and its
+// arguments; a "--" separating them has already been removed by parseArgs.
+func execWithVersion(manager *inruntime.Manager, args []string) error {
+	if len(args) < 2 {
+		return errors.NewMissingArgument("exec (requires version and command, e.g. 'gopher exec 1.21.0 -- go test ./...')")
+	}
+
+	version, vars, err := manager.ExecEnvironment(args[0])
+	if err != nil {
+		return err
+	}
+	log.Debug("running %v with Go %s", args[1:], version)
+
+	// Apply the environment to this process too, so the command itself is
+	// looked up on the version's PATH
+	for key, value := range vars {
+		if err := os.Setenv(key, value); err != nil {
+			return errors.Wrapf(err, errors.ErrCodeEnvironmentSetupFailed, "failed to set %s", key)
+		}
+	}
+
+	return runChild(exec.Command(args[1], args[2:]...))
+}
+
+// runSubshell starts the user's shell with a Go version activated, without
+// touching the active version. The parent environment is unchanged once the
+// shell exits.
+func runSubshell(manager *inruntime.Manager, args []string) error {
+	if len(args) < 1 {
+		return errors.NewMissingArgument("run (requires version, e.g. 'gopher run 1.21.0')")
+	}
+
+	version, vars, err := manager.ExecEnvironment(args[0])
+	if err != nil {
+		return err
+	}
+
+	for key, value := range vars {
+		if err := os.Setenv(key, value); err != nil {
+			return errors.Wrapf(err, errors.ErrCodeEnvironmentSetupFailed, "failed to set %s", key)
+		}
+	}
+	// Lets prompts and scripts tell they are inside a gopher subshell
+	if err := os.Setenv("GOPHER_SUBSHELL", version); err != nil {
+		return errors.Wrapf(err, errors.ErrCodeEnvironmentSetupFailed, "failed to set GOPHER_SUBSHELL")
+	}
+
+	shell := subshellCommand(os.Getenv)
+	fmt.Printf("Starting %s with Go %s (type 'exit' to return)\n", shell, version)
+	err = runChild(exec.Command(shell))
+	fmt.Printf("Left Go %s subshell\n", version)
+	return err
+}
+
+// subshellCommand returns the shell to start for 'gopher run': $SHELL on
+// Unix, PowerShell or cmd.exe on Windows
+func subshellCommand(getenv func(string) string) string {
+	if runtime.GOOS == "windows" {
+		if getenv("PSModulePath") != "" {
+			if _, err := exec.LookPath("pwsh"); err == nil {
+				return "pwsh"
+			}
+			return "powershell"
+		}
+		if comspec := getenv("COMSPEC"); comspec != "" {
+			return comspec
+		}
+		return "cmd.exe"
+	}
+
+	if shell := getenv("SHELL"); shell != "" {
+		return shell
+	}
+	return "/bin/sh"
+}
+
+// runChild runs cmd attached to the terminal and turns a non-zero exit into
+// an exitCodeError. Interrupts are left to the child to handle, so gopher
+// exits with the child's code rather than being killed first.
+func runChild(cmd *exec.Cmd) error {
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			code := exitErr.ExitCode()
+			if code < 0 {
+				code = 1 // Killed by a signal
+			}
+			return &exitCodeError{code: code}
+		}
+		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to run %s", cmd.Path)
+	}
+	return nil
+}
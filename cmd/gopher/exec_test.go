@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestRunChild_ExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	if err := runChild(exec.Command("sh", "-c", "exit 0")); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	err := runChild(exec.Command("sh", "-c", "exit 3"))
+	exitErr, ok := err.(*exitCodeError)
+	if !ok {
+		t.Fatalf("expected *exitCodeError, got %T (%v)", err, err)
+	}
+	if exitErr.code != 3 {
+		t.Errorf("exit code = %d, want 3", exitErr.code)
+	}
+
+	if err := runChild(exec.Command("gopher-test-command-that-does-not-exist")); err == nil {
+		t.Error("expected error for a missing command")
+	} else if _, ok := err.(*exitCodeError); ok {
+		t.Error("a missing command should not be reported as a child exit code")
+	}
+}
+
+func TestSubshellCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("tests Unix shell selection")
+	}
+
+	env := map[string]string{"SHELL": "/usr/bin/zsh"}
+	getenv := func(key string) string { return env[key] }
+
+	if got := subshellCommand(getenv); got != "/usr/bin/zsh" {
+		t.Errorf("subshellCommand() = %q, want /usr/bin/zsh", got)
+	}
+
+	delete(env, "SHELL")
+	if got := subshellCommand(getenv); got != "/bin/sh" {
+		t.Errorf("subshellCommand() without SHELL = %q, want /bin/sh", got)
+	}
+}
@@ -1,9 +1,18 @@
 package main
 
 import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/molmedoz/gopher/internal/config"
 	"github.com/molmedoz/gopher/internal/downloader"
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/log"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
 )
 
 func filterVersionsHelper(list []downloader.VersionInfo, filter string, stableOnly bool) []downloader.VersionInfo {
@@ -37,3 +46,552 @@ func paginateHelper(list []downloader.VersionInfo, page, pageSize int) []downloa
 	}
 	return list[start:end]
 }
+
+// applyDirOverrides replaces the configured install and download directories
+// with the given overrides (empty values are ignored) and makes sure the
+// resulting directories can be created.
+func applyDirOverrides(cfg *config.Config, installDir, downloadDir, symlinkDir string) error {
+	if installDir == "" && downloadDir == "" && symlinkDir == "" {
+		return nil
+	}
+
+	if installDir != "" {
+		abs, err := filepath.Abs(installDir)
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeInvalidArgument, "invalid install directory %s", installDir)
+		}
+		cfg.InstallDir = abs
+	}
+
+	if downloadDir != "" {
+		abs, err := filepath.Abs(downloadDir)
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeInvalidArgument, "invalid download directory %s", downloadDir)
+		}
+		cfg.DownloadDir = abs
+	}
+
+	if symlinkDir != "" {
+		abs, err := filepath.Abs(symlinkDir)
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeInvalidArgument, "invalid symlink directory %s", symlinkDir)
+		}
+		cfg.SymlinkDir = abs
+	}
+
+	if err := cfg.EnsureDirectories(); err != nil {
+		return errors.Wrapf(err, errors.ErrCodeInvalidArgument, "failed to prepare directories")
+	}
+
+	return nil
+}
+
+// applyConfigOption sets one configuration key on cfg. The change is made on
+// a copy and only applied when the resulting configuration is valid, so cfg
+// is left untouched on error.
+func applyConfigOption(cfg *config.Config, key, value string) error {
+	updated := *cfg
+	switch key {
+	case "install_dir", "download_dir":
+		if value == "" {
+			return errors.Newf(errors.ErrCodeInvalidConfigValue, "%s cannot be empty", key)
+		}
+		abs, err := filepath.Abs(value)
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeInvalidConfigValue, "invalid %s %s", key, value)
+		}
+		if key == "install_dir" {
+			updated.InstallDir = abs
+		} else {
+			updated.DownloadDir = abs
+		}
+	case "symlink_dir":
+		// Empty goes back to the platform's standard directory
+		if value != "" {
+			abs, err := filepath.Abs(value)
+			if err != nil {
+				return errors.Wrapf(err, errors.ErrCodeInvalidConfigValue, "invalid %s %s", key, value)
+			}
+			value = abs
+		}
+		updated.SymlinkDir = value
+	case "mirror_url":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.MirrorURL = value
+	case "auto_cleanup":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.AutoCleanup = value == "true"
+	case "keep_downloads":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.KeepDownloads = value == "true"
+	case "auto_install_on_use":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.AutoInstallOnUse = value == "true"
+	case "max_versions":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.Newf(errors.ErrCodeInvalidConfigValue, "max_versions must be a number, got %q", value)
+		}
+		updated.MaxVersions = n
+	case "gopath_mode":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.GOPATHMode = value
+	case "custom_gopath":
+		updated.CustomGOPATH = value
+	case "gobin_mode":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.GOBINMode = value
+	case "custom_gobin":
+		updated.CustomGOBIN = value
+	case "goproxy":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.GOPROXY = value
+	case "gosumdb":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.GOSUMDB = value
+	case "goflags":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.GOFLAGS = value
+	case "gotoolchain":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.GOTOOLCHAIN = value
+	case "default_channel":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.DefaultChannel = value
+	case "linked_binaries":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.LinkedBinaries = nil
+		for _, name := range strings.Split(value, ",") {
+			updated.LinkedBinaries = append(updated.LinkedBinaries, strings.TrimSpace(name))
+		}
+	case "pre_install":
+		// Any shell command; empty removes the hook
+		updated.PreInstall = value
+	case "post_install":
+		updated.PostInstall = value
+	case "post_use":
+		updated.PostUse = value
+	case "strict_hooks":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.StrictHooks = value == "true"
+	case "set_environment":
+		if err := errors.ValidateConfigValue(key, value); err != nil {
+			return err
+		}
+		updated.SetEnvironment = value == "true"
+	default:
+		return errors.NewUnknownConfigOption(key)
+	}
+
+	if err := updated.Validate(); err != nil {
+		return errors.Wrapf(err, errors.ErrCodeInvalidConfigValue, "invalid value for %s", key)
+	}
+
+	*cfg = updated
+	return nil
+}
+
+// Version keywords accepted wherever a concrete version is expected.
+const (
+	versionKeywordLatest       = "latest"
+	versionKeywordStable       = "stable"
+	versionKeywordLatestStable = "latest-stable"
+	versionKeywordLatestRC     = "latest-rc"
+	versionKeywordLatestBeta   = "latest-beta"
+)
+
+// Release channels decide which versions list-remote shows and "latest"
+// resolves to.
+const (
+	channelStable   = "stable"
+	channelUnstable = "unstable"
+)
+
+// activeChannel returns the release channel for this invocation: --stable
+// forces the stable channel, then --channel, then the default_channel setting.
+func activeChannel(cfg *config.Config) (string, error) {
+	if *stable {
+		return channelStable, nil
+	}
+	channel := *channelFlag
+	if channel == "" {
+		channel = cfg.DefaultChannel
+	}
+	switch channel {
+	case "", channelStable:
+		return channelStable, nil
+	case channelUnstable:
+		return channelUnstable, nil
+	}
+	return "", errors.Newf(errors.ErrCodeInvalidArgument, "unknown channel %q (use 'stable' or 'unstable')", channel)
+}
+
+// filterChannel returns the versions that belong to channel: stable releases
+// only for the stable channel, everything for the unstable channel.
+func filterChannel(versions []downloader.VersionInfo, channel string) []downloader.VersionInfo {
+	if channel == channelUnstable {
+		return versions
+	}
+	return filterStableVersions(versions)
+}
+
+// partialVersionRegex matches a major.minor version without a patch
+// component, e.g. "1.21" or "go1.21".
+var partialVersionRegex = regexp.MustCompile(`^(go)?\d+\.\d+$`)
+
+// isVersionKeyword reports whether spec is one of the magic version keywords.
+func isVersionKeyword(spec string) bool {
+	switch spec {
+	case versionKeywordLatest, versionKeywordStable, versionKeywordLatestStable,
+		versionKeywordLatestRC, versionKeywordLatestBeta:
+		return true
+	}
+	return false
+}
+
+// isPartialVersion reports whether spec is a major.minor version like "1.21".
+func isPartialVersion(spec string) bool {
+	return partialVersionRegex.MatchString(spec)
+}
+
+// zeroPatchRegex matches a Go 1.x version with a .0 patch, e.g. "1.20.0"
+var zeroPatchRegex = regexp.MustCompile(`^(?:go)?1\.(\d+)\.0$`)
+
+// firstReleaseVersion maps a spec like "1.20.0" to "go1.20". Before Go 1.21
+// the first release of a minor version had no .0 patch, so go1.20.0 does not
+// exist; "1.20.0" is how to ask for exactly that release, as "1.20" selects
+// the newest 1.20.x. Other specs are returned unchanged.
+func firstReleaseVersion(spec string) string {
+	match := zeroPatchRegex.FindStringSubmatch(spec)
+	if match == nil {
+		return spec
+	}
+	// Go 1.0 was released as "go1"
+	if minor, _ := strconv.Atoi(match[1]); minor < 1 || minor > 20 {
+		return spec
+	}
+	return "go1." + match[1]
+}
+
+// resolveVersionSpec turns a user supplied version spec into a concrete
+// version. The keywords "latest", "stable", "latest-stable", "latest-rc" and
+// "latest-beta" and partial versions such as "1.21" are resolved against the
+// versions available for download, and "1.20.0" style specs are mapped by
+// firstReleaseVersion; any other spec is returned unchanged.
+func resolveVersionSpec(ctx context.Context, manager *inruntime.Manager, spec string) (string, error) {
+	if !isVersionKeyword(spec) && !isPartialVersion(spec) {
+		return firstReleaseVersion(spec), nil
+	}
+
+	available, err := manager.ListAvailable(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, errors.ErrCodeNetworkUnavailable, "failed to resolve version %q", spec)
+	}
+
+	channel, err := activeChannel(manager.GetConfig())
+	if err != nil {
+		return "", err
+	}
+
+	version, ok := selectVersion(available, spec, channel)
+	if !ok {
+		return "", errors.Newf(errors.ErrCodeInvalidVersion, "no available Go version matches %q", spec)
+	}
+	return version, nil
+}
+
+// resolveInstalledVersionSpec resolves spec like resolveVersionSpec, but
+// against the installed versions instead of the remote release list, so
+// "use 1.21" switches to the newest installed 1.21.x. When nothing matches,
+// the not-installed error names the closest installed version.
+func resolveInstalledVersionSpec(manager *inruntime.Manager, spec string) (string, error) {
+	if !isVersionKeyword(spec) && !isPartialVersion(spec) {
+		return firstReleaseVersion(spec), nil
+	}
+
+	installed, err := manager.ListInstalled()
+	if err != nil {
+		return "", errors.Wrapf(err, errors.ErrCodeUnknown, "failed to list installed versions")
+	}
+
+	var candidates []downloader.VersionInfo
+	for _, v := range installed {
+		if v.IsSystem {
+			continue
+		}
+		candidates = append(candidates, downloader.VersionInfo{
+			Version: v.Version,
+			Stable:  downloader.IsStableVersion(v.Version),
+		})
+	}
+
+	channel, err := activeChannel(manager.GetConfig())
+	if err != nil {
+		return "", err
+	}
+
+	if version, ok := selectVersion(candidates, spec, channel); ok {
+		return version, nil
+	}
+
+	if closest, ok := closestVersion(candidates, spec); ok {
+		return "", errors.Newf(errors.ErrCodeVersionNotInstalled,
+			"version %s is not installed (closest installed version: %s)", spec, closest)
+	}
+	return "", errors.NewVersionNotInstalled(spec)
+}
+
+// selectVersion picks the version from list that best satisfies spec.
+//
+// "latest" selects the highest version in channel, so prereleases only on
+// the unstable channel, "stable" and "latest-stable" the highest stable
+// version, and "latest-rc" and "latest-beta" the highest release candidate
+// or beta. A partial version selects the highest matching stable release,
+// falling back to the highest matching prerelease when no stable release
+// exists yet.
+func selectVersion(list []downloader.VersionInfo, spec, channel string) (string, bool) {
+	var best, bestStable, bestRC, bestBeta string
+	newer := func(version, than string) bool {
+		return than == "" || downloader.CompareVersions(version, than) > 0
+	}
+	for _, v := range list {
+		if !isVersionKeyword(spec) && !matchesPartialVersion(v.Version, spec) {
+			continue
+		}
+		if newer(v.Version, best) {
+			best = v.Version
+		}
+		if v.Stable && newer(v.Version, bestStable) {
+			bestStable = v.Version
+		}
+		switch prereleaseKind(v.Version) {
+		case "rc":
+			if newer(v.Version, bestRC) {
+				bestRC = v.Version
+			}
+		case "beta":
+			if newer(v.Version, bestBeta) {
+				bestBeta = v.Version
+			}
+		}
+	}
+
+	switch spec {
+	case versionKeywordLatest:
+		if channel != channelUnstable {
+			return bestStable, bestStable != ""
+		}
+		return best, best != ""
+	case versionKeywordStable, versionKeywordLatestStable:
+		return bestStable, bestStable != ""
+	case versionKeywordLatestRC:
+		return bestRC, bestRC != ""
+	case versionKeywordLatestBeta:
+		return bestBeta, bestBeta != ""
+	}
+	if bestStable != "" {
+		return bestStable, true
+	}
+	return best, best != ""
+}
+
+// matchesPartialVersion reports whether version belongs to the release line
+// named by partial, e.g. "go1.21.3" and "go1.21rc2" both match "1.21" while
+// "go1.210.0" does not.
+func matchesPartialVersion(version, partial string) bool {
+	version = strings.TrimPrefix(version, "go")
+	partial = strings.TrimPrefix(partial, "go")
+	if !strings.HasPrefix(version, partial) {
+		return false
+	}
+	rest := version[len(partial):]
+	return rest == "" || rest[0] < '0' || rest[0] > '9'
+}
+
+// filterVersionRange keeps the versions in list between minVersion and
+// maxVersion, inclusive; an empty bound is open. A partial bound such as
+// "1.22" covers its whole release line, so a maximum of "1.22" keeps
+// go1.22.5 and a minimum of "1.20" keeps go1.20rc1.
+func filterVersionRange(list []downloader.VersionInfo, minVersion, maxVersion string) ([]downloader.VersionInfo, error) {
+	for _, bound := range []string{minVersion, maxVersion} {
+		if bound == "" {
+			continue
+		}
+		if err := inruntime.ValidateVersion(bound); err != nil {
+			return nil, errors.Wrapf(err, errors.ErrCodeInvalidVersion, "invalid version range bound %q", bound)
+		}
+	}
+	if minVersion != "" && maxVersion != "" &&
+		downloader.CompareVersions(minVersion, maxVersion) > 0 &&
+		!matchesPartialVersion(minVersion, maxVersion) {
+		return nil, errors.Newf(errors.ErrCodeInvalidVersion,
+			"--min %s is greater than --max %s", minVersion, maxVersion)
+	}
+
+	var filtered []downloader.VersionInfo
+	for _, v := range list {
+		if minVersion != "" && downloader.CompareVersions(v.Version, minVersion) < 0 &&
+			!(isPartialVersion(minVersion) && matchesPartialVersion(v.Version, minVersion)) {
+			continue
+		}
+		if maxVersion != "" && downloader.CompareVersions(v.Version, maxVersion) > 0 &&
+			!(isPartialVersion(maxVersion) && matchesPartialVersion(v.Version, maxVersion)) {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+	return filtered, nil
+}
+
+// remoteVersion is a version available for download, marked when it is
+// already installed
+type remoteVersion struct {
+	downloader.VersionInfo
+	Installed bool `json:"installed"`
+}
+
+// markInstalled pairs each available version with whether it is among the
+// installed versions, Gopher-managed or system
+func markInstalled(available []downloader.VersionInfo, installed []inruntime.Version) []remoteVersion {
+	have := make(map[string]bool, len(installed))
+	for _, v := range installed {
+		have[inruntime.NormalizeVersion(v.Version)] = true
+	}
+
+	marked := make([]remoteVersion, 0, len(available))
+	for _, v := range available {
+		marked = append(marked, remoteVersion{VersionInfo: v, Installed: have[inruntime.NormalizeVersion(v.Version)]})
+	}
+	return marked
+}
+
+// installedTag returns the suffix list-remote shows after an installed
+// version
+func installedTag(v remoteVersion) string {
+	if v.Installed {
+		return " [installed]"
+	}
+	return ""
+}
+
+// formatVersionRange describes a --min/--max range for display.
+func formatVersionRange(minVersion, maxVersion string) string {
+	switch {
+	case minVersion == "":
+		return "<= " + maxVersion
+	case maxVersion == "":
+		return ">= " + minVersion
+	}
+	return minVersion + " to " + maxVersion
+}
+
+// majorMinorRegex extracts the major and minor components of a version.
+var majorMinorRegex = regexp.MustCompile(`^(?:go)?(\d+)\.(\d+)`)
+
+// closestVersion returns the version in list whose major.minor is nearest to
+// spec, preferring the newer version on ties. Keywords have no meaningful
+// distance, so they fall back to the newest version.
+func closestVersion(list []downloader.VersionInfo, spec string) (string, bool) {
+	if len(list) == 0 {
+		return "", false
+	}
+
+	target := majorMinorRegex.FindStringSubmatch(spec)
+	best, bestDistance := "", -1
+	for _, v := range list {
+		distance := 0
+		if target != nil {
+			parts := majorMinorRegex.FindStringSubmatch(v.Version)
+			if parts == nil {
+				continue
+			}
+			distance = absDiff(parts[1], target[1])*1000 + absDiff(parts[2], target[2])
+		}
+		if bestDistance == -1 || distance < bestDistance ||
+			(distance == bestDistance && downloader.CompareVersions(v.Version, best) > 0) {
+			best, bestDistance = v.Version, distance
+		}
+	}
+	return best, best != ""
+}
+
+// absDiff returns the absolute difference between two numeric strings.
+func absDiff(a, b string) int {
+	x, _ := strconv.Atoi(a)
+	y, _ := strconv.Atoi(b)
+	if x > y {
+		return x - y
+	}
+	return y - x
+}
+
+// prereleaseKind returns "rc", "beta" or "alpha" for a prerelease version,
+// using the same markers as downloader.IsStableVersion, and "" otherwise
+func prereleaseKind(version string) string {
+	lower := strings.ToLower(version)
+	for _, kind := range []string{"rc", "beta", "alpha"} {
+		if strings.Contains(lower, kind) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// currentAliasNames returns the sorted names of the aliases pointing at the
+// active version. Aliases only resolve to gopher-managed versions, so none are
+// reported for the system Go. Failing to read aliases is not fatal here.
+func currentAliasNames(manager *inruntime.Manager, current *inruntime.Version) []string {
+	names := []string{}
+	if current.IsSystem {
+		return names
+	}
+
+	aliases, err := manager.AliasManager().GetAliasesByVersion(current.Version)
+	if err != nil {
+		log.Debug("failed to look up aliases for %s: %v", current.Version, err)
+		return names
+	}
+
+	for _, alias := range aliases {
+		names = append(names, alias.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// indentLines prefixes every non-empty line of text with indent
+func indentLines(text, indent string) string {
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "")
+}
@@ -1,9 +1,18 @@
 package main
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/molmedoz/gopher/internal/config"
 	"github.com/molmedoz/gopher/internal/downloader"
+	"github.com/molmedoz/gopher/internal/env"
+	"github.com/molmedoz/gopher/internal/errors"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
 )
 
 func sampleList() []downloader.VersionInfo {
@@ -77,3 +86,456 @@ func TestFilterVersionsHelper_NoMatches(t *testing.T) {
 		t.Fatalf("expected no matches, got %d", len(got))
 	}
 }
+
+func TestSelectVersion(t *testing.T) {
+	list := []downloader.VersionInfo{
+		{Version: "go1.24.7", Stable: true},
+		{Version: "go1.25rc1", Stable: false},
+		{Version: "go1.25.1", Stable: true},
+		{Version: "go1.26rc1", Stable: false},
+		{Version: "go1.21.10", Stable: true},
+		{Version: "go1.21.9", Stable: true},
+		{Version: "go1.2.2", Stable: true},
+	}
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"latest", "go1.26rc1"},
+		{"stable", "go1.25.1"},
+		{"latest-stable", "go1.25.1"},
+		{"1.21", "go1.21.10"},
+		{"go1.21", "go1.21.10"},
+		{"1.25", "go1.25.1"},
+		{"1.26", "go1.26rc1"},
+		{"1.2", "go1.2.2"},
+	}
+	for _, tt := range tests {
+		got, ok := selectVersion(list, tt.spec, channelUnstable)
+		if !ok || got != tt.want {
+			t.Errorf("selectVersion(%q) = %q, %v; want %q", tt.spec, got, ok, tt.want)
+		}
+	}
+
+	if got, ok := selectVersion(list, "1.99", channelUnstable); ok {
+		t.Errorf("selectVersion(1.99) = %q, want no match", got)
+	}
+	if _, ok := selectVersion(nil, "latest", channelUnstable); ok {
+		t.Error("selectVersion on empty list should not match")
+	}
+}
+
+func TestSelectVersion_Prereleases(t *testing.T) {
+	list := []downloader.VersionInfo{
+		{Version: "go1.25.1", Stable: true},
+		{Version: "go1.26beta1", Stable: false},
+		{Version: "go1.26beta2", Stable: false},
+		{Version: "go1.26rc2", Stable: false},
+		{Version: "go1.26rc1", Stable: false},
+		{Version: "go1.25rc3", Stable: false},
+	}
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"latest-rc", "go1.26rc2"},
+		{"latest-beta", "go1.26beta2"},
+		{"latest", "go1.26rc2"},
+	}
+	for _, tt := range tests {
+		got, ok := selectVersion(list, tt.spec, channelUnstable)
+		if !ok || got != tt.want {
+			t.Errorf("selectVersion(%q) = %q, %v; want %q", tt.spec, got, ok, tt.want)
+		}
+	}
+
+	// On the stable channel "latest" skips prereleases, but the explicit
+	// prerelease keywords still select them
+	if got, ok := selectVersion(list, "latest", channelStable); !ok || got != "go1.25.1" {
+		t.Errorf("selectVersion(latest, stable) = %q, %v; want go1.25.1", got, ok)
+	}
+	if got, ok := selectVersion(list, "latest-rc", channelStable); !ok || got != "go1.26rc2" {
+		t.Errorf("selectVersion(latest-rc, stable) = %q, %v; want go1.26rc2", got, ok)
+	}
+
+	stableOnly := []downloader.VersionInfo{{Version: "go1.25.1", Stable: true}}
+	if got, ok := selectVersion(stableOnly, "latest-rc", channelUnstable); ok {
+		t.Errorf("selectVersion(latest-rc) = %q, want no match", got)
+	}
+}
+
+func TestFirstReleaseVersion(t *testing.T) {
+	tests := map[string]string{
+		"1.20.0":   "go1.20",
+		"go1.19.0": "go1.19",
+		"1.1.0":    "go1.1",
+		"1.21.0":   "1.21.0", // go1.21.0 exists
+		"go1.22.0": "go1.22.0",
+		"1.0.0":    "1.0.0",
+		"1.20.1":   "1.20.1",
+		"1.20":     "1.20",
+		"stable":   "stable",
+	}
+	for spec, want := range tests {
+		if got := firstReleaseVersion(spec); got != want {
+			t.Errorf("firstReleaseVersion(%q) = %q, want %q", spec, got, want)
+		}
+	}
+}
+
+func TestSelectVersion_TwoComponentRelease(t *testing.T) {
+	list := []downloader.VersionInfo{
+		{Version: "go1.20rc3", Stable: false},
+		{Version: "go1.20", Stable: true},
+		{Version: "go1.19.13", Stable: true},
+	}
+	if got, ok := selectVersion(list, "1.20", channelUnstable); !ok || got != "go1.20" {
+		t.Errorf("selectVersion(1.20) = %q, %v; want go1.20", got, ok)
+	}
+
+	list = append(list, downloader.VersionInfo{Version: "go1.20.3", Stable: true})
+	if got, ok := selectVersion(list, "1.20", channelUnstable); !ok || got != "go1.20.3" {
+		t.Errorf("selectVersion(1.20) = %q, %v; want go1.20.3", got, ok)
+	}
+	if got, ok := selectVersion(list, "stable", channelUnstable); !ok || got != "go1.20.3" {
+		t.Errorf("selectVersion(stable) = %q, %v; want go1.20.3", got, ok)
+	}
+}
+
+func TestActiveChannel(t *testing.T) {
+	savedStable, savedChannel := *stable, *channelFlag
+	defer func() { *stable, *channelFlag = savedStable, savedChannel }()
+
+	tests := []struct {
+		stable  bool
+		flag    string
+		config  string
+		want    string
+		wantErr bool
+	}{
+		{false, "", "", channelStable, false},
+		{false, "", "unstable", channelUnstable, false},
+		{false, "unstable", "stable", channelUnstable, false},
+		{false, "stable", "unstable", channelStable, false},
+		{true, "unstable", "unstable", channelStable, false},
+		{false, "nightly", "stable", "", true},
+	}
+	for _, tt := range tests {
+		*stable, *channelFlag = tt.stable, tt.flag
+		got, err := activeChannel(&config.Config{DefaultChannel: tt.config})
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("activeChannel(stable=%v, channel=%q, config=%q) = %q, %v; want %q",
+				tt.stable, tt.flag, tt.config, got, err, tt.want)
+		}
+	}
+
+	list := []downloader.VersionInfo{{Version: "go1.25.1"}, {Version: "go1.26rc1"}}
+	if got := filterChannel(list, channelStable); len(got) != 1 || got[0].Version != "go1.25.1" {
+		t.Errorf("filterChannel(stable) = %v, want only go1.25.1", got)
+	}
+	if got := filterChannel(list, channelUnstable); len(got) != 2 {
+		t.Errorf("filterChannel(unstable) = %v, want both versions", got)
+	}
+}
+
+func TestIsPartialVersion(t *testing.T) {
+	for _, spec := range []string{"1.21", "go1.21"} {
+		if !isPartialVersion(spec) {
+			t.Errorf("isPartialVersion(%q) = false, want true", spec)
+		}
+	}
+	for _, spec := range []string{"1.21.0", "go1.21.0", "latest", "1", "1.21rc1"} {
+		if isPartialVersion(spec) {
+			t.Errorf("isPartialVersion(%q) = true, want false", spec)
+		}
+	}
+}
+
+func TestClosestVersion(t *testing.T) {
+	list := []downloader.VersionInfo{
+		{Version: "go1.20.7", Stable: true},
+		{Version: "go1.22.1", Stable: true},
+		{Version: "go1.22.3", Stable: true},
+	}
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"1.21", "go1.22.3"},
+		{"1.19", "go1.20.7"},
+		{"1.23", "go1.22.3"},
+		{"latest", "go1.22.3"},
+	}
+	for _, tt := range tests {
+		got, ok := closestVersion(list, tt.spec)
+		if !ok || got != tt.want {
+			t.Errorf("closestVersion(%q) = %q, %v; want %q", tt.spec, got, ok, tt.want)
+		}
+	}
+	if _, ok := closestVersion(nil, "1.21"); ok {
+		t.Error("closestVersion on empty list should not match")
+	}
+}
+
+func TestApplyDirOverrides(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := config.DefaultConfig()
+	originalDownloadDir := cfg.DownloadDir
+
+	installDir := filepath.Join(tmp, "versions")
+	if err := applyDirOverrides(cfg, installDir, "", ""); err != nil {
+		t.Fatalf("applyDirOverrides failed: %v", err)
+	}
+	if cfg.InstallDir != installDir {
+		t.Errorf("InstallDir = %q, want %q", cfg.InstallDir, installDir)
+	}
+	if cfg.DownloadDir != originalDownloadDir {
+		t.Errorf("DownloadDir changed to %q without an override", cfg.DownloadDir)
+	}
+	if _, err := os.Stat(installDir); err != nil {
+		t.Errorf("install dir was not created: %v", err)
+	}
+
+	downloadDir := filepath.Join(tmp, "downloads")
+	if err := applyDirOverrides(cfg, "", downloadDir, ""); err != nil {
+		t.Fatalf("applyDirOverrides failed: %v", err)
+	}
+	if cfg.DownloadDir != downloadDir {
+		t.Errorf("DownloadDir = %q, want %q", cfg.DownloadDir, downloadDir)
+	}
+
+	symlinkDir := filepath.Join(tmp, "bin")
+	if err := applyDirOverrides(cfg, "", "", symlinkDir); err != nil {
+		t.Fatalf("applyDirOverrides failed: %v", err)
+	}
+	if cfg.SymlinkDir != symlinkDir {
+		t.Errorf("SymlinkDir = %q, want %q", cfg.SymlinkDir, symlinkDir)
+	}
+}
+
+func TestApplyDirOverrides_NotCreatable(t *testing.T) {
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "file")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = filepath.Join(tmp, "downloads")
+	if err := applyDirOverrides(cfg, filepath.Join(file, "versions"), "", ""); err == nil {
+		t.Error("expected error when the install directory cannot be created")
+	}
+}
+
+func TestCurrentAliasNames(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.InstallDir = filepath.Join(root, "versions")
+	cfg.DownloadDir = filepath.Join(root, "downloads")
+	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
+
+	versionDir := filepath.Join(cfg.InstallDir, "go1.21.0")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	metadata := "version=go1.21.0\nos=linux\narch=amd64\ninstalled_at=2023-01-01T00:00:00Z\ninstall_dir=" + versionDir + "\n"
+	if err := os.WriteFile(filepath.Join(versionDir, ".gopher-metadata"), []byte(metadata), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"stable", "lts"} {
+		if err := manager.AliasManager().CreateAlias(name, "go1.21.0"); err != nil {
+			t.Fatalf("CreateAlias(%s) error: %v", name, err)
+		}
+	}
+
+	got := currentAliasNames(manager, &inruntime.Version{Version: "go1.21.0"})
+	if strings.Join(got, ",") != "lts,stable" {
+		t.Errorf("currentAliasNames() = %v, want [lts stable]", got)
+	}
+
+	got = currentAliasNames(manager, &inruntime.Version{Version: "go1.21.0", IsSystem: true})
+	if got == nil || len(got) != 0 {
+		t.Errorf("expected an empty, non-nil list for the system version, got %#v", got)
+	}
+}
+
+func TestFilterVersionRange(t *testing.T) {
+	list := []downloader.VersionInfo{
+		{Version: "go1.23.0", Stable: true},
+		{Version: "go1.23rc1", Stable: false},
+		{Version: "go1.22.5", Stable: true},
+		{Version: "go1.22.0", Stable: true},
+		{Version: "go1.21.3", Stable: true},
+		{Version: "go1.20.1", Stable: true},
+		{Version: "go1.20rc1", Stable: false},
+		{Version: "go1.19.13", Stable: true},
+	}
+	versions := func(list []downloader.VersionInfo) string {
+		var names []string
+		for _, v := range list {
+			names = append(names, v.Version)
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		name     string
+		min, max string
+		want     string
+	}{
+		{"partial bounds cover release lines", "1.20", "1.22", "go1.22.5,go1.22.0,go1.21.3,go1.20.1,go1.20rc1"},
+		{"exact bounds", "1.20.1", "1.22.0", "go1.22.0,go1.21.3,go1.20.1"},
+		{"min only", "1.22", "", "go1.23.0,go1.23rc1,go1.22.5,go1.22.0"},
+		{"max only", "", "1.19", "go1.19.13"},
+		{"same release line", "1.22", "1.22", "go1.22.5,go1.22.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterVersionRange(list, tt.min, tt.max)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if versions(got) != tt.want {
+				t.Errorf("got %s, want %s", versions(got), tt.want)
+			}
+		})
+	}
+
+	// Combined with --stable, prereleases in the range are dropped
+	got, err := filterVersionRange(filterStableVersions(list), "1.20", "1.22")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if versions(got) != "go1.22.5,go1.22.0,go1.21.3,go1.20.1" {
+		t.Errorf("stable range got %s", versions(got))
+	}
+
+	if _, err := filterVersionRange(list, "1.22", "1.20"); err == nil {
+		t.Error("expected error when --min is greater than --max")
+	}
+	if _, err := filterVersionRange(list, "banana", ""); err == nil {
+		t.Error("expected error for an invalid bound")
+	}
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	var buf strings.Builder
+	if err := writeJSONLines(&buf, sampleList()); err != nil {
+		t.Fatalf("writeJSONLines error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(sampleList()) {
+		t.Fatalf("want %d lines, got %d: %q", len(sampleList()), len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var v downloader.VersionInfo
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Fatalf("line %d is not a JSON object: %v", i, err)
+		}
+		if v.Version != sampleList()[i].Version {
+			t.Errorf("line %d version = %s, want %s", i, v.Version, sampleList()[i].Version)
+		}
+	}
+}
+
+func TestApplyConfigOption(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := config.DefaultConfig()
+
+	for key, value := range map[string]string{
+		"install_dir":  filepath.Join(tmp, "versions"),
+		"download_dir": filepath.Join(tmp, "downloads"),
+		"mirror_url":   "https://mirror.example.com/go/",
+		"auto_cleanup": "false",
+		"max_versions": "10",
+		"symlink_dir":  filepath.Join(tmp, "bin"),
+	} {
+		if err := applyConfigOption(cfg, key, value); err != nil {
+			t.Errorf("applyConfigOption(%s=%s) failed: %v", key, value, err)
+		}
+	}
+	if cfg.InstallDir != filepath.Join(tmp, "versions") || cfg.DownloadDir != filepath.Join(tmp, "downloads") {
+		t.Errorf("directories not applied: %q, %q", cfg.InstallDir, cfg.DownloadDir)
+	}
+	if cfg.SymlinkDir != filepath.Join(tmp, "bin") {
+		t.Errorf("SymlinkDir = %q, want %q", cfg.SymlinkDir, filepath.Join(tmp, "bin"))
+	}
+	if cfg.MirrorURL != "https://mirror.example.com/go/" || cfg.AutoCleanup || cfg.MaxVersions != 10 {
+		t.Errorf("options not applied: %+v", cfg)
+	}
+
+	for key, value := range map[string]string{
+		"max_versions":    "0",
+		"mirror_url":      "ftp://example.com",
+		"auto_cleanup":    "yes",
+		"install_dir":     "",
+		"goproxy":         "htps://proxy.golang.org",
+		"gosumdb":         "sum.golang.og",
+		"linked_binaries": "go,../gofmt",
+		"strict_hooks":    "yes",
+	} {
+		before := *cfg
+		if err := applyConfigOption(cfg, key, value); err == nil {
+			t.Errorf("applyConfigOption(%s=%s) should fail", key, value)
+		}
+		if !reflect.DeepEqual(*cfg, before) {
+			t.Errorf("applyConfigOption(%s=%s) changed the config on error", key, value)
+		}
+	}
+
+	err := applyConfigOption(cfg, "no_such_key", "x")
+	if ge, ok := err.(*errors.GopherError); !ok || ge.Code != errors.ErrCodeUnknownConfigOption {
+		t.Errorf("unknown key error = %v, want UNKNOWN_CONFIG_OPTION", err)
+	}
+}
+
+func TestMarkInstalled(t *testing.T) {
+	installed := []inruntime.Version{{Version: "go1.24.7"}, {Version: "1.25.1", IsSystem: true}}
+	got := markInstalled(sampleList(), installed)
+
+	want := map[string]bool{"go1.25.1": true, "go1.25rc1": false, "go1.24.7": true}
+	if len(got) != len(want) {
+		t.Fatalf("want %d versions, got %d", len(want), len(got))
+	}
+	for _, v := range got {
+		if v.Installed != want[v.Version] {
+			t.Errorf("%s installed = %v, want %v", v.Version, v.Installed, want[v.Version])
+		}
+	}
+
+	encoded, err := json.Marshal(got[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(encoded), `"version":"go1.25.1"`) || !strings.Contains(string(encoded), `"installed":true`) {
+		t.Errorf("unexpected JSON %s", encoded)
+	}
+}
+
+func TestGetEnvValue(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.InstallDir = filepath.Join(root, "versions")
+	cfg.GOFLAGS = ""
+	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
+
+	out := captureStdout(t, func() {
+		if err := getEnvValue([]string{"GOROOT", "1.21.0"}, manager); err != nil {
+			t.Errorf("getEnvValue failed: %v", err)
+		}
+	})
+	if want := filepath.Join(cfg.InstallDir, "go1.21.0") + "\n"; string(out) != want {
+		t.Errorf("GOROOT = %q, want %q", out, want)
+	}
+
+	// Unset and unknown variables print nothing and exit 1
+	for _, key := range []string{"GOFLAGS", "NOT_A_GO_VAR"} {
+		var err error
+		out := captureStdout(t, func() { err = getEnvValue([]string{key, "go1.21.0"}, manager) })
+		if exitErr, ok := err.(*exitCodeError); !ok || exitErr.code != 1 || len(out) != 0 {
+			t.Errorf("getEnvValue(%s) = %v with output %q, want exit code 1 and no output", key, err, out)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+// defaultHistoryLimit is how many switches 'gopher history' shows without a
+// count
+const defaultHistoryLimit = 10
+
+// showHistory shows the most recent version switches, or clears the history
+// with --clear
+func showHistory(manager *inruntime.Manager, args []string) error {
+	if *clearHistory {
+		if err := manager.ClearHistory(); err != nil {
+			return err
+		}
+		if *jsonOutput || *format == "yaml" {
+			return outputStructured(map[string]bool{"cleared": true})
+		}
+		fmt.Println("✓ Cleared version switch history")
+		return nil
+	}
+
+	if len(args) > 1 {
+		return errors.Newf(errors.ErrCodeInvalidArgument, "history takes at most one count")
+	}
+	limit := defaultHistoryLimit
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return errors.Newf(errors.ErrCodeInvalidArgument, "invalid history count %q (use a positive number)", args[0])
+		}
+		limit = n
+	}
+
+	records, err := manager.History(limit)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput || *format == "yaml" {
+		return outputStructured(records)
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No version switches recorded yet")
+		return nil
+	}
+
+	fmt.Println("Recent version switches (newest first):")
+	fmt.Println()
+	for _, r := range records {
+		from := r.From
+		if from == "" {
+			from = "-"
+		}
+		fmt.Printf("  %s  %-12s → %s\n", r.Time.Local().Format("2006-01-02 15:04"), from, r.To)
+	}
+	return nil
+}
@@ -22,16 +22,27 @@
 //
 // Commands:
 //
-//	list                    List installed Go versions (including system)
+//	list                    List installed Go versions (including system; --size for disk usage)
 //	list-remote             List available Go versions (with pagination and filtering)
-//	install <version>       Install a Go version
-//	uninstall <version>     Uninstall a Go version
-//	use <version>           Switch to a Go version (use 'system' for system Go)
-//	current                 Show current Go version
-//	system                  Show system Go information
+//	install <version>...    Install Go versions (--concurrent to install in parallel)
+//	uninstall <version>     Uninstall a Go version (--unused for all unused versions)
+//	reinstall <version>     Reinstall a Go version in place (keeps its aliases)
+//	verify [version]        Check installed versions for corruption (--reinstall to repair, --archive <file> for an archive)
+//	cache <subcommand>      Inspect or clean the download cache (list, size, path, clean)
+//	use <version>           Switch to a Go version (use 'system' for system Go, '-' for the previous one, --dry-run to preview)
+//	exec <version> -- <cmd> Run a command with a Go version without switching to it
+//	run <version>           Start a subshell with a Go version activated
+//	current                 Show current Go version (--short for the bare version, --fast for prompts)
+//	prompt                  Print the active version for a shell prompt
+//	history [n]             Show the last n version switches (default 10; --clear to reset)
+//	system                  Show system Go information (--refresh to re-read PATH)
 //	alias                   Manage version aliases (create, list, remove, show)
+//	config edit             Edit the configuration file in $EDITOR (validated on save)
+//	config dump             Print the configuration in effect, with resolved paths and sources
+//	migrate --to <dir>      Move versions, downloads, aliases and state to a new directory (--dry-run to preview)
+//	repair                  Remove or repoint gopher symlinks to deleted versions (--dry-run to preview)
 //	init                    Interactive setup wizard for platform-specific configuration
-//	setup                   Set up shell integration for persistent Go version switching
+//	setup                   Set up shell integration for persistent Go version switching (--non-interactive, --json)
 //	status                  Show persistence status and shell integration info
 //	debug                   Show debug information for troubleshooting
 //	version                 Show gopher version
@@ -40,10 +51,17 @@
 // Options:
 //
 //	--json                  Output in JSON format
+//	--format <format>       Output format for list commands and current: table, plain, json or yaml
 //	--config <path>         Path to configuration file
+//	--install-dir <path>    Override the install directory for this run
+//	--download-dir <path>   Override the download directory for this run
+//	--symlink-dir <path>    Override the directory of the go symlink for this run
 //	--help                  Show this help message
 //	--verbose, -v           Show detailed output (DEBUG level)
 //	--quiet, -q             Only show errors (ERROR level)
+//	--no-color              Disable colored output (also set by NO_COLOR)
+//	--offline               Use only cached data, never the network (also set by GOPHER_OFFLINE=1)
+//	--no-hooks              Skip the pre_install, post_install and post_use hooks
 //
 // Examples:
 //
@@ -70,22 +88,29 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
-	"time"
 
+	"github.com/molmedoz/gopher/internal/color"
 	"github.com/molmedoz/gopher/internal/config"
 	"github.com/molmedoz/gopher/internal/downloader"
 	"github.com/molmedoz/gopher/internal/env"
 	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/log"
 	inprogress "github.com/molmedoz/gopher/internal/progress"
 	inruntime "github.com/molmedoz/gopher/internal/runtime"
 )
@@ -126,16 +151,27 @@ USAGE:
     gopher <command> [arguments]
 
 COMMANDS:
-    list                    List installed Go versions (including system)
+    list                    List installed Go versions (including system; --size for disk usage)
     list-remote             List available Go versions (with pagination and filtering)
-    install <version>       Install a Go version
-    uninstall <version>     Uninstall a Go version
-    use <version>           Switch to a Go version (use 'system' for system Go)
-    current                 Show current Go version
-    system                  Show system Go information
+    install <version>...    Install Go versions (also: latest, stable, latest-rc, 1.21; --concurrent)
+    uninstall <version>     Uninstall a Go version (--unused for all unused versions)
+    reinstall <version>     Reinstall a Go version in place (keeps its aliases)
+    verify [version]        Check installed versions for corruption (--reinstall to repair, --archive <file> for an archive)
+    cache <subcommand>      Inspect or clean the download cache (list, size, path, clean)
+    use <version>           Switch to a Go version ('system', 'homebrew', '1.21', '-' for the previous; --dry-run to preview)
+    exec <version> -- <cmd> Run a command with a Go version without switching to it
+    run <version>           Start a subshell with a Go version activated
+    current                 Show current Go version (--short for the bare version, --fast for prompts)
+    prompt                  Print the active version for a shell prompt
+    history [n]             Show the last n version switches (default 10; --clear to reset)
+    system                  Show system Go information (--refresh to re-read PATH)
     alias                   Manage version aliases (create, list, remove, show)
+    config edit             Edit the configuration file in $EDITOR (validated on save)
+    config dump             Print the configuration in effect, with resolved paths and sources
+    migrate --to <dir>      Move versions, downloads, aliases and state to a new directory (--dry-run to preview)
+    repair                  Remove or repoint gopher symlinks to deleted versions (--dry-run to preview)
     init                    Interactive setup wizard for platform-specific configuration
-    setup                   Set up shell integration for persistent Go version switching
+    setup                   Set up shell integration for persistent Go version switching (--non-interactive, --json)
     status                  Show persistence status and shell integration info
     debug                   Show debug information for troubleshooting
     version                 Show gopher version
@@ -144,22 +180,54 @@ COMMANDS:
 EXAMPLES:
     gopher list
     gopher install 1.21.0
+    gopher install latest-stable
+    gopher install latest-rc
+    gopher install 1.21
+    gopher install --concurrent 1.21.0 1.22.0 1.23.0
+    gopher install --keep-going 1.21.0 1.22.0 1.23.0
+    gopher install 1.21.0 --checksum <sha256>
+    gopher install --from-file go1.21.0.linux-amd64.tar.gz
     gopher use 1.21.0
     gopher use system
+    gopher use homebrew
+    gopher use 1.21.0 --dry-run
+    gopher use 1.23.0 --install
+    gopher use 1.21.0 --no-hooks
+    gopher install 1.23.0 --default
+    gopher use -
     gopher system
     gopher uninstall 1.20.7
+    gopher uninstall --unused
+    gopher run 1.21.0
+    gopher exec 1.21.0 -- go test ./...
+    gopher reinstall 1.21.0
+    gopher verify
+    gopher verify --reinstall
+    gopher verify --archive go1.21.0.linux-amd64.tar.gz
+    gopher cache list
+    gopher cache clean 1.21.0
+    gopher history
+    gopher history --clear
+    gopher migrate --to ~/.local/share/gopher --dry-run
+    gopher repair --dry-run
+    gopher setup --non-interactive --json
     gopher alias create stable 1.21.0
     gopher alias list
     gopher use stable
     
-    # Pagination and filtering (flags must come before command)
+    # Pagination and filtering (flags may come before or after the command)
     gopher --no-interactive list
+    gopher list --size
     gopher --page-size 5 list-remote
     gopher --page 2 --page-size 10 list-remote
+    gopher --page all list-remote
     gopher --filter "1.21" list-remote
-    gopher --stable list-remote
+    gopher --channel unstable list-remote
+    gopher --min 1.20 --max 1.22 list-remote
+    gopher --latest-per-minor list-remote
+    gopher --json-lines list-remote | jq -r .version
     gopher --no-interactive list-remote
-    gopher --filter "rc" list-remote
+    gopher --channel unstable --filter "rc" list-remote
     
     # Verbosity control
     gopher --verbose install 1.21.0
@@ -170,6 +238,7 @@ EXAMPLES:
     # JSON output for scripting
     gopher --json list
     gopher --json current
+    gopher current --short
 
 For more information, visit: https://github.com/molmedoz/gopher
 `
@@ -177,20 +246,81 @@ For more information, visit: https://github.com/molmedoz/gopher
 
 var (
 	jsonOutput = flag.Bool("json", false, "Output in JSON format")
+	jsonLines  = flag.Bool("json-lines", false, "Output one compact JSON object per line (list-remote)")
+	format     = flag.String("format", "table", "Output format for list, list-remote and current: table, plain, json or yaml")
+	noColor    = flag.Bool("no-color", false, "Disable colored output (also disabled by NO_COLOR or when not a terminal)")
 	configPath = flag.String("config", "", "Path to config file")
-	helpFlag   = flag.Bool("help", false, "Show help information")
+
+	// Directory override flags
+	installDirFlag  = flag.String("install-dir", "", "Override the install directory for this run")
+	downloadDirFlag = flag.String("download-dir", "", "Override the download directory for this run")
+	symlinkDirFlag  = flag.String("symlink-dir", "", "Override the directory of the go symlink for this run")
+	helpFlag        = flag.Bool("help", false, "Show help information")
+
+	// Network flags
+	offline = flag.Bool("offline", false, "Use only cached data and never access the network (also GOPHER_OFFLINE=1)")
+
+	// Hook flags
+	noHooks = flag.Bool("no-hooks", false, "Skip the pre_install, post_install and post_use hooks")
 
 	// Pagination flags
-	pageSize      = flag.Int("page-size", 10, "Number of versions to show per page")
-	page          = flag.Int("page", 1, "Page number to display")
+	pageSize      = flag.Int("page-size", 10, "Number of versions to show per page (0 for all)")
+	page          = pageFlagVar("page", 1, "Page number to display, or 'all' for every version at once")
 	filter        = flag.String("filter", "", "Filter versions by text (e.g., '1.21', 'stable', 'rc')")
-	stable        = flag.Bool("stable", false, "Show only stable versions")
-	noInteractive = flag.Bool("no-interactive", false, "Disable interactive pagination (default: interactive)")
+	stable        = flag.Bool("stable", false, "Show only stable versions (same as --channel stable)")
+	channelFlag   = flag.String("channel", "", "Release channel: 'stable' or 'unstable' to include prereleases (default from default_channel)")
+	minVersion    = flag.String("min", "", "Show only versions at or above this version (e.g., '1.20')")
+	maxVersion    = flag.String("max", "", "Show only versions at or below this version (e.g., '1.22')")
+	latestMinor   = flag.Bool("latest-per-minor", false, "Show only the newest version of each minor release (list-remote)")
+	noInteractive = flag.Bool("no-interactive", false, "Disable interactive pagination and setup prompts (default: interactive)")
+
+	// List flags
+	showSize = flag.Bool("size", false, "Show the disk usage of each installed version (list)")
 
 	// Alias flags
 	override   = flag.Bool("override", false, "Allow overriding existing aliases without confirmation")
 	noOverride = flag.Bool("no-override", false, "Exit with error if alias already exists (no override allowed)")
 	force      = flag.Bool("force", false, "Force operation without confirmation (overrides all other flags)")
+	preview    = flag.Bool("preview", false, "Show what 'alias import' would change without applying it")
+
+	// Install flags
+	concurrent   = flag.Bool("concurrent", false, "Install several versions at once")
+	keepGoing    = flag.Bool("keep-going", false, "Continue past failures and report them at the end (install, uninstall --unused, alias bulk create)")
+	checksum     = flag.String("checksum", "", "Expected SHA256 of the archive, instead of the published checksum")
+	fromFile     = flag.String("from-file", "", "Install from a local Go archive instead of downloading it")
+	skipChecksum = flag.Bool("skip-checksum", false, "Install an archive that has no checksum to verify against")
+	cleanupAfter = flag.Bool("cleanup-after", false, "Delete the downloaded archive after installing, even with keep_downloads")
+
+	// Use flags
+	dryRun       = flag.Bool("dry-run", false, "Show what 'use', 'migrate' or 'repair' would change without applying it")
+	installOnUse = flag.Bool("install", false, "Install the version first if it is not installed (use)")
+	setDefault   = flag.Bool("default", false, "Make the version the default for new shells without changing this one (install, use)")
+
+	// Current flags
+	short = flag.Bool("short", false, "Print only the active version (current)")
+	fast  = flag.Bool("fast", false, "Print the recorded active version without checking it, for prompts (current)")
+
+	// System flags
+	refresh = flag.Bool("refresh", false, "Re-read PATH from a login shell before detecting system Go")
+
+	// Setup flags
+	nonInteractive = flag.Bool("non-interactive", false, "Answer the setup and init prompts with their defaults instead of asking")
+
+	// Env flags
+	shellFlag = flag.String("shell", "", "Shell to write 'env activate' and 'env deactivate' commands for (default: detected)")
+
+	// Uninstall flags
+	unused = flag.Bool("unused", false, "Uninstall every version that is not active or referenced by an alias")
+
+	// History flags
+	clearHistory = flag.Bool("clear", false, "Clear the version switch history (history)")
+
+	// Migrate flags
+	migrateTo = flag.String("to", "", "New root directory for gopher's data (migrate)")
+
+	// Verify flags
+	reinstall = flag.Bool("reinstall", false, "Reinstall versions that fail 'gopher verify'")
+	archive   = flag.String("archive", "", "Check that a Go archive could be installed, without extracting it (verify)")
 
 	// Logging flags
 	quiet   = flag.Bool("quiet", false, "Only show errors (sets log level to ERROR)")
@@ -219,13 +349,30 @@ func main() {
 		fmt.Fprint(os.Stderr, usageString)
 	}
 
-	// Parse flags first
-	flag.Parse()
+	// Parse global flags wherever they appear (before or after the command)
+	args, err := parseArgs(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		os.Exit(2)
+	}
+
+	// Initialize the log level from the logging flags
+	switch {
+	case *quiet || *q:
+		log.SetLevel(log.LevelError)
+	case *verbose || *v:
+		log.SetLevel(log.LevelDebug)
+	}
+
+	setupLogOutput()
+
+	if *noColor {
+		color.SetEnabled(false)
+	}
 
-	// Mark unused flags as intentionally available for future use
-	_ = quiet
-	_ = q
-	_ = v
+	if err := resolveFormat(); err != nil {
+		reportError(err)
+		os.Exit(exitCodeFor(err))
+	}
 
 	// Check for help flag
 	if *helpFlag {
@@ -233,9 +380,6 @@ func main() {
 		return
 	}
 
-	// Get non-flag arguments
-	args := flag.Args()
-
 	if len(args) < 1 {
 		flag.Usage()
 		os.Exit(1)
@@ -243,51 +387,73 @@ func main() {
 
 	command := args[0]
 	commandArgs := args[1:]
-
-	// Check for global flags in command args and re-parse if needed
-	hasGlobalFlags := false
-	for _, arg := range commandArgs {
-		if strings.HasPrefix(arg, "-") {
-			hasGlobalFlags = true
-			break
-		}
-	}
-
-	if hasGlobalFlags {
-		// Re-parse with all arguments to catch global flags after command
-		os.Args = os.Args[1:] // Remove program name
-		flag.Parse()
-		args = flag.Args()
-		if len(args) > 0 {
-			command = args[0]
-			commandArgs = args[1:]
-		}
-	}
+	outputCommand = command
 
 	// Load configuration
 	cfg, err := loadConfig()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to load configuration: %v\n", err)
-		os.Exit(1)
+		err = errors.Wrapf(err, errors.ErrCodeConfigLoadFailed, "failed to load configuration")
+		reportError(err)
+		os.Exit(exitCodeFor(err))
 	}
 
 	// Create version manager with default environment provider
 	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
 
+	if offlineMode() {
+		manager.SetOffline(true)
+	}
+	manager.SetNoHooks(*noHooks)
+
+	// Keep progress bars out of JSON and quiet output
+	if *jsonOutput || *jsonLines || *quiet || *q {
+		manager.SetProgressSink(downloader.NoopProgressSink{})
+	}
+
 	// Execute command
 	if err := executeCommand(manager, command, commandArgs); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		// Commands run on the user's behalf report through their exit code
+		if exitErr, ok := err.(*exitCodeError); ok {
+			os.Exit(exitErr.code)
+		}
+		reportError(err)
+		os.Exit(exitCodeFor(err))
 	}
 }
 
+// offlineMode reports whether --offline or GOPHER_OFFLINE asks gopher to stay
+// off the network
+func offlineMode() bool {
+	if *offline {
+		return true
+	}
+	value, _ := strconv.ParseBool(os.Getenv("GOPHER_OFFLINE"))
+	return value
+}
+
+// interruptContext returns a context canceled by Ctrl-C, so a download stops
+// cleanly instead of the process being killed mid-write. Other commands keep
+// the default interrupt behavior.
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
 func loadConfig() (*config.Config, error) {
 	configPath := *configPath
 	if configPath == "" {
 		configPath = config.GetConfigPath()
 	}
 
-	return config.Load(configPath)
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyDirOverrides(cfg, *installDirFlag, *downloadDirFlag, *symlinkDirFlag); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
 }
 
 func executeCommand(manager *inruntime.Manager, command string, args []string) error {
@@ -295,13 +461,41 @@ func executeCommand(manager *inruntime.Manager, command string, args []string) e
 	case "list":
 		return listInstalled(manager)
 	case "list-remote":
-		return listRemote(manager)
+		ctx, stop := interruptContext()
+		defer stop()
+		return listRemote(ctx, manager)
 	case "install":
+		if *fromFile != "" {
+			if len(args) > 1 || *concurrent {
+				return errors.New(errors.ErrCodeInvalidArgument, "--from-file installs a single version (e.g., 'gopher install --from-file go1.21.0.linux-amd64.tar.gz')")
+			}
+			version := ""
+			if len(args) == 1 {
+				version = args[0]
+			}
+			ctx, stop := interruptContext()
+			defer stop()
+			return installFromFile(ctx, manager, *fromFile, version)
+		}
 		if len(args) < 1 {
 			return errors.NewMissingArgument("install (requires version)")
 		}
-		return installVersion(manager, args[0])
+		if err := applyInstallFlags(manager, len(args)); err != nil {
+			return err
+		}
+		ctx, stop := interruptContext()
+		defer stop()
+		if len(args) > 1 || *concurrent {
+			if *setDefault {
+				return errors.New(errors.ErrCodeInvalidArgument, "--default applies to a single version")
+			}
+			return installVersions(ctx, manager, args)
+		}
+		return installVersion(ctx, manager, args[0])
 	case "uninstall":
+		if *unused {
+			return uninstallUnused(manager)
+		}
 		if len(args) < 1 {
 			return errors.NewMissingArgument("uninstall (requires version)")
 		}
@@ -311,8 +505,45 @@ func executeCommand(manager *inruntime.Manager, command string, args []string) e
 			return errors.NewMissingArgument("use (requires version or alias)")
 		}
 		return useVersion(manager, args[0])
+	case "reinstall":
+		if len(args) < 1 {
+			return errors.NewMissingArgument("reinstall (requires version)")
+		}
+		if err := applyInstallFlags(manager, len(args)); err != nil {
+			return err
+		}
+		ctx, stop := interruptContext()
+		defer stop()
+		return reinstallVersion(ctx, manager, args[0])
+	case "verify":
+		if *archive != "" {
+			if len(args) > 0 {
+				return errors.New(errors.ErrCodeInvalidArgument, "verify --archive takes no version")
+			}
+			return verifyArchive(manager, *archive)
+		}
+		version := ""
+		if len(args) > 0 {
+			version = args[0]
+		}
+		ctx, stop := interruptContext()
+		defer stop()
+		return verifyVersions(ctx, manager, version)
+	case "exec":
+		return execWithVersion(manager, args)
+	case "run":
+		return runSubshell(manager, args)
 	case "current":
 		return showCurrent(manager)
+	case "prompt":
+		showPrompt(manager)
+		return nil
+	case "history":
+		return showHistory(manager, args)
+	case "migrate":
+		return migrateData(manager, args)
+	case "repair":
+		return repairSymlinks(manager, args)
 	case "system":
 		return showSystem(manager)
 	case "version":
@@ -322,6 +553,8 @@ func executeCommand(manager *inruntime.Manager, command string, args []string) e
 			return showEnvHelp()
 		}
 		return handleEnvCommand(args[0], args[1:], manager)
+	case "config":
+		return handleConfigCommand(args, manager)
 	case "init":
 		return runInteractiveSetup(manager)
 	case "setup":
@@ -334,6 +567,8 @@ func executeCommand(manager *inruntime.Manager, command string, args []string) e
 		return handleAliasCommand(args, manager)
 	case "clean":
 		return cleanDownloadCache(manager)
+	case "cache":
+		return handleCacheCommand(args, manager)
 	case "purge":
 		return purgeAllData(manager)
 	case "help":
@@ -350,33 +585,69 @@ func listInstalled(manager *inruntime.Manager) error {
 	}
 
 	if len(versions) == 0 {
-		if *jsonOutput {
-			fmt.Println("[]")
-		} else {
-			fmt.Println("No Go versions installed.")
+		switch *format {
+		case "json", "yaml":
+			return outputStructured(map[string]any{
+				"versions": []any{},
+				"pagination": map[string]any{
+					"current_page": page.number,
+					"total_pages":  0,
+					"page_size":    *pageSize,
+					"total_count":  0,
+				},
+			})
+		case "plain":
+		default:
+			log.Info("No Go versions installed.")
 		}
 		return nil
 	}
 
+	log.Debug("found %d installed versions in %s", len(versions), manager.GetInstallDir())
+
+	// One version per line, unpaginated, for xargs and friends
+	if *format == "plain" {
+		for _, v := range versions {
+			fmt.Println(v.Version)
+		}
+		return nil
+	}
+
+	// Walking every version directory is slow, so sizes are opt-in
+	var totalSize int64
+	if *showSize {
+		totalSize, err = manager.AddDiskUsage(versions)
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to measure installed versions")
+		}
+	}
+
 	// Calculate pagination
+	if err := applyPageAll(len(versions)); err != nil {
+		return err
+	}
 	totalVersions := len(versions)
 	totalPages := (totalVersions + *pageSize - 1) / *pageSize
 
 	// Validate page number
-	if *page < 1 {
-		*page = 1
+	if page.number < 1 {
+		page.number = 1
 	}
-	if *page > totalPages && totalPages > 0 {
-		*page = totalPages
+	if page.number > totalPages && totalPages > 0 {
+		page.number = totalPages
 	}
 
-	// If interactive mode is enabled and not JSON output, start interactive pagination
-	if !*noInteractive && !*jsonOutput {
-		return listInstalledInteractive(versions, totalPages)
+	// If interactive mode is enabled and output is a table, start interactive pagination
+	if !*noInteractive && *format == "table" {
+		title := "Installed Go versions"
+		if *showSize {
+			title += fmt.Sprintf(" using %s", formatBytes(totalSize))
+		}
+		return listInstalledInteractive(versions, title)
 	}
 
 	// Calculate start and end indices
-	startIndex := (*page - 1) * *pageSize
+	startIndex := (page.number - 1) * *pageSize
 	endIndex := startIndex + *pageSize
 	if endIndex > totalVersions {
 		endIndex = totalVersions
@@ -385,50 +656,57 @@ func listInstalled(manager *inruntime.Manager) error {
 	// Get the page of versions
 	pageVersions := versions[startIndex:endIndex]
 
-	if *jsonOutput {
-		// For JSON output, include pagination metadata
+	if *format == "json" || *format == "yaml" {
+		// For JSON and YAML output, include pagination metadata
 		result := map[string]any{
 			"versions": pageVersions,
 			"pagination": map[string]any{
-				"current_page": *page,
+				"current_page": page.number,
 				"total_pages":  totalPages,
 				"page_size":    *pageSize,
 				"total_count":  totalVersions,
 			},
 		}
-		return outputJSON(result)
+		if *showSize {
+			result["total_size"] = totalSize
+		}
+		return outputStructured(result)
 	}
 
 	// Display pagination info
-	fmt.Printf("Installed Go versions (page %d of %d, showing %d of %d total):\n",
-		*page, totalPages, len(pageVersions), totalVersions)
-	fmt.Println()
+	log.Info("Installed Go versions (page %d of %d, showing %d of %d total):",
+		page.number, totalPages, len(pageVersions), totalVersions)
+	log.Info("")
 
-	// Display versions
+	// Display versions; they are the result, so --quiet keeps them
 	for _, v := range pageVersions {
-		fmt.Printf("%s\n", v.ColoredDisplayString())
+		fmt.Println(installedVersionLine(v))
+	}
+	if *showSize {
+		fmt.Println()
+		fmt.Printf("Total size: %s\n", formatBytes(totalSize))
 	}
 
 	// Display pagination controls
 	if totalPages > 1 {
-		fmt.Println()
-		fmt.Printf("Page %d of %d", *page, totalPages)
-		if *page > 1 {
-			fmt.Printf(" | Use --page %d for previous page", *page-1)
+		log.Info("")
+		controls := fmt.Sprintf("Page %d of %d", page.number, totalPages)
+		if page.number > 1 {
+			controls += fmt.Sprintf(" | Use --page %d for previous page", page.number-1)
 		}
-		if *page < totalPages {
-			fmt.Printf(" | Use --page %d for next page", *page+1)
+		if page.number < totalPages {
+			controls += fmt.Sprintf(" | Use --page %d for next page", page.number+1)
 		}
-		fmt.Println()
-		fmt.Printf("Use 'gopher --page-size <number> list' to change page size (current: %d)\n", *pageSize)
-		fmt.Println("Use 'gopher --no-interactive list' to disable interactive pagination")
+		log.Info("%s", controls)
+		log.Info("Use 'gopher --page-size <number> list' to change page size (current: %d)", *pageSize)
+		log.Info("Use 'gopher --no-interactive list' to disable interactive pagination")
 	}
 
 	return nil
 }
 
-func listRemote(manager *inruntime.Manager) error {
-	versions, err := manager.ListAvailable()
+func listRemote(ctx context.Context, manager *inruntime.Manager) error {
+	versions, err := manager.ListAvailable(ctx)
 	if err != nil {
 		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to list available versions")
 	}
@@ -438,88 +716,130 @@ func listRemote(manager *inruntime.Manager) error {
 		versions = filterVersions(versions, *filter)
 	}
 
-	// Apply stable filter if specified
-	if *stable {
-		versions = filterStableVersions(versions)
+	// Keep only the versions in the release channel
+	channel, err := activeChannel(manager.GetConfig())
+	if err != nil {
+		return err
+	}
+	versions = filterChannel(versions, channel)
+
+	// Apply version range if specified
+	if *minVersion != "" || *maxVersion != "" {
+		versions, err = filterVersionRange(versions, *minVersion, *maxVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Keep the newest patch of each release line
+	if *latestMinor {
+		versions = downloader.LatestPerMinor(versions)
+	}
+
+	if *format == "plain" {
+		for _, v := range versions {
+			fmt.Println(v.Version)
+		}
+		return nil
+	}
+
+	// Mark the versions that are already installed
+	installed, err := manager.ListInstalled()
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to list installed versions")
+	}
+	remote := markInstalled(versions, installed)
+
+	// Stream every matching version for tools like jq, without pagination
+	if *jsonLines {
+		return writeJSONLines(os.Stdout, remote)
 	}
 
 	// Calculate pagination
+	if err := applyPageAll(len(versions)); err != nil {
+		return err
+	}
 	totalVersions := len(versions)
 	totalPages := (totalVersions + *pageSize - 1) / *pageSize
 
 	// Validate page number
-	if *page < 1 {
-		*page = 1
+	if page.number < 1 {
+		page.number = 1
 	}
-	if *page > totalPages && totalPages > 0 {
-		*page = totalPages
+	if page.number > totalPages && totalPages > 0 {
+		page.number = totalPages
 	}
 
-	// If interactive mode is enabled and not JSON output, start interactive pagination
-	if !*noInteractive && !*jsonOutput {
-		return listRemoteInteractive(versions, totalPages)
+	// If interactive mode is enabled and output is a table, start interactive pagination
+	if !*noInteractive && *format == "table" {
+		return listRemoteInteractive(remote)
 	}
 
 	// Calculate start and end indices
-	startIndex := (*page - 1) * *pageSize
+	startIndex := (page.number - 1) * *pageSize
 	endIndex := startIndex + *pageSize
 	if endIndex > totalVersions {
 		endIndex = totalVersions
 	}
 
 	// Get the page of versions
-	pageVersions := versions[startIndex:endIndex]
+	pageVersions := remote[startIndex:endIndex]
 
-	if *jsonOutput {
-		// For JSON output, include pagination metadata
+	if *format == "json" || *format == "yaml" {
+		// For JSON and YAML output, include pagination metadata
 		result := map[string]any{
 			"versions": pageVersions,
 			"pagination": map[string]any{
-				"current_page": *page,
-				"total_pages":  totalPages,
-				"page_size":    *pageSize,
-				"total_count":  totalVersions,
-				"filter":       *filter,
-				"stable_only":  *stable,
+				"current_page":     page.number,
+				"total_pages":      totalPages,
+				"page_size":        *pageSize,
+				"total_count":      totalVersions,
+				"filter":           *filter,
+				"channel":          channel,
+				"min":              *minVersion,
+				"max":              *maxVersion,
+				"latest_per_minor": *latestMinor,
 			},
 		}
-		return outputJSON(result)
+		return outputStructured(result)
 	}
 
 	// Display pagination info
 	fmt.Printf("Available Go versions (page %d of %d, showing %d of %d total):\n",
-		*page, totalPages, len(pageVersions), totalVersions)
+		page.number, totalPages, len(pageVersions), totalVersions)
 
 	if *filter != "" {
 		fmt.Printf("Filtered by: '%s'\n", *filter)
 	}
-	if *stable {
-		fmt.Printf("Showing only stable versions\n")
+	if channel == channelStable {
+		fmt.Printf("Showing only stable versions (use --channel unstable to include prereleases)\n")
+	}
+	if *minVersion != "" || *maxVersion != "" {
+		fmt.Printf("Version range: %s\n", formatVersionRange(*minVersion, *maxVersion))
+	}
+	if *latestMinor {
+		fmt.Printf("Showing only the newest version of each minor release\n")
 	}
 	fmt.Println()
 
 	// Display versions
 	for i, v := range pageVersions {
-		status := "stable"
-		// Check if version is stable (no beta, rc, devel, etc.)
-		if strings.Contains(strings.ToLower(v.Version), "beta") ||
-			strings.Contains(strings.ToLower(v.Version), "rc") ||
-			strings.Contains(strings.ToLower(v.Version), "devel") ||
-			strings.Contains(strings.ToLower(v.Version), "alpha") {
-			status = "unstable"
+		status := channelStable
+		if !downloader.IsStableVersion(v.Version) {
+			status = channelUnstable
 		}
-		fmt.Printf("  %d. %s (%s)\n", startIndex+i+1, v.Version, status)
+		fmt.Printf("  %d. %s (%s)%s\n", startIndex+i+1, v.Version, status, installedTag(v))
 	}
 
 	// Display pagination controls
 	if totalPages > 1 {
 		fmt.Println()
-		fmt.Printf("Page %d of %d", *page, totalPages)
-		if *page > 1 {
-			fmt.Printf(" | Use --page %d for previous page", *page-1)
+		fmt.Printf("Page %d of %d", page.number, totalPages)
+		if page.number > 1 {
+			fmt.Printf(" | Use --page %d for previous page", page.number-1)
 		}
-		if *page < totalPages {
-			fmt.Printf(" | Use --page %d for next page", *page+1)
+		if page.number < totalPages {
+			fmt.Printf(" | Use --page %d for next page", page.number+1)
 		}
 		fmt.Println()
 		fmt.Printf("Use --page-size <number> to change page size (current: %d)\n", *pageSize)
@@ -529,407 +849,743 @@ func listRemote(manager *inruntime.Manager) error {
 }
 
 // listRemoteInteractive provides interactive pagination for list-remote command
-// listRemoteInteractive provides interactive pagination for VersionInfo lists
-func listRemoteInteractive(versions []downloader.VersionInfo, totalPages int) error {
-	currentPage := *page
-	scanner := bufio.NewScanner(os.Stdin)
+func listRemoteInteractive(versions []remoteVersion) error {
+	return paginateTerminal(versions, *pageSize, page.number, "Available Go versions",
+		func(v remoteVersion, i int) string {
+			return fmt.Sprintf("%d. %s%s", i+1, v.Version, installedTag(v))
+		},
+		func(v remoteVersion, query string) bool {
+			return versionMatches(v.Version, query)
+		})
+}
+
+// listInstalledInteractive provides interactive pagination for list command
+func listInstalledInteractive(versions []inruntime.Version, title string) error {
+	return paginateTerminal(versions, *pageSize, page.number, title,
+		func(v inruntime.Version, _ int) string {
+			return installedVersionLine(v)
+		},
+		func(v inruntime.Version, query string) bool {
+			return versionMatches(v.Version, query)
+		})
+}
 
-	for {
-		// Calculate start and end indices for current page
-		startIndex := (currentPage - 1) * *pageSize
-		endIndex := startIndex + *pageSize
-		if endIndex > len(versions) {
-			endIndex = len(versions)
+// installedVersionLine formats an installed version for 'list' with its size
+func installedVersionLine(v inruntime.Version) string {
+	if v.Size > 0 {
+		return fmt.Sprintf("%s  %s", v.ColoredDisplayString(), formatBytes(v.Size))
+	}
+	return v.ColoredDisplayString()
+}
+
+// filterVersionsString filters a list of version strings based on a filter string
+// Currently unused but kept for potential future use
+func filterVersionsString(versions []string, filter string) []string { //nolint:unused
+	if filter == "" {
+		return versions
+	}
+
+	filter = strings.ToLower(filter)
+	var filtered []string
+
+	for _, v := range versions {
+		// Check if version number contains the filter
+		if strings.Contains(strings.ToLower(v), filter) {
+			filtered = append(filtered, v)
 		}
+	}
 
-		// Get the page of versions
-		pageVersions := versions[startIndex:endIndex]
+	return filtered
+}
 
-		// Clear screen (optional - makes it cleaner)
-		fmt.Print("\033[2J\033[H")
+// filterVersions filters a list of VersionInfo based on a filter string
+func filterVersions(versions []downloader.VersionInfo, filter string) []downloader.VersionInfo {
+	if filter == "" {
+		return versions
+	}
 
-		// Display header
-		fmt.Printf("Available Go versions (page %d of %d, showing %d of %d total):\n",
-			currentPage, totalPages, len(pageVersions), len(versions))
+	var filtered []downloader.VersionInfo
 
-		// Display versions
-		for i, v := range pageVersions {
-			fmt.Printf("%d. %s\n", startIndex+i+1, v.Version)
+	for _, v := range versions {
+		if versionMatches(v.Version, filter) {
+			filtered = append(filtered, v)
 		}
+	}
 
-		// Display navigation options
-		fmt.Printf("\nNavigation options:\n")
-		fmt.Printf("  n/next - Next page\n")
-		fmt.Printf("  p/prev - Previous page\n")
-		fmt.Printf("  g <num> - Go to page number\n")
-		fmt.Printf("  q/quit - Exit\n")
-		fmt.Printf("  <Enter> - Next page\n")
+	return filtered
+}
+
+// versionMatches reports whether a version number contains filter, ignoring case
+func versionMatches(version, filter string) bool {
+	return strings.Contains(strings.ToLower(version), strings.ToLower(filter))
+}
 
-		// Get user input
-		fmt.Printf("\nEnter command: ")
-		if !scanner.Scan() {
-			break
+// filterStableVersionsString filters out non-stable versions from a list of version strings
+// Currently unused but kept for potential future use
+func filterStableVersionsString(versions []string) []string { //nolint:unused
+	var stable []string
+
+	for _, v := range versions {
+		if downloader.IsStableVersion(v) {
+			stable = append(stable, v)
 		}
+	}
+
+	return stable
+}
 
-		input := strings.TrimSpace(scanner.Text())
-		if input == "" {
-			// Default to next page
-			input = "n"
+// filterStableVersions filters out non-stable versions from a list of VersionInfo
+func filterStableVersions(versions []downloader.VersionInfo) []downloader.VersionInfo {
+	var stable []downloader.VersionInfo
+
+	for _, v := range versions {
+		if downloader.IsStableVersion(v.Version) {
+			stable = append(stable, v)
 		}
+	}
 
-		// Parse command
-		parts := strings.Fields(input)
-		if len(parts) == 0 {
-			continue
+	return stable
+}
+
+// applyInstallFlags passes --checksum, --skip-checksum and --cleanup-after
+// to the manager. A checksum belongs to one archive, so it cannot be given
+// for several versions.
+func applyInstallFlags(manager *inruntime.Manager, versions int) error {
+	if *cleanupAfter {
+		manager.GetConfig().KeepDownloads = false
+	}
+
+	if *checksum != "" {
+		if versions > 1 {
+			return errors.New(errors.ErrCodeInvalidArgument, "--checksum applies to a single version")
 		}
+		if err := manager.SetChecksum(*checksum); err != nil {
+			return err
+		}
+	}
+	manager.SetSkipChecksum(*skipChecksum)
+	return nil
+}
 
-		command := strings.ToLower(parts[0])
-		switch command {
-		case "q", "quit", "exit":
-			return nil
-		case "n", "next":
-			if currentPage < totalPages {
-				currentPage++
-			}
-		case "p", "prev", "previous":
-			if currentPage > 1 {
-				currentPage--
-			}
-		case "g", "goto":
-			if len(parts) >= 2 {
-				if targetPage, err := strconv.Atoi(parts[1]); err == nil {
-					if targetPage >= 1 && targetPage <= totalPages {
-						currentPage = targetPage
-					}
-				}
-			}
-		default:
-			fmt.Printf("Unknown command: %s\n", command)
-			time.Sleep(1 * time.Second)
+func installVersion(ctx context.Context, manager *inruntime.Manager, version string) error {
+	resolved, err := resolveVersionSpec(ctx, manager, version)
+	if err != nil {
+		return err
+	}
+	if resolved != version {
+		log.Info("Resolved %s to %s", version, resolved)
+		version = resolved
+	}
+
+	result, err := manager.Install(ctx, version)
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeInstallationFailed, "failed to install version %s", version)
+	}
+
+	var defaulted *inruntime.UseResult
+	if *setDefault {
+		defaulted, err = manager.SetDefault(result.Version)
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to make %s the default version", result.Version)
 		}
 	}
 
+	if *jsonOutput {
+		return outputJSON(installOutput{InstallResult: result, Default: defaulted})
+	}
+	if result.FromCache {
+		log.Info("Used the cached archive for %s", result.Version)
+	} else {
+		log.Info("Downloaded %s for %s", formatBytes(result.BytesDownloaded), result.Version)
+	}
+	if defaulted != nil {
+		showDefaultNote(defaulted)
+	}
 	return nil
 }
 
-// listRemoteInteractiveString provides interactive pagination for string-based version lists
-// Currently unused but kept for potential future use
-func listRemoteInteractiveString(versions []string, totalPages int) error { //nolint:unused
-	currentPage := *page
-	scanner := bufio.NewScanner(os.Stdin)
+// installFromFile installs the archive given with --from-file, first checking
+// it against --checksum if given. The version defaults to the one in the
+// archive's file name.
+func installFromFile(ctx context.Context, manager *inruntime.Manager, path, version string) error {
+	if *checksum != "" {
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeFileNotFound, "failed to read archive %s", path)
+		}
+		if want := strings.ToLower(strings.TrimSpace(*checksum)); sum != want {
+			return errors.Newf(errors.ErrCodeInstallationFailed, "%s has SHA256 %s, not %s given with --checksum", path, sum, want)
+		}
+	}
 
-	for {
-		// Calculate start and end indices for current page
-		startIndex := (currentPage - 1) * *pageSize
-		endIndex := startIndex + *pageSize
-		if endIndex > len(versions) {
-			endIndex = len(versions)
+	result, err := manager.InstallFromFile(ctx, path, version)
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeInstallationFailed, "failed to install from %s", path)
+	}
+
+	var defaulted *inruntime.UseResult
+	if *setDefault {
+		defaulted, err = manager.SetDefault(result.Version)
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to make %s the default version", result.Version)
 		}
+	}
 
-		// Get the page of versions
-		pageVersions := versions[startIndex:endIndex]
+	if *jsonOutput {
+		return outputJSON(installOutput{InstallResult: result, Default: defaulted})
+	}
+	log.Info("Installed %s from %s", result.Version, path)
+	if defaulted != nil {
+		showDefaultNote(defaulted)
+	}
+	return nil
+}
+
+// fileSHA256 returns the hex-encoded SHA256 of the file at path
+func fileSHA256(path string) (string, error) {
+	// #nosec G304 -- the archive is chosen by the user
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
 
-		// Clear screen (optional - makes it cleaner)
-		fmt.Print("\033[2J\033[H")
+// installOutput is the JSON output of 'install': the install result, plus
+// the new default version with --default
+type installOutput struct {
+	*inruntime.InstallResult
+	Default *inruntime.UseResult `json:"default,omitempty"`
+}
 
-		// Display header
-		fmt.Printf("Available Go versions (page %d of %d, showing %d of %d total):\n",
-			currentPage, totalPages, len(pageVersions), len(versions))
+// showDefaultNote explains that --default only affects new shells
+func showDefaultNote(result *inruntime.UseResult) {
+	log.Info("Go %s is now the default for new shells", result.Version)
+	log.Info("This shell is unchanged until you re-source your profile or run: eval \"$(gopher env activate %s)\"", result.Version)
+}
 
-		// Display versions
-		for i, version := range pageVersions {
-			fmt.Printf("%d. %s\n", startIndex+i+1, version)
+// installVersions installs several versions, at once with --concurrent, and
+// reports a per-version summary. The first version that cannot be resolved or
+// installed stops the rest; with --keep-going, every version is attempted.
+func installVersions(ctx context.Context, manager *inruntime.Manager, specs []string) error {
+	versions := make([]string, 0, len(specs))
+	distinct := make(map[string]bool, len(specs))
+	var unresolved []unresolvedSpec
+	for _, spec := range specs {
+		resolved, err := resolveVersionSpec(ctx, manager, spec)
+		if err != nil {
+			if !*keepGoing {
+				return err
+			}
+			log.Error("Failed to resolve %s: %v", spec, err)
+			unresolved = append(unresolved, unresolvedSpec{
+				before: len(distinct),
+				result: inruntime.InstallResult{Version: spec, Error: err.Error(), Err: err},
+			})
+			continue
 		}
+		if resolved != spec {
+			log.Info("Resolved %s to %s", spec, resolved)
+		}
+		versions = append(versions, resolved)
+		distinct[inruntime.NormalizeVersion(resolved)] = true
+	}
 
-		// Display navigation options
-		fmt.Printf("\nNavigation options:\n")
-		fmt.Printf("  n/next - Next page\n")
-		fmt.Printf("  p/prev - Previous page\n")
-		fmt.Printf("  g <num> - Go to page number\n")
-		fmt.Printf("  q/quit - Exit\n")
-		fmt.Printf("  <Enter> - Next page\n")
+	workers := 1
+	if *concurrent {
+		workers = inruntime.DefaultInstallWorkers
+		// Progress bars of parallel downloads would overwrite each other
+		manager.SetProgressSink(downloader.NoopProgressSink{})
+		if !*jsonOutput {
+			log.Info("Installing %d version(s), up to %d at a time...", len(distinct), workers)
+		}
+	}
 
-		// Get user input
-		fmt.Printf("\nEnter command: ")
-		if !scanner.Scan() {
-			break
+	finished := 0
+	results := manager.InstallMany(ctx, versions, workers, *keepGoing, func(result inruntime.InstallResult) {
+		finished++
+		if *jsonOutput {
+			return
+		}
+		switch {
+		case result.AlreadyInstalled:
+			log.Info("[%d/%d] %s is already installed", finished, len(distinct), result.Version)
+		case result.OK:
+			log.Info("[%d/%d] ✓ Installed %s", finished, len(distinct), result.Version)
+		default:
+			log.Info("[%d/%d] ✗ Failed to install %s", finished, len(distinct), result.Version)
 		}
+	})
+	results = mergeUnresolved(results, unresolved)
+
+	var failed, skipped []string
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			skipped = append(skipped, result.Version)
+		case !result.OK:
+			failed = append(failed, result.Version)
+		}
+	}
 
-		input := strings.TrimSpace(scanner.Text())
-		if input == "" {
-			// Default to next page
-			input = "n"
+	var err error
+	if len(failed) > 0 {
+		message := fmt.Sprintf("%d of %d versions failed to install: %s", len(failed), len(results), strings.Join(failed, ", "))
+		if len(skipped) > 0 {
+			message += fmt.Sprintf(" (skipped %s; use --keep-going to continue past failures)", strings.Join(skipped, ", "))
 		}
+		err = errors.New(errors.ErrCodeInstallationFailed, message)
+	}
 
-		// Parse command
-		parts := strings.Fields(input)
-		command := strings.ToLower(parts[0])
+	if *jsonOutput {
+		if jsonErr := outputJSON(results); jsonErr != nil {
+			return jsonErr
+		}
+		// The results already describe the failures
+		if err != nil {
+			return &exitCodeError{code: exitCodeFor(err)}
+		}
+		return nil
+	}
 
-		switch command {
-		case "n", "next":
-			if currentPage < totalPages {
-				currentPage++
-			} else {
-				fmt.Println("Already on the last page.")
-			}
-		case "p", "prev":
-			if currentPage > 1 {
-				currentPage--
-			} else {
-				fmt.Println("Already on the first page.")
-			}
-		case "g":
-			if len(parts) > 1 {
-				if pageNum, err := strconv.Atoi(parts[1]); err == nil {
-					if pageNum >= 1 && pageNum <= totalPages {
-						currentPage = pageNum
-					} else {
-						fmt.Printf("Page number must be between 1 and %d.\n", totalPages)
-					}
-				} else {
-					fmt.Println("Invalid page number.")
-				}
-			} else {
-				fmt.Println("Please specify a page number.")
-			}
-		case "q", "quit":
-			return nil
+	log.Info("")
+	log.Info("Summary:")
+	for _, result := range results {
+		switch {
+		case result.AlreadyInstalled:
+			log.Info("  - %s (already installed)", result.Version)
+		case result.OK:
+			log.Info("  ✓ %s", result.Version)
+		case result.Skipped:
+			log.Info("  - %s (skipped)", result.Version)
 		default:
-			fmt.Println("Invalid command. Use n/next, p/prev, g <num>, or q/quit.")
+			log.Info("  ✗ %s: %s", result.Version, errorMessage(result.Err))
 		}
 	}
-
-	return scanner.Err()
+	return err
 }
 
-// listInstalledInteractive provides interactive pagination for list command
-func listInstalledInteractive(versions []inruntime.Version, totalPages int) error {
-	currentPage := *page
-	scanner := bufio.NewScanner(os.Stdin)
+// unresolvedSpec is a version spec that failed to resolve with --keep-going,
+// kept with its place among the distinct versions given to InstallMany
+type unresolvedSpec struct {
+	before int // Index of the first InstallMany result that follows it
+	result inruntime.InstallResult
+}
 
-	for {
-		// Calculate start and end indices for current page
-		startIndex := (currentPage - 1) * *pageSize
-		endIndex := startIndex + *pageSize
-		if endIndex > len(versions) {
-			endIndex = len(versions)
+// mergeUnresolved puts the results of specs that failed to resolve back
+// among the InstallMany results, so they follow the order given on the
+// command line
+func mergeUnresolved(results []inruntime.InstallResult, unresolved []unresolvedSpec) []inruntime.InstallResult {
+	merged := make([]inruntime.InstallResult, 0, len(results)+len(unresolved))
+	for i, result := range results {
+		for len(unresolved) > 0 && unresolved[0].before <= i {
+			merged = append(merged, unresolved[0].result)
+			unresolved = unresolved[1:]
 		}
+		merged = append(merged, result)
+	}
+	for _, u := range unresolved {
+		merged = append(merged, u.result)
+	}
+	return merged
+}
+
+func uninstallVersion(manager *inruntime.Manager, version string) error {
+	spinner := inprogress.NewSpinner(fmt.Sprintf("Uninstalling Go %s", version))
+	spinner.Start()
 
-		// Get the page of versions
-		pageVersions := versions[startIndex:endIndex]
+	result, err := manager.Uninstall(version)
 
-		// Clear screen (optional - makes it cleaner)
-		fmt.Print("\033[2J\033[H")
+	spinner.Stop()
 
-		// Display header
-		fmt.Printf("Installed Go versions (page %d of %d, showing %d of %d total):\n",
-			currentPage, totalPages, len(pageVersions), len(versions))
-		fmt.Println()
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeUninstallationFailed, "failed to uninstall version %s", version)
+	}
 
-		// Display versions
-		for _, v := range pageVersions {
-			fmt.Printf("%s\n", v.ColoredDisplayString())
-		}
+	if *jsonOutput {
+		return outputJSON(result)
+	}
+	fmt.Printf("✓ Uninstalled %s, freed %s\n", result.Version, formatBytes(result.BytesFreed))
+	return nil
+}
 
-		// Display navigation options
-		fmt.Println()
-		fmt.Printf("Page %d of %d\n", currentPage, totalPages)
-		fmt.Println("Commands:")
-		fmt.Println("  n, next, →     - Next page")
-		fmt.Println("  p, prev, ←     - Previous page")
-		fmt.Println("  <number>       - Go to specific page")
-		fmt.Println("  q, quit, exit  - Quit")
-		fmt.Println("  h, help        - Show this help")
-		fmt.Print("\nEnter command: ")
-
-		// Read user input
-		if !scanner.Scan() {
-			break
-		}
-
-		input := strings.TrimSpace(strings.ToLower(scanner.Text()))
-
-		// Handle commands
-		switch input {
-		case "n", "next", "→", "":
-			if currentPage < totalPages {
-				currentPage++
-			} else {
-				fmt.Println("Already on the last page!")
-				fmt.Print("Press Enter to continue...")
-				scanner.Scan()
-			}
+// uninstallUnused removes every installed version that is not active and not
+// referenced by an alias, after confirmation unless --force is given. It stops
+// at the first version it fails to remove unless --keep-going is given.
+func uninstallUnused(manager *inruntime.Manager) error {
+	unusedVersions, err := manager.UnusedVersions()
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to find unused versions")
+	}
 
-		case "p", "prev", "←":
-			if currentPage > 1 {
-				currentPage--
-			} else {
-				fmt.Println("Already on the first page!")
-				fmt.Print("Press Enter to continue...")
-				scanner.Scan()
-			}
+	if len(unusedVersions) == 0 {
+		if *jsonOutput {
+			return outputJSON(map[string]any{"removed": []string{}, "bytes_freed": 0})
+		}
+		fmt.Println("No unused versions to remove.")
+		return nil
+	}
+
+	if !*jsonOutput {
+		fmt.Printf("Unused versions (not active and without aliases):\n")
+	}
+	for _, version := range unusedVersions {
+		size, err := manager.InstalledSize(version)
+		if err != nil {
+			log.Debug("failed to get size of %s: %v", version, err)
+		}
+		if !*jsonOutput {
+			fmt.Printf("  %s (%s)\n", version, formatBytes(size))
+		}
+	}
 
-		case "q", "quit", "exit":
-			fmt.Println("Goodbye!")
+	if !*force {
+		if *jsonOutput {
+			return errors.Newf(errors.ErrCodeInvalidArgument, "uninstall --unused requires --force with --json")
+		}
+		fmt.Println()
+		if !askForConfirmation(fmt.Sprintf("Uninstall %d version(s)?", len(unusedVersions))) {
+			fmt.Println("Uninstall cancelled.")
 			return nil
+		}
+	}
 
-		case "h", "help":
-			fmt.Println("\nNavigation Help:")
-			fmt.Println("  n, next, →     - Go to next page")
-			fmt.Println("  p, prev, ←     - Go to previous page")
-			fmt.Println("  <number>       - Jump to specific page (1-" + strconv.Itoa(totalPages) + ")")
-			fmt.Println("  q, quit, exit  - Exit the program")
-			fmt.Println("  h, help        - Show this help")
-			fmt.Print("\nPress Enter to continue...")
-			scanner.Scan()
+	var removed, failed []string
+	var bytesFreed int64
+	for _, version := range unusedVersions {
+		result, err := manager.Uninstall(version)
+		if err != nil {
+			log.Error("Failed to uninstall %s: %v", version, err)
+			failed = append(failed, version)
+			if !*keepGoing {
+				break
+			}
 			continue
+		}
+		removed = append(removed, version)
+		bytesFreed += result.BytesFreed
+	}
 
-		default:
-			// Try to parse as page number
-			if pageNum, err := strconv.Atoi(input); err == nil {
-				if pageNum >= 1 && pageNum <= totalPages {
-					currentPage = pageNum
-				} else {
-					fmt.Printf("Invalid page number! Please enter a number between 1 and %d.\n", totalPages)
-					fmt.Print("Press Enter to continue...")
-					scanner.Scan()
-				}
-			} else {
-				fmt.Println("Invalid command! Type 'h' or 'help' for available commands.")
-				fmt.Print("Press Enter to continue...")
-				scanner.Scan()
-			}
+	if *jsonOutput {
+		if err := outputJSON(map[string]any{"removed": removed, "bytes_freed": bytesFreed}); err != nil {
+			return err
 		}
+	} else {
+		fmt.Printf("✓ Uninstalled %d version(s), freed %s\n", len(removed), formatBytes(bytesFreed))
 	}
 
+	if len(failed) > 0 {
+		if !*keepGoing && len(removed)+len(failed) < len(unusedVersions) {
+			return errors.Newf(errors.ErrCodeUninstallationFailed, "failed to uninstall %s; stopped before the remaining %d version(s) (use --keep-going to continue past failures)",
+				failed[0], len(unusedVersions)-len(removed)-len(failed))
+		}
+		return errors.Newf(errors.ErrCodeUninstallationFailed, "failed to uninstall: %s", strings.Join(failed, ", "))
+	}
 	return nil
 }
 
-// filterVersions filters versions based on the provided filter text
-// filterVersionsString filters a list of version strings based on a filter string
-// Currently unused but kept for potential future use
-func filterVersionsString(versions []string, filter string) []string { //nolint:unused
-	if filter == "" {
-		return versions
+// verifyVersions checks the integrity of one installed version, or of all of
+// them when version is empty, and optionally reinstalls the corrupt ones
+func verifyVersions(ctx context.Context, manager *inruntime.Manager, version string) error {
+	var results []inruntime.VerifyResult
+	if version == "" {
+		all, err := manager.VerifyAll()
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to verify installed versions")
+		}
+		results = all
+	} else {
+		result, err := manager.Verify(version)
+		if err != nil {
+			return err
+		}
+		results = append(results, *result)
 	}
 
-	filter = strings.ToLower(filter)
-	var filtered []string
-
-	for _, v := range versions {
-		// Check if version number contains the filter
-		if strings.Contains(strings.ToLower(v), filter) {
-			filtered = append(filtered, v)
+	var corrupt []string
+	for _, result := range results {
+		if !result.OK {
+			corrupt = append(corrupt, result.Version)
 		}
 	}
 
-	return filtered
-}
-
-// filterVersions filters a list of VersionInfo based on a filter string
-func filterVersions(versions []downloader.VersionInfo, filter string) []downloader.VersionInfo {
-	if filter == "" {
-		return versions
+	if *jsonOutput {
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	} else {
+		if len(results) == 0 {
+			log.Info("No Go versions installed.")
+		}
+		for _, result := range results {
+			if result.OK {
+				fmt.Printf("%-12s OK\n", result.Version)
+				if result.SHA256 != "" {
+					log.Debug("    archive sha256: %s", result.SHA256)
+				}
+				continue
+			}
+			fmt.Printf("%-12s CORRUPT\n", result.Version)
+			for _, problem := range result.Problems {
+				fmt.Printf("    - %s\n", problem)
+			}
+		}
 	}
 
-	filter = strings.ToLower(filter)
-	var filtered []downloader.VersionInfo
+	if len(corrupt) == 0 {
+		return nil
+	}
 
-	for _, v := range versions {
-		// Check if version number contains the filter
-		if strings.Contains(strings.ToLower(v.Version), filter) {
-			filtered = append(filtered, v)
+	if !*reinstall {
+		if !*jsonOutput {
+			log.Info("")
+			log.Info("Run 'gopher verify --reinstall' to reinstall corrupt versions")
 		}
+		return errors.Newf(errors.ErrCodeInstallationFailed, "%d version(s) failed verification: %s", len(corrupt), strings.Join(corrupt, ", "))
 	}
 
-	return filtered
+	for _, v := range corrupt {
+		log.Info("Reinstalling Go %s...", v)
+		if _, err := manager.Reinstall(ctx, v); err != nil {
+			return errors.Wrapf(err, errors.ErrCodeInstallationFailed, "failed to reinstall version %s", v)
+		}
+	}
+	return nil
 }
 
-// filterStableVersionsString filters out non-stable versions from a list of version strings
-// Currently unused but kept for potential future use
-func filterStableVersionsString(versions []string) []string { //nolint:unused
-	var stable []string
+// verifyArchive checks that a downloaded or local archive could be installed,
+// without extracting it
+func verifyArchive(manager *inruntime.Manager, path string) error {
+	info, err := manager.ValidateArchive(path)
 
-	for _, v := range versions {
-		// Check if version is stable (no beta, rc, devel, etc.)
-		if !strings.Contains(strings.ToLower(v), "beta") &&
-			!strings.Contains(strings.ToLower(v), "rc") &&
-			!strings.Contains(strings.ToLower(v), "devel") &&
-			!strings.Contains(strings.ToLower(v), "alpha") {
-			stable = append(stable, v)
+	if *jsonOutput || *format == "yaml" {
+		if outErr := outputStructured(info); outErr != nil {
+			return outErr
 		}
+		return err
 	}
 
-	return stable
+	if info.Version != "" {
+		log.Info("Version: %s", info.Version)
+	}
+	log.Info("Entries: %d (%s extracted)", info.Entries, formatBytes(info.Size))
+	if err != nil {
+		log.Info("%s  INVALID", path)
+		return err
+	}
+	log.Info("%s  OK", path)
+	return nil
 }
 
-// filterStableVersions filters out non-stable versions from a list of VersionInfo
-func filterStableVersions(versions []downloader.VersionInfo) []downloader.VersionInfo {
-	var stable []downloader.VersionInfo
+// reinstallVersion installs a version again in one step, replacing any
+// existing installation while keeping aliases that point at it
+func reinstallVersion(ctx context.Context, manager *inruntime.Manager, version string) error {
+	reinstalled, err := manager.Reinstall(ctx, version)
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeInstallationFailed, "failed to reinstall version %s", version)
+	}
 
-	for _, v := range versions {
-		// Check if version is stable (no beta, rc, devel, etc.)
-		if !strings.Contains(strings.ToLower(v.Version), "beta") &&
-			!strings.Contains(strings.ToLower(v.Version), "rc") &&
-			!strings.Contains(strings.ToLower(v.Version), "devel") &&
-			!strings.Contains(strings.ToLower(v.Version), "alpha") {
-			stable = append(stable, v)
-		}
+	if *jsonOutput {
+		return outputJSON(map[string]string{"reinstalled": reinstalled})
 	}
 
-	return stable
+	log.Info("Successfully reinstalled Go %s", reinstalled)
+	return nil
 }
 
-func installVersion(manager *inruntime.Manager, version string) error {
-	if err := manager.Install(version); err != nil {
-		return errors.Wrapf(err, errors.ErrCodeInstallationFailed, "failed to install version %s", version)
+func useVersion(manager *inruntime.Manager, version string) error {
+	// "-" switches back to the previously active version, like 'cd -'
+	if version == "-" {
+		previous, err := manager.PreviousVersion()
+		if err != nil {
+			return err
+		}
+		version = previous
 	}
-	return nil
-}
 
-func uninstallVersion(manager *inruntime.Manager, version string) error {
-	spinner := inprogress.NewSpinner(fmt.Sprintf("Uninstalling Go %s", version))
-	spinner.Start()
+	// Aliases take precedence over version keywords (e.g. an alias named "stable")
+	target := version
+	if alias, isAlias := manager.AliasManager().GetAlias(version); isAlias {
+		target = alias.Version
+	} else {
+		resolved, err := resolveUseVersion(manager, version)
+		if err != nil {
+			return err
+		}
+		if resolved != version {
+			log.Debug("resolved %s to %s", version, resolved)
+		}
+		version, target = resolved, resolved
+	}
 
-	err := manager.Uninstall(version)
+	if err := ensureInstalledForUse(manager, target); err != nil {
+		return err
+	}
 
-	spinner.Stop()
+	if *dryRun {
+		return showUsePlan(manager, version)
+	}
+
+	if *setDefault {
+		result, err := manager.SetDefault(version)
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to make %s the default version", version)
+		}
+		if *jsonOutput {
+			return outputJSON(result)
+		}
+		showDefaultNote(result)
+		return nil
+	}
+
+	log.Info("Switching to Go %s...", version)
 
+	result, err := manager.Use(version)
 	if err != nil {
-		return errors.Wrapf(err, errors.ErrCodeUninstallationFailed, "failed to uninstall version %s", version)
+		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to switch to version %s", version)
 	}
 
+	if *jsonOutput {
+		return outputJSON(result)
+	}
+	log.Info("Successfully switched to Go %s", result.Version)
 	return nil
 }
 
-func useVersion(manager *inruntime.Manager, version string) error {
-	fmt.Printf("Switching to Go %s...\n", version)
+// showUsePlan prints what 'gopher use' would change for a version without
+// applying it
+func showUsePlan(manager *inruntime.Manager, version string) error {
+	plan, err := manager.PlanUse(version)
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to plan switch to version %s", version)
+	}
 
-	if err := manager.Use(version); err != nil {
-		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to switch to version %s", version)
+	if *jsonOutput {
+		return outputJSON(plan)
 	}
 
-	fmt.Printf("Successfully switched to Go %s\n", version)
+	fmt.Printf("Dry run for Go %s (nothing will be changed):\n", plan.Version)
+	if plan.Alias != "" {
+		fmt.Printf("  Resolve alias '%s' -> %s\n", plan.Alias, plan.Version)
+	}
+	if plan.Symlink == "" {
+		fmt.Printf("  Remove the gopher symlink so %s is found on PATH\n", plan.Target)
+	} else {
+		current := plan.CurrentTarget
+		if current == "" {
+			current = "(none)"
+		}
+		fmt.Printf("  Point %s at %s (currently %s)\n", plan.Symlink, plan.Target, current)
+		if !plan.SymlinkInPath {
+			fmt.Printf("  Warning: %s is not in your PATH\n", filepath.Dir(plan.Symlink))
+		}
+	}
+
+	if len(plan.Environment) == 0 {
+		fmt.Println("  Leave environment variables unchanged (set_environment is disabled)")
+		return nil
+	}
+	fmt.Println("  Set environment variables:")
+	keys := make([]string, 0, len(plan.Environment))
+	for key := range plan.Environment {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("    %s=%s\n", key, plan.Environment[key])
+	}
 	return nil
 }
 
 func showCurrent(manager *inruntime.Manager) error {
+	if *fast {
+		return showRecordedVersion(manager)
+	}
+
 	current, err := manager.GetCurrent()
 	if err != nil {
 		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to get current version")
 	}
 
-	if *jsonOutput {
-		return outputJSON(current)
+	// The short form is for prompts and Makefiles: the bare version, or
+	// nothing and exit code 1 when no Go is active
+	if *short || *format == "plain" {
+		if current.Version == "unknown" {
+			return &exitCodeError{code: 1}
+		}
+		fmt.Println(current.Version)
+		return nil
+	}
+
+	aliases := currentAliasNames(manager, current)
+
+	if *jsonOutput || *format == "yaml" {
+		return outputStructured(currentVersionOutput{Version: current, Aliases: aliases})
 	}
 
 	fmt.Printf("Current Go version: %s\n", current.String())
+	if len(aliases) > 0 {
+		fmt.Printf("Aliases: %s\n", strings.Join(aliases, ", "))
+	}
+	return nil
+}
+
+// showRecordedVersion prints the version recorded by 'gopher use', read from
+// the state file alone, like 'current --short' but without running go or
+// probing PATH. Nothing is printed and the exit code is 1 when no version
+// has been selected.
+func showRecordedVersion(manager *inruntime.Manager) error {
+	version, err := manager.GetActiveVersion()
+	if err != nil {
+		return err
+	}
+	if version == "" {
+		return &exitCodeError{code: 1}
+	}
+
+	if *jsonOutput || *format == "yaml" {
+		return outputStructured(map[string]string{"version": version})
+	}
+	fmt.Println(version)
 	return nil
 }
 
+// showPrompt prints the active Gopher-managed version for a shell prompt, or
+// nothing on system Go. It runs on every prompt render, so it only reads the
+// state file; inside 'gopher run' the subshell's version wins.
+func showPrompt(manager *inruntime.Manager) {
+	version := os.Getenv("GOPHER_SUBSHELL")
+	if version == "" {
+		version = manager.PromptVersion()
+	}
+	if version != "" {
+		fmt.Println(version)
+	}
+}
+
+// currentVersionOutput is the JSON shape of 'gopher current': the active
+// version plus the names of the aliases that resolve to it
+type currentVersionOutput struct {
+	*inruntime.Version
+	Aliases []string `json:"aliases"`
+}
+
 func showSystem(manager *inruntime.Manager) error {
-	systemInfo, err := manager.GetSystemInfo()
+	getSystemInfo := manager.GetSystemInfo
+	if *refresh {
+		getSystemInfo = manager.RefreshSystemInfo
+	}
+	systemInfo, err := getSystemInfo()
 	if err != nil {
+		if errors.IsErrorCode(err, errors.ErrCodeTimeoutExceeded) {
+			return err
+		}
 		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to get system information")
 	}
 
@@ -942,7 +1598,15 @@ func showSystem(manager *inruntime.Manager) error {
 	fmt.Printf("  GOROOT: %s\n", systemInfo.GOROOT)
 	fmt.Printf("  GOPATH: %s\n", systemInfo.GOPATH)
 	fmt.Printf("  Executable: %s\n", systemInfo.Executable)
+	fmt.Printf("  Source: %s\n", systemInfo.Source)
 	fmt.Printf("  Valid: %t\n", systemInfo.IsValid)
+	if brew := systemInfo.Homebrew; brew != nil {
+		fmt.Println()
+		fmt.Println("Homebrew Go (use with 'gopher use homebrew'):")
+		fmt.Printf("  Version: %s\n", brew.Version)
+		fmt.Printf("  GOROOT: %s\n", brew.GOROOT)
+		fmt.Printf("  Executable: %s\n", brew.Executable)
+	}
 	return nil
 }
 
@@ -992,15 +1656,25 @@ func showHelp() error {
 			"description": "Go version manager",
 			"commands": map[string]string{
 				"init":        "Interactive setup wizard for platform-specific configuration",
-				"list":        "List installed Go versions (including system)",
+				"list":        "List installed Go versions (including system; --size for disk usage)",
 				"list-remote": "List available Go versions (with pagination and filtering)",
-				"install":     "Install a Go version",
-				"uninstall":   "Uninstall a Go version",
-				"use":         "Switch to a Go version (use 'system' for system Go)",
-				"current":     "Show current Go version",
-				"system":      "Show system Go information",
+				"install":     "Install Go versions (--concurrent to install in parallel)",
+				"uninstall":   "Uninstall a Go version (--unused for all unused versions)",
+				"reinstall":   "Reinstall a Go version in place (keeps its aliases)",
+				"verify":      "Check installed versions for corruption (--reinstall to repair, --archive <file> for an archive)",
+				"cache":       "Inspect or clean the download cache (list, size, path, clean)",
+				"use":         "Switch to a Go version (use 'system' for system Go, '-' for the previous one, --dry-run to preview)",
+				"exec":        "Run a command with a Go version without switching to it",
+				"run":         "Start a subshell with a Go version activated",
+				"current":     "Show current Go version (--short for the bare version, --fast for prompts)",
+				"prompt":      "Print the active version for a shell prompt",
+				"history":     "Show the last n version switches (default 10; --clear to reset)",
+				"system":      "Show system Go information (--refresh to re-read PATH)",
 				"alias":       "Manage version aliases (create, list, remove, show)",
-				"setup":       "Set up shell integration for persistent Go version switching",
+				"config":      "Edit the configuration file in $EDITOR (edit) or print the configuration in effect (dump)",
+				"migrate":     "Move versions, downloads, aliases and state to a new directory (--dry-run to preview)",
+				"repair":      "Remove or repoint gopher symlinks to deleted versions (--dry-run to preview)",
+				"setup":       "Set up shell integration for persistent Go version switching (--non-interactive, --json)",
 				"status":      "Show persistence status and shell integration info",
 				"debug":       "Show debug information for troubleshooting",
 				"clean":       "Remove download cache to free disk space",
@@ -1012,21 +1686,54 @@ func showHelp() error {
 			"examples": []string{
 				"gopher init",
 				"gopher list",
+				"gopher list --size",
 				"gopher install 1.21.0",
+				"gopher install latest-stable",
+				"gopher install latest-rc",
+				"gopher install 1.21",
+				"gopher install --concurrent 1.21.0 1.22.0 1.23.0",
+				"gopher install --keep-going 1.21.0 1.22.0 1.23.0",
+				"gopher install 1.21.0 --checksum <sha256>",
+				"gopher install --from-file go1.21.0.linux-amd64.tar.gz",
 				"gopher use 1.21.0",
 				"gopher use system",
+				"gopher use 1.21.0 --dry-run",
+				"gopher use 1.23.0 --install",
+				"gopher use 1.21.0 --no-hooks",
+				"gopher install 1.23.0 --default",
+				"gopher use -",
+				"gopher use homebrew",
 				"gopher system",
 				"gopher uninstall 1.20.7",
+				"gopher uninstall --unused",
+				"gopher run 1.21.0",
+				"gopher exec 1.21.0 -- go test ./...",
+				"gopher reinstall 1.21.0",
+				"gopher verify",
+				"gopher verify --reinstall",
+				"gopher verify --archive go1.21.0.linux-amd64.tar.gz",
+				"gopher cache list",
+				"gopher cache clean 1.21.0",
+				"gopher history",
+				"gopher history --clear",
+				"gopher migrate --to ~/.local/share/gopher --dry-run",
+				"gopher repair --dry-run",
 				"gopher alias create stable 1.21.0",
 				"gopher alias list",
 				"gopher use stable",
 				"gopher setup",
+				"gopher setup --non-interactive --json",
 				"gopher status",
 				"gopher debug",
 				"gopher env list",
+				"gopher config edit",
+				"gopher config dump",
 				"gopher list-remote --page-size 5",
+				"gopher list-remote --page all",
 				"gopher list-remote --filter '1.21'",
 				"gopher list-remote --filter 'stable'",
+				"gopher list-remote --min 1.20 --max 1.22",
+				"gopher list-remote --latest-per-minor",
 			},
 			"documentation": "https://github.com/molmedoz/gopher",
 		}
@@ -1055,15 +1762,26 @@ func showHelp() error {
 	fmt.Println()
 	fmt.Println("COMMANDS:")
 	fmt.Println("  init                    Interactive setup wizard for platform-specific configuration")
-	fmt.Println("  list                    List installed Go versions (including system)")
+	fmt.Println("  list                    List installed Go versions (including system; --size for disk usage)")
 	fmt.Println("  list-remote             List available Go versions (with pagination and filtering)")
-	fmt.Println("  install <version>       Install a Go version")
-	fmt.Println("  uninstall <version>     Uninstall a Go version")
-	fmt.Println("  use <version>           Switch to a Go version (use 'system' for system Go)")
-	fmt.Println("  current                 Show current Go version")
-	fmt.Println("  system                  Show system Go information")
+	fmt.Println("  install <version>...    Install Go versions (also: latest, stable, latest-rc, 1.21; --concurrent)")
+	fmt.Println("  uninstall <version>     Uninstall a Go version (--unused for all unused versions)")
+	fmt.Println("  reinstall <version>     Reinstall a Go version in place (keeps its aliases)")
+	fmt.Println("  verify [version]        Check installed versions for corruption (--reinstall to repair, --archive <file> for an archive)")
+	fmt.Println("  cache <subcommand>      Inspect or clean the download cache (list, size, path, clean)")
+	fmt.Println("  use <version>           Switch to a Go version ('system', 'homebrew', '1.21', '-' for the previous; --dry-run to preview)")
+	fmt.Println("  exec <version> -- <cmd> Run a command with a Go version without switching to it")
+	fmt.Println("  run <version>           Start a subshell with a Go version activated")
+	fmt.Println("  current                 Show current Go version (--short for the bare version, --fast for prompts)")
+	fmt.Println("  prompt                  Print the active version for a shell prompt")
+	fmt.Println("  history [n]             Show the last n version switches (default 10; --clear to reset)")
+	fmt.Println("  system                  Show system Go information (--refresh to re-read PATH)")
 	fmt.Println("  alias                   Manage version aliases (create, list, remove, show)")
-	fmt.Println("  setup                   Set up shell integration for persistent Go version switching")
+	fmt.Println("  config edit             Edit the configuration file in $EDITOR (validated on save)")
+	fmt.Println("  config dump             Print the configuration in effect, with resolved paths and sources")
+	fmt.Println("  migrate --to <dir>      Move versions, downloads, aliases and state to a new directory (--dry-run to preview)")
+	fmt.Println("  repair                  Remove or repoint gopher symlinks to deleted versions (--dry-run to preview)")
+	fmt.Println("  setup                   Set up shell integration for persistent Go version switching (--non-interactive, --json)")
 	fmt.Println("  status                  Show persistence status and shell integration info")
 	fmt.Println("  debug                   Show debug information for troubleshooting")
 	fmt.Println("  version                 Show gopher version")
@@ -1076,71 +1794,143 @@ func showHelp() error {
 	fmt.Println("  # List all installed versions (interactive by default)")
 	fmt.Println("  gopher list")
 	fmt.Println("  gopher --no-interactive list")
+	fmt.Println("  gopher list --size")
 	fmt.Println()
 	fmt.Println("  # Install and use Go 1.21.0")
 	fmt.Println("  gopher install 1.21.0")
 	fmt.Println("  gopher use 1.21.0")
 	fmt.Println()
-	fmt.Println("  # Switch to system Go")
+	fmt.Println("  # Install the newest stable release, or the newest 1.21.x")
+	fmt.Println("  gopher install latest-stable")
+	fmt.Println("  gopher install latest-rc")
+	fmt.Println("  gopher install 1.21")
+	fmt.Println()
+	fmt.Println("  # Install several versions in parallel")
+	fmt.Println("  gopher install --concurrent 1.21.0 1.22.0 1.23.0")
+	fmt.Println("  gopher install --keep-going 1.21.0 1.22.0 1.23.0")
+	fmt.Println("  gopher install 1.21.0 --checksum <sha256>")
+	fmt.Println("  gopher install --from-file go1.21.0.linux-amd64.tar.gz")
+	fmt.Println()
+	fmt.Println("  # Switch to system or Homebrew Go")
 	fmt.Println("  gopher use system")
+	fmt.Println("  gopher use 1.21.0 --dry-run")
+	fmt.Println("  gopher use 1.23.0 --install")
+	fmt.Println("  gopher use 1.21.0 --no-hooks")
+	fmt.Println("  gopher install 1.23.0 --default")
+	fmt.Println("  gopher use -")
+	fmt.Println("  gopher use homebrew")
 	fmt.Println()
 	fmt.Println("  # Show system Go information")
 	fmt.Println("  gopher system")
 	fmt.Println()
 	fmt.Println("  # Set up persistent Go version switching")
 	fmt.Println("  gopher setup")
+	fmt.Println("  gopher setup --non-interactive --json")
 	fmt.Println("  gopher status")
 	fmt.Println()
-	fmt.Println("  # Debug information")
-	fmt.Println("  gopher debug")
+	fmt.Println("  # Debug information")
+	fmt.Println("  gopher debug")
+	fmt.Println()
+	fmt.Println("  # Remove old version")
+	fmt.Println("  gopher uninstall 1.20.7")
+	fmt.Println("  gopher uninstall --unused   # Versions not active and without aliases")
+	fmt.Println()
+	fmt.Println("  # Start a subshell with a version")
+	fmt.Println("  gopher run 1.21.0")
+	fmt.Println()
+	fmt.Println("  # Run a command with another Go version (active version unchanged)")
+	fmt.Println("  gopher exec 1.21.0 -- go test ./...")
+	fmt.Println()
+	fmt.Println("  # Reinstall a broken version")
+	fmt.Println("  gopher reinstall 1.21.0")
+	fmt.Println()
+	fmt.Println("  # Check installations for corruption")
+	fmt.Println("  gopher verify")
+	fmt.Println("  gopher verify --reinstall")
+	fmt.Println("  gopher verify --archive go1.21.0.linux-amd64.tar.gz")
+	fmt.Println()
+	fmt.Println("  # Inspect the download cache")
+	fmt.Println("  gopher cache list")
+	fmt.Println("  gopher cache clean 1.21.0")
+	fmt.Println()
+	fmt.Println("  # Recent version switches")
+	fmt.Println("  gopher history")
+	fmt.Println("  gopher history --clear")
+	fmt.Println()
+	fmt.Println("  # Move gopher's data to a new directory")
+	fmt.Println("  gopher migrate --to ~/.local/share/gopher --dry-run")
+	fmt.Println("  gopher migrate --to ~/.local/share/gopher")
 	fmt.Println()
-	fmt.Println("  # Remove old version")
-	fmt.Println("  gopher uninstall 1.20.7")
+	fmt.Println("  # Fix symlinks left behind by manually deleted versions")
+	fmt.Println("  gopher repair --dry-run")
+	fmt.Println("  gopher repair")
 	fmt.Println()
 	fmt.Println("  # Pagination and filtering")
 	fmt.Println("  gopher list-remote --page-size 5")
 	fmt.Println("  gopher list-remote --page 2 --page-size 10")
+	fmt.Println("  gopher list-remote --page all")
 	fmt.Println("  gopher list-remote --filter '1.21'")
-	fmt.Println("  gopher list-remote --stable")
+	fmt.Println("  gopher list-remote --channel unstable")
+	fmt.Println("  gopher list-remote --min 1.20 --max 1.22")
+	fmt.Println("  gopher list-remote --latest-per-minor")
 	fmt.Println("  gopher list-remote --interactive")
-	fmt.Println("  gopher list-remote --filter 'rc'")
+	fmt.Println("  gopher list-remote --channel unstable --filter 'rc'")
 	fmt.Println()
 	fmt.Println("  # Environment management")
 	fmt.Println("  gopher env list")
 	fmt.Println("  gopher env show go1.21.0")
+	fmt.Println("  gopher env get GOROOT go1.21.0")
 	fmt.Println("  gopher env set gopath_mode=version-specific")
 	fmt.Println("  gopher env set custom_gopath=/path/to/workspace")
 	fmt.Println("  gopher env reset")
+	fmt.Println("  gopher config edit")
+	fmt.Println("  gopher config dump")
 	fmt.Println()
 	fmt.Println("  # JSON output for scripting")
 	fmt.Println("  gopher list --json")
 	fmt.Println("  gopher current --json")
+	fmt.Println("  gopher current --short")
 	fmt.Println("  gopher system --json")
 	fmt.Println("  gopher env show go1.21.0 --json")
 	fmt.Println()
 	fmt.Println("CONFIGURATION:")
 	fmt.Println("  Gopher stores its configuration in:")
 	fmt.Println("  • Linux/macOS: ~/.gopher/config.json")
+	fmt.Println("  • Linux with XDG_CONFIG_HOME set: $XDG_CONFIG_HOME/gopher/config.json")
 	fmt.Printf("  • Windows: %s\\gopher\\config.json\n", "%USERPROFILE%")
 	fmt.Println()
 	fmt.Println("  Environment variables:")
 	fmt.Println("  • GOPHER_CONFIG: Path to custom configuration file")
 	fmt.Println("  • GOPHER_INSTALL_DIR: Custom installation directory")
 	fmt.Println("  • GOPHER_DOWNLOAD_DIR: Custom download directory")
+	fmt.Println("  • GOPHER_OFFLINE: Set to 1 to work offline, like --offline")
 	fmt.Println()
 	fmt.Println("OPTIONS:")
 	fmt.Println("  --json                  Output in JSON format")
+	fmt.Println("  --format <format>       Output format for list commands and current: table, plain, json or yaml")
 	fmt.Println("  --config <path>         Path to configuration file")
+	fmt.Println("  --install-dir <path>    Override the install directory for this run")
+	fmt.Println("  --download-dir <path>   Override the download directory for this run")
+	fmt.Println("  --symlink-dir <path>    Override the directory of the go symlink for this run")
 	fmt.Println("  --help                  Show this help message")
 	fmt.Println("  --verbose, -v           Show detailed output (DEBUG level)")
 	fmt.Println("  --quiet, -q             Only show errors (ERROR level)")
+	fmt.Println("  --no-color              Disable colored output (also set by NO_COLOR)")
+	fmt.Println("  --offline               Use only cached data, never the network (also set by GOPHER_OFFLINE=1)")
+	fmt.Println("  --no-hooks              Skip the pre_install, post_install and post_use hooks")
 	fmt.Println()
 	fmt.Println("PAGINATION & FILTERING (for list-remote):")
-	fmt.Println("  --page-size <number>    Number of versions per page (default: 10)")
-	fmt.Println("  --page <number>         Page number to display (default: 1)")
+	fmt.Println("  --page-size <number>    Number of versions per page (default: 10; 0 for all)")
+	fmt.Println("  --page <number|all>     Page number to display (default: 1), or 'all' for every version")
 	fmt.Println("  --filter <text>         Filter versions by text (e.g., '1.21', 'stable', 'rc')")
-	fmt.Println("  --stable                Show only stable versions")
+	fmt.Println("  --channel <channel>     'stable' (default) or 'unstable' to include prereleases;")
+	fmt.Println("                          also decides whether 'latest' may pick a prerelease")
+	fmt.Println("  --stable                Same as --channel stable")
+	fmt.Println("  --min <version>         Show only versions at or above this one (e.g., '1.20')")
+	fmt.Println("  --max <version>         Show only versions at or below this one (e.g., '1.22')")
+	fmt.Println("  --json-lines            Stream one JSON object per version (no pagination)")
 	fmt.Println("  --interactive           Enable interactive pagination (wait for user input)")
+	fmt.Println("  --size                  Show disk usage per installed version and in total (list)")
 	fmt.Println()
 	fmt.Println("DOCUMENTATION:")
 	fmt.Println("  https://github.com/molmedoz/gopher")
@@ -1154,15 +1944,37 @@ func showEnvHelp() error {
 	fmt.Println("Environment Management Commands:")
 	fmt.Println()
 	fmt.Println("  gopher env show [version]     - Show environment variables for a version")
+	fmt.Println("  gopher env get KEY [version]  - Print one variable, e.g. GOROOT (default: active version)")
 	fmt.Println("  gopher env set <key>=<value>  - Set a configuration option")
 	fmt.Println("  gopher env list               - List all configuration options")
 	fmt.Println("  gopher env reset              - Reset to default configuration")
+	fmt.Println("  gopher env paths              - Show where gopher stores its files")
+	fmt.Println("  gopher env activate <version> - Print commands that activate a version in this shell")
+	fmt.Println("  gopher env deactivate         - Print commands that undo 'env activate'")
 	fmt.Println()
 	fmt.Println("Configuration Options:")
-	fmt.Println("  gopath_mode                  - GOPATH management: shared, version-specific, custom")
+	fmt.Println("  install_dir                  - Directory where Go versions are installed")
+	fmt.Println("  download_dir                 - Directory for temporary downloads")
+	fmt.Println("  mirror_url                   - Go download mirror URL")
+	fmt.Println("  auto_cleanup                 - Automatically clean up old versions (true/false)")
+	fmt.Println("  keep_downloads               - Keep downloaded archives after install (true/false)")
+	fmt.Println("  auto_install_on_use          - Install missing versions on 'use' without asking (true/false)")
+	fmt.Println("  max_versions                 - Maximum number of versions to keep (at least 1)")
+	fmt.Println("  gopath_mode                  - GOPATH management: shared, version-specific, custom, per-project")
 	fmt.Println("  custom_gopath                - Custom GOPATH when mode is 'custom'")
-	fmt.Println("  goproxy                      - Go proxy URL")
-	fmt.Println("  gosumdb                      - Go checksum database")
+	fmt.Println("  gobin_mode                   - GOBIN management: gopath-bin, version-specific, custom")
+	fmt.Println("  custom_gobin                 - Custom GOBIN when mode is 'custom'")
+	fmt.Println("  goproxy                      - Go proxy URLs, 'direct' or 'off' (comma-separated)")
+	fmt.Println("  gosumdb                      - Go checksum database or 'off'")
+	fmt.Println("  goflags                      - Default go command flags, e.g. -mod=readonly")
+	fmt.Println("  gotoolchain                  - GOTOOLCHAIN: 'local' (default) stops Go switching toolchains, 'auto' allows it")
+	fmt.Println("  default_channel              - Release channel for list-remote and 'latest': stable, unstable")
+	fmt.Println("  linked_binaries              - Binaries linked next to the go symlink (comma-separated, default: go,gofmt)")
+	fmt.Println("  symlink_dir                  - Directory for the go symlink (default: ~/.local/bin; empty restores it)")
+	fmt.Println("  pre_install                  - Shell command run before installing a version (GOPHER_VERSION is set)")
+	fmt.Println("  post_install                 - Shell command run after installing a version (GOPHER_VERSION is set)")
+	fmt.Println("  post_use                     - Shell command run after switching versions (GOPHER_VERSION, GOPHER_PREVIOUS_VERSION)")
+	fmt.Println("  strict_hooks                 - Remove the version again when post_install fails (default: false)")
 	fmt.Println("  set_environment              - Whether to set environment variables")
 	fmt.Println()
 	fmt.Println("Examples:")
@@ -1170,6 +1982,9 @@ func showEnvHelp() error {
 	fmt.Println("  gopher env set gopath_mode=version-specific")
 	fmt.Println("  gopher env set custom_gopath=/path/to/go/workspace")
 	fmt.Println("  gopher env list")
+	fmt.Println("  eval \"$(gopher env activate go1.21.0)\"")
+	fmt.Println("  gopher env activate --shell fish go1.21.0 | source")
+	fmt.Println("  eval \"$(gopher env deactivate)\"")
 	return nil
 }
 
@@ -1181,6 +1996,8 @@ func handleEnvCommand(subcommand string, args []string, manager *inruntime.Manag
 			return errors.NewMissingArgument("show (requires version)")
 		}
 		return showEnvForVersion(args[0], manager)
+	case "get":
+		return getEnvValue(args, manager)
 	case "set":
 		if len(args) < 1 {
 			return errors.NewMissingArgument("set (requires key=value)")
@@ -1190,11 +2007,54 @@ func handleEnvCommand(subcommand string, args []string, manager *inruntime.Manag
 		return listConfigOptions(manager)
 	case "reset":
 		return resetConfig(manager)
+	case "paths":
+		return showEnvPaths(manager)
+	case "activate":
+		return activateVersion(manager, args)
+	case "deactivate":
+		return deactivateVersion()
 	default:
 		return errors.Newf(errors.ErrCodeInvalidArgument, "unknown env subcommand: %s", subcommand)
 	}
 }
 
+// getEnvValue prints one environment variable of a version, like
+// 'go env KEY', for Makefiles and scripts. args is the variable name and an
+// optional version, defaulting to the version recorded by 'gopher use'.
+// Nothing is printed and the exit code is 1 when the variable is not set.
+func getEnvValue(args []string, manager *inruntime.Manager) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.NewMissingArgument("get (requires KEY and optionally a version)")
+	}
+	key := args[0]
+
+	var version string
+	if len(args) == 2 {
+		version = args[1]
+	} else {
+		active, err := manager.GetActiveVersion()
+		if err != nil {
+			return err
+		}
+		if active == "" || active == "system" || active == inruntime.HomebrewVersion {
+			return errors.NewMissingArgument("get (requires a version when no gopher-managed version is active)")
+		}
+		version = active
+	}
+	version = "go" + strings.TrimPrefix(version, "go")
+
+	value, ok := manager.GetConfig().GetEnvironmentVariables(version)[key]
+	if !ok || value == "" {
+		return &exitCodeError{code: 1}
+	}
+
+	if *jsonOutput || *format == "yaml" {
+		return outputStructured(map[string]string{key: value})
+	}
+	fmt.Println(value)
+	return nil
+}
+
 // showEnvForVersion shows environment variables for a specific version
 func showEnvForVersion(version string, manager *inruntime.Manager) error {
 	// Normalize version
@@ -1211,8 +2071,9 @@ func showEnvForVersion(version string, manager *inruntime.Manager) error {
 		})
 	}
 
-	fmt.Printf("Environment variables for Go %s:\n", version)
-	fmt.Println()
+	log.Debug("using config GOPATH mode %q", manager.GetConfig().GOPATHMode)
+	log.Info("Environment variables for Go %s:", version)
+	log.Info("")
 	for key, value := range envVars {
 		fmt.Printf("  %s=%s\n", key, value)
 	}
@@ -1220,6 +2081,30 @@ func showEnvForVersion(version string, manager *inruntime.Manager) error {
 	return nil
 }
 
+// showEnvPaths shows where gopher keeps its files
+func showEnvPaths(manager *inruntime.Manager) error {
+	paths, err := getGopherPaths(manager)
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to determine gopher paths")
+	}
+
+	if *jsonOutput {
+		return outputJSON(paths)
+	}
+
+	log.Info("Gopher paths:")
+	log.Info("")
+	fmt.Printf("  Install Directory:  %s\n", paths.InstallDir)
+	fmt.Printf("  Download Directory: %s\n", paths.DownloadDir)
+	fmt.Printf("  State Directory:    %s\n", paths.StateDir)
+	fmt.Printf("  Scripts Directory:  %s\n", paths.ScriptsDir)
+	fmt.Printf("  Aliases File:       %s\n", paths.AliasesFile)
+	fmt.Printf("  Config File:        %s\n", paths.ConfigPath)
+	fmt.Printf("  Symlink Directory:  %s\n", paths.SymlinkDir)
+
+	return nil
+}
+
 // setConfigOption sets a configuration option
 func setConfigOption(keyValue string, manager *inruntime.Manager) error {
 	if err := errors.ValidateKeyValuePair(keyValue); err != nil {
@@ -1232,25 +2117,18 @@ func setConfigOption(keyValue string, manager *inruntime.Manager) error {
 
 	// Update config based on key
 	config := manager.GetConfig()
-	switch key {
-	case "gopath_mode":
-		if err := errors.ValidateConfigValue(key, value); err != nil {
-			return err
+	if err := applyConfigOption(config, key, value); err != nil {
+		return err
+	}
+	if key == "symlink_dir" {
+		inPath, err := manager.CheckSymlinkDir()
+		if err != nil {
+			return errors.Wrapf(err, errors.ErrCodeInvalidConfigValue, "invalid symlink_dir")
 		}
-		config.GOPATHMode = value
-	case "custom_gopath":
-		config.CustomGOPATH = value
-	case "goproxy":
-		config.GOPROXY = value
-	case "gosumdb":
-		config.GOSUMDB = value
-	case "set_environment":
-		if err := errors.ValidateConfigValue(key, value); err != nil {
-			return err
+		if !inPath {
+			dir, _ := manager.SymlinkDir()
+			log.Info("⚠️  %s is not in PATH; add it so 'go' finds the gopher symlink", dir)
 		}
-		config.SetEnvironment = value == "true"
-	default:
-		return errors.NewUnknownConfigOption(key)
 	}
 
 	// Save config
@@ -1259,7 +2137,8 @@ func setConfigOption(keyValue string, manager *inruntime.Manager) error {
 		return errors.NewConfigSaveFailed(configPath, err)
 	}
 
-	fmt.Printf("✓ Configuration updated: %s=%s\n", key, value)
+	log.Debug("saved configuration to %s", configPath)
+	log.Info("✓ Configuration updated: %s=%s", key, value)
 	return nil
 }
 
@@ -1271,17 +2150,31 @@ func listConfigOptions(manager *inruntime.Manager) error {
 		return outputJSON(config)
 	}
 
-	fmt.Println("Current Configuration:")
-	fmt.Println()
+	log.Debug("configuration loaded from %s", getConfigPath())
+	log.Info("Current Configuration:")
+	log.Info("")
 	fmt.Printf("  Install Directory: %s\n", config.InstallDir)
 	fmt.Printf("  Download Directory: %s\n", config.DownloadDir)
 	fmt.Printf("  Mirror URL: %s\n", config.MirrorURL)
 	fmt.Printf("  Auto Cleanup: %t\n", config.AutoCleanup)
+	fmt.Printf("  Keep Downloads: %t\n", config.KeepDownloads)
+	fmt.Printf("  Auto Install On Use: %t\n", config.AutoInstallOnUse)
 	fmt.Printf("  Max Versions: %d\n", config.MaxVersions)
 	fmt.Printf("  GOPATH Mode: %s\n", config.GOPATHMode)
 	fmt.Printf("  Custom GOPATH: %s\n", config.CustomGOPATH)
+	fmt.Printf("  GOBIN Mode: %s\n", config.GOBINMode)
+	fmt.Printf("  Custom GOBIN: %s\n", config.CustomGOBIN)
 	fmt.Printf("  GOPROXY: %s\n", config.GOPROXY)
 	fmt.Printf("  GOSUMDB: %s\n", config.GOSUMDB)
+	fmt.Printf("  GOFLAGS: %s\n", config.GOFLAGS)
+	fmt.Printf("  GOTOOLCHAIN: %s\n", config.GOTOOLCHAIN)
+	fmt.Printf("  Default Channel: %s\n", config.DefaultChannel)
+	fmt.Printf("  Linked Binaries: %s\n", strings.Join(config.LinkedBinaries, ", "))
+	fmt.Printf("  Symlink Directory: %s\n", config.SymlinkDir)
+	fmt.Printf("  Pre-install Hook: %s\n", config.PreInstall)
+	fmt.Printf("  Post-install Hook: %s\n", config.PostInstall)
+	fmt.Printf("  Post-use Hook: %s\n", config.PostUse)
+	fmt.Printf("  Strict Hooks: %t\n", config.StrictHooks)
 	fmt.Printf("  Set Environment: %t\n", config.SetEnvironment)
 
 	return nil
@@ -1296,7 +2189,7 @@ func resetConfig(manager *inruntime.Manager) error {
 		return fmt.Errorf("failed to save default configuration: %w", err)
 	}
 
-	fmt.Println("✓ Configuration reset to defaults")
+	log.Info("✓ Configuration reset to defaults")
 	return nil
 }
 
@@ -1308,10 +2201,87 @@ func getConfigPath() string {
 	return config.GetConfigPath()
 }
 
+// jsonSchemaVersion is the version of the structured output format. Bump it
+// when a command's output changes incompatibly.
+const jsonSchemaVersion = 1
+
+// outputCommand is the command being run, reported in every structured
+// response
+var outputCommand string
+
+// outputEnvelope wraps every --json and --format yaml response, so tools can
+// detect format changes and parse all commands the same way. A failed
+// command has a null data and an error.
+type outputEnvelope struct {
+	SchemaVersion int          `json:"schema_version"`
+	Command       string       `json:"command"`
+	Data          any          `json:"data"`
+	Error         *errorDetail `json:"error,omitempty"`
+}
+
+// newEnvelope returns the envelope for data from the current command
+func newEnvelope(data any) outputEnvelope {
+	return outputEnvelope{SchemaVersion: jsonSchemaVersion, Command: outputCommand, Data: data}
+}
+
+// setupLogOutput sends log messages to stderr when stdout carries a JSON or
+// YAML document, so that the document is all a parser sees
+func setupLogOutput() {
+	if *jsonOutput || *jsonLines || *format == "json" || *format == "yaml" {
+		log.SetOutput(os.Stderr, os.Stderr)
+	}
+}
+
+// outputJSON writes data to stdout under the data key of an output envelope
 func outputJSON(data any) error {
+	return writeEnvelope(newEnvelope(data))
+}
+
+// writeEnvelope writes an output envelope to stdout as indented JSON
+func writeEnvelope(envelope outputEnvelope) error {
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(data)
+	return encoder.Encode(envelope)
+}
+
+// outputFormats are the values accepted by --format
+var outputFormats = []string{"table", "plain", "json", "yaml"}
+
+// resolveFormat validates --format and reconciles it with --json, which is
+// kept as a shorthand for --format json
+func resolveFormat() error {
+	if !slices.Contains(outputFormats, *format) {
+		return errors.Newf(errors.ErrCodeInvalidArgument, "unknown output format %q (use %s)", *format, strings.Join(outputFormats, ", "))
+	}
+
+	switch {
+	case *jsonOutput && *format != "table" && *format != "json":
+		return errors.Newf(errors.ErrCodeInvalidArgument, "--json conflicts with --format %s", *format)
+	case *jsonOutput:
+		*format = "json"
+	case *format == "json":
+		*jsonOutput = true
+	}
+	return nil
+}
+
+// outputStructured writes data as YAML with --format yaml, otherwise as JSON
+func outputStructured(data any) error {
+	if *format == "yaml" {
+		return writeYAML(os.Stdout, newEnvelope(data))
+	}
+	return outputJSON(data)
+}
+
+// writeJSONLines writes each version as one compact JSON object per line
+func writeJSONLines[T any](w io.Writer, versions []T) error {
+	encoder := json.NewEncoder(w)
+	for _, v := range versions {
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // setupShellIntegration sets up shell integration for persistent Go version switching
@@ -1395,22 +2365,17 @@ func setupShellIntegration(manager *inruntime.Manager) error { //nolint:unused
 
 // showPersistenceStatus shows the current persistence status and shell integration info
 func showPersistenceStatus(manager *inruntime.Manager) error {
+	paths, err := getGopherPaths(manager)
+	if err != nil {
+		return err
+	}
+
 	// Check if state file exists
-	stateFile := filepath.Join(manager.GetConfig().InstallDir, "..", "state", "active-version")
-	stateExists := false
-	var activeVersion string
-
-	// stateFile is constructed from validated config.InstallDir
-	// #nosec G304 -- path validated through config system
-	if content, err := os.ReadFile(stateFile); err == nil {
-		stateExists = true
-		lines := strings.Split(string(content), "\n")
-		for _, line := range lines {
-			if strings.HasPrefix(line, "active_version=") {
-				activeVersion = strings.TrimPrefix(line, "active_version=")
-				break
-			}
-		}
+	stateFile := paths.StateFile
+	state, err := manager.ReadState()
+	stateExists := err == nil
+	if !stateExists {
+		state = &inruntime.State{}
 	}
 
 	// Check shell integration
@@ -1426,17 +2391,25 @@ func showPersistenceStatus(manager *inruntime.Manager) error {
 	}
 
 	// Check init script
-	initScript := filepath.Join(manager.GetConfig().InstallDir, "..", "scripts", "gopher-init.sh")
+	initScript := paths.InitScript
 	initScriptExists := false
 	if _, err := os.Stat(initScript); err == nil {
 		initScriptExists = true
 	}
 
+	shadow := manager.CheckPathShadowing()
+	activation := manager.CheckActivation()
+	goroot := manager.CheckGOROOT()
+	toolchain := manager.EffectiveGOTOOLCHAIN()
+	toolchainSwitches := inruntime.ToolchainSwitchingAllowed(toolchain)
+
 	status := map[string]any{
 		"persistence": map[string]any{
-			"enabled":        stateExists,
-			"active_version": activeVersion,
-			"state_file":     stateFile,
+			"enabled":          stateExists,
+			"active_version":   state.ActiveVersion,
+			"previous_version": state.PreviousVersion,
+			"updated_at":       state.UpdatedAt,
+			"state_file":       stateFile,
 		},
 		"shell_integration": map[string]any{
 			"shell":           shell,
@@ -1446,6 +2419,19 @@ func showPersistenceStatus(manager *inruntime.Manager) error {
 			"init_script":     initScript,
 			"script_exists":   initScriptExists,
 		},
+		"path": map[string]any{
+			"ok":     shadow == nil,
+			"shadow": shadow,
+		},
+		"activation": activation,
+		"goroot": map[string]any{
+			"ok":       goroot == nil,
+			"override": goroot,
+		},
+		"toolchain": map[string]any{
+			"gotoolchain":       toolchain,
+			"switching_allowed": toolchainSwitches,
+		},
 	}
 
 	if *jsonOutput {
@@ -1460,7 +2446,13 @@ func showPersistenceStatus(manager *inruntime.Manager) error {
 	// Persistence status
 	fmt.Println("Persistence:")
 	if stateExists {
-		fmt.Printf("  ✓ Enabled (active version: %s)\n", activeVersion)
+		fmt.Printf("  ✓ Enabled (active version: %s)\n", state.ActiveVersion)
+		if state.PreviousVersion != "" {
+			fmt.Printf("  Previous version: %s\n", state.PreviousVersion)
+		}
+		if !state.UpdatedAt.IsZero() {
+			fmt.Printf("  Updated: %s\n", state.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+		}
 	} else {
 		fmt.Println("  ✗ Disabled")
 	}
@@ -1493,6 +2485,56 @@ func showPersistenceStatus(manager *inruntime.Manager) error {
 	}
 	fmt.Println()
 
+	// PATH status
+	fmt.Println("PATH:")
+	if shadow == nil {
+		fmt.Println("  ✓ No other go shadows the gopher symlink")
+	} else {
+		fmt.Print(indentLines(shadow.Warning(), "  "))
+	}
+	fmt.Println()
+
+	// GOROOT status
+	fmt.Println("GOROOT:")
+	if goroot == nil {
+		fmt.Println("  ✓ GOROOT does not override the active version")
+	} else {
+		fmt.Print(indentLines(goroot.Warning(), "  "))
+	}
+	fmt.Println()
+
+	// Activation status
+	fmt.Println("Activation:")
+	if activation.SymlinkTarget != "" {
+		fmt.Printf("  Symlink: %s -> %s\n", activation.Symlink, activation.SymlinkTarget)
+	} else {
+		fmt.Printf("  Symlink: %s (missing)\n", activation.Symlink)
+	}
+	if activation.SymlinkMatchesState {
+		fmt.Println("  ✓ Symlink matches the active version")
+	} else {
+		fmt.Println("  ✗ Symlink does not match the active version (run 'gopher repair' or 'gopher use <version>')")
+	}
+	switch {
+	case activation.GoVersion == "":
+		fmt.Println("  ✗ 'go version' could not be run from PATH")
+	case activation.ActiveMatchesGoVersion:
+		fmt.Printf("  ✓ 'go version' reports %s\n", activation.GoVersion)
+	default:
+		fmt.Printf("  ✗ 'go version' reports %s, not the active version\n", activation.GoVersion)
+	}
+	fmt.Println()
+
+	// Toolchain status
+	fmt.Println("Toolchain:")
+	if toolchainSwitches {
+		fmt.Printf("  ✗ GOTOOLCHAIN=%s lets Go run a different toolchain than the active version\n", toolchain)
+		fmt.Printf("  Fix: %s\n", manager.ToolchainFix())
+	} else {
+		fmt.Printf("  ✓ GOTOOLCHAIN=%s keeps Go on the active version\n", toolchain)
+	}
+	fmt.Println()
+
 	// Recommendations
 	switch {
 	case !stateExists:
@@ -1503,6 +2545,9 @@ func showPersistenceStatus(manager *inruntime.Manager) error {
 		fmt.Println("Recommendations:")
 		fmt.Println("  • Run 'gopher setup' to configure shell integration")
 		fmt.Println("  • Restart your shell after setup")
+	case goroot != nil:
+		fmt.Println("Recommendations:")
+		fmt.Printf("  • GOROOT overrides the active version: %s\n", goroot.Fix)
 	default:
 		fmt.Println("✓ Persistence and shell integration are properly configured!")
 	}
@@ -1521,15 +2566,22 @@ func createGopherInitScript(manager *inruntime.Manager) (string, error) {
 
 	scriptPath := filepath.Join(scriptDir, "gopher-init.sh")
 
+	// Paths come from the config, as the gopher directories depend on the
+	// platform and the XDG variables
+	cfg := manager.GetConfig()
+	dataDir := filepath.Dir(cfg.InstallDir)
+	stateFile := inruntime.ShellQuote(filepath.Join(dataDir, "state", inruntime.StateFileName))
+	configFile := inruntime.ShellQuote(config.GetConfigPath())
+
 	scriptContent := `#!/bin/bash
 # Gopher Go Version Manager - Shell Integration
 # This script is automatically generated and should not be edited manually
 
 # Function to get the active Go version
 gopher_get_active_version() {
-    local state_file="$HOME/.gopher/state/active-version"
+    local state_file=` + stateFile + `
     if [[ -f "$state_file" ]]; then
-        local version=$(grep "active_version=" "$state_file" | cut -d'=' -f2)
+        local version=$(` + inruntime.StateShellReadCommand(`"$state_file"`) + `)
         if [[ -n "$version" ]]; then
             echo "$version"
             return 0
@@ -1538,6 +2590,22 @@ gopher_get_active_version() {
     return 1
 }
 
+# Function to get the GOBIN for a version from the gobin_mode setting
+gopher_gobin() {
+    local config_file=` + configFile + `
+    local mode=""
+    local custom=""
+    if [ -f "$config_file" ]; then
+        mode=$(grep -o '"gobin_mode": *"[^"]*"' "$config_file" | cut -d'"' -f4)
+        custom=$(grep -o '"custom_gobin": *"[^"]*"' "$config_file" | cut -d'"' -f4)
+    fi
+    case "$mode" in
+        version-specific) echo ` + inruntime.ShellQuote(filepath.Join(dataDir, "gobin")) + `"/$1" ;;
+        custom) echo "${custom:-$GOPATH/bin}" ;;
+        *) echo "$GOPATH/bin" ;;
+    esac
+}
+
 # Function to set up Go environment
 gopher_setup_go_env() {
     local version="$1"
@@ -1579,9 +2647,26 @@ gopher_setup_go_env() {
         else
             return 1
         fi
+    elif [[ "$version" == "homebrew" ]]; then
+        # Use Homebrew Go - ask it for its GOROOT, as bin/go is a Homebrew link
+        local go_path=""
+        for location in "/opt/homebrew/bin/go" "/usr/local/opt/go/libexec/bin/go" "/home/linuxbrew/.linuxbrew/bin/go"; do
+            if [[ -x "$location" ]]; then
+                go_path="$location"
+                break
+            fi
+        done
+
+        if [[ -n "$go_path" ]]; then
+            local goroot_dir=$("$go_path" env GOROOT)
+            export GOROOT="$goroot_dir"
+            export PATH="$goroot_dir/bin:$PATH"
+        else
+            return 1
+        fi
     else
         # Set up GOROOT for gopher-managed version
-        local goroot="$HOME/.gopher/versions/$version"
+        local goroot=` + inruntime.ShellQuote(cfg.InstallDir) + `"/$version"
         if [[ -d "$goroot" ]]; then
             export GOROOT="$goroot"
             export PATH="$goroot/bin:$PATH"
@@ -1592,9 +2677,9 @@ gopher_setup_go_env() {
 
     # Set up GOPATH based on configuration
     local gopath_mode="shared"  # Default mode
-    local state_file="$HOME/.gopher/state/active-version"
+    local state_file=` + stateFile + `
     if [[ -f "$state_file" ]]; then
-        local config_file="$HOME/.gopher/config.json"
+        local config_file=` + configFile + `
         if [[ -f "$config_file" ]]; then
             # Try to read GOPATH mode from config (simplified)
             local mode=$(grep -o '"gopath_mode":"[^"]*"' "$config_file" | cut -d'"' -f4)
@@ -1609,20 +2694,33 @@ gopher_setup_go_env() {
             export GOPATH="$HOME/go"
             ;;
         "version-specific")
-            export GOPATH="$HOME/.gopher/gopath/$version"
+            export GOPATH=` + inruntime.ShellQuote(filepath.Join(dataDir, "gopath")) + `"/$version"
             ;;
         "custom")
             # For custom mode, we'd need to read from config
             export GOPATH="$HOME/go"
             ;;
+        "per-project")
+            # Use <project root>/.gopher-gopath, falling back to the shared GOPATH
+            export GOPATH="$HOME/go"
+            local dir="$PWD"
+            while [[ -n "$dir" && "$dir" != "/" ]]; do
+                if [[ -d "$dir/.gopher-gopath" || -f "$dir/go.mod" || -e "$dir/.git" ]]; then
+                    export GOPATH="$dir/.gopher-gopath"
+                    break
+                fi
+                dir=$(dirname "$dir")
+            done
+            ;;
     esac
 
     # Set up other Go environment variables
     export GOPROXY="https://proxy.golang.org,direct"
     export GOSUMDB="sum.golang.org"
-    
-    # Add GOPATH/bin to PATH for Go tools
-    export PATH="$GOPATH/bin:$PATH"
+` + inruntime.GoSettingsScript(cfg) + `    
+    # Install Go tools to GOBIN and add it to PATH
+    export GOBIN="$(gopher_gobin "$version")"
+    export PATH="$GOBIN:$PATH"
     
     return 0
 }
@@ -1839,6 +2937,13 @@ func showDebugInfo(manager *inruntime.Manager) error {
 				filepath.Join(homeDir, "bin", "go"),
 			}
 		}
+		if dir := manager.GetConfig().SymlinkDir; dir != "" {
+			name := "go"
+			if runtime.GOOS == "windows" {
+				name = "go.exe"
+			}
+			symlinkPaths = append([]string{filepath.Join(dir, name)}, symlinkPaths...)
+		}
 
 		for _, symlinkPath := range symlinkPaths {
 			if _, err := os.Lstat(symlinkPath); err == nil {
@@ -1854,6 +2959,18 @@ func showDebugInfo(manager *inruntime.Manager) error {
 	}
 	fmt.Println()
 
+	// Show whether PATH finds the gopher symlink
+	fmt.Println("PATH check:")
+	if shadow := manager.CheckPathShadowing(); shadow != nil {
+		fmt.Print(indentLines(shadow.Warning(), "  "))
+	} else {
+		fmt.Println("  ✓ No other go shadows the gopher symlink")
+	}
+	if goroot := manager.CheckGOROOT(); goroot != nil {
+		fmt.Print(indentLines(goroot.Warning(), "  "))
+	}
+	fmt.Println()
+
 	// Show installed versions
 	fmt.Println("Installed Go versions:")
 	installed, err := manager.ListInstalled()
@@ -1988,6 +3105,8 @@ func handleAliasCommand(args []string, manager *inruntime.Manager) error {
 		return updateAlias(manager, subArgs[0], subArgs[1])
 	case "bulk":
 		return handleBulkAliasCommand(subArgs, manager)
+	case "group":
+		return handleAliasGroupCommand(subArgs, manager)
 	case "by-version":
 		if len(subArgs) < 1 {
 			return fmt.Errorf("version required for 'by-version' subcommand")
@@ -2002,6 +3121,8 @@ func handleAliasCommand(args []string, manager *inruntime.Manager) error {
 		return handleAliasExport(subArgs, manager)
 	case "import":
 		return handleAliasImport(subArgs, manager)
+	case "validate", "check":
+		return handleAliasValidate(subArgs, manager)
 	case "help":
 		return showAliasHelp()
 	default:
@@ -2093,6 +3214,10 @@ func handleAliasImport(args []string, manager *inruntime.Manager) error {
 
 	filename := args[0]
 
+	if *preview {
+		return previewAliasImport(manager, filename)
+	}
+
 	// Determine conflict resolution mode
 	allowOverride := *override
 	noOverride := *noOverride
@@ -2112,6 +3237,126 @@ func handleAliasImport(args []string, manager *inruntime.Manager) error {
 	return nil
 }
 
+// previewAliasImport prints which aliases an import would create, which
+// conflict with existing aliases and which are invalid, without writing
+// anything
+func previewAliasImport(manager *inruntime.Manager, filename string) error {
+	preview, err := manager.AliasManager().PreviewImport(filename)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		return outputJSON(preview)
+	}
+
+	fmt.Printf("Preview of importing %s (nothing has been changed):\n", filename)
+	if len(preview.Create) > 0 {
+		fmt.Println("\nWould create:")
+		for _, entry := range preview.Create {
+			fmt.Printf("  + %s -> %s\n", entry.Name, entry.Version)
+		}
+	}
+	if len(preview.Conflicts) > 0 {
+		fmt.Println("\nConflicts with existing aliases:")
+		for _, entry := range preview.Conflicts {
+			fmt.Printf("  ~ %s: %s -> %s\n", entry.Name, entry.Current, entry.Version)
+		}
+	}
+	if len(preview.Unchanged) > 0 {
+		fmt.Println("\nAlready up to date:")
+		for _, entry := range preview.Unchanged {
+			fmt.Printf("  = %s -> %s\n", entry.Name, entry.Version)
+		}
+	}
+	if len(preview.Invalid) > 0 {
+		fmt.Println("\nInvalid:")
+		for _, entry := range preview.Invalid {
+			fmt.Printf("  ! %s -> %s: %s\n", entry.Name, entry.Version, entry.Problem)
+		}
+	}
+
+	fmt.Println()
+	switch {
+	case len(preview.Invalid) > 0:
+		fmt.Println("Fix the invalid aliases before importing; the import fails while any remain.")
+	case len(preview.Conflicts) > 0:
+		fmt.Printf("Run 'gopher alias import %s' to apply, with --override or --force to replace conflicting aliases without asking.\n", filename)
+	default:
+		fmt.Printf("Run 'gopher alias import %s' to apply.\n", filename)
+	}
+	return nil
+}
+
+// handleAliasValidate handles the validate command, reporting aliases that
+// point at versions which are no longer installed and optionally repairing them
+func handleAliasValidate(args []string, manager *inruntime.Manager) error {
+	fix := false
+	reassignTo := ""
+
+	for _, arg := range args {
+		switch {
+		case arg == "--fix" || arg == "-fix":
+			fix = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag for alias validate: %s", arg)
+		case reassignTo == "":
+			reassignTo = arg
+		default:
+			return fmt.Errorf("alias validate accepts at most one version (e.g., 'gopher alias validate --fix 1.22.0')")
+		}
+	}
+
+	if reassignTo != "" && !fix {
+		return fmt.Errorf("a version to reassign to requires --fix (e.g., 'gopher alias validate --fix %s')", reassignTo)
+	}
+
+	am := manager.AliasManager()
+	dangling, err := am.FindDanglingAliases()
+	if err != nil && fix && errorCode(err) == errors.ErrCodeAliasFileCorrupt {
+		// Only an explicit --fix may replace a corrupt aliases file
+		backup, resetErr := am.ResetCorruptAliases()
+		if resetErr != nil {
+			return resetErr
+		}
+		if backup != "" {
+			fmt.Printf("✓ The aliases file was corrupt and has been reset (backup: %s)\n", backup)
+		}
+		dangling, err = am.FindDanglingAliases()
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(dangling) == 0 {
+		fmt.Println("✓ All aliases point to installed versions")
+		return nil
+	}
+
+	if !fix {
+		fmt.Printf("Found %d alias(es) pointing to versions that are not installed:\n", len(dangling))
+		for _, alias := range dangling {
+			fmt.Printf("  %-20s -> %s\n", alias.Name, alias.Version)
+		}
+		fmt.Println("\nRun 'gopher alias validate --fix' to remove them, or 'gopher alias validate --fix <version>' to reassign them")
+		return errors.Newf(errors.ErrCodeVersionNotInstalled, "%d alias(es) point to versions that are not installed", len(dangling))
+	}
+
+	repaired, err := am.RepairDanglingAliases(reassignTo)
+	if err != nil {
+		return err
+	}
+
+	for _, alias := range repaired {
+		if reassignTo == "" {
+			fmt.Printf("✓ Removed alias '%s' (version %s is not installed)\n", alias.Name, alias.Version)
+		} else {
+			fmt.Printf("✓ Reassigned alias '%s' from %s to %s\n", alias.Name, alias.Version, inruntime.NormalizeVersion(reassignTo))
+		}
+	}
+	return nil
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
@@ -2134,10 +3379,12 @@ SUBCOMMANDS:
     by-version <version>      Show all aliases for a specific version
     suggest <version>         Suggest common alias names for a version
     export <file>             Export aliases to JSON file
-    import <file>             Import aliases from JSON file
+    import [--preview] <file> Import aliases from JSON file (--preview shows conflicts without applying)
     remove <name>             Remove an alias
     update <name> <version>   Update an existing alias
     bulk                      Bulk alias operations (create multiple aliases)
+    group                     Manage alias groups (sets of aliases used together)
+    validate [--fix] [ver]    Report aliases whose version is not installed (--fix removes or reassigns them)
     help                      Show this help
 
 EXAMPLES:
@@ -2149,8 +3396,12 @@ EXAMPLES:
     gopher alias suggest 1.21.0       # Suggest common aliases for version 1.21.0
     gopher alias export aliases.json  # Export aliases to JSON file
     gopher alias import aliases.json  # Import aliases from JSON file
+    gopher alias import --preview aliases.json  # Show what the import would change
     gopher alias remove stable
     gopher alias update stable 1.22.0
+    gopher alias validate             # Report aliases pointing to uninstalled versions
+    gopher alias validate --fix       # Remove aliases pointing to uninstalled versions
+    gopher alias validate --fix 1.22.0  # Reassign them to 1.22.0 instead
     gopher use stable          # Use an alias with the 'use' command
     
     # Interactive conflict resolution (default behavior)
@@ -2173,6 +3424,10 @@ EXAMPLES:
     gopher alias bulk create stable=1.21.0 latest=1.22.0 dev=1.23.0
     gopher alias bulk create --override stable=1.22.0 latest=1.23.0
 
+    # Alias groups
+    gopher alias group create dev stable=1.22.0 tools=1.21.0
+    gopher alias group show dev
+
 ALIAS NAMING RULES:
     - Only letters, numbers, dots, hyphens, and underscores allowed
     - 1-50 characters long
@@ -2180,7 +3435,7 @@ ALIAS NAMING RULES:
     - Case-sensitive
 
 RESERVED NAMES:
-    list, install, uninstall, use, current, system, version, help, init, setup, status, debug, env, alias, sys, go, git, ls, cd, pwd`)
+    list, install, uninstall, use, current, system, homebrew, version, help, init, setup, status, debug, env, alias, sys, go, git, ls, cd, pwd`)
 	return nil
 }
 
@@ -2314,11 +3569,13 @@ EXAMPLES:
     gopher alias bulk create --override stable=1.21.0 latest=1.22.0
     gopher alias bulk create --no-override stable=1.21.0 latest=1.22.0
     gopher alias bulk create --force stable=1.21.0 latest=1.22.0
+    gopher alias bulk create --keep-going --no-override stable=1.21.0 latest=1.22.0
 
 OPTIONS:
     --override     Allow overriding existing aliases without confirmation
     --no-override  Exit with error if alias already exists (no override allowed)
     --force        Force operation without confirmation (overrides all other flags)
+    --keep-going   Create the other aliases when one fails, and report failures at the end
 
 BULK OPERATION FEATURES:
     • Create multiple aliases in one command
@@ -2355,6 +3612,10 @@ func createBulkAliases(manager *inruntime.Manager, args []string) error {
 		noOverride = false
 	}
 
+	if *keepGoing {
+		return createBulkAliasesKeepGoing(manager, aliases, allowOverride, noOverride, force)
+	}
+
 	// Create aliases
 	if err := manager.AliasManager().CreateAliasesBulk(aliases, allowOverride, noOverride, force); err != nil {
 		return err
@@ -2363,8 +3624,137 @@ func createBulkAliases(manager *inruntime.Manager, args []string) error {
 	return nil
 }
 
+// createBulkAliasesKeepGoing creates every alias it can and reports the ones
+// that failed at the end, for 'alias bulk create --keep-going'
+func createBulkAliasesKeepGoing(manager *inruntime.Manager, aliases map[string]string, allowOverride, noOverride, force bool) error {
+	results, err := manager.AliasManager().CreateAliasesKeepGoing(aliases, allowOverride, noOverride, force)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, result := range results {
+		if !result.OK {
+			failed = append(failed, result.Name)
+		}
+	}
+	if len(failed) > 0 {
+		err = errors.Newf(errors.ErrCodeInvalidArgument, "%d of %d aliases could not be created: %s",
+			len(failed), len(results), strings.Join(failed, ", "))
+	}
+
+	if *jsonOutput {
+		if jsonErr := outputJSON(results); jsonErr != nil {
+			return jsonErr
+		}
+		// The results already describe the failures
+		if err != nil {
+			return &exitCodeError{code: exitCodeFor(err)}
+		}
+		return nil
+	}
+
+	fmt.Println("Summary:")
+	for _, result := range results {
+		switch {
+		case !result.OK:
+			fmt.Printf("  ✗ %s: %s\n", result.Name, result.Error)
+		case result.Updated:
+			fmt.Printf("  ✓ %s -> %s (updated)\n", result.Name, result.Version)
+		default:
+			fmt.Printf("  ✓ %s -> %s\n", result.Name, result.Version)
+		}
+	}
+	return err
+}
+
+// handleAliasGroupCommand handles alias group operations
+func handleAliasGroupCommand(args []string, manager *inruntime.Manager) error {
+	if len(args) < 1 {
+		return showAliasGroupHelp()
+	}
+
+	subcommand := args[0]
+	subArgs := args[1:]
+
+	switch subcommand {
+	case "create":
+		if len(subArgs) < 2 {
+			return fmt.Errorf("group create requires a group name and at least one alias (e.g., 'gopher alias group create dev stable=1.22.0')")
+		}
+		return createAliasGroup(manager, subArgs[0], subArgs[1:])
+	case "show":
+		if len(subArgs) < 1 {
+			return fmt.Errorf("group show requires a group name (e.g., 'gopher alias group show dev')")
+		}
+		return showAliasGroup(manager, subArgs[0])
+	case "help":
+		return showAliasGroupHelp()
+	default:
+		return fmt.Errorf("unknown group subcommand: %s (use 'gopher alias group help' for available commands)", subcommand)
+	}
+}
+
+// showAliasGroupHelp shows help for the alias group command
+func showAliasGroupHelp() error {
+	fmt.Println(`gopher alias group - Manage alias groups
+
+USAGE:
+    gopher alias group create <group> <name1=version1> [name2=version2] ...
+    gopher alias group show <group>
+
+A group is a named set of aliases that belong together, such as the
+versions used by one environment. Groups are stored in the aliases file.
+
+EXAMPLES:
+    gopher alias group create dev stable=1.22.0 tools=1.21.0
+    gopher alias group show dev
+
+STANDARD GROUP NAMES:
+    production, development, testing, staging, personal`)
+	return nil
+}
+
+// createAliasGroup creates a group of aliases
+func createAliasGroup(manager *inruntime.Manager, group string, args []string) error {
+	bindings := make(map[string]string)
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid alias format '%s' (expected 'name=version')", arg)
+		}
+		bindings[parts[0]] = parts[1]
+	}
+
+	if err := manager.AliasManager().CreateAliasGroup(group, bindings); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Created alias group '%s' with %d alias(es)\n", group, len(bindings))
+	return showAliasGroup(manager, group)
+}
+
+// showAliasGroup shows the aliases of a group
+func showAliasGroup(manager *inruntime.Manager, group string) error {
+	aliases, err := manager.AliasManager().GetAliasGroup(group)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Alias group: %s\n", group)
+	for _, alias := range aliases {
+		fmt.Printf("  %-20s -> %s\n", alias.Name, alias.Version)
+	}
+
+	return nil
+}
+
 // setupShellIntegrationEnhanced provides an enhanced setup experience
 func setupShellIntegrationEnhanced(manager *inruntime.Manager) error {
+	if *jsonOutput {
+		return outputSetupReport(manager, false)
+	}
+
 	fmt.Println("🔧 Gopher Environment Setup")
 	fmt.Println("===========================")
 	fmt.Println()
@@ -2443,7 +3833,7 @@ func setupShellIntegrationEnhanced(manager *inruntime.Manager) error {
 	fmt.Println()
 
 	// Ask if user wants to proceed
-	if !askForConfirmation("Do you want to set up shell integration?") {
+	if !setupNonInteractive() && !askForConfirmation("Do you want to set up shell integration?") {
 		fmt.Println("Setup cancelled.")
 		return nil
 	}
@@ -2465,7 +3855,7 @@ func setupShellIntegrationEnhanced(manager *inruntime.Manager) error {
 	// Add symlink directory to PATH if needed
 	if !systemInfo.IsInPath {
 		fmt.Printf("Adding %s to PATH...\n", systemInfo.SymlinkDir)
-		if err := addDirectoryToPath(systemInfo.SymlinkDir, systemInfo.ShellProfile); err != nil {
+		if _, err := addDirectoryToPath(systemInfo.SymlinkDir, systemInfo.ShellProfile); err != nil {
 			fmt.Printf("⚠️  Failed to add to PATH: %v\n", err)
 			fmt.Printf("Please manually add this to your %s:\n", systemInfo.ShellProfile)
 			fmt.Printf("export PATH=\"%s:$PATH\"\n", systemInfo.SymlinkDir)
@@ -2512,16 +3902,17 @@ func setupShellIntegrationEnhanced(manager *inruntime.Manager) error {
 	fmt.Println()
 	fmt.Println("═══════════════════════════════════════════════════════════════")
 	fmt.Println()
+	cfg := manager.GetConfig()
 	fmt.Println("📁 Directories created:")
-	fmt.Printf("  Config:    %s/.gopher/config.json\n", systemInfo.HomeDir)
-	fmt.Printf("  Versions:  %s/.gopher/versions\n", systemInfo.HomeDir)
-	fmt.Printf("  Downloads: %s/.gopher/downloads\n", systemInfo.HomeDir)
+	fmt.Printf("  Config:    %s\n", config.GetConfigPath())
+	fmt.Printf("  Versions:  %s\n", cfg.InstallDir)
+	fmt.Printf("  Downloads: %s\n", cfg.DownloadDir)
 	fmt.Printf("  Symlinks:  %s\n", systemInfo.SymlinkDir)
 	fmt.Println()
 
 	if systemInfo.IsDocker {
 		fmt.Println("🐳 Docker Environment:")
-		fmt.Println("- Run 'source ~/.gopher/scripts/gopher-init.sh' in each session")
+		fmt.Printf("- Run 'source %s' in each session\n", initScript)
 		fmt.Println("- Or add it to your shell profile manually")
 		fmt.Println()
 	}
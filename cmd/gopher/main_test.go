@@ -0,0 +1,215 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/env"
+	"github.com/molmedoz/gopher/internal/log"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+// writeTestArchive writes a minimal Go archive for version to the download
+// cache and returns its filename and SHA256
+func writeTestArchive(t *testing.T, downloadDir, version string) (string, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	files := map[string]string{
+		"go/bin/go":  "#!/bin/sh\necho \"go version " + version + " " + runtime.GOOS + "/" + runtime.GOARCH + "\"\n",
+		"go/VERSION": version + "\n",
+	}
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	filename := fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+	if err := os.WriteFile(filepath.Join(downloadDir, filename), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return filename, hex.EncodeToString(sum[:])
+}
+
+// TestMutatingCommandsJSONStdout runs install, use and uninstall with --json
+// and checks that stdout holds nothing but the envelope
+func TestMutatingCommandsJSONStdout(t *testing.T) {
+	if runtime.GOOS == "windows" || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" && runtime.GOARCH != "386") {
+		t.Skip("test archive is a tar.gz for a standard platform")
+	}
+
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	if err := os.MkdirAll(home, 0750); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("SHELL", "/bin/bash")
+
+	downloadDir := filepath.Join(root, "gopher", "downloads")
+	var rows string
+	for _, version := range []string{"go1.21.0", "go1.22.0"} {
+		filename, sum := writeTestArchive(t, downloadDir, version)
+		rows += fmt.Sprintf(`<tr><td><a class="download" href="/dl/%s">%s</a></td><td>0.0MB</td><td><tt>%s</tt></td></tr>`, filename, filename, sum)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<table>%s</table>", rows)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.InstallDir = filepath.Join(root, "gopher", "versions")
+	cfg.DownloadDir = downloadDir
+	cfg.MirrorURL = server.URL
+	cfg.SymlinkDir = filepath.Join(root, "bin")
+	cfg.KeepDownloads = true
+	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
+
+	savedJSON, savedCommand := *jsonOutput, outputCommand
+	defer func() {
+		*jsonOutput, outputCommand = savedJSON, savedCommand
+		log.SetOutput(os.Stdout, os.Stderr)
+	}()
+	*jsonOutput = true
+
+	commands := []struct {
+		command string
+		args    []string
+	}{
+		{"install", []string{"1.21.0", "1.22.0"}},
+		{"use", []string{"1.22.0"}},
+		{"uninstall", []string{"1.21.0"}},
+	}
+	for _, c := range commands {
+		outputCommand = c.command
+		var cmdErr error
+		out := captureStdout(t, func() {
+			// As in main, with the log writing to the captured stdout
+			log.SetOutput(os.Stdout, os.Stderr)
+			setupLogOutput()
+			cmdErr = executeCommand(manager, c.command, c.args)
+		})
+		if cmdErr != nil {
+			t.Fatalf("%s failed: %v", c.command, cmdErr)
+		}
+		var envelope outputEnvelope
+		decodeOnlyDocument(t, out, &envelope)
+		if envelope.Command != c.command || envelope.Data == nil {
+			t.Errorf("%s --json: unexpected envelope %s", c.command, out)
+		}
+	}
+
+	// A failed command reports only the error envelope
+	outputCommand = "use"
+	out := captureStdout(t, func() {
+		log.SetOutput(os.Stdout, os.Stderr)
+		setupLogOutput()
+		if err := executeCommand(manager, "use", []string{"1.99.0"}); err == nil {
+			t.Error("use of a version that is not installed succeeded")
+		} else {
+			reportError(err)
+		}
+	})
+	var envelope outputEnvelope
+	decodeOnlyDocument(t, out, &envelope)
+	if envelope.Error == nil || envelope.Data != nil {
+		t.Errorf("use --json: expected an error envelope, got %s", out)
+	}
+}
+
+// TestQuietKeepsResults checks that --quiet hides status lines but not what
+// the command was run for
+func TestQuietKeepsResults(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.InstallDir = filepath.Join(root, "versions")
+	cfg.DownloadDir = filepath.Join(root, "downloads")
+	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
+
+	versionDir := filepath.Join(cfg.InstallDir, "go1.21.0")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	metadata := "version=go1.21.0\nos=" + runtime.GOOS + "\narch=" + runtime.GOARCH + "\ninstalled_at=2023-01-01T00:00:00Z\ninstall_dir=" + versionDir + "\n"
+	if err := os.WriteFile(filepath.Join(versionDir, ".gopher-metadata"), []byte(metadata), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	savedLevel, savedInteractive, savedFormat := log.GetLevel(), *noInteractive, *format
+	defer func() {
+		log.SetLevel(savedLevel)
+		*noInteractive, *format = savedInteractive, savedFormat
+	}()
+	log.SetLevel(log.LevelError)
+	*noInteractive, *format = true, "table"
+
+	tests := []struct {
+		name string
+		run  func() error
+		want string
+	}{
+		{"list", func() error { return listInstalled(manager) }, "go1.21.0"},
+		{"env", func() error { return showEnvForVersion("1.21.0", manager) }, "GOROOT=" + versionDir},
+		{"env list", func() error { return listConfigOptions(manager) }, "Install Directory: " + cfg.InstallDir},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := captureStdout(t, func() {
+				if err := tt.run(); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if !strings.Contains(string(out), tt.want) {
+				t.Errorf("--quiet output %q does not contain %q", out, tt.want)
+			}
+		})
+	}
+}
+
+// TestMergeUnresolved checks that specs that failed to resolve keep their
+// place among the installed versions
+func TestMergeUnresolved(t *testing.T) {
+	// install bad1 1.21.0 bad2 1.21.0 1.22.0 bad3, where 1.21.0 is given twice
+	results := []inruntime.InstallResult{{Version: "go1.21.0"}, {Version: "go1.22.0"}}
+	unresolved := []unresolvedSpec{
+		{before: 0, result: inruntime.InstallResult{Version: "bad1"}},
+		{before: 1, result: inruntime.InstallResult{Version: "bad2"}},
+		{before: 2, result: inruntime.InstallResult{Version: "bad3"}},
+	}
+
+	var got []string
+	for _, result := range mergeUnresolved(results, unresolved) {
+		got = append(got, result.Version)
+	}
+	want := "bad1 go1.21.0 bad2 go1.22.0 bad3"
+	if strings.Join(got, " ") != want {
+		t.Errorf("mergeUnresolved order = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+// migrateData moves installed versions, downloads, aliases and state to the
+// root given with --to and updates the configuration, or shows what it would
+// move with --dry-run
+func migrateData(manager *inruntime.Manager, args []string) error {
+	if len(args) > 0 {
+		return errors.Newf(errors.ErrCodeInvalidArgument, "migrate takes no arguments (use --to <dir>)")
+	}
+	if *migrateTo == "" {
+		return errors.New(errors.ErrCodeMissingArgument, "migrate requires --to <dir>")
+	}
+	root, err := filepath.Abs(*migrateTo)
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeInvalidArgument, "invalid migration target %s", *migrateTo)
+	}
+
+	if *dryRun {
+		plan, err := manager.PlanMigration(root)
+		if err != nil {
+			return err
+		}
+		if *jsonOutput || *format == "yaml" {
+			return outputStructured(plan)
+		}
+		fmt.Printf("Dry run for migrating to %s (nothing will be changed):\n", plan.Root)
+		printMigrationPlan(plan)
+		return nil
+	}
+
+	configPath := getConfigPath()
+	plan, err := manager.Migrate(root, func() error {
+		if err := manager.GetConfig().Save(configPath); err != nil {
+			return errors.NewConfigSaveFailed(configPath, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput || *format == "yaml" {
+		return outputStructured(plan)
+	}
+	fmt.Printf("✓ Migrated gopher data to %s\n", plan.Root)
+	printMigrationPlan(plan)
+	fmt.Println()
+	fmt.Println("Run 'gopher setup' to regenerate the shell integration for the new directories.")
+	return nil
+}
+
+// printMigrationPlan prints the moves, config changes and symlink updates of
+// a migration
+func printMigrationPlan(plan *inruntime.MigrationPlan) {
+	if len(plan.Moves) == 0 {
+		fmt.Println("  Nothing to move")
+	}
+	for _, move := range plan.Moves {
+		fmt.Printf("  Move %s -> %s\n", move.From, move.To)
+	}
+	fmt.Printf("  Set install_dir=%s\n", plan.InstallDir)
+	fmt.Printf("  Set download_dir=%s\n", plan.DownloadDir)
+	for _, link := range plan.Symlinks {
+		fmt.Printf("  Repoint %s -> %s\n", link.Path, link.Target)
+	}
+}
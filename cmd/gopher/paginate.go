@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/molmedoz/gopher/internal/errors"
+)
+
+// pageFlag is the value of --page: a page number, or "all" to print every
+// version at once
+type pageFlag struct {
+	number int
+	all    bool
+}
+
+// pageFlagVar defines a --page style flag with the given default page
+func pageFlagVar(name string, value int, usage string) *pageFlag {
+	p := &pageFlag{number: value}
+	flag.Var(p, name, usage)
+	return p
+}
+
+func (p *pageFlag) String() string {
+	if p.all {
+		return "all"
+	}
+	return strconv.Itoa(p.number)
+}
+
+func (p *pageFlag) Set(value string) error {
+	if value == "all" {
+		p.all = true
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("must be a page number or 'all'")
+	}
+	p.number, p.all = n, false
+	return nil
+}
+
+// applyPageAll turns --page all and --page-size 0 into one page holding all
+// total versions, printed without interactive pagination
+func applyPageAll(total int) error {
+	if *pageSize < 0 {
+		return errors.Newf(errors.ErrCodeInvalidArgument, "--page-size must be a positive number, or 0 for all versions (got %d)", *pageSize)
+	}
+	if !page.all && *pageSize != 0 {
+		return nil
+	}
+	page.number = 1
+	*pageSize = max(total, 1)
+	*noInteractive = true
+	return nil
+}
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[2J\033[H"
+
+// paginateTerminal runs paginate on stdin and stdout. When stdin is a
+// terminal it is switched to raw mode so the arrow keys page directly;
+// otherwise commands are read a line at a time.
+func paginateTerminal[T any](items []T, pageSize, startPage int, title string, render func(item T, index int) string, match func(item T, query string) bool) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return paginate(newLineCommandReader(os.Stdin), os.Stdout, items, pageSize, startPage, title, render, match)
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		// Fall back to line input rather than failing the listing
+		return paginate(newLineCommandReader(os.Stdin), os.Stdout, items, pageSize, startPage, title, render, match)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	// Raw mode turns off output processing, so newlines need a carriage return
+	out := crlfWriter{os.Stdout}
+	return paginate(newKeyCommandReader(os.Stdin, out), out, items, pageSize, startPage, title, render, match)
+}
+
+// paginate shows items one page at a time and reads navigation commands from
+// commands until the user quits or the input is exhausted. title heads every
+// page and render formats one item given its 0-based index in items. When
+// match is not nil, "/" searches the items and jumps to the page holding the
+// first one that matches the query.
+//
+// Commands:
+//
+//	n, next, →, <Enter>   next page
+//	p, prev, ←            previous page
+//	<num>, g <num>        go to a page
+//	/<text>               jump to the first match (when match is set)
+//	h, help               show the commands
+//	q, quit, exit         quit
+func paginate[T any](commands commandReader, out io.Writer, items []T, pageSize, startPage int, title string, render func(item T, index int) string, match func(item T, query string) bool) error {
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	totalPages := (len(items) + pageSize - 1) / pageSize
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	currentPage := min(max(startPage, 1), totalPages)
+
+	notice := ""
+	showHelp := false
+
+	for {
+		startIndex := (currentPage - 1) * pageSize
+		endIndex := min(startIndex+pageSize, len(items))
+
+		fmt.Fprint(out, clearScreen)
+		fmt.Fprintf(out, "%s (page %d of %d, showing %d of %d total):\n\n",
+			title, currentPage, totalPages, endIndex-startIndex, len(items))
+		for i := startIndex; i < endIndex; i++ {
+			fmt.Fprintln(out, render(items[i], i))
+		}
+
+		fmt.Fprintln(out)
+		if showHelp {
+			fmt.Fprintln(out, "Navigation help:")
+			fmt.Fprintln(out, "  n, next, →, <Enter>  - Next page")
+			fmt.Fprintln(out, "  p, prev, ←           - Previous page")
+			fmt.Fprintf(out, "  <num>, g <num>       - Go to page (1-%d)\n", totalPages)
+			if match != nil {
+				fmt.Fprintln(out, "  /<text>              - Jump to the first match for text")
+			}
+			fmt.Fprintln(out, "  h, help              - Show this help")
+			fmt.Fprintln(out, "  q, quit, exit        - Quit")
+			fmt.Fprintln(out)
+			showHelp = false
+		}
+		if notice != "" {
+			fmt.Fprintln(out, notice)
+			notice = ""
+		}
+		if match != nil {
+			fmt.Fprint(out, "n: next, p: prev, <num>: go to page, /: search, h: help, q: quit > ")
+		} else {
+			fmt.Fprint(out, "n: next, p: prev, <num>: go to page, h: help, q: quit > ")
+		}
+
+		line, err := commands.ReadCommand()
+		if err == nil && match != nil && strings.TrimSpace(line) == "/" {
+			fmt.Fprint(out, "Search: ")
+			line, err = commands.ReadCommand()
+			line = "/" + line
+		}
+		if err != nil {
+			fmt.Fprintln(out)
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if query, ok := strings.CutPrefix(strings.TrimSpace(line), "/"); ok && match != nil {
+			query = strings.TrimSpace(query)
+			if query == "" {
+				continue
+			}
+			if index := firstMatch(items, query, match); index >= 0 {
+				currentPage = index/pageSize + 1
+				notice = fmt.Sprintf("Found %q on page %d.", query, currentPage)
+			} else {
+				notice = fmt.Sprintf("No match for %q.", query)
+			}
+			continue
+		}
+
+		command, arg, _ := strings.Cut(strings.ToLower(strings.TrimSpace(line)), " ")
+		switch command {
+		case "", "n", "next":
+			if currentPage < totalPages {
+				currentPage++
+			} else {
+				notice = "Already on the last page."
+			}
+		case "p", "prev", "previous":
+			if currentPage > 1 {
+				currentPage--
+			} else {
+				notice = "Already on the first page."
+			}
+		case "h", "help":
+			showHelp = true
+		case "q", "quit", "exit":
+			return nil
+		default:
+			pageArg := command
+			if command == "g" || command == "goto" {
+				pageArg = strings.TrimSpace(arg)
+			}
+			pageNum, err := strconv.Atoi(pageArg)
+			switch {
+			case err != nil:
+				notice = fmt.Sprintf("Unknown command %q. Type 'h' for help.", command)
+			case pageNum < 1 || pageNum > totalPages:
+				notice = fmt.Sprintf("Page number must be between 1 and %d.", totalPages)
+			default:
+				currentPage = pageNum
+			}
+		}
+	}
+}
+
+// firstMatch returns the index of the first item matching query, or -1
+func firstMatch[T any](items []T, query string, match func(item T, query string) bool) int {
+	for i, item := range items {
+		if match(item, query) {
+			return i
+		}
+	}
+	return -1
+}
+
+// commandReader reads the navigation commands typed at the pagination prompt
+type commandReader interface {
+	// ReadCommand returns the next command, or io.EOF when input ends
+	ReadCommand() (string, error)
+}
+
+// lineCommandReader reads one command per line, for piped input and other
+// non-terminal stdin
+type lineCommandReader struct {
+	scanner *bufio.Scanner
+}
+
+func newLineCommandReader(in io.Reader) *lineCommandReader {
+	return &lineCommandReader{scanner: bufio.NewScanner(in)}
+}
+
+func (r *lineCommandReader) ReadCommand() (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// keyCommandReader reads commands from a terminal in raw mode. The Right and
+// Left arrow keys page immediately; anything else is echoed and collected
+// until Enter, so the letter and page-number commands keep working.
+type keyCommandReader struct {
+	in   *bufio.Reader
+	echo io.Writer
+}
+
+func newKeyCommandReader(in io.Reader, echo io.Writer) *keyCommandReader {
+	return &keyCommandReader{in: bufio.NewReader(in), echo: echo}
+}
+
+func (r *keyCommandReader) ReadCommand() (string, error) {
+	var line []byte
+	for {
+		b, err := r.in.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				return string(line), nil
+			}
+			return "", err
+		}
+
+		switch b {
+		case '\x1b':
+			switch r.readEscapeSequence() {
+			case 'C':
+				return "next", nil
+			case 'D':
+				return "prev", nil
+			}
+		case '\r', '\n':
+			fmt.Fprint(r.echo, "\n")
+			return string(line), nil
+		case 0x7f, '\b':
+			if len(line) > 0 {
+				_, size := utf8.DecodeLastRune(line)
+				line = line[:len(line)-size]
+				fmt.Fprint(r.echo, "\b \b")
+			}
+		case 0x03: // Ctrl-C
+			return "quit", nil
+		case 0x04: // Ctrl-D
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+		default:
+			if b >= ' ' {
+				line = append(line, b)
+				_, _ = r.echo.Write([]byte{b})
+			}
+		}
+	}
+}
+
+// readEscapeSequence consumes the rest of an escape sequence after ESC and
+// returns its final byte. Arrow keys arrive as "ESC [ C" or, in application
+// cursor mode, "ESC O C"; modified keys carry parameters ("ESC [ 1 ; 5 C").
+func (r *keyCommandReader) readEscapeSequence() byte {
+	b, err := r.in.ReadByte()
+	if err != nil || (b != '[' && b != 'O') {
+		return 0
+	}
+	for {
+		b, err = r.in.ReadByte()
+		if err != nil {
+			return 0
+		}
+		if b >= 0x40 && b <= 0x7e {
+			return b
+		}
+	}
+}
+
+// crlfWriter translates "\n" to "\r\n" for a terminal in raw mode
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
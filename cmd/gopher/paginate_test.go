@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// runPaginate drives paginate over items 1..n with a scripted input and
+// returns the page headers it printed, in order
+func runPaginate(t *testing.T, n, pageSize, startPage int, input string) (headers []string, output string) {
+	t.Helper()
+
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i + 1
+	}
+
+	var out bytes.Buffer
+	err := paginate(newLineCommandReader(strings.NewReader(input)), &out, items, pageSize, startPage, "Items",
+		func(item, _ int) string { return fmt.Sprintf("item %d", item) }, nil)
+	if err != nil {
+		t.Fatalf("paginate() error = %v", err)
+	}
+
+	for _, line := range strings.Split(out.String(), "\n") {
+		if idx := strings.Index(line, "Items (page "); idx >= 0 {
+			headers = append(headers, line[idx:])
+		}
+	}
+	return headers, out.String()
+}
+
+func TestPaginate_Navigation(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		startPage int
+		wantPages []int
+	}{
+		{"quit immediately", "q\n", 1, []int{1}},
+		{"enter goes to next page", "\n\nq\n", 1, []int{1, 2, 3}},
+		{"next and previous", "n\nnext\np\nprev\nq\n", 1, []int{1, 2, 3, 2, 1}},
+		{"goto page", "g 3\nq\n", 1, []int{1, 3}},
+		{"bare page number", "2\nquit\n", 1, []int{1, 2}},
+		{"start page is honoured", "p\nexit\n", 3, []int{3, 2}},
+		{"start page is clamped", "q\n", 9, []int{3}},
+		{"stays on last page", "n\nq\n", 3, []int{3, 3}},
+		{"stays on first page", "p\nq\n", 1, []int{1, 1}},
+		{"end of input stops", "n\n", 1, []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers, _ := runPaginate(t, 5, 2, tt.startPage, tt.input)
+			if len(headers) != len(tt.wantPages) {
+				t.Fatalf("got %d pages %v, want %v", len(headers), headers, tt.wantPages)
+			}
+			for i, want := range tt.wantPages {
+				prefix := fmt.Sprintf("Items (page %d of 3,", want)
+				if !strings.HasPrefix(headers[i], prefix) {
+					t.Errorf("page %d header = %q, want prefix %q", i, headers[i], prefix)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginate_Rendering(t *testing.T) {
+	_, output := runPaginate(t, 5, 2, 3, "q\n")
+
+	if !strings.Contains(output, "Items (page 3 of 3, showing 1 of 5 total):") {
+		t.Errorf("missing last page header in %q", output)
+	}
+	if !strings.Contains(output, "item 5") || strings.Contains(output, "item 4") {
+		t.Errorf("last page should only show item 5, got %q", output)
+	}
+}
+
+func TestPaginate_Notices(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"unknown command", "x\nq\n", `Unknown command "x"`},
+		{"page out of range", "g 7\nq\n", "Page number must be between 1 and 3."},
+		{"last page", "3\nn\nq\n", "Already on the last page."},
+		{"first page", "p\nq\n", "Already on the first page."},
+		{"help", "h\nq\n", "Navigation help:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output := runPaginate(t, 5, 2, 1, tt.input)
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestPaginate_Empty(t *testing.T) {
+	headers, _ := runPaginate(t, 0, 10, 1, "n\nq\n")
+	if len(headers) != 2 || !strings.HasPrefix(headers[0], "Items (page 1 of 1, showing 0 of 0 total)") {
+		t.Errorf("unexpected headers for empty list: %v", headers)
+	}
+}
+
+func TestKeyCommandReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"right and left arrows", "\x1b[C\x1b[D", []string{"next", "prev"}},
+		{"application cursor mode", "\x1bOC\x1bOD", []string{"next", "prev"}},
+		{"modified arrow", "\x1b[1;5C", []string{"next"}},
+		{"other keys ignored", "\x1b[A\x1b[Bq\r", []string{"q"}},
+		{"typed commands", "g 2\r3\n", []string{"g 2", "3"}},
+		{"enter alone", "\r", []string{""}},
+		{"backspace", "x\x7fn\r", []string{"n"}},
+		{"ctrl-c quits", "1\x03", []string{"quit"}},
+		{"unterminated input", "q", []string{"q"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newKeyCommandReader(strings.NewReader(tt.input), io.Discard)
+			var got []string
+			for {
+				command, err := reader.ReadCommand()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("ReadCommand() error = %v", err)
+				}
+				got = append(got, command)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commands = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginate_ArrowKeys(t *testing.T) {
+	var out bytes.Buffer
+	reader := newKeyCommandReader(strings.NewReader("\x1b[C\x1b[C\x1b[Dq\r"), &out)
+	err := paginate(reader, &out, []int{1, 2, 3, 4, 5}, 2, 1, "Items",
+		func(item, _ int) string { return fmt.Sprintf("item %d", item) }, nil)
+	if err != nil {
+		t.Fatalf("paginate() error = %v", err)
+	}
+
+	var pages []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if idx := strings.Index(line, "Items (page "); idx >= 0 {
+			pages = append(pages, line[idx+len("Items (page "):idx+len("Items (page ")+1])
+		}
+	}
+	if want := []string{"1", "2", "3", "2"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("visited pages %v, want %v", pages, want)
+	}
+}
+
+func TestCRLFWriter(t *testing.T) {
+	var out bytes.Buffer
+	n, err := crlfWriter{&out}.Write([]byte("a\nb\n"))
+	if err != nil || n != 4 {
+		t.Fatalf("Write() = %d, %v", n, err)
+	}
+	if got := out.String(); got != "a\r\nb\r\n" {
+		t.Errorf("output = %q, want %q", got, "a\r\nb\r\n")
+	}
+}
+
+func TestPaginate_Search(t *testing.T) {
+	versions := []string{"go1.23.0", "go1.22.5", "go1.22.0", "go1.21.13", "go1.21.0"}
+	match := func(v, query string) bool { return versionMatches(v, query) }
+
+	tests := []struct {
+		name      string
+		input     string
+		wantPages []string
+		wantText  string
+	}{
+		{"inline query", "/1.21\nq\n", []string{"1", "2"}, `Found "1.21" on page 2.`},
+		{"search prompt", "/\n1.21.0\nq\n", []string{"1", "3"}, "Search: "},
+		{"no match keeps page", "2\n/1.19\nq\n", []string{"1", "2", "2"}, `No match for "1.19".`},
+		{"case insensitive", "/GO1.22.5\nq\n", []string{"1", "1"}, `Found "GO1.22.5" on page 1.`},
+		{"empty query", "/ \nq\n", []string{"1", "1"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := paginate(newLineCommandReader(strings.NewReader(tt.input)), &out, versions, 2, 1, "Versions",
+				func(v string, _ int) string { return v }, match)
+			if err != nil {
+				t.Fatalf("paginate() error = %v", err)
+			}
+
+			var pages []string
+			for _, line := range strings.Split(out.String(), "\n") {
+				if idx := strings.Index(line, "Versions (page "); idx >= 0 {
+					pages = append(pages, line[idx+len("Versions (page "):idx+len("Versions (page ")+1])
+				}
+			}
+			if !reflect.DeepEqual(pages, tt.wantPages) {
+				t.Errorf("visited pages %v, want %v", pages, tt.wantPages)
+			}
+			if !strings.Contains(out.String(), tt.wantText) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantText, out.String())
+			}
+		})
+	}
+}
+
+func TestPaginate_SearchDisabled(t *testing.T) {
+	_, output := runPaginate(t, 5, 2, 1, "/1\nq\n")
+	if !strings.Contains(output, `Unknown command "/1"`) {
+		t.Errorf("search should be unavailable without a matcher:\n%s", output)
+	}
+}
+
+func TestPageFlag(t *testing.T) {
+	p := &pageFlag{number: 1}
+	if err := p.Set("3"); err != nil || p.number != 3 || p.all {
+		t.Fatalf("Set(3) = %v, got %+v", err, p)
+	}
+	if err := p.Set("all"); err != nil || !p.all || p.String() != "all" {
+		t.Fatalf("Set(all) = %v, got %+v", err, p)
+	}
+	if err := p.Set("two"); err == nil {
+		t.Error("expected error for a non-numeric page")
+	}
+}
+
+func TestApplyPageAll(t *testing.T) {
+	savedPage, savedSize, savedInteractive := *page, *pageSize, *noInteractive
+	defer func() { *page, *pageSize, *noInteractive = savedPage, savedSize, savedInteractive }()
+
+	tests := []struct {
+		name      string
+		page      pageFlag
+		pageSize  int
+		wantSize  int
+		wantPaged bool
+		wantErr   bool
+	}{
+		{"paged", pageFlag{number: 2}, 10, 10, true, false},
+		{"page all", pageFlag{number: 2, all: true}, 10, 25, false, false},
+		{"page size 0", pageFlag{number: 1}, 0, 25, false, false},
+		{"negative page size", pageFlag{number: 1}, -1, -1, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*page, *pageSize, *noInteractive = tt.page, tt.pageSize, false
+			err := applyPageAll(25)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyPageAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *pageSize != tt.wantSize {
+				t.Errorf("page size = %d, want %d", *pageSize, tt.wantSize)
+			}
+			if *noInteractive == tt.wantPaged {
+				t.Errorf("noInteractive = %v, want %v", *noInteractive, !tt.wantPaged)
+			}
+			if !tt.wantPaged && page.number != 1 {
+				t.Errorf("page = %d, want 1", page.number)
+			}
+		})
+	}
+}
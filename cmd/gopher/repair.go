@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+// repairSymlinks removes or repoints gopher symlinks whose version no longer
+// exists, or shows what it would change with --dry-run
+func repairSymlinks(manager *inruntime.Manager, args []string) error {
+	if len(args) > 0 {
+		return errors.Newf(errors.ErrCodeInvalidArgument, "repair takes no arguments")
+	}
+
+	var repairs []inruntime.SymlinkRepair
+	if *dryRun {
+		repairs = manager.PlanSymlinkRepair()
+	} else {
+		repairs = manager.RepairSymlinks()
+	}
+
+	failed := 0
+	for _, repair := range repairs {
+		if repair.Error != "" {
+			failed++
+		}
+	}
+
+	if *jsonOutput || *format == "yaml" {
+		if err := outputStructured(repairs); err != nil {
+			return err
+		}
+	} else {
+		printSymlinkRepairs(repairs)
+	}
+
+	if failed > 0 {
+		return errors.Newf(errors.ErrCodeSymlinkFailed, "failed to repair %d of %d symlinks", failed, len(repairs))
+	}
+	return nil
+}
+
+// printSymlinkRepairs prints what repairSymlinks changed or would change
+func printSymlinkRepairs(repairs []inruntime.SymlinkRepair) {
+	if len(repairs) == 0 {
+		fmt.Println("✓ No broken gopher symlinks found")
+		return
+	}
+
+	if *dryRun {
+		fmt.Printf("Found %d broken gopher symlinks (nothing will be changed):\n", len(repairs))
+	}
+	for _, repair := range repairs {
+		verb := "Removed"
+		if repair.Action == inruntime.SymlinkRepairRepoint {
+			verb = "Repointed"
+		}
+		if *dryRun {
+			verb = "Would remove"
+			if repair.Action == inruntime.SymlinkRepairRepoint {
+				verb = "Would repoint"
+			}
+		}
+
+		line := fmt.Sprintf("%s %s (was %s)", verb, repair.Path, repair.Target)
+		if repair.NewTarget != "" {
+			line = fmt.Sprintf("%s %s -> %s (was %s)", verb, repair.Path, repair.NewTarget, repair.Target)
+		}
+		if repair.Error != "" {
+			fmt.Printf("  ✗ %s: %s\n", line, repair.Error)
+		} else {
+			fmt.Printf("  ✓ %s\n", line)
+		}
+	}
+}
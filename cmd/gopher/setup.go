@@ -10,11 +10,16 @@ import (
 	"strings"
 
 	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/errors"
 	inruntime "github.com/molmedoz/gopher/internal/runtime"
 )
 
 // runInteractiveSetup runs the interactive setup wizard
 func runInteractiveSetup(manager *inruntime.Manager) error {
+	if *jsonOutput {
+		return outputSetupReport(manager, true)
+	}
+
 	fmt.Println("🚀 Welcome to Gopher Setup Wizard!")
 	fmt.Println("This wizard will help you configure Gopher for your system.")
 	fmt.Println()
@@ -38,12 +43,15 @@ func runInteractiveSetup(manager *inruntime.Manager) error {
 		return fmt.Errorf("shell integration setup failed: %w", err)
 	}
 
-	// Step 5: Test and verify setup
+	// Step 5: Check that Go versions can be downloaded
+	checkMirror(manager)
+
+	// Step 6: Test and verify setup
 	if err := testAndVerifySetup(manager, systemInfo); err != nil {
 		return fmt.Errorf("setup verification failed: %w", err)
 	}
 
-	// Step 6: Show completion and next steps
+	// Step 7: Show completion and next steps
 	showSetupCompletion(systemInfo)
 
 	return nil
@@ -389,6 +397,68 @@ func detectPackageManager() string {
 	return ""
 }
 
+// GopherPaths holds the locations gopher reads and writes
+type GopherPaths struct {
+	InstallDir  string `json:"install_dir"`
+	DownloadDir string `json:"download_dir"`
+	StateDir    string `json:"state_dir"`
+	StateFile   string `json:"state_file"`
+	ScriptsDir  string `json:"scripts_dir"`
+	InitScript  string `json:"init_script"`
+	AliasesFile string `json:"aliases_file"`
+	ConfigPath  string `json:"config_path"`
+	SymlinkDir  string `json:"symlink_dir"`
+}
+
+// getGopherPaths derives gopher's directory layout from the configuration.
+// State, scripts and aliases live next to the install directory
+// (e.g. ~/.gopher/state when versions are in ~/.gopher/versions).
+func getGopherPaths(manager *inruntime.Manager) (*GopherPaths, error) {
+	cfg := manager.GetConfig()
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	root := filepath.Dir(filepath.Clean(cfg.InstallDir))
+	stateDir := filepath.Join(root, "state")
+	scriptsDir := filepath.Join(root, "scripts")
+
+	return &GopherPaths{
+		InstallDir:  cfg.InstallDir,
+		DownloadDir: cfg.DownloadDir,
+		StateDir:    stateDir,
+		StateFile:   filepath.Join(stateDir, inruntime.StateFileName),
+		ScriptsDir:  scriptsDir,
+		InitScript:  filepath.Join(scriptsDir, "gopher-init.sh"),
+		AliasesFile: filepath.Join(root, "aliases.json"),
+		ConfigPath:  getConfigPath(),
+		SymlinkDir:  configuredSymlinkDir(cfg, homeDir),
+	}, nil
+}
+
+// configuredSymlinkDir returns symlink_dir when it is set, otherwise the
+// platform's standard symlink directory
+func configuredSymlinkDir(cfg *config.Config, homeDir string) string {
+	if cfg.SymlinkDir != "" {
+		return cfg.SymlinkDir
+	}
+	return getSymlinkDir(homeDir)
+}
+
+// getSymlinkDir returns the directory where the go symlink is created
+func getSymlinkDir(homeDir string) string {
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(homeDir, "AppData", "Local", "bin")
+	case "darwin", "linux":
+		return filepath.Join(homeDir, ".local", "bin")
+	default:
+		return filepath.Join(homeDir, "bin")
+	}
+}
+
 // SystemInfo holds detected system information
 type SystemInfo struct {
 	Platform         string
@@ -469,14 +539,7 @@ func detectSystemInfo(manager *inruntime.Manager) (*SystemInfo, error) {
 	}
 
 	// Determine symlink directory
-	switch info.Platform {
-	case "windows":
-		info.SymlinkDir = filepath.Join(info.HomeDir, "AppData", "Local", "bin")
-	case "darwin", "linux":
-		info.SymlinkDir = filepath.Join(info.HomeDir, ".local", "bin")
-	default:
-		info.SymlinkDir = filepath.Join(info.HomeDir, "bin")
-	}
+	info.SymlinkDir = configuredSymlinkDir(manager.GetConfig(), info.HomeDir)
 
 	// Check if symlink directory is in PATH
 	info.IsInPath = isDirectoryInPath(info.SymlinkDir)
@@ -589,7 +652,7 @@ func runInteractiveConfiguration(manager *inruntime.Manager, info *SystemInfo) e
 			fmt.Println("   Then restart your terminal.")
 		} else {
 			// Unix/Linux/macOS: Try to add to shell profile
-			if err := addDirectoryToPath(info.SymlinkDir, info.ShellProfile); err != nil {
+			if _, err := addDirectoryToPath(info.SymlinkDir, info.ShellProfile); err != nil {
 				fmt.Printf("   ❌ Failed to add to PATH: %v\n", err)
 				fmt.Printf("   Please manually add this to your %s:\n", info.ShellProfile)
 				fmt.Printf("   export PATH=\"%s:$PATH\"\n", info.SymlinkDir)
@@ -607,7 +670,7 @@ func runInteractiveConfiguration(manager *inruntime.Manager, info *SystemInfo) e
 		fmt.Println("   - Turn on 'Developer Mode'")
 		fmt.Println("   - Restart your terminal after enabling")
 
-		if !askForConfirmation("Have you enabled Developer Mode?") {
+		if setupNonInteractive() || !askForConfirmation("Have you enabled Developer Mode?") {
 			fmt.Println("   ⚠️  Please enable Developer Mode and run 'gopher init' again")
 		}
 	}
@@ -663,33 +726,25 @@ func testAndVerifySetup(manager *inruntime.Manager, info *SystemInfo) error {
 	fmt.Println("\n🧪 Testing Setup")
 	fmt.Println("================")
 
-	// Test 1: Check if gopher command works
-	fmt.Print("1. Testing gopher command... ")
-	if isCommandInPath("gopher") {
-		fmt.Println("✅")
-	} else {
-		fmt.Println("❌")
-		fmt.Println("   Please add gopher to your PATH or use full path")
-	}
-
-	// Test 2: Test symlink creation
-	fmt.Print("2. Testing symlink creation... ")
-	if err := testSymlinkCreation(info.SymlinkDir); err != nil {
-		fmt.Printf("❌ (%v)\n", err)
-		if info.Platform == "windows" && !info.HasDeveloperMode {
-			fmt.Println("   Enable Developer Mode or run as Administrator")
-		}
-	} else {
-		fmt.Println("✅")
-	}
-
-	// Test 3: Test shell integration (Unix only)
-	if runtime.GOOS != "windows" {
-		fmt.Print("3. Testing shell integration... ")
-		if isGopherConfigured(info.ShellProfile) {
+	for i, check := range runSetupChecks(info) {
+		fmt.Printf("%d. Testing %s... ", i+1, strings.ReplaceAll(check.Name, "_", " "))
+		if check.OK {
 			fmt.Println("✅")
+			continue
+		}
+		if check.Error != "" {
+			fmt.Printf("❌ (%s)\n", check.Error)
 		} else {
 			fmt.Println("❌")
+		}
+		switch check.Name {
+		case "gopher_command":
+			fmt.Println("   Please add gopher to your PATH or use full path")
+		case "symlink_creation":
+			if info.Platform == "windows" && !info.HasDeveloperMode {
+				fmt.Println("   Enable Developer Mode or run as Administrator")
+			}
+		case "shell_integration":
 			fmt.Printf("   Please run: source %s\n", info.ShellProfile)
 		}
 	}
@@ -697,6 +752,73 @@ func testAndVerifySetup(manager *inruntime.Manager, info *SystemInfo) error {
 	return nil
 }
 
+// SetupCheck is the result of one check made by setup and init
+type SetupCheck struct {
+	Name    string `json:"name"`              // gopher_command, symlink_creation, shell_integration or mirror
+	OK      bool   `json:"ok"`                // Whether the check passed
+	Skipped bool   `json:"skipped,omitempty"` // Not run, e.g. the mirror check in offline mode
+	Error   string `json:"error,omitempty"`   // Why the check failed, when known
+}
+
+// runSetupChecks checks that gopher is usable after setup: the gopher
+// command is in PATH, symlinks can be created in the symlink directory and,
+// except on Windows, the shell profile loads gopher
+func runSetupChecks(info *SystemInfo) []SetupCheck {
+	checks := []SetupCheck{{Name: "gopher_command", OK: isCommandInPath("gopher")}}
+
+	symlink := SetupCheck{Name: "symlink_creation", OK: true}
+	if err := testSymlinkCreation(info.SymlinkDir); err != nil {
+		symlink.OK, symlink.Error = false, err.Error()
+	}
+	checks = append(checks, symlink)
+
+	if runtime.GOOS != "windows" {
+		checks = append(checks, SetupCheck{Name: "shell_integration", OK: isGopherConfigured(info.ShellProfile)})
+	}
+	return checks
+}
+
+// checkMirror reports whether the download mirror can be reached, so that a
+// firewall or proxy problem shows up now instead of as a failed install
+func checkMirror(manager *inruntime.Manager) {
+	mirror := manager.GetConfig().MirrorURL
+	fmt.Println("\n🌐 Checking Download Mirror")
+	fmt.Println("==========================")
+	fmt.Printf("Mirror: %s\n", mirror)
+
+	check := runMirrorCheck(manager)
+	switch {
+	case check.Skipped:
+		fmt.Println("⏭️  Skipped (offline mode)")
+	case !check.OK:
+		fmt.Printf("❌ Unreachable: %s\n", check.Error)
+		fmt.Println("   Installing Go versions will fail until the mirror can be reached.")
+		fmt.Println("   Behind a proxy, set HTTPS_PROXY (and HTTP_PROXY) before running gopher.")
+		fmt.Println("   Or use a mirror you can reach: gopher env set mirror_url=<url>")
+	default:
+		fmt.Println("✅ Reachable")
+	}
+}
+
+// runMirrorCheck checks that the download mirror can be reached; it is
+// skipped in offline mode
+func runMirrorCheck(manager *inruntime.Manager) SetupCheck {
+	check := SetupCheck{Name: "mirror"}
+	if offlineMode() {
+		check.Skipped = true
+		return check
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+	if err := manager.CheckMirror(ctx); err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	check.OK = true
+	return check
+}
+
 // showSetupCompletion shows setup completion and next steps
 func showSetupCompletion(info *SystemInfo) {
 	fmt.Println("\n🎉 Setup Complete!")
@@ -717,8 +839,9 @@ func showSetupCompletion(info *SystemInfo) {
 	}
 
 	if info.IsDocker {
+		scriptsDir := filepath.Join(filepath.Dir(config.DefaultConfig().InstallDir), "scripts")
 		fmt.Println("\n🐳 Docker Environment:")
-		fmt.Println("- Run 'source ~/.gopher/scripts/gopher-init.sh' in each session")
+		fmt.Printf("- Run 'source %s' in each session\n", filepath.Join(scriptsDir, "gopher-init.sh"))
 		fmt.Println("- Or add it to your shell profile manually")
 	}
 
@@ -849,10 +972,12 @@ func showLinuxNextSteps(info *SystemInfo) {
 	fmt.Println("  • Use 'gopher setup' for persistent shell integration")
 	fmt.Println("  • Use 'gopher system' to switch back to system Go")
 	fmt.Println()
+	// The directories follow the XDG variables when they are set
+	cfg := config.DefaultConfig()
 	fmt.Println("📁 Directories created:")
-	fmt.Printf("  Config:    %s/.gopher/config.json\n", info.HomeDir)
-	fmt.Printf("  Versions:  %s/.gopher/versions\n", info.HomeDir)
-	fmt.Printf("  Downloads: %s/.gopher/downloads\n", info.HomeDir)
+	fmt.Printf("  Config:    %s\n", config.GetConfigPath())
+	fmt.Printf("  Versions:  %s\n", cfg.InstallDir)
+	fmt.Printf("  Downloads: %s\n", cfg.DownloadDir)
 	fmt.Printf("  Symlinks:  %s\n", info.SymlinkDir)
 }
 
@@ -864,21 +989,125 @@ func showGenericNextSteps(info *SystemInfo) {
 	fmt.Println("3. Verify: go version")
 }
 
+// SetupReport is what 'setup --json' and 'init --json' configured
+type SetupReport struct {
+	Platform         string       `json:"platform"`                    // GOOS/GOARCH
+	Shell            string       `json:"shell"`                       // Detected shell
+	ProfilePath      string       `json:"profile_path,omitempty"`      // Shell profile that loads gopher; empty on Windows
+	InitScript       string       `json:"init_script,omitempty"`       // Script the profile sources
+	ShellIntegration string       `json:"shell_integration"`           // configured, already_configured, manual (no profile; source init_script) or not_needed (Windows)
+	SymlinkDir       string       `json:"symlink_dir"`                 // Directory of the go symlink
+	PathChanges      []string     `json:"path_changes"`                // Directories added to PATH in the profile
+	PathInstructions string       `json:"path_instructions,omitempty"` // Command that adds the symlink directory to PATH, when gopher could not
+	Mirror           *SetupCheck  `json:"mirror,omitempty"`            // Download mirror check (init only)
+	Tests            []SetupCheck `json:"tests"`                       // Checks run after setup
+}
+
+// outputSetupReport runs setup without prompts and prints what it did as
+// JSON. withMirror adds the download mirror check that init makes. As this
+// edits the shell profile unasked, --non-interactive must be given too.
+func outputSetupReport(manager *inruntime.Manager, withMirror bool) error {
+	if !*nonInteractive && !*noInteractive {
+		return errors.Newf(errors.ErrCodeInvalidArgument, "%s --json changes your shell profile without asking; add --non-interactive to confirm (e.g., 'gopher %s --json --non-interactive')", outputCommand, outputCommand)
+	}
+
+	info, err := detectSystemInfo(manager)
+	if err != nil {
+		return fmt.Errorf("failed to detect system info: %w", err)
+	}
+	report, err := applySetup(manager, info)
+	if err != nil {
+		return err
+	}
+	if withMirror {
+		mirror := runMirrorCheck(manager)
+		report.Mirror = &mirror
+	}
+	return outputJSON(report)
+}
+
+// applySetup sets up shell integration and PATH with the defaults the
+// wizards apply, without prompting or printing, and runs the setup checks.
+// On Windows nothing is changed; PATH instructions are returned instead.
+func applySetup(manager *inruntime.Manager, info *SystemInfo) (*SetupReport, error) {
+	report := &SetupReport{
+		Platform:    info.Platform + "/" + info.Arch,
+		Shell:       info.Shell,
+		SymlinkDir:  info.SymlinkDir,
+		PathChanges: []string{},
+	}
+
+	if runtime.GOOS == "windows" {
+		report.ShellIntegration = "not_needed"
+		if !info.IsInPath {
+			report.PathInstructions = fmt.Sprintf(`[Environment]::SetEnvironmentVariable("PATH", "%s;" + [Environment]::GetEnvironmentVariable("PATH", "User"), "User")`, info.SymlinkDir)
+		}
+		report.Tests = runSetupChecks(info)
+		return report, nil
+	}
+
+	// Rewriting the init script picks up the current configuration
+	initScript, err := createGopherInitScript(manager)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gopher init script: %w", err)
+	}
+	report.InitScript = initScript
+
+	// Without a known profile (an unsupported shell, or a container) the
+	// user sources the init script themselves
+	if info.ShellProfile == "" {
+		report.ShellIntegration = "manual"
+		report.Tests = runSetupChecks(info)
+		return report, nil
+	}
+
+	report.ProfilePath = info.ShellProfile
+	report.ShellIntegration = "already_configured"
+	if !isGopherConfigured(info.ShellProfile) {
+		report.ShellIntegration = "configured"
+	}
+	if err := addToShellProfile(info.ShellProfile, initScript); err != nil {
+		return nil, fmt.Errorf("failed to add to shell profile: %w", err)
+	}
+
+	if !info.IsInPath {
+		added, err := addDirectoryToPath(info.SymlinkDir, info.ShellProfile)
+		switch {
+		case err != nil:
+			report.PathInstructions = fmt.Sprintf("export PATH=\"%s:$PATH\"", info.SymlinkDir)
+		case added:
+			report.PathChanges = append(report.PathChanges, info.SymlinkDir)
+		}
+	}
+
+	report.Tests = runSetupChecks(info)
+	return report, nil
+}
+
+// setupNonInteractive reports whether setup and init answer their prompts
+// with the defaults instead of asking: with --non-interactive or
+// --no-interactive
+func setupNonInteractive() bool {
+	return *nonInteractive || *noInteractive
+}
+
 // Helper functions for the new setup system
 
-func addDirectoryToPath(dir, profilePath string) error {
+// addDirectoryToPath adds dir to PATH in the shell profile, and reports
+// whether the profile changed (false when it already adds dir)
+func addDirectoryToPath(dir, profilePath string) (bool, error) {
 	// Read current profile
 	// #nosec G304 -- profilePath is user's shell profile file (validated path)
 	content, err := os.ReadFile(profilePath)
 	if err != nil && !os.IsNotExist(err) {
-		return err
+		return false, err
 	}
 
 	profileContent := string(content)
 
 	// Check if already added
 	if strings.Contains(profileContent, dir) {
-		return nil // Already added
+		return false, nil // Already added
 	}
 
 	// Add PATH export
@@ -886,7 +1115,10 @@ func addDirectoryToPath(dir, profilePath string) error {
 
 	// Write updated profile
 	// #nosec G306 -- 0644 required for shell profile files (must be readable by shell)
-	return os.WriteFile(profilePath, []byte(profileContent+pathExport), 0644)
+	if err := os.WriteFile(profilePath, []byte(profileContent+pathExport), 0644); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 func isGopherConfigured(profilePath string) bool {
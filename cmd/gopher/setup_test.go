@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/env"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+func TestGetGopherPaths(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.InstallDir = filepath.Join(root, "versions")
+	cfg.DownloadDir = filepath.Join(root, "downloads")
+	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
+
+	paths, err := getGopherPaths(manager)
+	if err != nil {
+		t.Fatalf("getGopherPaths failed: %v", err)
+	}
+
+	want := map[string]string{
+		"install_dir":  cfg.InstallDir,
+		"download_dir": cfg.DownloadDir,
+		"state_dir":    filepath.Join(root, "state"),
+		"state_file":   filepath.Join(root, "state", "active-version"),
+		"scripts_dir":  filepath.Join(root, "scripts"),
+		"init_script":  filepath.Join(root, "scripts", "gopher-init.sh"),
+		"aliases_file": filepath.Join(root, "aliases.json"),
+	}
+	got := map[string]string{
+		"install_dir":  paths.InstallDir,
+		"download_dir": paths.DownloadDir,
+		"state_dir":    paths.StateDir,
+		"state_file":   paths.StateFile,
+		"scripts_dir":  paths.ScriptsDir,
+		"init_script":  paths.InitScript,
+		"aliases_file": paths.AliasesFile,
+	}
+	for key, w := range want {
+		if got[key] != w {
+			t.Errorf("%s = %q, want %q", key, got[key], w)
+		}
+	}
+	if paths.ConfigPath == "" || paths.SymlinkDir == "" {
+		t.Errorf("config path and symlink dir must be set, got %q and %q", paths.ConfigPath, paths.SymlinkDir)
+	}
+}
+
+func TestApplySetup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell integration is not set up on Windows")
+	}
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	if err := os.MkdirAll(home, 0750); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("SHELL", "/bin/bash")
+	t.Setenv("PATH", filepath.Join(root, "empty"))
+
+	cfg := config.DefaultConfig()
+	cfg.InstallDir = filepath.Join(root, "gopher", "versions")
+	cfg.DownloadDir = filepath.Join(root, "gopher", "downloads")
+	cfg.SymlinkDir = filepath.Join(root, "bin")
+	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
+
+	info, err := detectSystemInfo(manager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := applySetup(manager, info)
+	if err != nil {
+		t.Fatalf("applySetup failed: %v", err)
+	}
+
+	profile := filepath.Join(home, ".bashrc")
+	if report.ProfilePath != profile || report.ShellIntegration != "configured" {
+		t.Errorf("profile %q (%s), want %q (configured)", report.ProfilePath, report.ShellIntegration, profile)
+	}
+	if want := filepath.Join(root, "gopher", "scripts", "gopher-init.sh"); report.InitScript != want {
+		t.Errorf("init script = %q, want %q", report.InitScript, want)
+	}
+	if len(report.PathChanges) != 1 || report.PathChanges[0] != cfg.SymlinkDir {
+		t.Errorf("path changes = %v, want [%s]", report.PathChanges, cfg.SymlinkDir)
+	}
+	content, err := os.ReadFile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), report.InitScript) || !strings.Contains(string(content), cfg.SymlinkDir) {
+		t.Errorf("profile does not load gopher:\n%s", content)
+	}
+	checks := map[string]bool{}
+	for _, check := range report.Tests {
+		checks[check.Name] = check.OK
+	}
+	if ok, found := checks["gopher_command"]; !found || ok {
+		t.Errorf("gopher_command should fail with an empty PATH: %+v", report.Tests)
+	}
+	if !checks["symlink_creation"] || !checks["shell_integration"] {
+		t.Errorf("expected symlink and shell integration checks to pass: %+v", report.Tests)
+	}
+
+	// A second run changes nothing
+	report, err = applySetup(manager, info)
+	if err != nil {
+		t.Fatalf("applySetup failed: %v", err)
+	}
+	if report.ShellIntegration != "already_configured" || len(report.PathChanges) != 0 {
+		t.Errorf("second run reported %s with path changes %v", report.ShellIntegration, report.PathChanges)
+	}
+	again, err := os.ReadFile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(content) {
+		t.Errorf("second run rewrote the profile:\n%s", again)
+	}
+}
+
+// TestSetupJSONRequiresNonInteractive checks that --json alone does not edit
+// the shell profile
+func TestSetupJSONRequiresNonInteractive(t *testing.T) {
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	if err := os.MkdirAll(home, 0750); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("SHELL", "/bin/bash")
+
+	cfg := config.DefaultConfig()
+	cfg.InstallDir = filepath.Join(root, "gopher", "versions")
+	cfg.DownloadDir = filepath.Join(root, "gopher", "downloads")
+	cfg.SymlinkDir = filepath.Join(root, "bin")
+	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
+
+	savedJSON, savedNon, savedNo, savedCommand := *jsonOutput, *nonInteractive, *noInteractive, outputCommand
+	defer func() {
+		*jsonOutput, *nonInteractive, *noInteractive, outputCommand = savedJSON, savedNon, savedNo, savedCommand
+	}()
+	*jsonOutput, *nonInteractive, *noInteractive, outputCommand = true, false, false, "setup"
+
+	if err := setupShellIntegrationEnhanced(manager); err == nil || !strings.Contains(err.Error(), "--non-interactive") {
+		t.Fatalf("setup --json without --non-interactive = %v, want an error", err)
+	}
+	entries, err := os.ReadDir(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("setup --json wrote to the home directory: %v", entries)
+	}
+}
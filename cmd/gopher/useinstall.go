@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/log"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+// stdinIsTerminal reports whether 'use' can ask before installing a missing
+// version; tests replace it
+var stdinIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// autoInstallOnUse reports whether 'use' installs missing versions without
+// asking, because of --install or auto_install_on_use
+func autoInstallOnUse(manager *inruntime.Manager) bool {
+	return *installOnUse || manager.GetConfig().AutoInstallOnUse
+}
+
+// canInstallOnUse reports whether 'use' may install a missing version: when
+// it does so without asking, or when it can ask on a terminal. JSON output
+// is never interleaved with a prompt, and --dry-run never installs.
+func canInstallOnUse(manager *inruntime.Manager) bool {
+	if *dryRun {
+		return false
+	}
+	return autoInstallOnUse(manager) || (!*jsonOutput && stdinIsTerminal())
+}
+
+// resolveUseVersion resolves spec to the version 'use' switches to. Partial
+// versions and keywords resolve against the installed versions; when none
+// matches and the version may be installed, they resolve against the
+// releases instead, so the newest matching release can be installed.
+func resolveUseVersion(manager *inruntime.Manager, spec string) (string, error) {
+	resolved, err := resolveInstalledVersionSpec(manager, spec)
+	if err == nil || !errors.IsErrorCode(err, errors.ErrCodeVersionNotInstalled) || !canInstallOnUse(manager) {
+		return resolved, err
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+	return resolveVersionSpec(ctx, manager, spec)
+}
+
+// ensureInstalledForUse installs version before 'use' switches to it, if it
+// is missing and may be installed (see canInstallOnUse). Invalid and special
+// versions such as "system" are left for Use to handle.
+func ensureInstalledForUse(manager *inruntime.Manager, version string) error {
+	if inruntime.ValidateVersion(version) != nil || !canInstallOnUse(manager) {
+		return nil
+	}
+
+	version = inruntime.NormalizeVersion(version)
+	installed, err := manager.IsInstalled(version)
+	if err != nil || installed {
+		return nil
+	}
+
+	if !autoInstallOnUse(manager) && !askForConfirmation(fmt.Sprintf("%s is not installed, install now?", version)) {
+		return errors.NewVersionNotInstalled(version)
+	}
+
+	log.Info("Installing Go %s...", version)
+	ctx, stop := interruptContext()
+	defer stop()
+	if _, err := manager.Install(ctx, version); err != nil {
+		return errors.Wrapf(err, errors.ErrCodeInstallationFailed, "failed to install version %s", version)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/env"
+	"github.com/molmedoz/gopher/internal/errors"
+	inruntime "github.com/molmedoz/gopher/internal/runtime"
+)
+
+func TestEnsureInstalledForUse(t *testing.T) {
+	root := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.InstallDir = filepath.Join(root, "versions")
+	cfg.DownloadDir = filepath.Join(root, "downloads")
+	manager := inruntime.NewManager(cfg, &env.DefaultProvider{})
+
+	versionDir := filepath.Join(cfg.InstallDir, "go1.21.0")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	metadata := "version=go1.21.0\nos=linux\narch=amd64\ninstalled_at=2023-01-01T00:00:00Z\ninstall_dir=" + versionDir + "\n"
+	if err := os.WriteFile(filepath.Join(versionDir, ".gopher-metadata"), []byte(metadata), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	savedTerminal, savedStdin := stdinIsTerminal, os.Stdin
+	savedInstall, savedDryRun, savedJSON := *installOnUse, *dryRun, *jsonOutput
+	defer func() {
+		stdinIsTerminal, os.Stdin = savedTerminal, savedStdin
+		*installOnUse, *dryRun, *jsonOutput = savedInstall, savedDryRun, savedJSON
+	}()
+
+	tests := []struct {
+		name     string
+		version  string
+		terminal bool
+		install  bool
+		dryRun   bool
+		json     bool
+		answer   string
+		wantErr  bool
+	}{
+		{"installed version", "1.21.0", true, true, false, false, "", false},
+		{"special version left to use", "system", true, true, false, false, "", false},
+		{"no terminal and no flag", "1.23.0", false, false, false, false, "", false},
+		{"dry run never installs", "1.23.0", true, true, true, false, "", false},
+		{"json never prompts", "1.23.0", true, false, false, true, "", false},
+		{"prompt declined", "1.23.0", true, false, false, false, "n\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdinIsTerminal = func() bool { return tt.terminal }
+			*installOnUse, *dryRun, *jsonOutput = tt.install, tt.dryRun, tt.json
+
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			_, _ = w.WriteString(tt.answer)
+			w.Close()
+			os.Stdin = r
+
+			err = ensureInstalledForUse(manager, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ensureInstalledForUse(%s) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.IsErrorCode(err, errors.ErrCodeVersionNotInstalled) {
+				t.Errorf("expected VERSION_NOT_INSTALLED, got %v", err)
+			}
+		})
+	}
+}
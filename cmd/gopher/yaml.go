@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// writeYAML writes data as YAML. The value is first encoded as JSON, so it
+// uses the same field names and omitempty rules as --json, and is then
+// rewritten as block-style YAML keeping the key order of the JSON.
+func writeYAML(w io.Writer, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	node, err := decodeYAMLNode(decoder)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	writeYAMLNode(&buf, node, 0)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// yamlField is one key of a JSON object, kept in document order
+type yamlField struct {
+	key   string
+	value any
+}
+
+// decodeYAMLNode reads one JSON value, decoding objects as []yamlField so
+// their key order survives
+func decodeYAMLNode(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		fields := []yamlField{}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeYAMLNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, yamlField{key: keyToken.(string), value: value})
+		}
+		_, err := decoder.Token() // Closing brace
+		return fields, err
+	case json.Delim('['):
+		items := []any{}
+		for decoder.More() {
+			item, err := decodeYAMLNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err := decoder.Token() // Closing bracket
+		return items, err
+	default:
+		return token, nil
+	}
+}
+
+// writeYAMLNode writes a decoded value as a YAML block at the given indent
+func writeYAMLNode(buf *bytes.Buffer, node any, indent int) {
+	pad := strings.Repeat(" ", indent)
+
+	switch n := node.(type) {
+	case []yamlField:
+		if len(n) == 0 {
+			buf.WriteString(pad + "{}\n")
+			return
+		}
+		for _, field := range n {
+			buf.WriteString(pad + yamlScalar(field.key) + ":")
+			writeYAMLValue(buf, field.value, indent+2)
+		}
+	case []any:
+		if len(n) == 0 {
+			buf.WriteString(pad + "[]\n")
+			return
+		}
+		for _, item := range n {
+			buf.WriteString(pad + "-")
+			if fields, ok := item.([]yamlField); ok && len(fields) > 0 {
+				// The first key of an object shares the "- " line
+				var nested bytes.Buffer
+				writeYAMLNode(&nested, fields, indent+2)
+				buf.WriteString(" " + strings.TrimPrefix(nested.String(), pad+"  "))
+				continue
+			}
+			writeYAMLValue(buf, item, indent+2)
+		}
+	default:
+		buf.WriteString(pad + yamlScalar(n) + "\n")
+	}
+}
+
+// writeYAMLValue writes the value of a mapping key or sequence item, which
+// follows the ":" or "-" already written
+func writeYAMLValue(buf *bytes.Buffer, value any, indent int) {
+	switch v := value.(type) {
+	case []yamlField:
+		if len(v) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteString("\n")
+		writeYAMLNode(buf, v, indent)
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteString("\n")
+		writeYAMLNode(buf, v, indent)
+	default:
+		buf.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+// yamlPlainRegex matches strings that can be written unquoted without being
+// read back as another type or as YAML syntax
+var yamlPlainRegex = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_./+-]*$`)
+
+// yamlReservedWords are plain strings YAML would read as booleans or null
+var yamlReservedWords = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"null": true, "y": true, "n": true,
+}
+
+// yamlScalar formats a JSON scalar as YAML. Strings are quoted, JSON-style,
+// unless they are unambiguous plain words such as version numbers.
+func yamlScalar(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return fmt.Sprint(v)
+	case json.Number:
+		return v.String()
+	case string:
+		if yamlPlainRegex.MatchString(v) && !yamlReservedWords[strings.ToLower(v)] {
+			return v
+		}
+		quoted, _ := json.Marshal(v)
+		return string(quoted)
+	default:
+		return fmt.Sprint(v)
+	}
+}
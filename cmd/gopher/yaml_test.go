@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteYAML(t *testing.T) {
+	type version struct {
+		Version     string    `json:"version"`
+		InstalledAt time.Time `json:"installed_at"`
+		IsActive    bool      `json:"is_active"`
+		Path        string    `json:"path,omitempty"`
+	}
+
+	data := map[string]any{
+		"versions": []version{
+			{Version: "go1.21.0", InstalledAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), IsActive: true, Path: "/usr/local/go/bin/go"},
+			{Version: "go1.22.0", InstalledAt: time.Date(2024, 2, 6, 12, 30, 0, 0, time.UTC)},
+		},
+		"pagination": map[string]any{
+			"current_page": 1,
+			"filter":       "",
+			"tags":         []string{},
+			"min":          "1.20",
+			"off":          "off",
+		},
+	}
+
+	want := `pagination:
+  current_page: 1
+  filter: ""
+  min: "1.20"
+  "off": "off"
+  tags: []
+versions:
+  - version: go1.21.0
+    installed_at: "2023-01-01T00:00:00Z"
+    is_active: true
+    path: /usr/local/go/bin/go
+  - version: go1.22.0
+    installed_at: "2024-02-06T12:30:00Z"
+    is_active: false
+`
+
+	var buf bytes.Buffer
+	if err := writeYAML(&buf, data); err != nil {
+		t.Fatalf("writeYAML() error = %v", err)
+	}
+	if buf.String() != want {
+		t.Errorf("writeYAML() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteYAML_TopLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		want string
+	}{
+		{"empty list", []string{}, "[]\n"},
+		{"empty map", map[string]int{}, "{}\n"},
+		{"scalar list", []string{"go1.21.0", "needs: quoting"}, "- go1.21.0\n- \"needs: quoting\"\n"},
+		{"nested lists", [][]int{{1, 2}, {}}, "-\n  - 1\n  - 2\n- []\n"},
+		{"scalar", "yes", "\"yes\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeYAML(&buf, tt.data); err != nil {
+				t.Fatalf("writeYAML() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("writeYAML() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveFormat(t *testing.T) {
+	savedFormat, savedJSON := *format, *jsonOutput
+	defer func() { *format, *jsonOutput = savedFormat, savedJSON }()
+
+	tests := []struct {
+		format   string
+		json     bool
+		want     string
+		wantJSON bool
+		wantErr  bool
+	}{
+		{format: "table", want: "table"},
+		{format: "plain", want: "plain"},
+		{format: "yaml", want: "yaml"},
+		{format: "json", want: "json", wantJSON: true},
+		{format: "table", json: true, want: "json", wantJSON: true},
+		{format: "json", json: true, want: "json", wantJSON: true},
+		{format: "yaml", json: true, wantErr: true},
+		{format: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		*format, *jsonOutput = tt.format, tt.json
+		err := resolveFormat()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("resolveFormat(%q, json=%t) expected error", tt.format, tt.json)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("resolveFormat(%q, json=%t) error = %v", tt.format, tt.json, err)
+			continue
+		}
+		if *format != tt.want || *jsonOutput != tt.wantJSON {
+			t.Errorf("resolveFormat(%q, json=%t) = %q, json=%t; want %q, json=%t",
+				tt.format, tt.json, *format, *jsonOutput, tt.want, tt.wantJSON)
+		}
+	}
+}
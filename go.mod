@@ -2,6 +2,10 @@ module github.com/molmedoz/gopher
 
 go 1.24.9
 
-require golang.org/x/term v0.36.0
+require (
+	github.com/klauspost/compress v1.18.0
+	github.com/ulikunitz/xz v0.5.15
+	golang.org/x/term v0.36.0
+)
 
 require golang.org/x/sys v0.37.0 // indirect
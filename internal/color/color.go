@@ -34,12 +34,14 @@
 //	inactiveColor := color.InactiveVersion()
 //
 // The package automatically detects if the output is going to a terminal
-// and disables colors when appropriate (e.g., when redirecting to a file).
+// and disables colors when appropriate (e.g., when redirecting to a file,
+// when NO_COLOR is set, or after SetEnabled(false)).
 package color
 
 import (
 	"os"
 	"runtime"
+	"sync/atomic"
 )
 
 // ANSI color codes
@@ -115,8 +117,25 @@ func DimColor() ColorFunc {
 	}
 }
 
-// IsColorEnabled checks if color output is enabled
+// forceDisabled is set by SetEnabled(false), e.g. for the --no-color flag
+var forceDisabled atomic.Bool
+
+// SetEnabled turns color output off (false) or back to automatic detection
+// (true). Colors are never forced on for non-terminal output.
+func SetEnabled(enabled bool) {
+	forceDisabled.Store(!enabled)
+}
+
+// IsColorEnabled checks if color output is enabled.
+//
+// Colors are disabled by SetEnabled(false), when the NO_COLOR environment
+// variable is set to any non-empty value (see https://no-color.org), and
+// when stdout is not a terminal.
 func IsColorEnabled() bool {
+	if forceDisabled.Load() || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
 	// Check if we're in a terminal
 	if runtime.GOOS == "windows" {
 		// On Windows, check for ANSI support
@@ -0,0 +1,23 @@
+package color
+
+import "testing"
+
+func TestIsColorEnabled_Disabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if IsColorEnabled() {
+		t.Error("NO_COLOR should disable colors")
+	}
+	if got := ActiveVersion()("go1.21.0"); got != "go1.21.0" {
+		t.Errorf("ActiveVersion with NO_COLOR = %q, want plain text", got)
+	}
+
+	t.Setenv("NO_COLOR", "")
+	SetEnabled(false)
+	defer SetEnabled(true)
+	if IsColorEnabled() {
+		t.Error("SetEnabled(false) should disable colors")
+	}
+	if got := InactiveVersion()("go1.20.0"); got != "go1.20.0" {
+		t.Errorf("InactiveVersion when disabled = %q, want plain text", got)
+	}
+}
@@ -42,21 +42,41 @@ import (
 	"strings"
 
 	"github.com/molmedoz/gopher/internal/env"
+	"github.com/molmedoz/gopher/internal/fileutil"
 	"github.com/molmedoz/gopher/internal/security"
 )
 
+// CurrentSchemaVersion is the version of the config file format written by
+// this release. Bump it, and add a step to migrate, when a change needs more
+// than defaults for the new fields.
+const CurrentSchemaVersion = 1
+
 // Config represents gopher configuration
 type Config struct {
-	InstallDir     string `json:"install_dir"`     // Directory where Go versions are installed
-	DownloadDir    string `json:"download_dir"`    // Directory for temporary downloads
-	MirrorURL      string `json:"mirror_url"`      // Go download mirror URL
-	AutoCleanup    bool   `json:"auto_cleanup"`    // Automatically clean up old versions
-	MaxVersions    int    `json:"max_versions"`    // Maximum number of versions to keep
-	GOPATHMode     string `json:"gopath_mode"`     // GOPATH management mode: "shared", "version-specific", "custom"
-	CustomGOPATH   string `json:"custom_gopath"`   // Custom GOPATH when mode is "custom"
-	GOPROXY        string `json:"goproxy"`         // Go proxy URL
-	GOSUMDB        string `json:"gosumdb"`         // Go checksum database
-	SetEnvironment bool   `json:"set_environment"` // Whether to set environment variables
+	SchemaVersion    int      `json:"schema_version"`      // Config file format version; see CurrentSchemaVersion
+	InstallDir       string   `json:"install_dir"`         // Directory where Go versions are installed
+	DownloadDir      string   `json:"download_dir"`        // Directory for temporary downloads
+	MirrorURL        string   `json:"mirror_url"`          // Go download mirror URL
+	AutoCleanup      bool     `json:"auto_cleanup"`        // Automatically clean up old versions
+	KeepDownloads    bool     `json:"keep_downloads"`      // Keep downloaded archives after install, for reinstalls
+	MaxVersions      int      `json:"max_versions"`        // Maximum number of versions to keep
+	GOPATHMode       string   `json:"gopath_mode"`         // GOPATH management mode: "shared", "version-specific", "custom", "per-project"
+	CustomGOPATH     string   `json:"custom_gopath"`       // Custom GOPATH when mode is "custom"
+	GOBINMode        string   `json:"gobin_mode"`          // GOBIN management mode: "gopath-bin", "version-specific", "custom"
+	CustomGOBIN      string   `json:"custom_gobin"`        // Custom GOBIN when mode is "custom"
+	GOPROXY          string   `json:"goproxy"`             // Go proxy URL
+	GOSUMDB          string   `json:"gosumdb"`             // Go checksum database
+	GOFLAGS          string   `json:"goflags"`             // Default go command flags, e.g. "-mod=readonly"; unset when empty
+	GOTOOLCHAIN      string   `json:"gotoolchain"`         // Go toolchain selection; "local" keeps Go from switching away from the active version
+	DefaultChannel   string   `json:"default_channel"`     // Release channel for list-remote and "latest": "stable" or "unstable"
+	SetEnvironment   bool     `json:"set_environment"`     // Whether to set environment variables
+	AutoInstallOnUse bool     `json:"auto_install_on_use"` // Install a missing version on 'use' without asking
+	LinkedBinaries   []string `json:"linked_binaries"`     // Binaries of the active version linked next to the go symlink, e.g. "gofmt"
+	SymlinkDir       string   `json:"symlink_dir"`         // Directory for the go symlink; the platform's standard directory when empty
+	PreInstall       string   `json:"pre_install"`         // Shell command run before a version is installed; a failure aborts the install
+	PostInstall      string   `json:"post_install"`        // Shell command run after a version is installed
+	PostUse          string   `json:"post_use"`            // Shell command run after switching versions; GOPHER_PREVIOUS_VERSION holds the old one
+	StrictHooks      bool     `json:"strict_hooks"`        // Treat a failing post_install hook as a failed install and remove the version, instead of only warning
 }
 
 // DefaultConfig returns the default configuration using os.Getenv
@@ -67,16 +87,24 @@ func DefaultConfig() *Config {
 // DefaultConfigWithEnv returns the default configuration with the given environment provider
 func DefaultConfigWithEnv(envProvider env.Provider) *Config {
 	return &Config{
+		SchemaVersion:  CurrentSchemaVersion,
 		InstallDir:     getDefaultInstallDirWithEnv(envProvider),
 		DownloadDir:    getDefaultDownloadDirWithEnv(envProvider),
 		MirrorURL:      "https://go.dev/dl/",
 		AutoCleanup:    true,
+		KeepDownloads:  true,
 		MaxVersions:    5,
 		GOPATHMode:     "shared",
 		CustomGOPATH:   "",
+		GOBINMode:      "gopath-bin",
+		CustomGOBIN:    "",
 		GOPROXY:        "https://proxy.golang.org,direct",
 		GOSUMDB:        "sum.golang.org",
+		GOFLAGS:        "",
+		GOTOOLCHAIN:    "local",
+		DefaultChannel: "stable",
 		SetEnvironment: true,
+		LinkedBinaries: []string{"go", "gofmt"},
 	}
 }
 
@@ -84,24 +112,95 @@ func DefaultConfigWithEnv(envProvider env.Provider) *Config {
 
 // getDefaultInstallDirWithEnv returns the default installation directory with the given environment provider
 func getDefaultInstallDirWithEnv(envProvider env.Provider) string {
-	homeDir := getUserHomeDirWithEnv(envProvider)
-	switch runtime.GOOS {
-	case "windows":
-		return filepath.Join(homeDir, "gopher", "versions")
-	default:
-		return filepath.Join(homeDir, ".gopher", "versions")
-	}
+	return filepath.Join(gopherDataDirWithEnv(envProvider), "versions")
 }
 
 // getDefaultDownloadDirWithEnv returns the default download directory with the given environment provider
 func getDefaultDownloadDirWithEnv(envProvider env.Provider) string {
+	return filepath.Join(gopherCacheDirWithEnv(envProvider), "downloads")
+}
+
+// legacyGopherDirWithEnv returns the single directory gopher keeps all of its
+// files in when the XDG directories are not used: ~/.gopher, or ~/gopher on
+// Windows
+func legacyGopherDirWithEnv(envProvider env.Provider) string {
 	homeDir := getUserHomeDirWithEnv(envProvider)
 	switch runtime.GOOS {
 	case "windows":
-		return filepath.Join(homeDir, "gopher", "downloads")
+		return filepath.Join(homeDir, "gopher")
 	default:
-		return filepath.Join(homeDir, ".gopher", "downloads")
+		return filepath.Join(homeDir, ".gopher")
+	}
+}
+
+// xdgBaseDirs maps each XDG base directory variable to its default relative
+// to the home directory, as given by the XDG Base Directory spec.
+var xdgBaseDirs = map[string]string{
+	"XDG_CONFIG_HOME": ".config",
+	"XDG_DATA_HOME":   filepath.Join(".local", "share"),
+	"XDG_CACHE_HOME":  ".cache",
+}
+
+// xdgBaseDirWithEnv returns the value of xdgVar if it holds an absolute path.
+// The spec says relative paths are invalid and should be ignored.
+func xdgBaseDirWithEnv(envProvider env.Provider, xdgVar string) string {
+	base := envProvider.Getenv(xdgVar)
+	if base == "" || !filepath.IsAbs(base) {
+		return ""
+	}
+	return base
+}
+
+// useXDGWithEnv reports whether gopher lays its directories out under the XDG
+// base directories instead of the single legacy directory. The decision is
+// made once for all directories: only on Linux, when at least one XDG
+// variable holds an absolute path and no ~/.gopher from an earlier install
+// exists, so existing setups keep working unchanged and a partial XDG setup
+// never splits gopher's files between the two layouts.
+func useXDGWithEnv(envProvider env.Provider) bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if _, err := os.Stat(legacyGopherDirWithEnv(envProvider)); err == nil {
+		return false
+	}
+	for xdgVar := range xdgBaseDirs {
+		if xdgBaseDirWithEnv(envProvider, xdgVar) != "" {
+			return true
+		}
 	}
+	return false
+}
+
+// xdgGopherDirWithEnv returns the gopher directory under the XDG base
+// directory named by xdgVar (e.g. $XDG_DATA_HOME/gopher), falling back to
+// the spec's default (e.g. ~/.local/share) when xdgVar is unset. The legacy
+// directory is returned when useXDGWithEnv is false.
+func xdgGopherDirWithEnv(envProvider env.Provider, xdgVar string) string {
+	if !useXDGWithEnv(envProvider) {
+		return legacyGopherDirWithEnv(envProvider)
+	}
+	base := xdgBaseDirWithEnv(envProvider, xdgVar)
+	if base == "" {
+		base = filepath.Join(getUserHomeDirWithEnv(envProvider), xdgBaseDirs[xdgVar])
+	}
+	return filepath.Join(base, "gopher")
+}
+
+// gopherConfigDirWithEnv returns the directory holding config.json
+func gopherConfigDirWithEnv(envProvider env.Provider) string {
+	return xdgGopherDirWithEnv(envProvider, "XDG_CONFIG_HOME")
+}
+
+// gopherDataDirWithEnv returns the directory holding installed versions,
+// aliases and state
+func gopherDataDirWithEnv(envProvider env.Provider) string {
+	return xdgGopherDirWithEnv(envProvider, "XDG_DATA_HOME")
+}
+
+// gopherCacheDirWithEnv returns the directory holding downloaded archives
+func gopherCacheDirWithEnv(envProvider env.Provider) string {
+	return xdgGopherDirWithEnv(envProvider, "XDG_CACHE_HOME")
 }
 
 // getUserHomeDir returns the user's home directory using os.Getenv
@@ -148,16 +247,9 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("invalid config path: %w", err)
 	}
 
-	// Scope config file access to user home directory or gopher config directory
+	// Scope config file access to the gopher config directory
 	// This prevents accessing config files outside safe locations
-	homeDir := getUserHomeDir()
-	var safeRoot string
-	switch runtime.GOOS {
-	case "windows":
-		safeRoot = filepath.Join(homeDir, "gopher")
-	default:
-		safeRoot = filepath.Join(homeDir, ".gopher")
-	}
+	safeRoot := filepath.Dir(GetConfigPath())
 
 	// Validate config path is within safe root
 	// For testing, allow paths that start with /tmp or /var (common test directories)
@@ -202,17 +294,39 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
+	// Decode over the defaults so fields missing from older files get them.
+	// Files from before schema_version existed are version 0.
+	config := DefaultConfig()
+	config.SchemaVersion = 0
+	if err := json.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	if config.migrate() {
+		// Not fatal: the file is migrated again on the next load
+		_ = config.Save(safeConfigPath)
+	}
+
 	// Ensure all required directories exist (handles upgrades and missing dirs)
 	if err := config.EnsureDirectories(); err != nil {
 		return nil, fmt.Errorf("failed to create required directories: %w", err)
 	}
 
-	return &config, nil
+	return config, nil
+}
+
+// migrate upgrades a config loaded from an older schema version in place and
+// reports whether anything changed. Files written by a newer release are left
+// alone.
+func (c *Config) migrate() bool {
+	if c.SchemaVersion >= CurrentSchemaVersion {
+		return false
+	}
+
+	// 0 -> 1: schema_version was added. Fields missing from the file already
+	// hold their defaults, as Load decodes over DefaultConfig.
+	c.SchemaVersion = CurrentSchemaVersion
+	return true
 }
 
 // Save saves configuration to file
@@ -222,15 +336,8 @@ func (c *Config) Save(configPath string) error {
 		return fmt.Errorf("invalid config path: %w", err)
 	}
 
-	// Scope config file access to user home directory or gopher config directory
-	homeDir := getUserHomeDir()
-	var safeRoot string
-	switch runtime.GOOS {
-	case "windows":
-		safeRoot = filepath.Join(homeDir, "gopher")
-	default:
-		safeRoot = filepath.Join(homeDir, ".gopher")
-	}
+	// Scope config file access to the gopher config directory
+	safeRoot := filepath.Dir(GetConfigPath())
 
 	// Validate config path is within safe root
 	// For testing, allow paths that start with /tmp or /var (common test directories)
@@ -267,8 +374,10 @@ func (c *Config) Save(configPath string) error {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
+	// Replace the file atomically so a crash mid-write cannot leave a
+	// truncated config that breaks every later command
 	// #nosec G306 -- 0644 acceptable for config file (contains non-sensitive user preferences)
-	if err := os.WriteFile(safeConfigPath, data, 0644); err != nil {
+	if err := fileutil.WriteAtomic(safeConfigPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -277,13 +386,14 @@ func (c *Config) Save(configPath string) error {
 
 // GetConfigPath returns the default config file path
 func GetConfigPath() string {
-	homeDir := getUserHomeDir()
-	switch runtime.GOOS {
-	case "windows":
-		return filepath.Join(homeDir, "gopher", "config.json")
-	default:
-		return filepath.Join(homeDir, ".gopher", "config.json")
-	}
+	return GetConfigPathWithEnv(&env.DefaultProvider{})
+}
+
+// GetConfigPathWithEnv returns the default config file path with the given
+// environment provider: config.json in $XDG_CONFIG_HOME/gopher on Linux when
+// the XDG directories are in use, otherwise in ~/.gopher (~/gopher on Windows)
+func GetConfigPathWithEnv(envProvider env.Provider) string {
+	return filepath.Join(gopherConfigDirWithEnv(envProvider), "config.json")
 }
 
 // Validate validates the configuration
@@ -305,12 +415,31 @@ func (c *Config) Validate() error {
 		c.GOPATHMode = "shared"
 	}
 
-	if c.GOPATHMode != "shared" && c.GOPATHMode != "version-specific" && c.GOPATHMode != "custom" {
-		return fmt.Errorf("gopath_mode must be 'shared', 'version-specific', or 'custom'")
+	if c.GOPATHMode != "shared" && c.GOPATHMode != "version-specific" && c.GOPATHMode != "custom" && c.GOPATHMode != "per-project" {
+		return fmt.Errorf("gopath_mode must be 'shared', 'version-specific', 'custom', or 'per-project'")
 	}
 	if c.GOPATHMode == "custom" && c.CustomGOPATH == "" {
 		return fmt.Errorf("custom_gopath must be set when gopath_mode is 'custom'")
 	}
+
+	// Default GOBIN mode to GOPATH/bin when unset
+	if c.GOBINMode == "" {
+		c.GOBINMode = "gopath-bin"
+	}
+	if c.GOBINMode != "gopath-bin" && c.GOBINMode != "version-specific" && c.GOBINMode != "custom" {
+		return fmt.Errorf("gobin_mode must be 'gopath-bin', 'version-specific', or 'custom'")
+	}
+	if c.GOBINMode == "custom" && c.CustomGOBIN == "" {
+		return fmt.Errorf("custom_gobin must be set when gobin_mode is 'custom'")
+	}
+
+	// Default to the stable release channel when unset
+	if c.DefaultChannel == "" {
+		c.DefaultChannel = "stable"
+	}
+	if c.DefaultChannel != "stable" && c.DefaultChannel != "unstable" {
+		return fmt.Errorf("default_channel must be 'stable' or 'unstable'")
+	}
 	return nil
 }
 
@@ -328,6 +457,12 @@ func (c *Config) EnsureDirectories() error {
 		}
 	}
 
+	// A custom GOBIN is shared by every version; the others live under a
+	// GOPATH or version and are created by 'go install' when needed
+	if c.GOBINMode == "custom" {
+		dirs = append(dirs, c.CustomGOBIN)
+	}
+
 	for _, dir := range dirs {
 		if dir == "" {
 			continue
@@ -349,23 +484,95 @@ func (c *Config) GetGOPATH(version string) string {
 func (c *Config) GetGOPATHWithEnv(version string, envProvider env.Provider) string {
 	switch c.GOPATHMode {
 	case "shared":
-		// Use system GOPATH or default
-		if gopath := envProvider.Getenv("GOPATH"); gopath != "" {
-			return gopath
-		}
-		// Default GOPATH
-		homeDir := getUserHomeDirWithEnv(envProvider)
-		return filepath.Join(homeDir, "go")
+		return sharedGOPATH(envProvider)
 	case "version-specific":
 		// Create version-specific GOPATH
 		return filepath.Join(c.InstallDir, version, "gopath")
 	case "custom":
 		return c.CustomGOPATH
+	case "per-project":
+		// Fall back to the shared GOPATH outside of a project
+		if wd, err := os.Getwd(); err == nil {
+			if gopath, ok := projectGOPATH(wd); ok {
+				return gopath
+			}
+		}
+		return sharedGOPATH(envProvider)
 	default:
 		return envProvider.Getenv("GOPATH")
 	}
 }
 
+// sharedGOPATH returns the GOPATH from the environment, or the default ~/go
+func sharedGOPATH(envProvider env.Provider) string {
+	if gopath := envProvider.Getenv("GOPATH"); gopath != "" {
+		return gopath
+	}
+	homeDir := getUserHomeDirWithEnv(envProvider)
+	return filepath.Join(homeDir, "go")
+}
+
+// projectGOPATHDir is the directory holding a project's GOPATH in
+// "per-project" mode
+const projectGOPATHDir = ".gopher-gopath"
+
+// projectGOPATH returns the per-project GOPATH for dir: a .gopher-gopath
+// directory in the nearest enclosing project root. A project root is the
+// closest directory, starting at dir, that already has a .gopher-gopath
+// directory, a go.mod file or a .git entry.
+//
+// Returns false if dir is not inside a project.
+func projectGOPATH(dir string) (string, bool) {
+	dir = filepath.Clean(dir)
+	for {
+		for _, marker := range []string{projectGOPATHDir, "go.mod", ".git"} {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return filepath.Join(dir, projectGOPATHDir), true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// GetGOBIN returns the GOBIN for the given Go version using os.Getenv
+func (c *Config) GetGOBIN(version string) string {
+	return c.GetGOBINWithEnv(version, &env.DefaultProvider{})
+}
+
+// GetGOBINWithEnv returns the GOBIN for the given Go version with the given
+// environment provider: the bin directory of the version's GOPATH by default,
+// a directory per version next to the install directory in
+// "version-specific" mode, or CustomGOBIN in "custom" mode
+func (c *Config) GetGOBINWithEnv(version string, envProvider env.Provider) string {
+	switch c.GOBINMode {
+	case "version-specific":
+		return filepath.Join(filepath.Dir(c.InstallDir), "gobin", version)
+	case "custom":
+		return c.CustomGOBIN
+	default:
+		return filepath.Join(c.GetGOPATHWithEnv(version, envProvider), "bin")
+	}
+}
+
+// GoSettings returns the GOFLAGS and GOTOOLCHAIN values that are
+// configured. Unlike the rest of the environment they do not depend on the
+// version, so the shell init scripts can write them as they are.
+func (c *Config) GoSettings() map[string]string {
+	settings := make(map[string]string)
+	if c.GOFLAGS != "" {
+		settings["GOFLAGS"] = c.GOFLAGS
+	}
+	if c.GOTOOLCHAIN != "" {
+		settings["GOTOOLCHAIN"] = c.GOTOOLCHAIN
+	}
+	return settings
+}
+
 // GetGOROOT returns the GOROOT for the given Go version
 func (c *Config) GetGOROOT(version string) string {
 	return filepath.Join(c.InstallDir, version)
@@ -387,8 +594,9 @@ func (c *Config) GetEnvironmentVariablesWithEnv(version string, envProvider env.
 	// Set GOROOT
 	env["GOROOT"] = c.GetGOROOT(version)
 
-	// Set GOPATH
+	// Set GOPATH and GOBIN
 	env["GOPATH"] = c.GetGOPATHWithEnv(version, envProvider)
+	env["GOBIN"] = c.GetGOBINWithEnv(version, envProvider)
 
 	// Set GOPROXY if configured
 	if c.GOPROXY != "" {
@@ -400,12 +608,16 @@ func (c *Config) GetEnvironmentVariablesWithEnv(version string, envProvider env.
 		env["GOSUMDB"] = c.GOSUMDB
 	}
 
-	// Add Go binary and GOPATH/bin to PATH
+	// Set GOFLAGS and GOTOOLCHAIN if configured
+	for key, value := range c.GoSettings() {
+		env[key] = value
+	}
+
+	// Add Go binary and GOBIN to PATH
 	goBin := filepath.Join(c.GetGOROOT(version), "bin")
-	gopathBin := filepath.Join(c.GetGOPATHWithEnv(version, envProvider), "bin")
 
-	// Build PATH with Go binary first, then GOPATH/bin, then existing PATH
-	pathComponents := []string{goBin, gopathBin}
+	// Build PATH with Go binary first, then GOBIN, then existing PATH
+	pathComponents := []string{goBin, env["GOBIN"]}
 	if currentPath := envProvider.Getenv("PATH"); currentPath != "" {
 		pathComponents = append(pathComponents, currentPath)
 	}
@@ -3,7 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/molmedoz/gopher/internal/env"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -134,6 +138,119 @@ func TestConfigSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestLoad_MigratesSchemaV0(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.json")
+
+	// A config written before schema_version, goproxy, gosumdb,
+	// gopath_mode and set_environment existed
+	v0 := `{
+  "install_dir": "` + filepath.ToSlash(filepath.Join(tempDir, "install")) + `",
+  "download_dir": "` + filepath.ToSlash(filepath.Join(tempDir, "download")) + `",
+  "mirror_url": "https://example.com/dl/",
+  "auto_cleanup": false,
+  "max_versions": 3
+}`
+	if err := os.WriteFile(configPath, []byte(v0), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	defaults := DefaultConfig()
+	if cfg.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", cfg.SchemaVersion, CurrentSchemaVersion)
+	}
+	if cfg.GOPROXY != defaults.GOPROXY || cfg.GOSUMDB != defaults.GOSUMDB {
+		t.Errorf("GOPROXY, GOSUMDB = %q, %q; want defaults %q, %q", cfg.GOPROXY, cfg.GOSUMDB, defaults.GOPROXY, defaults.GOSUMDB)
+	}
+	if cfg.GOPATHMode != defaults.GOPATHMode || !cfg.SetEnvironment {
+		t.Errorf("GOPATHMode, SetEnvironment = %q, %t; want defaults", cfg.GOPATHMode, cfg.SetEnvironment)
+	}
+	// Values present in the file are kept
+	if cfg.MirrorURL != "https://example.com/dl/" || cfg.AutoCleanup || cfg.MaxVersions != 3 {
+		t.Errorf("file values not kept: %+v", cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("migrated config is invalid: %v", err)
+	}
+
+	// The upgraded config is saved back
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"schema_version": 1`) || !strings.Contains(string(data), `"goproxy"`) {
+		t.Errorf("config file was not upgraded:\n%s", data)
+	}
+}
+
+func TestConfigSave_Atomic(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.json")
+
+	cfg := DefaultConfig()
+	cfg.MaxVersions = 3
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatal(err)
+	}
+	cfg.MaxVersions = 7
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.MaxVersions != 7 {
+		t.Errorf("MaxVersions = %d, want 7", loaded.MaxVersions)
+	}
+
+	// A save that cannot replace the target leaves no temporary or empty file
+	blocked := filepath.Join(tempDir, "blocked")
+	if err := os.MkdirAll(filepath.Join(blocked, "child"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(blocked); err == nil {
+		t.Fatal("expected Save to fail when the target is a directory")
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != "config.json" && entry.Name() != "blocked" {
+			t.Errorf("unexpected file left behind: %s", entry.Name())
+		}
+	}
+}
+
+func TestLoad_KeepsNewerSchema(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.json")
+
+	cfg := DefaultConfig()
+	cfg.InstallDir = filepath.Join(tempDir, "install")
+	cfg.DownloadDir = filepath.Join(tempDir, "download")
+	cfg.SchemaVersion = CurrentSchemaVersion + 1
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.SchemaVersion != CurrentSchemaVersion+1 {
+		t.Errorf("SchemaVersion = %d, want %d", loaded.SchemaVersion, CurrentSchemaVersion+1)
+	}
+}
+
 func TestConfigEnsureDirectories(t *testing.T) {
 	// Create a temporary directory for testing
 	tempDir := t.TempDir()
@@ -171,3 +288,72 @@ func TestGetConfigPath(t *testing.T) {
 		t.Error("GetConfigPath() should return absolute path")
 	}
 }
+
+func TestXDGDirectories(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the XDG directories are only used on Linux")
+	}
+	home := t.TempDir()
+	xdg := t.TempDir()
+	envProvider := env.NewMockProvider(map[string]string{
+		"HOME":            home,
+		"XDG_CONFIG_HOME": filepath.Join(xdg, "config"),
+		"XDG_DATA_HOME":   filepath.Join(xdg, "data"),
+		"XDG_CACHE_HOME":  "relative/cache", // Invalid, so ignored
+	})
+
+	cfg := DefaultConfigWithEnv(envProvider)
+	if want := filepath.Join(xdg, "data", "gopher", "versions"); cfg.InstallDir != want {
+		t.Errorf("InstallDir = %q, want %q", cfg.InstallDir, want)
+	}
+	if want := filepath.Join(home, ".cache", "gopher", "downloads"); cfg.DownloadDir != want {
+		t.Errorf("DownloadDir = %q, want %q", cfg.DownloadDir, want)
+	}
+	if want := filepath.Join(xdg, "config", "gopher", "config.json"); GetConfigPathWithEnv(envProvider) != want {
+		t.Errorf("GetConfigPathWithEnv() = %q, want %q", GetConfigPathWithEnv(envProvider), want)
+	}
+
+	// An existing ~/.gopher keeps everything in the legacy layout
+	if err := os.MkdirAll(filepath.Join(home, ".gopher"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	cfg = DefaultConfigWithEnv(envProvider)
+	if want := filepath.Join(home, ".gopher", "versions"); cfg.InstallDir != want {
+		t.Errorf("InstallDir with ~/.gopher = %q, want %q", cfg.InstallDir, want)
+	}
+	if want := filepath.Join(home, ".gopher", "config.json"); GetConfigPathWithEnv(envProvider) != want {
+		t.Errorf("GetConfigPathWithEnv() with ~/.gopher = %q, want %q", GetConfigPathWithEnv(envProvider), want)
+	}
+}
+
+func TestXDGDirectories_OnlyConfigHome(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the XDG directories are only used on Linux")
+	}
+	home := t.TempDir()
+	xdg := t.TempDir()
+	envProvider := env.NewMockProvider(map[string]string{
+		"HOME":            home,
+		"XDG_CONFIG_HOME": filepath.Join(xdg, "config"),
+	})
+
+	cfg := DefaultConfigWithEnv(envProvider)
+	if want := filepath.Join(home, ".local", "share", "gopher", "versions"); cfg.InstallDir != want {
+		t.Errorf("InstallDir = %q, want %q", cfg.InstallDir, want)
+	}
+	if want := filepath.Join(home, ".cache", "gopher", "downloads"); cfg.DownloadDir != want {
+		t.Errorf("DownloadDir = %q, want %q", cfg.DownloadDir, want)
+	}
+
+	// Creating the directories must not create ~/.gopher and flip the
+	// config path back to the legacy layout
+	if err := cfg.EnsureDirectories(); err != nil {
+		t.Fatalf("EnsureDirectories() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".gopher")); err == nil {
+		t.Error("EnsureDirectories() created ~/.gopher")
+	}
+	if want := filepath.Join(xdg, "config", "gopher", "config.json"); GetConfigPathWithEnv(envProvider) != want {
+		t.Errorf("GetConfigPathWithEnv() = %q, want %q", GetConfigPathWithEnv(envProvider), want)
+	}
+}
@@ -77,6 +77,92 @@ func TestGetGOPATH_CustomMode(t *testing.T) {
 	}
 }
 
+func TestGetGOBIN_Modes(t *testing.T) {
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, ".gopher", "versions")
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"", filepath.Join(tmp, "ws", "bin")},
+		{"gopath-bin", filepath.Join(tmp, "ws", "bin")},
+		{"version-specific", filepath.Join(tmp, ".gopher", "gobin", "go1.21.0")},
+		{"custom", filepath.Join(tmp, "tools")},
+	}
+	for _, tt := range tests {
+		cfg := &Config{
+			InstallDir:     installDir,
+			DownloadDir:    filepath.Join(tmp, "dl"),
+			GOPATHMode:     "custom",
+			CustomGOPATH:   filepath.Join(tmp, "ws"),
+			GOBINMode:      tt.mode,
+			CustomGOBIN:    filepath.Join(tmp, "tools"),
+			SetEnvironment: true,
+		}
+		if got := cfg.GetGOBIN("go1.21.0"); got != tt.want {
+			t.Errorf("GetGOBIN(%q)=%q want %q", tt.mode, got, tt.want)
+		}
+
+		env := cfg.GetEnvironmentVariables("go1.21.0")
+		if env["GOBIN"] != tt.want {
+			t.Errorf("GOBIN(%q)=%q want %q", tt.mode, env["GOBIN"], tt.want)
+		}
+		wantPath := filepath.Join(installDir, "go1.21.0", "bin") + string(os.PathListSeparator) + tt.want
+		if !strings.HasPrefix(env["PATH"], wantPath) {
+			t.Errorf("PATH(%q)=%q does not start with %q", tt.mode, env["PATH"], wantPath)
+		}
+	}
+}
+
+func TestGetEnvironmentVariables_GoSettings(t *testing.T) {
+	cfg := DefaultConfig()
+	env := cfg.GetEnvironmentVariables("go1.21.0")
+	if env["GOTOOLCHAIN"] != "local" {
+		t.Errorf("GOTOOLCHAIN=%q want local by default", env["GOTOOLCHAIN"])
+	}
+	if _, ok := env["GOFLAGS"]; ok {
+		t.Errorf("GOFLAGS should be unset by default, got %q", env["GOFLAGS"])
+	}
+
+	cfg.GOFLAGS = "-mod=readonly"
+	cfg.GOTOOLCHAIN = ""
+	env = cfg.GetEnvironmentVariables("go1.21.0")
+	if env["GOFLAGS"] != "-mod=readonly" {
+		t.Errorf("GOFLAGS=%q want -mod=readonly", env["GOFLAGS"])
+	}
+	if _, ok := env["GOTOOLCHAIN"]; ok {
+		t.Errorf("GOTOOLCHAIN should be unset when empty, got %q", env["GOTOOLCHAIN"])
+	}
+}
+
+func TestValidate_GOBINMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.GOBINMode = "elsewhere"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for an unknown gobin_mode")
+	}
+	cfg.GOBINMode = "custom"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for custom gobin_mode without custom_gobin")
+	}
+	cfg.CustomGOBIN = "/tmp/tools"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate error: %v", err)
+	}
+}
+
+func TestValidate_DefaultChannel(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DefaultChannel = ""
+	if err := cfg.Validate(); err != nil || cfg.DefaultChannel != "stable" {
+		t.Errorf("Validate() = %v, default_channel %q; want stable", err, cfg.DefaultChannel)
+	}
+	cfg.DefaultChannel = "nightly"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for an unknown default_channel")
+	}
+}
+
 func TestGetEnvironmentVariables_Disabled(t *testing.T) {
 	tmp := t.TempDir()
 	cfg := &Config{InstallDir: tmp, DownloadDir: filepath.Join(tmp, "dl"), SetEnvironment: false}
@@ -98,6 +184,17 @@ func TestEnsureDirectories_Creates(t *testing.T) {
 	}
 }
 
+func TestEnsureDirectories_CustomGOBIN(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := &Config{InstallDir: filepath.Join(tmp, "inst"), DownloadDir: filepath.Join(tmp, "dl"), GOBINMode: "custom", CustomGOBIN: filepath.Join(tmp, "tools")}
+	if err := cfg.EnsureDirectories(); err != nil {
+		t.Fatalf("EnsureDirectories error: %v", err)
+	}
+	if st, err := os.Stat(cfg.CustomGOBIN); err != nil || !st.IsDir() {
+		t.Fatalf("custom GOBIN %s not created", cfg.CustomGOBIN)
+	}
+}
+
 // Sanity: GetConfigPath returns absolute path appropriate for OS
 func TestGetConfigPath_IsAbsolute(t *testing.T) {
 	p := GetConfigPath()
@@ -105,3 +202,65 @@ func TestGetConfigPath_IsAbsolute(t *testing.T) {
 		t.Fatalf("GetConfigPath not absolute: %q (%s)", p, runtime.GOOS)
 	}
 }
+
+func TestProjectGOPATH(t *testing.T) {
+	tmp := t.TempDir()
+	project := filepath.Join(tmp, "project")
+	nested := filepath.Join(project, "cmd", "app")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, "go.mod"), []byte("module example.com/app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := projectGOPATH(nested)
+	if !ok || got != filepath.Join(project, ".gopher-gopath") {
+		t.Fatalf("projectGOPATH(nested)=%q,%v want %q", got, ok, filepath.Join(project, ".gopher-gopath"))
+	}
+
+	// An existing .gopher-gopath marks a root even without go.mod
+	sub := filepath.Join(project, "cmd")
+	if err := os.Mkdir(filepath.Join(sub, ".gopher-gopath"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := projectGOPATH(nested); got != filepath.Join(sub, ".gopher-gopath") {
+		t.Fatalf("projectGOPATH should prefer the nearest .gopher-gopath, got %q", got)
+	}
+
+	// Outside any project there is no per-project GOPATH under tmp
+	outside := filepath.Join(tmp, "outside")
+	if err := os.Mkdir(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := projectGOPATH(outside); ok && strings.HasPrefix(got, tmp) {
+		t.Fatalf("projectGOPATH(outside)=%q, want no project under %q", got, tmp)
+	}
+}
+
+func TestGetGOPATH_PerProject(t *testing.T) {
+	tmp := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(tmp)
+
+	cfg := &Config{InstallDir: filepath.Join(tmp, "install"), DownloadDir: filepath.Join(tmp, "dl"), GOPATHMode: "per-project"}
+	want := filepath.Join(tmp, ".gopher-gopath")
+	// The working directory may be reported through symlinks (e.g. /private/var on macOS)
+	if got := cfg.GetGOPATH("go1.21.0"); filepath.Base(got) != ".gopher-gopath" || !sameDir(t, filepath.Dir(got), tmp) {
+		t.Fatalf("GetGOPATH(per-project)=%q want %q", got, want)
+	}
+	valid := DefaultConfig()
+	valid.GOPATHMode = "per-project"
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("per-project mode should validate: %v", err)
+	}
+}
+
+func sameDir(t *testing.T, a, b string) bool {
+	t.Helper()
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && ra == rb
+}
@@ -1,8 +1,10 @@
 package downloader
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,20 +17,113 @@ import (
 	"strings"
 	"time"
 
+	"github.com/molmedoz/gopher/internal/fileutil"
+	"github.com/molmedoz/gopher/internal/log"
 	"github.com/molmedoz/gopher/internal/progress"
 )
 
 // Downloader handles downloading Go versions
 type Downloader struct {
-	client  *http.Client
-	baseURL string
+	client       *http.Client
+	baseURL      string
+	progress     ProgressSink // nil renders a terminal progress bar
+	checksum     string       // Expected SHA256 that replaces the published one
+	skipChecksum bool         // Allow archives without any checksum to verify against
+	offline      bool         // Never access the network; see SetOffline
+	pageCache    string       // File the downloads page is saved to and read from offline
+}
+
+// ErrOffline is returned, wrapped, by operations that need the network while
+// the downloader is offline
+var ErrOffline = errors.New("offline mode: network access is disabled")
+
+// partSuffix is appended to an archive's name while it downloads
+const partSuffix = ".part"
+
+// sha256Regex matches a hex-encoded SHA256 checksum as published on the
+// downloads page
+var sha256Regex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// sharedTransport is used by every downloader so that the listing, checksum
+// and archive requests of an install reuse connections instead of paying for
+// a new TLS handshake each time
+var sharedTransport = newTransport()
+
+// newTransport returns an http.Transport tuned for a handful of requests to
+// the same download host
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 20
+	transport.MaxIdleConnsPerHost = 4
+	transport.IdleConnTimeout = 90 * time.Second
+	transport.TLSHandshakeTimeout = 10 * time.Second
+	transport.ForceAttemptHTTP2 = true
+	return transport
+}
+
+// drainAndClose reads what is left of a response body (up to a limit) and
+// closes it, so the connection can be reused even on early returns
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, 64*1024)
+	_ = body.Close()
+}
+
+// get makes a GET request that is canceled with ctx
+func (d *Downloader) get(ctx context.Context, url string) (*http.Response, error) {
+	if d.offline {
+		return nil, fmt.Errorf("%w (GET %s)", ErrOffline, url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return d.client.Do(req)
+}
+
+// mirrorCheckTimeout bounds CheckMirror, so that a mirror behind a firewall
+// that silently drops connections is reported quickly
+var mirrorCheckTimeout = 10 * time.Second
+
+// CheckMirror reports whether the mirror can be reached, with a HEAD request
+// to its base URL that falls back to GET for servers that do not allow HEAD.
+// It fails on network errors and on error responses.
+func (d *Downloader) CheckMirror(ctx context.Context) error {
+	if d.offline {
+		return fmt.Errorf("%w (mirror %s)", ErrOffline, d.baseURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mirrorCheckTimeout)
+	defer cancel()
+
+	url := d.baseURL + "/"
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return fmt.Errorf("invalid mirror URL %s: %w", d.baseURL, err)
+		}
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("mirror %s is unreachable: %w", d.baseURL, err)
+		}
+		drainAndClose(resp.Body)
+
+		if method == http.MethodHead && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+			continue
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("mirror %s returned %s", d.baseURL, resp.Status)
+		}
+		return nil
+	}
+	return nil
 }
 
 // New creates a new downloader
 func New(baseURL string) *Downloader {
 	return &Downloader{
 		client: &http.Client{
-			Timeout: 30 * time.Minute, // Long timeout for large downloads
+			Transport: sharedTransport,
+			Timeout:   30 * time.Minute, // Long timeout for large downloads
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				// Follow redirects
 				return nil
@@ -38,20 +133,67 @@ func New(baseURL string) *Downloader {
 	}
 }
 
-// WithClient creates a downloader with a custom http.Client (for testing)
+// WithClient creates a downloader with a custom http.Client (for testing).
+// A nil client gets the shared transport, like New.
 func WithClient(baseURL string, client *http.Client) *Downloader {
 	if client == nil {
-		client = &http.Client{Timeout: 30 * time.Minute}
+		client = &http.Client{Transport: sharedTransport, Timeout: 30 * time.Minute}
 	}
 	return &Downloader{client: client, baseURL: strings.TrimSuffix(baseURL, "/")}
 }
 
+// SetPageCache sets a file the downloads page is saved to whenever it is
+// fetched, so that it can be read back in offline mode. An empty path
+// disables the cache.
+func (d *Downloader) SetPageCache(path string) {
+	d.pageCache = path
+}
+
+// SetOffline makes the downloader work only from cached data: the version
+// list and checksums come from the page cache, archives must already be in
+// the download directory (verified against the checksum saved with them, or
+// else the page cache), and anything else fails at once with ErrOffline
+// instead of waiting for a network timeout.
+func (d *Downloader) SetOffline(offline bool) {
+	d.offline = offline
+}
+
+// SetProgressSink sets where download progress is reported.
+// Passing nil restores the default terminal progress bar.
+func (d *Downloader) SetProgressSink(sink ProgressSink) {
+	d.progress = sink
+}
+
+// SetChecksum sets the SHA256 that downloaded archives are verified
+// against, instead of the checksum published on the downloads page. The
+// downloads page is then not fetched at all, which allows installing from a
+// mirror that publishes no checksums, or offline from an archive already in
+// the download directory. Passing "" restores the published checksum.
+func (d *Downloader) SetChecksum(sha256 string) error {
+	sha256 = strings.ToLower(strings.TrimSpace(sha256))
+	if sha256 != "" && !sha256Regex.MatchString(sha256) {
+		return fmt.Errorf("invalid SHA256 checksum %q (expected 64 hex characters)", sha256)
+	}
+	d.checksum = sha256
+	return nil
+}
+
+// SetSkipChecksum allows downloads that have no checksum to verify against,
+// neither published nor set with SetChecksum. Such archives are used
+// unverified, with a warning. A checksum that is available is always checked.
+func (d *Downloader) SetSkipChecksum(skip bool) {
+	d.skipChecksum = skip
+}
+
 // DownloadInfo contains information about a download
 type DownloadInfo struct {
 	URL      string
 	Filename string
 	Size     int64
 	SHA256   string
+	// FromCache is set when the archive was reused from the download
+	// directory instead of being downloaded
+	FromCache bool
 }
 
 // GoRelease represents a Go release from the API
@@ -89,19 +231,23 @@ type File struct {
 	SHA256   string `json:"sha256"`
 }
 
-// GetDownloadInfo returns download information for a Go version
-func (d *Downloader) GetDownloadInfo(version string) (*DownloadInfo, error) {
+// GetDownloadInfo returns download information for a Go version. The request
+// for the downloads page is canceled with ctx.
+func (d *Downloader) GetDownloadInfo(ctx context.Context, version string) (*DownloadInfo, error) {
 	// Remove 'go' prefix if present
 	version = strings.TrimPrefix(version, "go")
 
 	// Determine filename based on OS and architecture
-	filename := d.getFilename(version)
+	filename, err := d.getFilename(version)
+	if err != nil {
+		return nil, err
+	}
 
 	// Construct download URL
 	url := fmt.Sprintf("%s/%s", d.baseURL, filename)
 
 	// Get file size and SHA256 from the checksum file
-	size, sha256, err := d.getFileInfo(version)
+	size, sha256, err := d.getFileInfo(ctx, version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file info: %w", err)
 	}
@@ -114,58 +260,228 @@ func (d *Downloader) GetDownloadInfo(version string) (*DownloadInfo, error) {
 	}, nil
 }
 
-// Download downloads a Go version to the specified directory
-func (d *Downloader) Download(version string, downloadDir string) (string, error) {
-	info, err := d.GetDownloadInfo(version)
+// Download downloads a Go version to the specified directory. Canceling ctx
+// stops the download.
+func (d *Downloader) Download(ctx context.Context, version string, downloadDir string) (string, error) {
+	localPath, _, err := d.DownloadWithInfo(ctx, version, downloadDir)
+	return localPath, err
+}
+
+// DownloadWithInfo downloads a Go version to the specified directory like
+// Download, and also returns the download information, including the SHA256
+// the archive was verified against
+func (d *Downloader) DownloadWithInfo(ctx context.Context, version string, downloadDir string) (string, *DownloadInfo, error) {
+	// Offline, a cached archive is verified against the checksum saved with
+	// it, so neither the downloads page nor --skip-checksum is needed
+	if d.offline && d.checksum == "" {
+		if localPath, cached, ok := d.savedArchive(ctx, version, downloadDir); ok {
+			return localPath, cached, nil
+		}
+	}
+
+	info, err := d.downloadInfo(ctx, version)
 	if err != nil {
-		return "", fmt.Errorf("failed to get download info: %w", err)
+		return "", nil, fmt.Errorf("failed to get download info: %w", err)
 	}
 
+	// Without a checksum neither a cached archive nor a fresh download can be
+	// verified
+	if !sha256Regex.MatchString(info.SHA256) {
+		if !d.skipChecksum {
+			return "", nil, fmt.Errorf("no valid SHA256 checksum published for %s; refusing to use an unverified archive (pass --checksum <sha256>, or --skip-checksum to install without verification)", info.Filename)
+		}
+		return d.downloadUnverified(ctx, info, downloadDir)
+	}
+	log.Debug("download URL: %s", info.URL)
+	log.Debug("expected SHA256: %s", info.SHA256)
+
 	// Create download directory if it doesn't exist
 	// #nosec G301 -- 0755 acceptable for temporary download directory
 	if err := os.MkdirAll(downloadDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create download directory: %w", err)
+		return "", nil, fmt.Errorf("failed to create download directory: %w", err)
 	}
 
 	// Construct local file path
 	localPath := filepath.Join(downloadDir, info.Filename)
 
-	// Check if file already exists and is valid
+	// Reuse a cached archive only if it matches the published checksum; a
+	// truncated or stale one is downloaded again
+	if _, err := os.Stat(localPath); err == nil {
+		progress.Emit(ctx, progress.ProgressEvent{Stage: progress.StageVerifying})
+	}
 	if d.isValidFile(localPath, info.SHA256) {
-		return localPath, nil
+		log.Debug("reusing cached archive %s", localPath)
+		saveChecksum(localPath, info.SHA256)
+		cached := *info
+		cached.FromCache = true
+		return localPath, &cached, nil
+	}
+	if _, err := os.Stat(localPath); err == nil {
+		log.Debug("cached archive %s failed checksum verification, downloading again", localPath)
+		if err := os.Remove(localPath); err != nil {
+			return "", nil, fmt.Errorf("failed to remove invalid cached archive: %w", err)
+		}
+	}
+
+	if d.offline {
+		return "", nil, fmt.Errorf("%w and %s is not in the download cache", ErrOffline, info.Filename)
 	}
 
 	// Download the file
-	if err := d.downloadFile(info.URL, localPath); err != nil {
-		return "", fmt.Errorf("failed to download file: %w", err)
+	if err := d.downloadFile(ctx, info.URL, localPath); err != nil {
+		return "", nil, fmt.Errorf("failed to download file: %w", err)
 	}
 
 	// Verify the downloaded file
+	progress.Emit(ctx, progress.ProgressEvent{Stage: progress.StageVerifying})
 	if !d.isValidFile(localPath, info.SHA256) {
 		if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
-			return "", fmt.Errorf("downloaded file failed verification (checksum mismatch); cleanup failed: %w", err)
+			return "", nil, fmt.Errorf("downloaded file failed verification (checksum mismatch); cleanup failed: %w", err)
+		}
+		return "", nil, fmt.Errorf("downloaded file failed verification (checksum mismatch)")
+	}
+	saveChecksum(localPath, info.SHA256)
+
+	return localPath, info, nil
+}
+
+// ChecksumSuffix is appended to the name of a cached archive for the file
+// holding the SHA256 the archive was verified against
+const ChecksumSuffix = ".sha256"
+
+// saveChecksum records the SHA256 a cached archive was verified against next
+// to it, in the format of sha256sum. A failure only costs offline reuse, so
+// it is not an error.
+func saveChecksum(localPath, sha256 string) {
+	data := fmt.Sprintf("%s  %s\n", sha256, filepath.Base(localPath))
+	// #nosec G306 -- 0644 acceptable for a checksum of a cached archive
+	if err := os.WriteFile(localPath+ChecksumSuffix, []byte(data), 0644); err != nil {
+		log.Debug("failed to save checksum of %s: %v", localPath, err)
+	}
+}
+
+// savedChecksum returns the SHA256 saved with a cached archive by
+// saveChecksum, or an empty string if there is none
+func savedChecksum(localPath string) string {
+	// #nosec G304 -- localPath is within the download directory
+	data, err := os.ReadFile(localPath + ChecksumSuffix)
+	if err != nil {
+		return ""
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || !sha256Regex.MatchString(fields[0]) {
+		return ""
+	}
+	return fields[0]
+}
+
+// savedArchive returns the cached archive of version in downloadDir if it
+// matches the checksum saved with it
+func (d *Downloader) savedArchive(ctx context.Context, version, downloadDir string) (string, *DownloadInfo, bool) {
+	filename, err := d.getFilename(strings.TrimPrefix(version, "go"))
+	if err != nil {
+		return "", nil, false
+	}
+	localPath := filepath.Join(downloadDir, filename)
+	sha256 := savedChecksum(localPath)
+	if sha256 == "" {
+		return "", nil, false
+	}
+
+	progress.Emit(ctx, progress.ProgressEvent{Stage: progress.StageVerifying})
+	if !d.isValidFile(localPath, sha256) {
+		log.Debug("cached archive %s does not match its saved checksum", localPath)
+		return "", nil, false
+	}
+	log.Debug("reusing cached archive %s, verified against its saved checksum", localPath)
+	return localPath, &DownloadInfo{
+		URL:       fmt.Sprintf("%s/%s", d.baseURL, filename),
+		Filename:  filename,
+		SHA256:    sha256,
+		FromCache: true,
+	}, true
+}
+
+// downloadInfo returns the download information for a version. With a
+// checksum set by SetChecksum the downloads page is not fetched; with
+// SetSkipChecksum a page that cannot be fetched or does not list the archive
+// leaves the checksum empty instead of failing.
+func (d *Downloader) downloadInfo(ctx context.Context, version string) (*DownloadInfo, error) {
+	if d.checksum == "" {
+		info, err := d.GetDownloadInfo(ctx, version)
+		if err == nil || !d.skipChecksum {
+			return info, err
 		}
-		return "", fmt.Errorf("downloaded file failed verification (checksum mismatch)")
+		log.Debug("no published checksum: %v", err)
+	}
+
+	filename, err := d.getFilename(strings.TrimPrefix(version, "go"))
+	if err != nil {
+		return nil, err
+	}
+	return &DownloadInfo{
+		URL:      fmt.Sprintf("%s/%s", d.baseURL, filename),
+		Filename: filename,
+		SHA256:   d.checksum,
+	}, nil
+}
+
+// downloadUnverified downloads an archive that has no checksum to verify
+// against, reusing one already in downloadDir
+func (d *Downloader) downloadUnverified(ctx context.Context, info *DownloadInfo, downloadDir string) (string, *DownloadInfo, error) {
+	log.Error("WARNING: no SHA256 checksum is available for %s; installing it WITHOUT verification (--skip-checksum)", info.Filename)
+
+	// #nosec G301 -- 0755 acceptable for temporary download directory
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create download directory: %w", err)
 	}
 
-	return localPath, nil
+	localPath := filepath.Join(downloadDir, info.Filename)
+	if _, err := os.Stat(localPath); err == nil {
+		log.Debug("reusing cached archive %s", localPath)
+		cached := *info
+		cached.FromCache = true
+		return localPath, &cached, nil
+	}
+	if d.offline {
+		return "", nil, fmt.Errorf("%w and %s is not in the download cache", ErrOffline, info.Filename)
+	}
+	if err := d.downloadFile(ctx, info.URL, localPath); err != nil {
+		return "", nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	return localPath, info, nil
+}
+
+// releaseArches maps GOARCH values to the architecture names used in Go
+// release archives. Go publishes no binary releases for other architectures.
+var releaseArches = map[string]string{
+	"386":      "386",
+	"amd64":    "amd64",
+	"arm":      "armv6l",
+	"arm64":    "arm64",
+	"loong64":  "loong64",
+	"mips":     "mips",
+	"mipsle":   "mipsle",
+	"mips64":   "mips64",
+	"mips64le": "mips64le",
+	"ppc64":    "ppc64",
+	"ppc64le":  "ppc64le",
+	"riscv64":  "riscv64",
+	"s390x":    "s390x",
 }
 
 // getFilename returns the appropriate filename for the current platform
-func (d *Downloader) getFilename(version string) string {
-	os := runtime.GOOS
-	arch := runtime.GOARCH
+func (d *Downloader) getFilename(version string) (string, error) {
+	return platformFilename(version, runtime.GOOS, runtime.GOARCH)
+}
 
-	// Handle special cases for architecture names
-	switch arch {
-	case "amd64":
-		arch = "amd64"
-	case "arm64":
-		arch = "arm64"
-	case "386":
-		arch = "386"
-	default:
-		arch = "amd64" // Default fallback
+// platformFilename returns the release archive name of version for goos and
+// goarch, or an error if Go publishes no binary release for goarch
+func platformFilename(version, goos, goarch string) (string, error) {
+	os := goos
+	arch, ok := releaseArches[goarch]
+	if !ok {
+		return "", fmt.Errorf("no Go binary releases are published for the %s architecture", goarch)
 	}
 
 	// Handle special cases for OS names
@@ -182,35 +498,73 @@ func (d *Downloader) getFilename(version string) string {
 	}
 
 	if os == "windows" {
-		return fmt.Sprintf("go%s.%s-%s.zip", version, os, arch)
+		return fmt.Sprintf("go%s.%s-%s.zip", version, os, arch), nil
 	}
 
-	return fmt.Sprintf("go%s.%s-%s.tar.gz", version, os, arch)
+	return fmt.Sprintf("go%s.%s-%s.tar.gz", version, os, arch), nil
 }
 
-// getFileInfo retrieves file size and SHA256 from the HTML page
-func (d *Downloader) getFileInfo(version string) (int64, string, error) {
-	// Download the main downloads page
-	pageURL := d.baseURL + "/"
+// downloadsPage returns the HTML of the downloads page, which lists every
+// version and checksum. A fetched page is saved to the page cache; offline,
+// the cached copy is returned instead.
+func (d *Downloader) downloadsPage(ctx context.Context) ([]byte, error) {
+	if d.offline {
+		if d.pageCache == "" {
+			return nil, fmt.Errorf("%w and no downloads page is cached", ErrOffline)
+		}
+		// #nosec G304 -- pageCache is set by the manager from the validated download directory
+		data, err := os.ReadFile(d.pageCache)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w and no list of Go versions is cached yet (run 'gopher list-remote' once while online)", ErrOffline)
+		}
+		return data, err
+	}
 
-	resp, err := d.client.Get(pageURL)
+	resp, err := d.get(ctx, d.baseURL+"/")
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to download downloads page: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, "", fmt.Errorf("failed to download downloads page: HTTP %d (check your internet connection)", resp.StatusCode)
+		return nil, fmt.Errorf("HTTP %d (check your internet connection)", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page content: %w", err)
+	}
+
+	if d.pageCache != "" {
+		if err := d.savePage(data); err != nil {
+			log.Debug("failed to cache downloads page: %v", err)
+		}
 	}
+	return data, nil
+}
 
-	// Read page content
-	pageData, err := io.ReadAll(resp.Body)
+// savePage writes a fetched downloads page to the page cache
+func (d *Downloader) savePage(data []byte) error {
+	// #nosec G301 -- 0755 acceptable for the download directory
+	if err := os.MkdirAll(filepath.Dir(d.pageCache), 0755); err != nil {
+		return err
+	}
+	// #nosec G306 -- the cached page is public data
+	return fileutil.WriteAtomic(d.pageCache, data, 0644)
+}
+
+// getFileInfo retrieves file size and SHA256 from the HTML page
+func (d *Downloader) getFileInfo(ctx context.Context, version string) (int64, string, error) {
+	pageData, err := d.downloadsPage(ctx)
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to read downloads page: %w", err)
+		return 0, "", fmt.Errorf("failed to download downloads page: %w", err)
 	}
 
 	// Parse HTML to find our file
-	filename := d.getFilename(version)
+	filename, err := d.getFilename(version)
+	if err != nil {
+		return 0, "", err
+	}
 	sha256, size, err := d.parseFileInfoFromHTML(string(pageData), filename)
 	if err != nil {
 		return 0, "", fmt.Errorf("failed to parse file info: %w", err)
@@ -276,10 +630,13 @@ func (d *Downloader) parseFileInfoFromHTML(html, filename string) (string, int64
 }
 
 // getFileSize gets the size of a file by making a HEAD request
-func (d *Downloader) getFileSize(filename string) (int64, error) {
+func (d *Downloader) getFileSize(ctx context.Context, filename string) (int64, error) {
 	url := fmt.Sprintf("%s/%s", d.baseURL, filename)
+	if d.offline {
+		return 0, fmt.Errorf("%w (HEAD %s)", ErrOffline, url)
+	}
 
-	req, err := http.NewRequest("HEAD", url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -288,7 +645,7 @@ func (d *Downloader) getFileSize(filename string) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
 		return 0, fmt.Errorf("failed to get file size: HTTP %d (check your internet connection)", resp.StatusCode)
@@ -298,21 +655,45 @@ func (d *Downloader) getFileSize(filename string) (int64, error) {
 }
 
 // downloadFile downloads a file from URL to local path
-func (d *Downloader) downloadFile(url, localPath string) error {
+//
+// The body is written to localPath with partSuffix appended and only renamed
+// to localPath once complete. On any failure, including a canceled ctx, the
+// partial file is removed, so localPath never holds a truncated archive.
+func (d *Downloader) downloadFile(ctx context.Context, url, localPath string) error {
+	partPath := localPath + partSuffix
+
 	// Create the file
-	// #nosec G304 -- localPath is constructed from validated downloadDir and filename
-	file, err := os.Create(localPath)
+	// #nosec G304 -- partPath is constructed from validated downloadDir and filename
+	file, err := os.Create(partPath)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer file.Close()
 
+	err = d.writeDownload(ctx, url, file, filepath.Base(localPath))
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write file: %w", closeErr)
+	}
+	if err == nil {
+		if renameErr := os.Rename(partPath, localPath); renameErr != nil {
+			err = fmt.Errorf("failed to save file: %w", renameErr)
+		}
+	}
+	if err != nil {
+		_ = os.Remove(partPath)
+		return err
+	}
+	return nil
+}
+
+// writeDownload writes the body at url to file, reporting progress under
+// name
+func (d *Downloader) writeDownload(ctx context.Context, url string, file *os.File, name string) error {
 	// Make the request
-	resp, err := d.client.Get(url)
+	resp, err := d.get(ctx, url)
 	if err != nil {
 		return fmt.Errorf("failed to Umake request: %w", err)
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("failed to download file: HTTP %d (check your internet connection)", resp.StatusCode)
@@ -320,25 +701,40 @@ func (d *Downloader) downloadFile(url, localPath string) error {
 
 	// Get file size for progress tracking
 	fileSize := resp.ContentLength
+
+	// Report progress events to a handler carried by ctx as well as to the
+	// sink or progress bar below
+	var dst io.Writer = file
+	if progress.HasEvents(ctx) {
+		dst = &sinkWriter{writer: file, sink: eventSink{ctx: ctx}, total: fileSize}
+	}
+
+	// Report progress through the configured sink when one is set
+	if d.progress != nil {
+		writer := &sinkWriter{writer: dst, sink: d.progress, total: fileSize}
+		if _, err := io.Copy(writer, resp.Body); err != nil {
+			return fmt.Errorf("failed to copy file: %w", err)
+		}
+		return nil
+	}
+
 	if fileSize <= 0 {
 		// If Content-Length is not available, we can't show progress
-		fmt.Printf("Downloading %s...\n", filepath.Base(localPath))
-		_, err = io.Copy(file, resp.Body)
+		fmt.Fprintf(os.Stderr, "Downloading %s...\n", name)
+		_, err = io.Copy(dst, resp.Body)
 		if err != nil {
 			return fmt.Errorf("failed to copy file: %w", err)
 		}
-		fmt.Println("✓ Download complete")
+		fmt.Fprintln(os.Stderr, "✓ Download complete")
 		return nil
 	}
 
 	// Create progress bar
-	progressBar := progress.NewProgressBar(fileSize, fmt.Sprintf("Downloading %s", filepath.Base(localPath)))
-
-	// Create progress writer
-	progressWriter := progress.NewProgressWriter(file, progressBar)
+	progressBar := progress.NewProgressBar(fileSize, fmt.Sprintf("Downloading %s", name))
 
 	// Copy the response body to the file with progress tracking
-	_, err = io.Copy(progressWriter, resp.Body)
+	writer := &sinkWriter{writer: dst, sink: &progressBarSink{bar: progressBar}, total: fileSize}
+	_, err = io.Copy(writer, resp.Body)
 	if err != nil {
 		return fmt.Errorf("failed to Ucopy file: %w", err)
 	}
@@ -351,6 +747,11 @@ func (d *Downloader) downloadFile(url, localPath string) error {
 
 // isValidFile checks if a file exists and has the correct SHA256
 func (d *Downloader) isValidFile(filePath, expectedSHA256 string) bool {
+	// A missing or malformed checksum never validates a file
+	if !sha256Regex.MatchString(expectedSHA256) {
+		return false
+	}
+
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return false
@@ -373,31 +774,22 @@ func (d *Downloader) isValidFile(filePath, expectedSHA256 string) bool {
 	return actualSHA256 == expectedSHA256
 }
 
-// Cleanup removes a downloaded file
+// Cleanup removes a downloaded file and the checksum saved with it
 func (d *Downloader) Cleanup(filePath string) error {
+	if err := os.Remove(filePath + ChecksumSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
 	return os.Remove(filePath)
 }
 
-// ListAvailableVersions fetches all available Go versions from the official page
-func (d *Downloader) ListAvailableVersions() ([]VersionInfo, error) {
+// ListAvailableVersions fetches all available Go versions from the official
+// page. The request is canceled with ctx.
+func (d *Downloader) ListAvailableVersions(ctx context.Context) ([]VersionInfo, error) {
 	// Fetch from the Go downloads page
-	pageURL := d.baseURL + "/"
-
-	resp, err := d.client.Get(pageURL)
+	htmlContent, err := d.downloadsPage(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch releases page: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch releases page: HTTP %d (check your internet connection)", resp.StatusCode)
-	}
-
-	// Read the HTML content
-	htmlContent, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read page content: %w", err)
-	}
 
 	// Parse versions from HTML
 	versions, err := d.parseVersionsFromHTML(string(htmlContent))
@@ -441,6 +833,8 @@ func (d *Downloader) isCompatibleFile(file GoFile) bool {
 		archMatch = file.Arch == "arm64" || file.Arch == "aarch64"
 	case "386":
 		archMatch = file.Arch == "386" || file.Arch == "i386"
+	case "arm":
+		archMatch = file.Arch == "armv6l" || file.Arch == "arm"
 	default:
 		archMatch = file.Arch == runtime.GOARCH
 	}
@@ -451,9 +845,73 @@ func (d *Downloader) isCompatibleFile(file GoFile) bool {
 	return archMatch && kindMatch
 }
 
+// CompareVersions compares two Go version strings, taking prerelease
+// suffixes (rc, beta, alpha) into account. Development builds ("devel
+// go1.22-abc123") are newer than every release.
+// Returns -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
+func CompareVersions(v1, v2 string) int {
+	return compareVersions(v1, v2)
+}
+
+// LatestPerMinor returns the highest version of each major.minor release
+// line in versions, e.g. go1.22.5 for 1.22.x. Each is placed where its line
+// first appears, so a newest-first list stays newest first. Filter the
+// channel beforehand to keep prereleases out.
+func LatestPerMinor(versions []VersionInfo) []VersionInfo {
+	type line struct{ major, minor int }
+	index := make(map[line]int)
+	var result []VersionInfo
+	for _, v := range versions {
+		parts := parseVersionParts(strings.TrimPrefix(v.Version, "go"))
+		key := line{parts.major, parts.minor}
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(result)
+			result = append(result, v)
+			continue
+		}
+		if compareVersions(v.Version, result[i].Version) > 0 {
+			result[i] = v
+		}
+	}
+	return result
+}
+
+// IsStableVersion reports whether version is a final release rather than a
+// beta, release candidate, alpha or development build.
+func IsStableVersion(version string) bool {
+	lower := strings.ToLower(version)
+	for _, marker := range []string{"beta", "rc", "alpha", develPrefix} {
+		if strings.Contains(lower, marker) {
+			return false
+		}
+	}
+	return true
+}
+
+// develPrefix starts the version 'go version' reports for a toolchain built
+// from source, e.g. "devel go1.22-abc123"
+const develPrefix = "devel"
+
 // compareVersions compares two version strings
 // Returns -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
 func compareVersions(v1, v2 string) int {
+	// A devel build is ahead of every release. Two devel builds are ordered
+	// by the release they are based on, then by their text.
+	devel1, base1 := splitDevelVersion(v1)
+	devel2, base2 := splitDevelVersion(v2)
+	switch {
+	case devel1 && !devel2:
+		return 1
+	case !devel1 && devel2:
+		return -1
+	case devel1 && devel2:
+		if cmp := compareVersions(base1, base2); cmp != 0 {
+			return cmp
+		}
+		return strings.Compare(v1, v2)
+	}
+
 	// Remove 'go' prefix for comparison
 	v1 = strings.TrimPrefix(v1, "go")
 	v2 = strings.TrimPrefix(v2, "go")
@@ -490,6 +948,17 @@ func compareVersions(v1, v2 string) int {
 	return comparePrerelease(parts1.prerelease, parts2.prerelease)
 }
 
+// splitDevelVersion reports whether version is a devel build and returns the
+// release it is based on ("devel go1.22-abc123" -> "go1.22")
+func splitDevelVersion(version string) (bool, string) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(version), develPrefix)
+	if !ok {
+		return false, version
+	}
+	base, _, _ := strings.Cut(strings.TrimSpace(rest), "-")
+	return true, base
+}
+
 // versionParts represents parsed version components
 type versionParts struct {
 	major      int
@@ -649,16 +1118,36 @@ func comparePrereleasePart(p1, p2 string) int {
 		return 1
 	}
 
-	// Both are non-numeric, compare lexicographically
-	if p1 < p2 {
+	// Both are non-numeric: compare the labels (alpha < beta < rc), then
+	// their numbers, so rc10 sorts after rc2
+	label1, n1 := splitPrereleaseLabel(p1)
+	label2, n2 := splitPrereleaseLabel(p2)
+	if label1 != label2 {
+		if label1 < label2 {
+			return -1
+		}
+		return 1
+	}
+	if n1 < n2 {
 		return -1
 	}
-	if p1 > p2 {
+	if n1 > n2 {
 		return 1
 	}
 	return 0
 }
 
+// splitPrereleaseLabel splits a prerelease part like "rc2" into its label
+// and trailing number; a missing number counts as 0
+func splitPrereleaseLabel(part string) (string, int) {
+	i := len(part)
+	for i > 0 && part[i-1] >= '0' && part[i-1] <= '9' {
+		i--
+	}
+	n, _ := parsePrereleaseNumber(part[i:])
+	return part[:i], n
+}
+
 // parsePrereleaseNumber tries to parse a string as a number
 func parsePrereleaseNumber(s string) (int, bool) {
 	if s == "" {
@@ -750,25 +1239,21 @@ func (d *Downloader) extractVersionFromHref(href string) string {
 
 // addVersionToMap adds a version to the version map
 func (d *Downloader) addVersionToMap(versionMap map[string]VersionInfo, version string) {
-	// Determine if it's stable (not beta, rc, etc.)
-	stable := !strings.Contains(strings.ToLower(version), "beta") &&
-		!strings.Contains(strings.ToLower(version), "rc") &&
-		!strings.Contains(strings.ToLower(version), "alpha")
-
-	// Create a compatible file entry for current platform
-	compatibleFiles := []File{
-		{
-			Filename: d.getFilename(version),
+	// Create a compatible file entry for current platform, if there is one
+	compatibleFiles := []File{}
+	if filename, err := d.getFilename(version); err == nil {
+		compatibleFiles = append(compatibleFiles, File{
+			Filename: filename,
 			OS:       runtime.GOOS,
 			Arch:     runtime.GOARCH,
 			Size:     0,  // Will be filled when actually downloading
 			SHA256:   "", // Will be filled when actually downloading
-		},
+		})
 	}
 
 	versionMap[version] = VersionInfo{
 		Version:     version,
-		Stable:      stable,
+		Stable:      IsStableVersion(version),
 		ReleaseDate: "", // We don't have release dates from HTML
 		Files:       compatibleFiles,
 	}
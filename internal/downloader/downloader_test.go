@@ -1,13 +1,20 @@
 package downloader
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -31,6 +38,23 @@ func TestParseVersionNumber(t *testing.T) {
 	}
 }
 
+func TestIsStableVersion(t *testing.T) {
+	for v, want := range map[string]bool{
+		"go1.21.0":            true,
+		"go1.20":              true,
+		"1.22.5":              true,
+		"go1.21rc2":           false,
+		"go1.21beta1":         false,
+		"go1.5alpha1":         false,
+		"devel go1.22-abc123": false,
+		"GO1.21RC1":           false,
+	} {
+		if got := IsStableVersion(v); got != want {
+			t.Errorf("IsStableVersion(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
 func TestCompareVersions(t *testing.T) {
 	cases := []struct {
 		a, b string
@@ -39,7 +63,14 @@ func TestCompareVersions(t *testing.T) {
 		{"go1.25.1", "go1.25.0", 1},
 		{"go1.25.1", "go1.24.9", 1},
 		{"go1.25rc2", "go1.25rc1", 1},
+		{"go1.25rc1", "go1.25beta1", 1},
+		{"devel go1.22-abc123", "go1.25.1", 1},
+		{"devel go1.22-abc123", "go1.26rc1", 1},
+		{"go1.21.0", "devel go1.20-abc123", -1},
+		{"devel go1.23-abc123", "devel go1.22-def456", 1},
+		{"devel go1.22-abc123", "devel go1.22-abc123", 0},
 		{"go1.25", "go1.25rc3", 1}, // stable > prerelease
+		{"go1.20.1", "go1.20", 1},  // two-component first release
 		{"go1.21.3", "go1.21.3", 0},
 		{"go1.19.9", "go1.20.0", -1},
 	}
@@ -51,6 +82,26 @@ func TestCompareVersions(t *testing.T) {
 	}
 }
 
+func TestLatestPerMinor(t *testing.T) {
+	var versions []VersionInfo
+	for _, v := range []string{"go1.23rc1", "go1.22.1", "go1.22.10", "go1.22.2", "go1.21.0", "go1.22rc2", "go1.21.13", "go1.20"} {
+		versions = append(versions, VersionInfo{Version: v})
+	}
+
+	var got []string
+	for _, v := range LatestPerMinor(versions) {
+		got = append(got, v.Version)
+	}
+	want := []string{"go1.23rc1", "go1.22.10", "go1.21.13", "go1.20"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("LatestPerMinor = %v, want %v", got, want)
+	}
+
+	if got := LatestPerMinor(nil); len(got) != 0 {
+		t.Errorf("LatestPerMinor(nil) = %v, want empty", got)
+	}
+}
+
 func TestParseVersionParts(t *testing.T) {
 	p := parseVersionParts("1.25.3rc2")
 	if p.major != 1 || p.minor != 25 || p.patch != 3 {
@@ -81,6 +132,7 @@ func TestComparePrerelease(t *testing.T) {
 		{"rc2", "rc1", 1},
 		{"beta1", "alpha1", 1},
 		{"rc1", "rc1", 0},
+		{"rc10", "rc9", 1},
 	}
 
 	for _, c := range cases {
@@ -97,7 +149,8 @@ func TestComparePrereleasePart(t *testing.T) {
 	}{
 		{"rc1", "rc2", -1},
 		{"rc2", "rc1", 1},
-		{"rc10", "rc2", -1},
+		{"rc10", "rc2", 1},
+		{"beta2", "rc1", -1},
 		{"alpha1", "alpha1", 0},
 		{"", "rc1", -1},
 		{"rc1", "", 1},
@@ -166,13 +219,52 @@ func TestWithClientNil(t *testing.T) {
 	}
 }
 
+func TestSharedTransport(t *testing.T) {
+	if New("https://go.dev/dl/").client.Transport != sharedTransport {
+		t.Error("New should use the shared transport")
+	}
+	if WithClient("https://go.dev/dl/", nil).client.Transport != sharedTransport {
+		t.Error("WithClient(nil) should use the shared transport")
+	}
+	if !sharedTransport.ForceAttemptHTTP2 || sharedTransport.MaxIdleConnsPerHost < 2 || sharedTransport.IdleConnTimeout == 0 {
+		t.Errorf("shared transport is not tuned: %+v", sharedTransport)
+	}
+}
+
+func TestConnectionReuse(t *testing.T) {
+	var newConns int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "missing.tar.gz") {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", "42")
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConns, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	d := WithClient(server.URL, &http.Client{Transport: newTransport()})
+	for _, name := range []string{"go1.21.0.tar.gz", "missing.tar.gz", "go1.22.0.tar.gz"} {
+		_, _ = d.getFileSize(context.Background(), name)
+	}
+
+	if n := atomic.LoadInt32(&newConns); n != 1 {
+		t.Errorf("expected requests to share one connection, opened %d", n)
+	}
+}
+
 func TestGetFilename(t *testing.T) {
 	d := New("https://go.dev/dl/")
 
 	// Test with current platform
-	result := d.getFilename("1.21.0")
-	if result == "" {
-		t.Error("Expected non-empty filename")
+	result, err := d.getFilename("1.21.0")
+	if err != nil {
+		t.Fatalf("getFilename failed: %v", err)
 	}
 
 	// Test that it contains expected elements
@@ -187,6 +279,39 @@ func TestGetFilename(t *testing.T) {
 	}
 }
 
+func TestPlatformFilename(t *testing.T) {
+	tests := []struct {
+		goos, goarch string
+		want         string
+		wantErr      bool
+	}{
+		{"darwin", "arm64", "go1.21.0.darwin-arm64.tar.gz", false},
+		{"darwin", "amd64", "go1.21.0.darwin-amd64.tar.gz", false},
+		{"linux", "386", "go1.21.0.linux-386.tar.gz", false},
+		{"windows", "386", "go1.21.0.windows-386.zip", false},
+		{"linux", "arm", "go1.21.0.linux-armv6l.tar.gz", false},
+		{"linux", "riscv64", "go1.21.0.linux-riscv64.tar.gz", false},
+		{"linux", "loong64", "go1.21.0.linux-loong64.tar.gz", false},
+		{"linux", "wasm", "", true},
+		{"linux", "sparc64", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos+"/"+tt.goarch, func(t *testing.T) {
+			got, err := platformFilename("1.21.0", tt.goos, tt.goarch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("platformFilename() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("platformFilename() = %q, want %q", got, tt.want)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), tt.goarch) {
+				t.Errorf("error should name the architecture: %v", err)
+			}
+		})
+	}
+}
+
 func TestDownloadInfo(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -234,7 +359,7 @@ func TestDownloadInfo(t *testing.T) {
 	d := New(server.URL)
 
 	// Test GetDownloadInfo
-	info, err := d.GetDownloadInfo("1.21.0")
+	info, err := d.GetDownloadInfo(context.Background(), "1.21.0")
 	if err != nil {
 		t.Fatalf("GetDownloadInfo failed: %v", err)
 	}
@@ -318,7 +443,7 @@ func TestDownload(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Test Download
-	filePath, err := d.Download("1.21.0", tmpDir)
+	filePath, err := d.Download(context.Background(), "1.21.0", tmpDir)
 	if err != nil {
 		t.Fatalf("Download failed: %v", err)
 	}
@@ -399,7 +524,7 @@ func TestDownloadExistingFile(t *testing.T) {
 	}
 
 	// Test Download with existing file
-	resultPath, err := d.Download("1.21.0", tmpDir)
+	resultPath, err := d.Download(context.Background(), "1.21.0", tmpDir)
 	if err != nil {
 		t.Fatalf("Download failed: %v", err)
 	}
@@ -418,6 +543,322 @@ func TestDownloadExistingFile(t *testing.T) {
 	}
 }
 
+func TestDownloadReplacesInvalidCachedFile(t *testing.T) {
+	content := []byte("mock file content")
+	sum := sha256.Sum256(content)
+	filename := fmt.Sprintf("go1.21.0.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		filename = fmt.Sprintf("go1.21.0.%s-%s.zip", runtime.GOOS, runtime.GOARCH)
+	}
+
+	archiveRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `<table><tr><td><a class="download" href="/dl/%s">%s</a></td><td>0.0MB</td><td><tt>%x</tt></td></tr></table>`, filename, filename, sum)
+		case "/" + filename:
+			archiveRequests++
+			_, _ = w.Write(content)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	// A truncated archive left in the cache by an interrupted download
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, filename)
+	if err := os.WriteFile(filePath, content[:4], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := New(server.URL)
+	resultPath, info, err := d.DownloadWithInfo(context.Background(), "1.21.0", tmpDir)
+	if err != nil {
+		t.Fatalf("DownloadWithInfo failed: %v", err)
+	}
+	if resultPath != filePath {
+		t.Errorf("Expected path %s, got %s", filePath, resultPath)
+	}
+	if archiveRequests != 1 {
+		t.Errorf("Expected the archive to be downloaded again, got %d requests", archiveRequests)
+	}
+	if info.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected SHA256 %x, got %s", sum, info.SHA256)
+	}
+	if info.FromCache {
+		t.Error("Expected a downloaded archive not to be reported as cached")
+	}
+
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("Expected the cached file to be replaced, got %q", got)
+	}
+
+	// The now valid archive is reused
+	_, info, err = d.DownloadWithInfo(context.Background(), "1.21.0", tmpDir)
+	if err != nil {
+		t.Fatalf("DownloadWithInfo failed: %v", err)
+	}
+	if !info.FromCache || archiveRequests != 1 {
+		t.Errorf("Expected the cached archive to be reused, got FromCache=%v after %d requests", info.FromCache, archiveRequests)
+	}
+}
+
+// newChecksumlessServer serves archive at /<filename> and no downloads page,
+// like a mirror that publishes no checksums. pageRequests counts requests
+// for the page.
+func newChecksumlessServer(t *testing.T, filename string, archive []byte, pageRequests *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + filename:
+			_, _ = w.Write(archive)
+		case "/":
+			*pageRequests++
+			http.NotFound(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadWithChecksumOverride(t *testing.T) {
+	content := []byte("mock file content")
+	sum := sha256.Sum256(content)
+	d := New("https://go.dev/dl/")
+	filename, _ := d.getFilename("1.21.0")
+
+	pageRequests := 0
+	server := newChecksumlessServer(t, filename, content, &pageRequests)
+
+	d = New(server.URL)
+	if err := d.SetChecksum(strings.ToUpper(hex.EncodeToString(sum[:]))); err != nil {
+		t.Fatalf("SetChecksum failed: %v", err)
+	}
+	_, info, err := d.DownloadWithInfo(context.Background(), "1.21.0", t.TempDir())
+	if err != nil {
+		t.Fatalf("DownloadWithInfo failed: %v", err)
+	}
+	if info.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected SHA256 %x, got %s", sum, info.SHA256)
+	}
+	if pageRequests != 0 {
+		t.Errorf("Expected the downloads page not to be fetched, got %d requests", pageRequests)
+	}
+
+	// A checksum that does not match the archive fails verification
+	wrong := sha256.Sum256([]byte("other content"))
+	if err := d.SetChecksum(hex.EncodeToString(wrong[:])); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := d.DownloadWithInfo(context.Background(), "1.21.0", t.TempDir()); err == nil {
+		t.Error("Expected a checksum mismatch error")
+	}
+
+	if err := d.SetChecksum("not-a-checksum"); err == nil {
+		t.Error("Expected an error for a malformed checksum")
+	}
+}
+
+func TestDownloadWithoutChecksum(t *testing.T) {
+	content := []byte("mock file content")
+	d := New("https://go.dev/dl/")
+	filename, _ := d.getFilename("1.21.0")
+
+	pageRequests := 0
+	server := newChecksumlessServer(t, filename, content, &pageRequests)
+
+	d = New(server.URL)
+	if _, _, err := d.DownloadWithInfo(context.Background(), "1.21.0", t.TempDir()); err == nil {
+		t.Fatal("Expected an error when no checksum is available")
+	}
+
+	d.SetSkipChecksum(true)
+	tmpDir := t.TempDir()
+	path, info, err := d.DownloadWithInfo(context.Background(), "1.21.0", tmpDir)
+	if err != nil {
+		t.Fatalf("DownloadWithInfo with skip failed: %v", err)
+	}
+	if info.SHA256 != "" {
+		t.Errorf("Expected no SHA256, got %s", info.SHA256)
+	}
+	if got, err := os.ReadFile(path); err != nil || !bytes.Equal(got, content) {
+		t.Errorf("Expected the archive at %s, got %q, %v", path, got, err)
+	}
+}
+
+func TestDownloadCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Serve part of the archive, then cancel and stall like a hung download
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	d := New(server.URL)
+	d.SetProgressSink(NoopProgressSink{})
+	sum := sha256.Sum256([]byte("archive"))
+	if err := d.SetChecksum(hex.EncodeToString(sum[:])); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	_, err := d.Download(ctx, "1.21.0", tmpDir)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	assertEmptyDir(t, tmpDir)
+
+	// A canceled context stops requests before they are made
+	if _, err := d.ListAvailableVersions(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from ListAvailableVersions, got %v", err)
+	}
+}
+
+func TestDownloadFailureLeavesNoFile(t *testing.T) {
+	// The connection drops after part of the archive was sent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	d := New(server.URL)
+	d.SetProgressSink(NoopProgressSink{})
+	sum := sha256.Sum256([]byte("archive"))
+	if err := d.SetChecksum(hex.EncodeToString(sum[:])); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	if _, err := d.Download(context.Background(), "1.21.0", tmpDir); err == nil {
+		t.Fatal("Expected the truncated download to fail")
+	}
+	assertEmptyDir(t, tmpDir)
+}
+
+// assertEmptyDir fails the test if dir holds any file, such as a partial
+// download
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		t.Errorf("Expected no files left behind, found %s", entry.Name())
+	}
+}
+
+func TestOffline(t *testing.T) {
+	content := []byte("mock file content")
+	sum := sha256.Sum256(content)
+	filename, _ := New("https://go.dev/dl/").getFilename("1.21.0")
+	page := fmt.Sprintf(`<tr>
+		<td><a class="download" href="/dl/%s">%s</a></td>
+		<td>0.0MB</td>
+		<td><tt>%x</tt></td>
+	</tr>`, filename, filename, sum)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(page))
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	downloadDir := t.TempDir()
+	pageCache := filepath.Join(downloadDir, "downloads.html")
+	d := New(server.URL)
+	d.SetPageCache(pageCache)
+	d.SetOffline(true)
+
+	// Nothing is cached yet
+	if _, err := d.ListAvailableVersions(context.Background()); !errors.Is(err, ErrOffline) {
+		t.Fatalf("ListAvailableVersions offline without a cache = %v, want ErrOffline", err)
+	}
+
+	// An online listing saves the page
+	d.SetOffline(false)
+	online, err := d.ListAvailableVersions(context.Background())
+	if err != nil {
+		t.Fatalf("ListAvailableVersions failed: %v", err)
+	}
+	if _, err := os.Stat(pageCache); err != nil {
+		t.Fatalf("Expected the downloads page to be cached: %v", err)
+	}
+
+	d.SetOffline(true)
+	requests = 0
+	offline, err := d.ListAvailableVersions(context.Background())
+	if err != nil {
+		t.Fatalf("ListAvailableVersions offline failed: %v", err)
+	}
+	if len(offline) != len(online) {
+		t.Errorf("Expected %d cached versions, got %d", len(online), len(offline))
+	}
+
+	// An archive that is not cached cannot be downloaded
+	if _, err := d.Download(context.Background(), "1.21.0", downloadDir); !errors.Is(err, ErrOffline) {
+		t.Errorf("Download offline without the archive = %v, want ErrOffline", err)
+	}
+
+	// A cached archive is verified against the cached checksum
+	if err := os.WriteFile(filepath.Join(downloadDir, filename), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Download(context.Background(), "1.21.0", downloadDir); err != nil {
+		t.Errorf("Download offline with a cached archive failed: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests offline, got %d", requests)
+	}
+
+	// The checksum saved with the archive verifies it without the page cache
+	if err := os.Remove(pageCache); err != nil {
+		t.Fatal(err)
+	}
+	_, info, err := d.DownloadWithInfo(context.Background(), "1.21.0", downloadDir)
+	if err != nil {
+		t.Fatalf("Download offline with a saved checksum failed: %v", err)
+	}
+	if !info.FromCache || info.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected the cached archive with SHA256 %x, got %+v", sum, info)
+	}
+
+	// A tampered archive does not match its saved checksum
+	if err := os.WriteFile(filepath.Join(downloadDir, filename), []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Download(context.Background(), "1.21.0", downloadDir); err == nil {
+		t.Error("Expected a tampered cached archive to be rejected offline")
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests offline, got %d", requests)
+	}
+}
+
 func TestIsValidFile(t *testing.T) {
 	d := New("https://go.dev/dl/")
 
@@ -439,6 +880,11 @@ func TestIsValidFile(t *testing.T) {
 		t.Error("Expected file to be invalid with incorrect hash")
 	}
 
+	// A missing checksum never validates a file
+	if d.isValidFile(tmpFile, "") {
+		t.Error("Expected file to be invalid without a checksum")
+	}
+
 	// Test with non-existent file
 	if d.isValidFile("/nonexistent/file", hash) {
 		t.Error("Expected non-existent file to be invalid")
@@ -495,7 +941,7 @@ func TestListAvailableVersions(t *testing.T) {
 	d := New(server.URL)
 
 	// Test ListAvailableVersions
-	versions, err := d.ListAvailableVersions()
+	versions, err := d.ListAvailableVersions(context.Background())
 	if err != nil {
 		t.Fatalf("ListAvailableVersions failed: %v", err)
 	}
@@ -578,7 +1024,7 @@ func TestGetFileSize(t *testing.T) {
 	d := New(server.URL)
 
 	// Test getFileSize
-	size, err := d.getFileSize("go1.21.0.linux-amd64.tar.gz")
+	size, err := d.getFileSize(context.Background(), "go1.21.0.linux-amd64.tar.gz")
 	if err != nil {
 		t.Fatalf("getFileSize failed: %v", err)
 	}
@@ -598,7 +1044,7 @@ func TestGetFileSizeError(t *testing.T) {
 	d := New(server.URL)
 
 	// Test getFileSize with error
-	_, err := d.getFileSize("nonexistent.tar.gz")
+	_, err := d.getFileSize(context.Background(), "nonexistent.tar.gz")
 	if err == nil {
 		t.Error("Expected error for non-existent file")
 	}
@@ -733,3 +1179,51 @@ func TestIsVersionInMap(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckMirror(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantErr bool
+	}{
+		{"reachable", func(w http.ResponseWriter, r *http.Request) {}, false},
+		{"HEAD not allowed", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		}, false},
+		{"error response", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			err := WithClient(server.URL, server.Client()).CheckMirror(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckMirror() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := server.URL
+		server.Close()
+
+		if err := WithClient(url, nil).CheckMirror(context.Background()); err == nil {
+			t.Error("expected error for a closed server")
+		}
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		d := New("https://go.dev/dl/")
+		d.SetOffline(true)
+		if err := d.CheckMirror(context.Background()); !errors.Is(err, ErrOffline) {
+			t.Errorf("expected ErrOffline, got %v", err)
+		}
+	})
+}
@@ -24,3 +24,11 @@ type FileSystem interface {
 type ProgressWriter interface {
 	Write(p []byte) (n int, err error)
 }
+
+// ProgressSink receives download progress updates.
+//
+// done is the number of bytes written so far and total is the expected size,
+// or a value <= 0 when the server did not report a Content-Length.
+type ProgressSink interface {
+	Update(done, total int64)
+}
@@ -0,0 +1,52 @@
+package downloader
+
+import (
+	"context"
+	"io"
+
+	"github.com/molmedoz/gopher/internal/progress"
+)
+
+// NoopProgressSink discards all progress updates. Use it when output must stay
+// machine-readable (e.g. --json) or when embedding the downloader as a library.
+type NoopProgressSink struct{}
+
+// Update implements ProgressSink
+func (NoopProgressSink) Update(done, total int64) {}
+
+// progressBarSink renders progress updates as a terminal progress bar
+type progressBarSink struct {
+	bar *progress.ProgressBar
+}
+
+// Update implements ProgressSink
+func (s *progressBarSink) Update(done, total int64) {
+	s.bar.Update(done)
+}
+
+// eventSink reports download progress as progress events to the handler
+// carried by ctx; see progress.WithEvents
+type eventSink struct {
+	ctx context.Context
+}
+
+// Update implements ProgressSink
+func (s eventSink) Update(done, total int64) {
+	progress.Emit(s.ctx, progress.ProgressEvent{Stage: progress.StageDownloading, Done: done, Total: total})
+}
+
+// sinkWriter wraps an io.Writer and reports the bytes written to a ProgressSink
+type sinkWriter struct {
+	writer io.Writer
+	sink   ProgressSink
+	done   int64
+	total  int64
+}
+
+// Write implements io.Writer
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.done += int64(n)
+	w.sink.Update(w.done, w.total)
+	return n, err
+}
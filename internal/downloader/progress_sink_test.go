@@ -0,0 +1,67 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+// recordingSink captures progress updates for assertions
+type recordingSink struct {
+	updates [][2]int64
+}
+
+func (s *recordingSink) Update(done, total int64) {
+	s.updates = append(s.updates, [2]int64{done, total})
+}
+
+func TestSinkWriter(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &recordingSink{}
+	w := &sinkWriter{writer: &buf, sink: sink, total: 10}
+
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := w.Write([]byte("world")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if buf.String() != "helloworld" {
+		t.Errorf("Expected 'helloworld', got %q", buf.String())
+	}
+	want := [][2]int64{{5, 10}, {10, 10}}
+	if len(sink.updates) != len(want) {
+		t.Fatalf("Expected %d updates, got %d", len(want), len(sink.updates))
+	}
+	for i := range want {
+		if sink.updates[i] != want[i] {
+			t.Errorf("Update %d: expected %v, got %v", i, want[i], sink.updates[i])
+		}
+	}
+}
+
+func TestDownloadWithProgressSink(t *testing.T) {
+	filename := "go1.21.0.linux-amd64.tar.gz"
+	d, _, _ := SetupMockDownloadScenario(t, "1.21.0", filename)
+	sink := &recordingSink{}
+	d.SetProgressSink(sink)
+
+	if err := d.downloadFile(context.Background(), d.baseURL+"/dl/"+filename, filepath.Join(t.TempDir(), filename)); err != nil {
+		t.Fatalf("downloadFile failed: %v", err)
+	}
+
+	if len(sink.updates) == 0 {
+		t.Fatal("Expected progress updates to be reported to the sink")
+	}
+	last := sink.updates[len(sink.updates)-1]
+	if last[0] != int64(len("mock file content")) || last[1] != last[0] {
+		t.Errorf("Expected final update to report completion, got %v", last)
+	}
+}
+
+func TestNoopProgressSink(t *testing.T) {
+	var sink ProgressSink = NoopProgressSink{}
+	sink.Update(1, 2) // must not panic or print
+}
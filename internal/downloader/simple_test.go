@@ -1,6 +1,7 @@
 package downloader
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -66,7 +67,7 @@ func TestDownloadWithMock(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Test Download
-	filePath, err := d.Download("1.21.0", tmpDir)
+	filePath, err := d.Download(context.Background(), "1.21.0", tmpDir)
 	if err != nil {
 		t.Fatalf("Download failed: %v", err)
 	}
@@ -131,7 +132,7 @@ func TestGetDownloadInfo(t *testing.T) {
 	d := New(server.URL)
 
 	// Test GetDownloadInfo - should work for current platform
-	info, err := d.GetDownloadInfo("1.21.0")
+	info, err := d.GetDownloadInfo(context.Background(), "1.21.0")
 	if err != nil {
 		t.Fatalf("GetDownloadInfo failed: %v", err)
 	}
@@ -59,6 +59,7 @@ const (
 	ErrCodeUninstallationFailed    ErrorCode = "UNINSTALLATION_FAILED"
 	ErrCodeDownloadFailed          ErrorCode = "DOWNLOAD_FAILED"
 	ErrCodeExtractionFailed        ErrorCode = "EXTRACTION_FAILED"
+	ErrCodePlatformMismatch        ErrorCode = "PLATFORM_MISMATCH"
 
 	// System errors
 	ErrCodeSystemGoNotAvailable   ErrorCode = "SYSTEM_GO_NOT_AVAILABLE"
@@ -79,6 +80,7 @@ const (
 	ErrCodeAliasSaveFailed    ErrorCode = "ALIAS_SAVE_FAILED"
 	ErrCodeAliasUpdateFailed  ErrorCode = "ALIAS_UPDATE_FAILED"
 	ErrCodeAliasRemoveFailed  ErrorCode = "ALIAS_REMOVE_FAILED"
+	ErrCodeAliasFileCorrupt   ErrorCode = "ALIAS_FILE_CORRUPT"
 
 	// File system errors
 	ErrCodeFileNotFound       ErrorCode = "FILE_NOT_FOUND"
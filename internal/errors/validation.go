@@ -23,8 +23,10 @@ func (v *Validator) ValidateVersion(version string) error {
 	// Remove 'go' prefix if present
 	version = strings.TrimPrefix(version, "go")
 
-	// Basic version format validation (semantic versioning)
-	versionRegex := regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?(?:-([a-zA-Z0-9\-]+))?(?:\+([a-zA-Z0-9\-]+))?$`)
+	// Basic version format validation (semantic versioning), plus Go's own
+	// prerelease suffixes ("1.22rc1", "1.21beta2") and two-component first
+	// releases ("1.20")
+	versionRegex := regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?(?:(?:rc|beta|alpha)\d+)?(?:-([a-zA-Z0-9\-]+))?(?:\+([a-zA-Z0-9\-]+))?$`)
 	if !versionRegex.MatchString(version) {
 		return NewInvalidVersion(version)
 	}
@@ -57,6 +59,7 @@ func (v *Validator) ValidateAliasName(name string) error {
 	reservedNames := map[string]bool{
 		"system":      true,
 		"sys":         true,
+		"homebrew":    true,
 		"install":     true,
 		"uninstall":   true,
 		"use":         true,
@@ -109,7 +112,7 @@ func (v *Validator) ValidateAliasName(name string) error {
 func (v *Validator) ValidateConfigValue(key, value string) error {
 	switch key {
 	case "gopath_mode":
-		validModes := []string{"shared", "version-specific", "custom"}
+		validModes := []string{"shared", "version-specific", "custom", "per-project"}
 		for _, mode := range validModes {
 			if value == mode {
 				return nil
@@ -117,6 +120,31 @@ func (v *Validator) ValidateConfigValue(key, value string) error {
 		}
 		return New(ErrCodeInvalidConfigValue, fmt.Sprintf("gopath_mode must be one of: %s", strings.Join(validModes, ", ")))
 
+	case "gobin_mode":
+		validModes := []string{"gopath-bin", "version-specific", "custom"}
+		for _, mode := range validModes {
+			if value == mode {
+				return nil
+			}
+		}
+		return New(ErrCodeInvalidConfigValue, fmt.Sprintf("gobin_mode must be one of: %s", strings.Join(validModes, ", ")))
+
+	case "default_channel":
+		if value != "stable" && value != "unstable" {
+			return New(ErrCodeInvalidConfigValue, "default_channel must be 'stable' or 'unstable'")
+		}
+		return nil
+
+	case "linked_binaries":
+		// Comma-separated binary names from the version's bin directory
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+				return New(ErrCodeInvalidConfigValue, fmt.Sprintf("linked_binaries must be comma-separated binary names, got %q", value))
+			}
+		}
+		return nil
+
 	case "set_environment":
 		if value != "true" && value != "false" {
 			return New(ErrCodeInvalidConfigValue, "set_environment must be 'true' or 'false'")
@@ -129,6 +157,24 @@ func (v *Validator) ValidateConfigValue(key, value string) error {
 		}
 		return nil
 
+	case "keep_downloads":
+		if value != "true" && value != "false" {
+			return New(ErrCodeInvalidConfigValue, "keep_downloads must be 'true' or 'false'")
+		}
+		return nil
+
+	case "strict_hooks":
+		if value != "true" && value != "false" {
+			return New(ErrCodeInvalidConfigValue, "strict_hooks must be 'true' or 'false'")
+		}
+		return nil
+
+	case "auto_install_on_use":
+		if value != "true" && value != "false" {
+			return New(ErrCodeInvalidConfigValue, "auto_install_on_use must be 'true' or 'false'")
+		}
+		return nil
+
 	case "max_versions":
 		// This would need to be parsed as an integer, but we'll do basic validation here
 		if value == "" {
@@ -152,11 +198,109 @@ func (v *Validator) ValidateConfigValue(key, value string) error {
 		}
 		return nil
 
+	case "custom_gobin":
+		if value == "" {
+			return New(ErrCodeInvalidConfigValue, "custom_gobin cannot be empty when gobin_mode is 'custom'")
+		}
+		return nil
+
+	case "goproxy":
+		return validateGOPROXY(value)
+
+	case "gosumdb":
+		return validateGOSUMDB(value)
+
+	case "goflags":
+		return validateGOFLAGS(value)
+
+	case "gotoolchain":
+		return validateGOTOOLCHAIN(value)
+
 	default:
 		return NewUnknownConfigOption(key)
 	}
 }
 
+// gotoolchainRegex matches a GOTOOLCHAIN setting: "local", "auto", "path" or
+// a toolchain name, optionally followed by "+auto" or "+path"
+var gotoolchainRegex = regexp.MustCompile(`^(local|auto|path|go1(\.\d+){1,2}((rc|beta)\d+)?)(\+(auto|path))?$`)
+
+// validateGOFLAGS checks that value is a space-separated list of flags, as
+// the go command requires; empty leaves GOFLAGS unset
+func validateGOFLAGS(value string) error {
+	for _, field := range strings.Fields(value) {
+		if !strings.HasPrefix(field, "-") {
+			return Newf(ErrCodeInvalidFormat, "invalid goflags entry %q: each entry must be a flag such as -mod=readonly", field)
+		}
+	}
+	return nil
+}
+
+// validateGOTOOLCHAIN checks that value is a GOTOOLCHAIN setting; empty
+// leaves GOTOOLCHAIN unset
+func validateGOTOOLCHAIN(value string) error {
+	if value != "" && !gotoolchainRegex.MatchString(value) {
+		return Newf(ErrCodeInvalidFormat, "invalid gotoolchain %q: must be 'local', 'auto', 'path' or a toolchain such as go1.21.0, optionally with +auto or +path", value)
+	}
+	return nil
+}
+
+// knownChecksumDBs lists the checksum databases the go command knows the
+// public keys of
+var knownChecksumDBs = []string{"sum.golang.org", "sum.golang.google.cn"}
+
+// validateGOPROXY checks that value is a GOPROXY list: proxy URLs or the
+// keywords "direct" and "off", separated by ',' or '|'
+func validateGOPROXY(value string) error {
+	if value == "" {
+		return New(ErrCodeInvalidFormat, "goproxy cannot be empty (use 'direct' or 'off' to disable the proxy)")
+	}
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '|' }) {
+		if entry == "direct" || entry == "off" {
+			continue
+		}
+		if !strings.HasPrefix(entry, "https://") && !strings.HasPrefix(entry, "http://") && !strings.HasPrefix(entry, "file://") {
+			return Newf(ErrCodeInvalidFormat, "invalid goproxy entry %q: must be a URL, 'direct' or 'off'", entry)
+		}
+		if strings.ContainsAny(entry, " \t") {
+			return Newf(ErrCodeInvalidFormat, "invalid goproxy entry %q: must not contain spaces", entry)
+		}
+	}
+	return nil
+}
+
+// validateGOSUMDB checks that value is "off", a known checksum database, or
+// a database given with its public key ("name+key [url]")
+func validateGOSUMDB(value string) error {
+	if value == "off" {
+		return nil
+	}
+	invalid := Newf(ErrCodeInvalidFormat, "invalid gosumdb %q: must be 'off', one of %s, or '<name>+<key> [url]'",
+		value, strings.Join(knownChecksumDBs, ", "))
+
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return invalid
+	}
+	if len(fields) == 2 && !strings.HasPrefix(fields[1], "https://") && !strings.HasPrefix(fields[1], "http://") {
+		return Newf(ErrCodeInvalidFormat, "invalid gosumdb URL %q", fields[1])
+	}
+
+	name, key, hasKey := strings.Cut(fields[0], "+")
+	if hasKey {
+		if name == "" || key == "" {
+			return invalid
+		}
+		return nil
+	}
+	for _, known := range knownChecksumDBs {
+		if name == known {
+			return nil
+		}
+	}
+	return invalid
+}
+
 // ValidatePath validates a file or directory path
 func (v *Validator) ValidatePath(path string) error {
 	if path == "" {
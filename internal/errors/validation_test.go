@@ -14,6 +14,10 @@ func TestValidateVersion(t *testing.T) {
 		{"valid version with go prefix", "go1.21.0", false},
 		{"valid version with beta", "1.21.0-beta1", false},
 		{"valid version with rc", "1.21.0-rc1", false},
+		{"go release candidate", "go1.22rc1", false},
+		{"go beta", "1.21beta2", false},
+		{"two-component release", "go1.20", false},
+		{"unknown suffix", "1.22foo1", true},
 		{"empty version", "", true},
 		{"invalid format", "invalid", true},
 		{"too many parts", "1.2.3.4", true},
@@ -90,6 +94,27 @@ func TestValidateConfigValue(t *testing.T) {
 		{"empty mirror_url", "mirror_url", "", true},
 		{"valid custom_gopath", "custom_gopath", "/path/to/gopath", false},
 		{"empty custom_gopath", "custom_gopath", "", true},
+		{"valid goproxy", "goproxy", "https://proxy.golang.org,direct", false},
+		{"valid goproxy pipe", "goproxy", "https://goproxy.io|https://proxy.golang.org|direct", false},
+		{"valid goproxy direct", "goproxy", "direct", false},
+		{"valid goproxy off", "goproxy", "off", false},
+		{"invalid goproxy typo", "goproxy", "htps://proxy.golang.org", true},
+		{"invalid goproxy keyword", "goproxy", "https://proxy.golang.org,drect", true},
+		{"empty goproxy", "goproxy", "", true},
+		{"valid gosumdb", "gosumdb", "sum.golang.org", false},
+		{"valid gosumdb china", "gosumdb", "sum.golang.google.cn", false},
+		{"valid gosumdb off", "gosumdb", "off", false},
+		{"valid gosumdb with key and url", "gosumdb", "sum.example.com+abc123 https://sum.example.com", false},
+		{"invalid gosumdb typo", "gosumdb", "sum.golang.og", true},
+		{"invalid gosumdb url", "gosumdb", "sum.example.com+abc123 sum.example.com", true},
+		{"empty gosumdb", "gosumdb", "", true},
+		{"valid goflags", "goflags", "-mod=readonly -trimpath", false},
+		{"invalid goflags", "goflags", "mod=readonly", true},
+		{"valid gotoolchain local", "gotoolchain", "local", false},
+		{"valid gotoolchain version", "gotoolchain", "go1.21.0+auto", false},
+		{"invalid gotoolchain", "gotoolchain", "latest", true},
+		{"valid default_channel", "default_channel", "unstable", false},
+		{"invalid default_channel", "default_channel", "beta", true},
 		{"unknown config option", "unknown_option", "value", true},
 	}
 
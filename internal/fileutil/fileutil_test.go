@@ -0,0 +1,56 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+
+	if err := WriteAtomic(path, []byte("first"), 0644); err != nil {
+		t.Fatalf("WriteAtomic error: %v", err)
+	}
+	if err := WriteAtomic(path, []byte("second"), 0644); err != nil {
+		t.Fatalf("WriteAtomic error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "second" {
+		t.Errorf("expected 'second', got %q", string(data))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the target file, found %d entries", len(entries))
+	}
+}
+
+func TestWriteAtomicFailureLeavesNoTempFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// Renaming over a non-empty directory fails after the data was written
+	target := filepath.Join(dir, "target")
+	if err := os.MkdirAll(filepath.Join(target, "child"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAtomic(target, []byte("data"), 0644); err == nil {
+		t.Fatal("expected an error replacing a directory")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "target" {
+		t.Errorf("expected only the target directory, found %v", entries)
+	}
+}
@@ -0,0 +1,65 @@
+package installer
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// decompressors are the decoders for the compressed tar formats. Their output
+// goes through extractTar, so every format gets the same path, symlink and
+// size checks.
+var decompressors = map[ArchiveType]func(io.Reader) (io.ReadCloser, error){
+	ArchiveTarGz: func(r io.Reader) (io.ReadCloser, error) {
+		gzReader, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return gzReader, nil
+	},
+	ArchiveTarXz: func(r io.Reader) (io.ReadCloser, error) {
+		xzReader, err := xz.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create xz reader: %w", err)
+		}
+		return io.NopCloser(xzReader), nil
+	},
+	ArchiveTarZst: func(r io.Reader) (io.ReadCloser, error) {
+		// The tar stream is read sequentially, so decoding ahead in
+		// goroutines would only use more memory
+		zstdReader, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		return zstdReader.IOReadCloser(), nil
+	},
+}
+
+// ArchiveTypeOf returns the archive type of filePath from its extension
+func ArchiveTypeOf(filePath string) (ArchiveType, bool) {
+	for _, archiveType := range []ArchiveType{ArchiveTarGz, ArchiveTarXz, ArchiveTarZst, ArchiveZip} {
+		if strings.HasSuffix(filePath, "."+string(archiveType)) {
+			return archiveType, true
+		}
+	}
+	return "", false
+}
+
+// isTar reports whether archiveType is a compressed tar archive
+func isTar(archiveType ArchiveType) bool {
+	return archiveType == ArchiveTarGz || archiveType == ArchiveTarXz || archiveType == ArchiveTarZst
+}
+
+// decompressTar returns the tar stream of the compressed tar archive read
+// from r. The caller must close it.
+func decompressTar(r io.Reader, archiveType ArchiveType) (io.ReadCloser, error) {
+	decompress, ok := decompressors[archiveType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported archive type: %q", archiveType)
+	}
+	return decompress(r)
+}
@@ -3,7 +3,9 @@ package installer
 import (
 	"archive/tar"
 	"archive/zip"
-	"compress/gzip"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -16,30 +18,198 @@ import (
 	"github.com/molmedoz/gopher/internal/security"
 )
 
+// Extraction limits guarding against decompression bombs. Official Go
+// releases extract to well under 1GB and around 15,000 entries.
+const (
+	// DefaultMaxExtractedSize is the default cap on the total number of bytes
+	// extracted from one archive
+	DefaultMaxExtractedSize int64 = 2 << 30 // 2GB
+
+	// DefaultMaxArchiveEntries is the default cap on the number of entries
+	// in one archive
+	DefaultMaxArchiveEntries = 100000
+)
+
+// maxFileSize caps a single file in an archive. Go installations are
+// typically < 500MB, but allow up to 1GB per file for safety.
+const maxFileSize = 1 << 30 // 1GB
+
+// ArchiveType is the format of an archive given to InstallFromReader
+type ArchiveType string
+
+const (
+	// ArchiveTarGz is a gzip-compressed tar archive, used on Linux and macOS
+	ArchiveTarGz ArchiveType = "tar.gz"
+
+	// ArchiveTarXz is an xz-compressed tar archive, offered by some mirrors
+	ArchiveTarXz ArchiveType = "tar.xz"
+
+	// ArchiveTarZst is a zstd-compressed tar archive, offered by some
+	// mirrors
+	ArchiveTarZst ArchiveType = "tar.zst"
+
+	// ArchiveZip is a ZIP archive, used on Windows
+	ArchiveZip ArchiveType = "zip"
+)
+
 // Installer handles installing Go versions
 type Installer struct {
-	installDir string
+	installDir       string
+	maxExtractedSize int64 // Total bytes allowed per archive
+	maxEntries       int   // Entries allowed per archive
 }
 
 // New creates a new installer
 func New(installDir string) *Installer {
 	return &Installer{
-		installDir: installDir,
+		installDir:       installDir,
+		maxExtractedSize: DefaultMaxExtractedSize,
+		maxEntries:       DefaultMaxArchiveEntries,
 	}
 }
 
+// SetExtractionLimits sets the maximum total extracted size and number of
+// entries allowed for one archive. Values <= 0 restore the defaults.
+func (i *Installer) SetExtractionLimits(maxExtractedSize int64, maxEntries int) {
+	if maxExtractedSize <= 0 {
+		maxExtractedSize = DefaultMaxExtractedSize
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxArchiveEntries
+	}
+	i.maxExtractedSize = maxExtractedSize
+	i.maxEntries = maxEntries
+}
+
+// extractionBudget tracks how much of the extraction limits an archive has used
+type extractionBudget struct {
+	maxSize    int64
+	maxEntries int
+	size       int64
+	entries    int
+}
+
+// newExtractionBudget returns a budget using the installer's limits
+func (i *Installer) newExtractionBudget() *extractionBudget {
+	return &extractionBudget{maxSize: i.maxExtractedSize, maxEntries: i.maxEntries}
+}
+
+// addEntry accounts for one archive entry of the given size and fails once
+// either limit is exceeded
+func (b *extractionBudget) addEntry(name string, size int64) error {
+	b.entries++
+	if b.entries > b.maxEntries {
+		return fmt.Errorf("archive has too many entries (limit: %d)", b.maxEntries)
+	}
+	b.size += size
+	if b.size > b.maxSize {
+		return fmt.Errorf("archive exceeds maximum extracted size at %s (limit: %d bytes)", name, b.maxSize)
+	}
+	return nil
+}
+
+// archiveStructure records whether an archive's entries look like a Go
+// distribution: everything under "go/", with the go binary in a bin directory
+type archiveStructure struct {
+	goBinaryName string
+	hasGoPrefix  bool
+	hasGoBinary  bool
+}
+
+// newArchiveStructure returns a structure check for the current platform's
+// go binary name
+func newArchiveStructure() *archiveStructure {
+	goBinaryName := "go"
+	if runtime.GOOS == "windows" {
+		goBinaryName = "go.exe"
+	}
+	return &archiveStructure{goBinaryName: goBinaryName}
+}
+
+// add records one archive entry
+func (s *archiveStructure) add(name string) {
+	// Check if this archive has the required "go/" prefix
+	if strings.HasPrefix(name, "go/") {
+		s.hasGoPrefix = true
+	}
+	// Check if this archive contains the go binary
+	if strings.HasSuffix(name, "/bin/"+s.goBinaryName) || name == "bin/"+s.goBinaryName {
+		s.hasGoBinary = true
+	}
+}
+
+// check returns an error unless the entries added form a Go distribution
+func (s *archiveStructure) check() error {
+	if !s.hasGoPrefix {
+		return fmt.Errorf("archive does not have required 'go/' prefix")
+	}
+	if !s.hasGoBinary {
+		return fmt.Errorf("archive does not contain go binary")
+	}
+	return nil
+}
+
 // Install installs a Go version from a downloaded file
 func (i *Installer) Install(version, filePath string) error {
+	return i.InstallWithSHA256(version, filePath, "")
+}
+
+// InstallWithSHA256 installs a Go version from a downloaded file and records
+// the archive's verified SHA256 in the version metadata. An empty checksum is
+// simply not recorded.
+func (i *Installer) InstallWithSHA256(version, filePath, archiveSHA256 string) error {
+	if err := security.ValidatePath(filePath); err != nil {
+		return fmt.Errorf("invalid file path: %w", err)
+	}
+	return i.install(version, func(targetDir string) (string, error) {
+		return archiveSHA256, i.extractArchive(filePath, targetDir)
+	})
+}
+
+// InstallFromReader installs a Go version from an archive read from r, for
+// callers that already hold the archive bytes (e.g. from an object store)
+// rather than a downloaded file. The archive is not verified against a
+// published checksum; its SHA256 is computed while reading and recorded in
+// the version metadata.
+//
+// ZIP archives need random access, so they are buffered in a temporary file
+// in the install directory first.
+//
+// Example:
+//
+//	resp, err := http.Get(url)
+//	defer resp.Body.Close()
+//	err = inst.InstallFromReader("go1.21.0", resp.Body, installer.ArchiveTarGz)
+func (i *Installer) InstallFromReader(version string, r io.Reader, archiveType ArchiveType) error {
+	if !isTar(archiveType) && archiveType != ArchiveZip {
+		return fmt.Errorf("unsupported archive type: %q", archiveType)
+	}
+	return i.install(version, func(targetDir string) (string, error) {
+		hash := sha256.New()
+		tee := io.TeeReader(r, hash)
+		if err := i.extractReader(tee, archiveType, targetDir); err != nil {
+			return "", err
+		}
+		// Read any trailing bytes the extractor did not need, so the
+		// checksum covers the whole archive
+		if _, err := io.Copy(io.Discard, tee); err != nil {
+			return "", fmt.Errorf("failed to read archive: %w", err)
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	})
+}
+
+// install extracts a version into its directory with extract, replacing any
+// existing installation, and writes its metadata. extract returns the archive
+// checksum to record, or "" to record none.
+func (i *Installer) install(version string, extract func(targetDir string) (string, error)) error {
 	// Print installation start message
-	fmt.Printf("Installing Go %s\n", version)
+	fmt.Fprintf(os.Stderr, "Installing Go %s\n", version)
 
 	// Validate input paths for security
 	if err := security.ValidatePath(version); err != nil {
 		return fmt.Errorf("invalid version: %w", err)
 	}
-	if err := security.ValidatePath(filePath); err != nil {
-		return fmt.Errorf("invalid file path: %w", err)
-	}
 	if err := security.ValidateDirectoryPath(i.installDir); err != nil {
 		return fmt.Errorf("invalid install directory: %w", err)
 	}
@@ -58,22 +228,25 @@ func (i *Installer) Install(version, filePath string) error {
 		return fmt.Errorf("failed to remove existing installation: %w", err)
 	}
 
-	// Extract the archive with progress
-	if err := i.extractArchive(filePath, targetDir); err != nil {
+	// Extract the archive with progress; a rejected or broken archive must not
+	// leave a partial installation behind
+	archiveSHA256, err := extract(targetDir)
+	if err != nil {
+		_ = os.RemoveAll(targetDir)
 		return fmt.Errorf("failed to extract archive: %w", err)
 	}
 
 	// Create version metadata with spinner
 	metadataSpinner := progress.NewSpinner("Creating version metadata")
 	metadataSpinner.Start()
-	err := i.createVersionMetadata(version, targetDir)
+	err = i.createVersionMetadata(version, targetDir, archiveSHA256)
 	metadataSpinner.Stop()
 
 	if err != nil {
 		return fmt.Errorf("failed to create version metadata: %w", err)
 	}
 
-	fmt.Printf("✓ Successfully installed Go %s\n", version)
+	fmt.Fprintf(os.Stderr, "✓ Successfully installed Go %s\n", version)
 	return nil
 }
 
@@ -138,47 +311,165 @@ func (i *Installer) extractArchive(filePath, targetDir string) error {
 	defer file.Close()
 
 	// Determine archive type and extract accordingly
-	ext := filepath.Ext(filePath)
-	switch ext {
-	case ".gz":
-		if strings.HasSuffix(filePath, ".tar.gz") {
-			err = i.extractTarGz(file, targetDir)
-		} else {
-			err = fmt.Errorf("unsupported .gz format: %s", filePath)
-		}
-	case ".zip":
-		err = i.extractZip(filePath, targetDir)
-	case ".msi":
-		err = i.extractMSI(filePath, targetDir)
+	archiveType, ok := ArchiveTypeOf(filePath)
+	switch {
+	case ok && isTar(archiveType):
+		return i.extractCompressedTar(file, archiveType, targetDir)
+	case ok && archiveType == ArchiveZip:
+		return i.extractZip(filePath, targetDir)
+	case filepath.Ext(filePath) == ".msi":
+		return i.extractMSI(filePath, targetDir)
 	default:
-		err = fmt.Errorf("unsupported archive format: %s", ext)
+		return fmt.Errorf("unsupported archive format: %s", filepath.Ext(filePath))
 	}
+}
 
+// extractCompressedTar decompresses a tar archive of the given type read
+// from r and extracts it to the target directory
+func (i *Installer) extractCompressedTar(r io.Reader, archiveType ArchiveType, targetDir string) error {
+	tarStream, err := decompressTar(r, archiveType)
 	if err != nil {
 		return err
 	}
+	defer tarStream.Close()
+	return i.extractTar(tarStream, targetDir)
+}
+
+// extractReader extracts an archive of the given type read from r to the
+// target directory
+func (i *Installer) extractReader(r io.Reader, archiveType ArchiveType, targetDir string) error {
+	spinner := progress.NewSpinner("Extracting archive")
+	spinner.Start()
+	defer spinner.Stop()
+
+	switch {
+	case isTar(archiveType):
+		return i.extractCompressedTar(r, archiveType, targetDir)
+	case archiveType == ArchiveZip:
+		// zip.Reader needs random access; buffer the archive, which is no
+		// larger than what it may extract to
+		tmp, err := os.CreateTemp(i.installDir, ".archive-*.zip")
+		if err != nil {
+			return fmt.Errorf("failed to buffer archive: %w", err)
+		}
+		defer func() { _ = os.Remove(tmp.Name()) }()
+		n, err := io.Copy(tmp, io.LimitReader(r, i.maxExtractedSize+1))
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to buffer archive: %w", err)
+		}
+		if n > i.maxExtractedSize {
+			return fmt.Errorf("archive exceeds maximum size (limit: %d bytes)", i.maxExtractedSize)
+		}
+		return i.extractZip(tmp.Name(), targetDir)
+	default:
+		return fmt.Errorf("unsupported archive type: %q", archiveType)
+	}
+}
+
+// safeExtractPath returns the path an archive entry is extracted to, with the
+// root "go/" directory stripped. Entries that would land outside targetDir
+// (e.g. "go/../../evil", known as Zip Slip) are rejected.
+func safeExtractPath(targetDir, name string) (string, error) {
+	// Work with an absolute path so only traversal is checked, not the
+	// characters a relative path is restricted to
+	absTargetDir, err := filepath.Abs(targetDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve target directory: %w", err)
+	}
+
+	path := strings.TrimPrefix(name, "go/")
+	targetPath := filepath.Join(absTargetDir, path)
+
+	safePath, err := security.ValidatePathWithinRoot(targetPath, absTargetDir)
+	if err != nil {
+		return "", fmt.Errorf("archive entry %s escapes the install directory: %w", name, err)
+	}
+	return safePath, nil
+}
+
+// createSafeSymlink creates a symlink at linkPath pointing to linkname, which
+// must be relative and stay within targetDir once resolved from the link's
+// directory
+func createSafeSymlink(targetDir, linkPath, linkname string) error {
+	if err := checkSymlinkTarget(targetDir, linkPath, linkname); err != nil {
+		return err
+	}
+
+	// #nosec G301 -- 0755 acceptable for archive extraction parent directories
+	if err := os.MkdirAll(filepath.Dir(linkPath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	_ = os.Remove(linkPath)
+	if err := os.Symlink(linkname, linkPath); err != nil {
+		return fmt.Errorf("failed to create symlink: %w", err)
+	}
+	return nil
+}
 
+// checkSymlinkTarget checks, without touching the filesystem, that a symlink
+// at linkPath pointing to linkname stays within targetDir
+func checkSymlinkTarget(targetDir, linkPath, linkname string) error {
+	if linkname == "" || filepath.IsAbs(linkname) || strings.HasPrefix(linkname, "/") {
+		return fmt.Errorf("symlink target %q must be a relative path", linkname)
+	}
+
+	absTargetDir, err := filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target directory: %w", err)
+	}
+	resolved := filepath.Join(filepath.Dir(linkPath), linkname)
+	if _, err := security.ValidatePathWithinRoot(resolved, absTargetDir); err != nil {
+		return fmt.Errorf("symlink target %q escapes the install directory", linkname)
+	}
 	return nil
 }
 
-// extractTarGz extracts a tar.gz archive
-func (i *Installer) extractTarGz(file *os.File, targetDir string) error {
-	gzReader, err := gzip.NewReader(file)
+// checkResolvesWithinRoot resolves symlinks in path (or in its deepest
+// existing ancestor, if path does not exist yet) and ensures the result is
+// still inside realRoot, which must itself be free of symlinks
+func checkResolvesWithinRoot(path, realRoot string) error {
+	existing := path
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
+		return fmt.Errorf("failed to resolve %s: %w", existing, err)
+	}
+	if _, err := security.ValidatePathWithinRoot(resolved, realRoot); err != nil {
+		return fmt.Errorf("path resolves outside the install directory")
 	}
-	defer gzReader.Close()
+	return nil
+}
 
-	tarReader := tar.NewReader(gzReader)
+// extractTar extracts an uncompressed tar stream
+func (i *Installer) extractTar(r io.Reader, targetDir string) error {
+	tarReader := tar.NewReader(r)
+	budget := i.newExtractionBudget()
 
-	var hasGoPrefix bool
-	var hasGoBinary bool
-	var goBinaryName string
-	if runtime.GOOS == "windows" {
-		goBinaryName = "go.exe"
-	} else {
-		goBinaryName = "go"
+	// Resolve the real target directory so entries can be checked against it
+	// after symlinks created by earlier entries are followed
+	// #nosec G301 -- 0755 required for Go installation directory (needs to be executable)
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return fmt.Errorf("failed to create target directory: %w", err)
 	}
+	realTargetDir, err := filepath.EvalSymlinks(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target directory: %w", err)
+	}
+	var symlinks []string
+	structure := newArchiveStructure()
 
 	for {
 		header, err := tarReader.Next()
@@ -189,20 +480,29 @@ func (i *Installer) extractTarGz(file *os.File, targetDir string) error {
 			return fmt.Errorf("failed to read tar header: %w", err)
 		}
 
-		// Check if this archive has the required "go/" prefix
-		if strings.HasPrefix(header.Name, "go/") {
-			hasGoPrefix = true
+		// Guard against decompression bombs across the whole archive;
+		// only regular files take up space
+		entrySize := int64(0)
+		if header.Typeflag == tar.TypeReg {
+			entrySize = header.Size
 		}
-
-		// Check if this archive contains the go binary
-		if strings.HasSuffix(header.Name, "/bin/"+goBinaryName) {
-			hasGoBinary = true
+		if err := budget.addEntry(header.Name, entrySize); err != nil {
+			return err
 		}
+		structure.add(header.Name)
 
-		// Skip the root "go" directory and extract contents directly
-		path := strings.TrimPrefix(header.Name, "go/")
+		// Skip the root "go" directory and extract contents directly,
+		// rejecting entries that would land outside targetDir (Zip Slip)
+		targetPath, err := safeExtractPath(targetDir, header.Name)
+		if err != nil {
+			return err
+		}
 
-		targetPath := filepath.Join(targetDir, path)
+		// Never write through a symlink created by an earlier entry that
+		// leads outside the target directory
+		if err := checkResolvesWithinRoot(targetPath, realTargetDir); err != nil {
+			return fmt.Errorf("archive entry %s: %w", header.Name, err)
+		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
@@ -220,8 +520,6 @@ func (i *Installer) extractTarGz(file *os.File, targetDir string) error {
 			}
 
 			// Check file size to prevent decompression bomb attacks
-			// Go installations are typically < 500MB, but allow up to 1GB per file for safety
-			const maxFileSize = 1 << 30 // 1GB
 			if header.Size > maxFileSize {
 				return fmt.Errorf("file %s exceeds maximum size (limit: %d bytes, got: %d bytes)", header.Name, maxFileSize, header.Size)
 			}
@@ -254,18 +552,44 @@ func (i *Installer) extractTarGz(file *os.File, targetDir string) error {
 			if err := os.Chmod(targetPath, os.FileMode(mode)); err != nil {
 				return fmt.Errorf("failed to set file permissions: %w", err)
 			}
+		case tar.TypeSymlink:
+			if err := createSafeSymlink(targetDir, targetPath, header.Linkname); err != nil {
+				return fmt.Errorf("archive entry %s: %w", header.Name, err)
+			}
+			symlinks = append(symlinks, targetPath)
+		case tar.TypeLink:
+			// Hard link targets are archive paths of earlier entries
+			linkSource, err := safeExtractPath(targetDir, header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := checkResolvesWithinRoot(linkSource, realTargetDir); err != nil {
+				return fmt.Errorf("archive entry %s: %w", header.Name, err)
+			}
+			// #nosec G301 -- 0755 acceptable for archive extraction parent directories
+			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+				return fmt.Errorf("failed to create parent directory: %w", err)
+			}
+			_ = os.Remove(targetPath)
+			if err := os.Link(linkSource, targetPath); err != nil {
+				return fmt.Errorf("failed to create hard link: %w", err)
+			}
 		}
 	}
 
-	// Validate archive structure
-	if !hasGoPrefix {
-		return fmt.Errorf("archive does not have required 'go/' prefix")
-	}
-	if !hasGoBinary {
-		return fmt.Errorf("archive does not contain go binary")
+	// Symlinks were checked lexically when created; now that every entry
+	// exists, make sure none of them resolves outside the target directory
+	for _, link := range symlinks {
+		if _, err := filepath.EvalSymlinks(link); err != nil {
+			continue // Dangling links do not point anywhere
+		}
+		if err := checkResolvesWithinRoot(link, realTargetDir); err != nil {
+			return fmt.Errorf("symlink %s: %w", link, err)
+		}
 	}
 
-	return nil
+	// Validate archive structure
+	return structure.check()
 }
 
 // extractZip extracts a ZIP archive
@@ -276,28 +600,39 @@ func (i *Installer) extractZip(filePath, targetDir string) error {
 	}
 	defer reader.Close()
 
-	var hasGoPrefix bool
-	var hasGoBinary bool
-	var goBinaryName string
-	if runtime.GOOS == "windows" {
-		goBinaryName = "go.exe"
-	} else {
-		goBinaryName = "go"
-	}
+	structure := newArchiveStructure()
 
+	// Guard against decompression bombs across the whole archive before
+	// extracting anything. Each file is copied through a LimitReader, so the
+	// declared sizes bound what is actually written.
+	budget := i.newExtractionBudget()
 	for _, file := range reader.File {
-		// Check if this archive has the required "go/" prefix
-		if strings.HasPrefix(file.Name, "go/") {
-			hasGoPrefix = true
+		size := int64(0)
+		if !file.FileInfo().IsDir() {
+			// Saturate instead of overflowing; anything this large is over the limit
+			size = budget.maxSize + 1
+			if file.UncompressedSize64 <= uint64(budget.maxSize) {
+				// #nosec G115 -- bounded by maxSize above, safe to convert to int64
+				size = int64(file.UncompressedSize64)
+			}
+		}
+		if err := budget.addEntry(file.Name, size); err != nil {
+			return err
 		}
 
-		// Skip the root "go" directory and extract contents directly
-		path := strings.TrimPrefix(file.Name, "go/")
-		targetPath := filepath.Join(targetDir, path)
+		// Reject the archive before writing anything if an entry escapes
+		if _, err := safeExtractPath(targetDir, file.Name); err != nil {
+			return err
+		}
+	}
 
-		// Check if this archive contains the go binary (after trimming go/ prefix)
-		if strings.HasSuffix(path, "/bin/"+goBinaryName) || path == "bin/"+goBinaryName {
-			hasGoBinary = true
+	for _, file := range reader.File {
+		structure.add(file.Name)
+
+		// Skip the root "go" directory and extract contents directly
+		targetPath, err := safeExtractPath(targetDir, file.Name)
+		if err != nil {
+			return err
 		}
 
 		// Skip empty directories
@@ -321,8 +656,6 @@ func (i *Installer) extractZip(filePath, targetDir string) error {
 		}
 
 		// Check file size to prevent decompression bomb attacks
-		// Go installations are typically < 500MB, but allow up to 1GB per file for safety
-		const maxFileSize = 1 << 30 // 1GB
 		// Safe conversion: uint64 → int64 with bounds check (maxFileSize ensures it fits in int64)
 		if file.UncompressedSize64 > maxFileSize {
 			_ = rc.Close()
@@ -360,14 +693,177 @@ func (i *Installer) extractZip(filePath, targetDir string) error {
 	}
 
 	// Validate archive structure
-	if !hasGoPrefix {
-		return fmt.Errorf("archive does not have required 'go/' prefix")
+	return structure.check()
+}
+
+// ArchiveInfo describes an archive checked by Validate
+type ArchiveInfo struct {
+	Path    string      `json:"path"`
+	Type    ArchiveType `json:"type"`
+	Version string      `json:"version,omitempty"` // From go/VERSION; empty if the archive has none
+	Entries int         `json:"entries"`
+	Size    int64       `json:"size"` // Total bytes the archive would extract to
+}
+
+// versionFileName is the file in a Go distribution that names its version
+const versionFileName = "go/VERSION"
+
+// Validate checks that the archive at filePath can be installed, without
+// extracting it: every entry stays inside the install directory, the
+// extraction limits are respected, and the archive is a Go distribution with
+// the "go/" prefix and a go binary. Only headers and the small go/VERSION file
+// are read.
+//
+// The returned info holds what was read before any error, so the detected
+// version is available even when the structure is wrong.
+//
+// Example:
+//
+//	info, err := inst.Validate("/tmp/go1.21.0.linux-amd64.tar.gz")
+//	if err != nil {
+//	    return fmt.Errorf("%s is not installable: %w", info.Path, err)
+//	}
+func (i *Installer) Validate(filePath string) (*ArchiveInfo, error) {
+	info := &ArchiveInfo{Path: filePath}
+	if err := security.ValidatePath(filePath); err != nil {
+		return info, fmt.Errorf("invalid file path: %w", err)
 	}
-	if !hasGoBinary {
-		return fmt.Errorf("archive does not contain go binary")
+
+	archiveType, ok := ArchiveTypeOf(filePath)
+	if !ok {
+		return info, fmt.Errorf("unsupported archive format: %s", filepath.Ext(filePath))
 	}
+	info.Type = archiveType
+	if archiveType == ArchiveZip {
+		return info, i.validateZip(filePath, info)
+	}
+	return info, i.validateTar(filePath, info)
+}
 
-	return nil
+// validationRoot is where entry paths are resolved against during Validate.
+// Nothing is written there; the checks on entry paths are lexical.
+func (i *Installer) validationRoot() string {
+	return filepath.Join(i.installDir, "validate")
+}
+
+// validateTar walks the headers of a compressed tar archive for Validate
+func (i *Installer) validateTar(filePath string, info *ArchiveInfo) error {
+	// #nosec G304 -- path validated by Validate
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	tarStream, err := decompressTar(file, info.Type)
+	if err != nil {
+		return err
+	}
+	defer tarStream.Close()
+
+	tarReader := tar.NewReader(tarStream)
+	budget := i.newExtractionBudget()
+	structure := newArchiveStructure()
+	root := i.validationRoot()
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar header: %w", err)
+		}
+
+		entrySize := int64(0)
+		if header.Typeflag == tar.TypeReg {
+			entrySize = header.Size
+		}
+		if err := budget.addEntry(header.Name, entrySize); err != nil {
+			return err
+		}
+		info.Entries, info.Size = budget.entries, budget.size
+		structure.add(header.Name)
+
+		targetPath, err := safeExtractPath(root, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			if header.Size > maxFileSize {
+				return fmt.Errorf("file %s exceeds maximum size (limit: %d bytes, got: %d bytes)", header.Name, maxFileSize, header.Size)
+			}
+			if header.Name == versionFileName {
+				info.Version = readVersionFile(tarReader)
+			}
+		case tar.TypeSymlink:
+			if err := checkSymlinkTarget(root, targetPath, header.Linkname); err != nil {
+				return fmt.Errorf("archive entry %s: %w", header.Name, err)
+			}
+		case tar.TypeLink:
+			if _, err := safeExtractPath(root, header.Linkname); err != nil {
+				return err
+			}
+		}
+	}
+
+	return structure.check()
+}
+
+// validateZip walks the entries of a ZIP archive for Validate
+func (i *Installer) validateZip(filePath string, info *ArchiveInfo) error {
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open zip file: %w", err)
+	}
+	defer reader.Close()
+
+	budget := i.newExtractionBudget()
+	structure := newArchiveStructure()
+	root := i.validationRoot()
+
+	for _, file := range reader.File {
+		size := int64(0)
+		if !file.FileInfo().IsDir() {
+			if file.UncompressedSize64 > maxFileSize {
+				return fmt.Errorf("file %s exceeds maximum size (%d bytes): %d", file.Name, maxFileSize, file.UncompressedSize64)
+			}
+			// #nosec G115 -- size checked above to be <= maxFileSize (1GB), safe to convert to int64
+			size = int64(file.UncompressedSize64)
+		}
+		if err := budget.addEntry(file.Name, size); err != nil {
+			return err
+		}
+		info.Entries, info.Size = budget.entries, budget.size
+		structure.add(file.Name)
+
+		if _, err := safeExtractPath(root, file.Name); err != nil {
+			return err
+		}
+
+		if file.Name == versionFileName {
+			rc, err := file.Open()
+			if err != nil {
+				return fmt.Errorf("failed to open file in zip: %w", err)
+			}
+			info.Version = readVersionFile(rc)
+			_ = rc.Close()
+		}
+	}
+
+	return structure.check()
+}
+
+// readVersionFile returns the version named on the first line of a go/VERSION
+// file, e.g. "go1.21.0"
+func readVersionFile(r io.Reader) string {
+	scanner := bufio.NewScanner(io.LimitReader(r, 4096))
+	if scanner.Scan() {
+		return strings.TrimSpace(scanner.Text())
+	}
+	return ""
 }
 
 // extractMSI extracts a Windows MSI file
@@ -388,8 +884,10 @@ func (i *Installer) extractMSI(filePath, targetDir string) error {
 	return fmt.Errorf("MSI extraction not implemented yet")
 }
 
-// createVersionMetadata creates metadata for the installed version
-func (i *Installer) createVersionMetadata(version, targetDir string) error {
+// createVersionMetadata creates metadata for the installed version.
+// archiveSHA256 is the checksum of the archive the version was installed from;
+// it is omitted when empty.
+func (i *Installer) createVersionMetadata(version, targetDir, archiveSHA256 string) error {
 	metadata := map[string]any{
 		"version":      version,
 		"os":           runtime.GOOS,
@@ -397,6 +895,9 @@ func (i *Installer) createVersionMetadata(version, targetDir string) error {
 		"installed_at": time.Now().Format(time.RFC3339),
 		"install_dir":  targetDir,
 	}
+	if archiveSHA256 != "" {
+		metadata["sha256"] = archiveSHA256
+	}
 
 	// Write metadata to a file
 	// Validate targetDir to ensure metadata path is within safe bounds
@@ -443,21 +944,25 @@ func (i *Installer) GetVersionMetadata(version string) (map[string]string, error
 
 	metadata := make(map[string]string)
 
-	// Read metadata line by line
-	var line string
-	for {
-		_, err := fmt.Fscanf(file, "%s\n", &line)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to read metadata: %w", err)
+	// Read metadata line by line; values may contain spaces (e.g. install
+	// paths), so only the first '=' separates key from value
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
 		}
 
 		parts := strings.SplitN(line, "=", 2)
-		if len(parts) == 2 {
-			metadata[parts[0]] = parts[1]
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("failed to read metadata: line %d is not in key=value format", lineNum)
 		}
+		metadata[parts[0]] = parts[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
 	}
 
 	return metadata, nil
@@ -5,10 +5,17 @@ import (
 	"archive/zip"
 	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
 )
 
 func TestIsInstalledFalseWhenMissing(t *testing.T) {
@@ -47,7 +54,7 @@ func TestCreateVersionMetadata(t *testing.T) {
 	if err := os.MkdirAll(target, 0755); err != nil {
 		t.Fatal(err)
 	}
-	if err := inst.createVersionMetadata(ver, target); err != nil {
+	if err := inst.createVersionMetadata(ver, target, ""); err != nil {
 		t.Fatalf("createVersionMetadata error: %v", err)
 	}
 	if _, err := os.Stat(filepath.Join(target, ".gopher-metadata")); err != nil {
@@ -63,7 +70,7 @@ func TestGetVersionMetadata_ReadBack(t *testing.T) {
 	if err := os.MkdirAll(target, 0755); err != nil {
 		t.Fatal(err)
 	}
-	if err := inst.createVersionMetadata(ver, target); err != nil {
+	if err := inst.createVersionMetadata(ver, target, ""); err != nil {
 		t.Fatalf("createVersionMetadata error: %v", err)
 	}
 	meta, err := inst.GetVersionMetadata(ver)
@@ -75,6 +82,70 @@ func TestGetVersionMetadata_ReadBack(t *testing.T) {
 	}
 }
 
+func TestGetVersionMetadata_SHA256(t *testing.T) {
+	tdir := t.TempDir()
+	inst := New(tdir)
+	sum := "4a6f9a3b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f"
+
+	withSum := filepath.Join(tdir, "go1.21.0")
+	if err := os.MkdirAll(withSum, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := inst.createVersionMetadata("go1.21.0", withSum, sum); err != nil {
+		t.Fatalf("createVersionMetadata error: %v", err)
+	}
+	meta, err := inst.GetVersionMetadata("go1.21.0")
+	if err != nil {
+		t.Fatalf("GetVersionMetadata error: %v", err)
+	}
+	if meta["sha256"] != sum {
+		t.Errorf("sha256 meta mismatch: %q", meta["sha256"])
+	}
+
+	// Metadata written without a checksum (e.g. by older releases) has no sha256 key
+	withoutSum := filepath.Join(tdir, "go1.20.0")
+	if err := os.MkdirAll(withoutSum, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := inst.createVersionMetadata("go1.20.0", withoutSum, ""); err != nil {
+		t.Fatalf("createVersionMetadata error: %v", err)
+	}
+	meta, err = inst.GetVersionMetadata("go1.20.0")
+	if err != nil {
+		t.Fatalf("GetVersionMetadata error: %v", err)
+	}
+	if _, ok := meta["sha256"]; ok {
+		t.Errorf("expected no sha256 key, got %q", meta["sha256"])
+	}
+}
+
+func TestGetVersionMetadata_ValuesWithSpaces(t *testing.T) {
+	tdir := filepath.Join(t.TempDir(), "My Go Versions")
+	inst := New(tdir)
+	ver := "go1.21.0"
+	target := filepath.Join(tdir, ver)
+	if err := os.MkdirAll(target, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := inst.createVersionMetadata(ver, target, ""); err != nil {
+		t.Fatalf("createVersionMetadata error: %v", err)
+	}
+
+	meta, err := inst.GetVersionMetadata(ver)
+	if err != nil {
+		t.Fatalf("GetVersionMetadata error: %v", err)
+	}
+	if meta["install_dir"] != target {
+		t.Errorf("install_dir = %q, want %q", meta["install_dir"], target)
+	}
+	if meta["version"] != ver {
+		t.Errorf("version = %q, want %q", meta["version"], ver)
+	}
+	if meta["os"] == "" || meta["arch"] == "" || meta["installed_at"] == "" {
+		t.Errorf("expected all fields to be read back, got %v", meta)
+	}
+}
+
 func TestUninstall_NotInstalled(t *testing.T) {
 	tdir := t.TempDir()
 	inst := New(tdir)
@@ -126,6 +197,47 @@ func createTarGz(t *testing.T, files map[string][]byte) string {
 	return tmp
 }
 
+// createCompressedTar writes files as a tar archive compressed as
+// archiveType, which is .tar.xz or .tar.zst
+func createCompressedTar(t *testing.T, archiveType ArchiveType, files map[string][]byte) string {
+	t.Helper()
+	var buf bytes.Buffer
+	var compressor io.WriteCloser
+	var err error
+	switch archiveType {
+	case ArchiveTarXz:
+		compressor, err = xz.NewWriter(&buf)
+	case ArchiveTarZst:
+		compressor, err = zstd.NewWriter(&buf)
+	default:
+		t.Fatalf("unexpected archive type %q", archiveType)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(compressor)
+	for name, data := range files {
+		hdr := &tar.Header{Name: name, Mode: 0755, Size: int64(len(data))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressor.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tmp := filepath.Join(t.TempDir(), "go."+string(archiveType))
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return tmp
+}
+
 func createZip(t *testing.T, files map[string][]byte) string {
 	t.Helper()
 	tmp := filepath.Join(t.TempDir(), "go.zip")
@@ -205,6 +317,71 @@ func TestInstaller_Install_FromTarGz(t *testing.T) {
 	}
 }
 
+func TestInstaller_InstallFromReader(t *testing.T) {
+	goBinaryName := "go"
+	if runtime.GOOS == "windows" {
+		goBinaryName = "go.exe"
+	}
+	files := map[string][]byte{
+		"go/bin/" + goBinaryName: []byte("#!/bin/sh\n"),
+		"go/VERSION":             []byte("go1.2.3\n"),
+	}
+
+	for _, tc := range []struct {
+		archiveType ArchiveType
+		path        string
+	}{
+		{ArchiveTarGz, createTarGz(t, files)},
+		{ArchiveZip, createZip(t, files)},
+	} {
+		t.Run(string(tc.archiveType), func(t *testing.T) {
+			tdir := t.TempDir()
+			inst := New(tdir)
+			data, err := os.ReadFile(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := inst.InstallFromReader("go1.2.3", bytes.NewReader(data), tc.archiveType); err != nil {
+				t.Fatalf("InstallFromReader error: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(tdir, "go1.2.3", "bin", goBinaryName)); err != nil {
+				t.Fatalf("installed go binary missing: %v", err)
+			}
+
+			// The checksum of the whole archive is recorded
+			meta, err := inst.GetVersionMetadata("go1.2.3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := fmt.Sprintf("%x", sha256.Sum256(data)); meta["sha256"] != want {
+				t.Errorf("sha256 = %q, want %q", meta["sha256"], want)
+			}
+
+			// No buffered archive is left in the install directory
+			entries, _ := os.ReadDir(tdir)
+			if len(entries) != 1 {
+				t.Errorf("install dir holds %d entries, want only the version", len(entries))
+			}
+		})
+	}
+}
+
+func TestInstaller_InstallFromReader_Invalid(t *testing.T) {
+	tdir := t.TempDir()
+	inst := New(tdir)
+
+	if err := inst.InstallFromReader("go1.2.3", strings.NewReader("data"), "rar"); err == nil {
+		t.Error("expected an error for an unsupported archive type")
+	}
+	if err := inst.InstallFromReader("go1.2.3", strings.NewReader("not an archive"), ArchiveTarGz); err == nil {
+		t.Error("expected an error for a broken archive")
+	}
+	if _, err := os.Stat(filepath.Join(tdir, "go1.2.3")); !os.IsNotExist(err) {
+		t.Error("a failed install left a partial version behind")
+	}
+}
+
 func TestInstaller_ListInstalled(t *testing.T) {
 	tdir := t.TempDir()
 	inst := New(tdir)
@@ -226,7 +403,7 @@ func TestInstaller_ListInstalled(t *testing.T) {
 			t.Fatal(err)
 		}
 		// Create metadata
-		if err := inst.createVersionMetadata(ver, vdir); err != nil {
+		if err := inst.createVersionMetadata(ver, vdir, ""); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -350,7 +527,7 @@ func TestInstaller_Uninstall_WithMetadata(t *testing.T) {
 	}
 
 	// Create metadata
-	if err := inst.createVersionMetadata(ver, vdir); err != nil {
+	if err := inst.createVersionMetadata(ver, vdir, ""); err != nil {
 		t.Fatal(err)
 	}
 
@@ -386,7 +563,7 @@ func TestInstaller_Install_OverwriteExisting(t *testing.T) {
 	}
 
 	// Create initial metadata
-	if err := inst.createVersionMetadata(ver, vdir); err != nil {
+	if err := inst.createVersionMetadata(ver, vdir, ""); err != nil {
 		t.Fatal(err)
 	}
 
@@ -406,3 +583,359 @@ func TestInstaller_Install_OverwriteExisting(t *testing.T) {
 		t.Fatalf("version should still be installed")
 	}
 }
+
+func TestInstaller_Install_ExceedsTotalExtractedSize(t *testing.T) {
+	files := map[string][]byte{
+		"go/bin/go":  bytes.Repeat([]byte("x"), 600),
+		"go/VERSION": []byte("go1.2.3\n"),
+		"go/a.txt":   bytes.Repeat([]byte("a"), 600),
+	}
+
+	for name, archive := range map[string]string{
+		"tar.gz": createTarGz(t, files),
+		"zip":    createZip(t, files),
+	} {
+		t.Run(name, func(t *testing.T) {
+			tdir := t.TempDir()
+			inst := New(tdir)
+			// Each file is well under the per-file limit, but together they exceed 1KB
+			inst.SetExtractionLimits(1024, 0)
+
+			archivePath := filepath.Join(t.TempDir(), "go."+name)
+			if err := os.Rename(archive, archivePath); err != nil {
+				t.Fatal(err)
+			}
+			err := inst.Install("go1.2.3", archivePath)
+			if err == nil {
+				t.Fatal("expected error for archive exceeding total extracted size")
+			}
+			if !strings.Contains(err.Error(), "maximum extracted size") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestInstaller_Install_TooManyEntries(t *testing.T) {
+	files := map[string][]byte{
+		"go/bin/go":  []byte("#!/bin/sh\n"),
+		"go/VERSION": []byte("go1.2.3\n"),
+	}
+	for n := 0; n < 10; n++ {
+		files[fmt.Sprintf("go/pkg/file%d.txt", n)] = []byte("x")
+	}
+
+	for name, archive := range map[string]string{
+		"tar.gz": createTarGz(t, files),
+		"zip":    createZip(t, files),
+	} {
+		t.Run(name, func(t *testing.T) {
+			tdir := t.TempDir()
+			inst := New(tdir)
+			inst.SetExtractionLimits(0, 5)
+
+			archivePath := filepath.Join(t.TempDir(), "go."+name)
+			if err := os.Rename(archive, archivePath); err != nil {
+				t.Fatal(err)
+			}
+			err := inst.Install("go1.2.3", archivePath)
+			if err == nil {
+				t.Fatal("expected error for archive with too many entries")
+			}
+			if !strings.Contains(err.Error(), "too many entries") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestInstaller_SetExtractionLimits_Defaults(t *testing.T) {
+	inst := New(t.TempDir())
+	inst.SetExtractionLimits(10, 10)
+	inst.SetExtractionLimits(0, -1)
+	if inst.maxExtractedSize != DefaultMaxExtractedSize || inst.maxEntries != DefaultMaxArchiveEntries {
+		t.Errorf("expected defaults to be restored, got %d bytes / %d entries", inst.maxExtractedSize, inst.maxEntries)
+	}
+}
+
+func TestInstaller_Install_RejectsPathTraversal(t *testing.T) {
+	files := map[string][]byte{
+		"go/bin/go":       []byte("#!/bin/sh\n"),
+		"go/VERSION":      []byte("go1.2.3\n"),
+		"go/../../evil":   []byte("pwned"),
+		"go/../escape.sh": []byte("pwned"),
+	}
+
+	for name, archive := range map[string]string{
+		"tar.gz": createTarGz(t, files),
+		"zip":    createZip(t, files),
+	} {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			tdir := filepath.Join(root, "versions")
+			inst := New(tdir)
+
+			archivePath := filepath.Join(t.TempDir(), "go."+name)
+			if err := os.Rename(archive, archivePath); err != nil {
+				t.Fatal(err)
+			}
+			err := inst.Install("go1.2.3", archivePath)
+			if err == nil {
+				t.Fatal("expected archive with ../ entries to be rejected")
+			}
+			if !strings.Contains(err.Error(), "escapes the install directory") {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			for _, p := range []string{filepath.Join(root, "evil"), filepath.Join(tdir, "escape.sh")} {
+				if _, err := os.Stat(p); !os.IsNotExist(err) {
+					t.Errorf("file written outside the version directory: %s", p)
+				}
+			}
+			if inst.IsInstalled("go1.2.3") {
+				t.Error("rejected archive left a partial installation behind")
+			}
+		})
+	}
+}
+
+// tarEntry describes one entry for createTarGzEntries
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	data     []byte
+}
+
+// createTarGzEntries builds a tar.gz with entries in the given order,
+// including symlinks and hard links
+func createTarGzEntries(t *testing.T, entries []tarEntry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: e.typeflag, Linkname: e.linkname, Mode: 0755, Size: int64(len(e.data))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if len(e.data) > 0 {
+			if _, err := tw.Write(e.data); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tmp := filepath.Join(t.TempDir(), "go.tar.gz")
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return tmp
+}
+
+func TestInstaller_Install_TarSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlink creation requires extra privileges on Windows")
+	}
+
+	tdir := t.TempDir()
+	inst := New(tdir)
+	archive := createTarGzEntries(t, []tarEntry{
+		{name: "go/bin/go", typeflag: tar.TypeReg, data: []byte("#!/bin/sh\n")},
+		{name: "go/VERSION", typeflag: tar.TypeReg, data: []byte("go1.2.3\n")},
+		{name: "go/pkg/tool/go", typeflag: tar.TypeSymlink, linkname: "../../bin/go"},
+		{name: "go/bin/go-hardlink", typeflag: tar.TypeLink, linkname: "go/bin/go"},
+	})
+
+	if err := inst.Install("go1.2.3", archive); err != nil {
+		t.Fatalf("Install error: %v", err)
+	}
+
+	link := filepath.Join(tdir, "go1.2.3", "pkg", "tool", "go")
+	target, err := os.Readlink(link)
+	if err != nil {
+		t.Fatalf("symlink not recreated: %v", err)
+	}
+	if target != "../../bin/go" {
+		t.Errorf("symlink target = %q, want %q", target, "../../bin/go")
+	}
+	if data, err := os.ReadFile(link); err != nil || string(data) != "#!/bin/sh\n" {
+		t.Errorf("symlink does not resolve to the go binary: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(tdir, "go1.2.3", "bin", "go-hardlink")); err != nil || string(data) != "#!/bin/sh\n" {
+		t.Errorf("hard link not recreated: %v", err)
+	}
+}
+
+func TestInstaller_Install_TarSymlinkEscapes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlink creation requires extra privileges on Windows")
+	}
+
+	base := []tarEntry{
+		{name: "go/bin/go", typeflag: tar.TypeReg, data: []byte("#!/bin/sh\n")},
+		{name: "go/VERSION", typeflag: tar.TypeReg, data: []byte("go1.2.3\n")},
+	}
+	tests := map[string][]tarEntry{
+		"relative escape": {
+			{name: "go/evil", typeflag: tar.TypeSymlink, linkname: "../../../etc/passwd"},
+		},
+		"absolute target": {
+			{name: "go/evil", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"},
+		},
+		"escaping hard link": {
+			{name: "go/evil", typeflag: tar.TypeLink, linkname: "go/../../outside"},
+		},
+		"escape through another symlink": {
+			{name: "go/up", typeflag: tar.TypeSymlink, linkname: "link2/.."},
+			{name: "go/link2", typeflag: tar.TypeSymlink, linkname: "."},
+			{name: "go/up/evil", typeflag: tar.TypeReg, data: []byte("pwned")},
+		},
+	}
+
+	for name, entries := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			tdir := filepath.Join(root, "versions")
+			inst := New(tdir)
+			archive := createTarGzEntries(t, append(append([]tarEntry{}, base...), entries...))
+
+			if err := inst.Install("go1.2.3", archive); err == nil {
+				t.Fatal("expected archive with an escaping link to be rejected")
+			}
+			if _, err := os.Stat(filepath.Join(tdir, "evil")); !os.IsNotExist(err) {
+				t.Error("file written outside the version directory")
+			}
+			if inst.IsInstalled("go1.2.3") {
+				t.Error("rejected archive left a partial installation behind")
+			}
+		})
+	}
+}
+
+func TestInstaller_Validate(t *testing.T) {
+	tdir := filepath.Join(t.TempDir(), "versions")
+	inst := New(tdir)
+
+	goBinaryName := "go"
+	if runtime.GOOS == "windows" {
+		goBinaryName = "go.exe"
+	}
+	files := map[string][]byte{
+		"go/bin/" + goBinaryName: []byte("#!/bin/sh\n"),
+		"go/VERSION":             []byte("go1.2.3\ntime 2023-01-01T00:00:00Z\n"),
+	}
+
+	for name, archive := range map[string]string{
+		"tar.gz": createTarGz(t, files),
+		"zip":    createZip(t, files),
+	} {
+		info, err := inst.Validate(archive)
+		if err != nil {
+			t.Fatalf("%s: Validate error: %v", name, err)
+		}
+		if string(info.Type) != name || info.Version != "go1.2.3" || info.Entries != 2 {
+			t.Errorf("%s: unexpected info %+v", name, info)
+		}
+	}
+
+	// Nothing may be written, not even the install directory
+	if _, err := os.Stat(tdir); !os.IsNotExist(err) {
+		t.Errorf("Validate created %s (err: %v)", tdir, err)
+	}
+
+	noBinary := createTarGz(t, map[string][]byte{"go/VERSION": []byte("go1.2.3\n")})
+	info, err := inst.Validate(noBinary)
+	if err == nil || !strings.Contains(err.Error(), "go binary") {
+		t.Errorf("expected missing go binary error, got %v", err)
+	}
+	if info.Version != "go1.2.3" {
+		t.Errorf("version should be detected despite the error, got %q", info.Version)
+	}
+
+	escaping := createTarGz(t, map[string][]byte{"go/../../evil": []byte("x")})
+	if _, err := inst.Validate(escaping); err == nil {
+		t.Error("expected error for entry escaping the install directory")
+	}
+
+	if _, err := inst.Validate(filepath.Join(t.TempDir(), "go.rar")); err == nil {
+		t.Error("expected error for unsupported archive format")
+	}
+}
+
+func TestInstaller_Install_CompressedTar(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fixtures hold a Unix go binary")
+	}
+	for _, archiveType := range []ArchiveType{ArchiveTarXz, ArchiveTarZst} {
+		t.Run(string(archiveType), func(t *testing.T) {
+			tdir := t.TempDir()
+			inst := New(tdir)
+			fixture := filepath.Join("testdata", "go1.2.3."+string(archiveType))
+
+			info, err := inst.Validate(fixture)
+			if err != nil || info.Version != "go1.2.3" || info.Type != archiveType {
+				t.Fatalf("Validate = %+v, %v", info, err)
+			}
+
+			if err := inst.Install("go1.2.3", fixture); err != nil {
+				t.Fatalf("Install error: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(tdir, "go1.2.3", "bin", "go")); err != nil {
+				t.Fatalf("installed go binary missing: %v", err)
+			}
+			if data, err := os.ReadFile(filepath.Join(tdir, "go1.2.3", "VERSION")); err != nil || !strings.HasPrefix(string(data), "go1.2.3") {
+				t.Errorf("installed VERSION = %q, %v", data, err)
+			}
+
+			// A corrupt or truncated archive fails instead of installing
+			// part of the tree
+			corrupt := filepath.Join(t.TempDir(), "corrupt."+string(archiveType))
+			if err := os.WriteFile(corrupt, []byte("not compressed"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := inst.Install("go1.2.4", corrupt); err == nil {
+				t.Error("expected an error for a corrupt archive")
+			}
+			if _, err := os.Stat(filepath.Join(tdir, "go1.2.4")); !os.IsNotExist(err) {
+				t.Errorf("corrupt archive left an installation behind (err: %v)", err)
+			}
+			data, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			truncated := filepath.Join(t.TempDir(), "truncated."+string(archiveType))
+			if err := os.WriteFile(truncated, data[:len(data)/2], 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := inst.Install("go1.2.5", truncated); err == nil {
+				t.Error("expected an error for a truncated archive")
+			}
+			if _, err := os.Stat(filepath.Join(tdir, "go1.2.5")); !os.IsNotExist(err) {
+				t.Errorf("truncated archive left an installation behind (err: %v)", err)
+			}
+
+			// The same archive installs from a reader
+			if err := inst.InstallFromReader("go1.2.6", bytes.NewReader(data), archiveType); err != nil {
+				t.Fatalf("InstallFromReader error: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(tdir, "go1.2.6", "bin", "go")); err != nil {
+				t.Errorf("go binary installed from reader missing: %v", err)
+			}
+
+			// The decompressed stream gets the same path checks as .tar.gz
+			escaping := createCompressedTar(t, archiveType, map[string][]byte{"go/../../evil": []byte("x")})
+			if err := inst.Install("go1.2.7", escaping); err == nil {
+				t.Error("expected error for entry escaping the install directory")
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(tdir), "evil")); !os.IsNotExist(err) {
+				t.Errorf("escaping entry was written (err: %v)", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Package log provides a minimal leveled logger for Gopher's CLI output.
+//
+// Informational and debug messages go to stdout, errors go to stderr. The
+// active level decides which messages are printed:
+//
+//   - LevelDebug: everything, including diagnostic detail
+//   - LevelInfo: regular command output (default)
+//   - LevelError: errors only (used by --quiet)
+//
+// Usage:
+//
+//	log.SetLevel(log.LevelDebug)
+//	log.Info("Switching to Go %s...", version)
+//	log.Debug("resolved download URL: %s", url)
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level is the minimum severity a message needs to be printed
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelError
+)
+
+// String returns the string representation of the level
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Logger writes leveled messages to an output and an error stream
+type Logger struct {
+	mu     sync.Mutex
+	level  Level
+	out    io.Writer
+	errOut io.Writer
+}
+
+// New creates a logger writing to stdout and stderr at the given level
+func New(level Level) *Logger {
+	return &Logger{
+		level:  level,
+		out:    os.Stdout,
+		errOut: os.Stderr,
+	}
+}
+
+// SetLevel sets the minimum level that will be printed
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// GetLevel returns the current level
+func (l *Logger) GetLevel() Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
+
+// SetOutput sets the writers used for regular and error messages
+func (l *Logger) SetOutput(out, errOut io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out = out
+	l.errOut = errOut
+}
+
+// Debug prints a diagnostic message, prefixed with [DEBUG]
+func (l *Logger) Debug(format string, args ...any) {
+	l.print(LevelDebug, "[DEBUG] "+format, args...)
+}
+
+// Info prints a regular informational message
+func (l *Logger) Info(format string, args ...any) {
+	l.print(LevelInfo, format, args...)
+}
+
+// Error prints an error message to the error stream
+func (l *Logger) Error(format string, args ...any) {
+	l.print(LevelError, format, args...)
+}
+
+// print formats the message, appends a newline if missing and writes it
+func (l *Logger) print(level Level, format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if level < l.level {
+		return
+	}
+
+	message := fmt.Sprintf(format, args...)
+	if !strings.HasSuffix(message, "\n") {
+		message += "\n"
+	}
+
+	w := l.out
+	if level >= LevelError {
+		w = l.errOut
+	}
+	_, _ = io.WriteString(w, message)
+}
+
+// Global logger instance
+var (
+	DefaultLogger = New(LevelInfo)
+)
+
+// SetLevel sets the level of the default logger
+func SetLevel(level Level) {
+	DefaultLogger.SetLevel(level)
+}
+
+// GetLevel returns the level of the default logger
+func GetLevel() Level {
+	return DefaultLogger.GetLevel()
+}
+
+// SetOutput sets the writers of the default logger
+func SetOutput(out, errOut io.Writer) {
+	DefaultLogger.SetOutput(out, errOut)
+}
+
+// Debug prints a diagnostic message using the default logger
+func Debug(format string, args ...any) {
+	DefaultLogger.Debug(format, args...)
+}
+
+// Info prints an informational message using the default logger
+func Info(format string, args ...any) {
+	DefaultLogger.Info(format, args...)
+}
+
+// Error prints an error message using the default logger
+func Error(format string, args ...any) {
+	DefaultLogger.Error(format, args...)
+}
@@ -0,0 +1,75 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLevelString(t *testing.T) {
+	tests := map[Level]string{
+		LevelDebug: "DEBUG",
+		LevelInfo:  "INFO",
+		LevelError: "ERROR",
+		Level(99):  "UNKNOWN",
+	}
+	for level, want := range tests {
+		if got := level.String(); got != want {
+			t.Errorf("Level(%d).String() = %q, want %q", level, got, want)
+		}
+	}
+}
+
+func TestLoggerLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     Level
+		wantOut   string
+		wantError string
+	}{
+		{"debug", LevelDebug, "[DEBUG] d 1\ni 2\n", "e 3\n"},
+		{"info", LevelInfo, "i 2\n", "e 3\n"},
+		{"error", LevelError, "", "e 3\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			l := New(tt.level)
+			l.SetOutput(&out, &errOut)
+
+			l.Debug("d %d", 1)
+			l.Info("i %d", 2)
+			l.Error("e %d", 3)
+
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if errOut.String() != tt.wantError {
+				t.Errorf("stderr = %q, want %q", errOut.String(), tt.wantError)
+			}
+		})
+	}
+}
+
+func TestLoggerKeepsExistingNewline(t *testing.T) {
+	var out bytes.Buffer
+	l := New(LevelInfo)
+	l.SetOutput(&out, &out)
+
+	l.Info("line\n")
+	l.Info("")
+
+	if out.String() != "line\n\n" {
+		t.Errorf("got %q, want %q", out.String(), "line\n\n")
+	}
+}
+
+func TestDefaultLogger(t *testing.T) {
+	original := GetLevel()
+	defer SetLevel(original)
+
+	SetLevel(LevelError)
+	if GetLevel() != LevelError {
+		t.Errorf("GetLevel() = %v, want %v", GetLevel(), LevelError)
+	}
+}
@@ -0,0 +1,46 @@
+package progress
+
+import "context"
+
+// Stage is a step of installing a Go version
+type Stage string
+
+// Install stages, in the order they are reported
+const (
+	StageResolving   Stage = "resolving"   // Looking up the archive and its checksum
+	StageDownloading Stage = "downloading" // Fetching the archive; reported repeatedly with bytes
+	StageVerifying   Stage = "verifying"   // Checking the archive against its checksum
+	StageExtracting  Stage = "extracting"  // Unpacking into the install directory
+	StageDone        Stage = "done"        // The version is installed
+)
+
+// ProgressEvent is a typed progress update, for callers such as GUIs that
+// render progress themselves instead of the terminal output
+type ProgressEvent struct {
+	Stage   Stage  `json:"stage"`
+	Version string `json:"version"`
+	Done    int64  `json:"done,omitempty"`  // Bytes downloaded so far (StageDownloading)
+	Total   int64  `json:"total,omitempty"` // Expected bytes, or <= 0 when unknown
+}
+
+// eventsKey is the context key of the event handler
+type eventsKey struct{}
+
+// WithEvents returns a copy of ctx whose events are reported to handler by
+// Emit. The handler is called synchronously by the goroutine doing the work.
+func WithEvents(ctx context.Context, handler func(ProgressEvent)) context.Context {
+	return context.WithValue(ctx, eventsKey{}, handler)
+}
+
+// HasEvents reports whether ctx carries an event handler
+func HasEvents(ctx context.Context) bool {
+	_, ok := ctx.Value(eventsKey{}).(func(ProgressEvent))
+	return ok
+}
+
+// Emit reports event to the handler carried by ctx, if any
+func Emit(ctx context.Context, event ProgressEvent) {
+	if handler, ok := ctx.Value(eventsKey{}).(func(ProgressEvent)); ok {
+		handler(event)
+	}
+}
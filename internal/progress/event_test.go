@@ -0,0 +1,27 @@
+package progress
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEmit(t *testing.T) {
+	// Without a handler events are dropped
+	ctx := context.Background()
+	if HasEvents(ctx) {
+		t.Error("HasEvents() = true for a context without a handler")
+	}
+	Emit(ctx, ProgressEvent{Stage: StageDone})
+
+	var got []ProgressEvent
+	ctx = WithEvents(ctx, func(event ProgressEvent) { got = append(got, event) })
+	if !HasEvents(ctx) {
+		t.Error("HasEvents() = false for a context with a handler")
+	}
+	Emit(ctx, ProgressEvent{Stage: StageDownloading, Done: 10, Total: 20})
+	Emit(ctx, ProgressEvent{Stage: StageDone})
+
+	if len(got) != 2 || got[0].Stage != StageDownloading || got[0].Done != 10 || got[0].Total != 20 || got[1].Stage != StageDone {
+		t.Errorf("unexpected events: %+v", got)
+	}
+}
@@ -3,6 +3,7 @@ package progress
 import (
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -63,7 +64,7 @@ func (pb *ProgressBar) Finish() {
 	pb.display()
 	// Print final line with newline
 	if !pb.config.Silent {
-		fmt.Println()
+		fmt.Fprintln(pb.terminal.output)
 	}
 }
 
@@ -222,10 +223,10 @@ func (s *Spinner) Stop() {
 
 // SimpleProgress shows simple progress messages
 func SimpleProgress(message string) {
-	fmt.Printf("⏳ %s...\n", message)
+	fmt.Fprintf(os.Stderr, "⏳ %s...\n", message)
 }
 
 // CompleteProgress shows completion message
 func CompleteProgress(message string) {
-	fmt.Printf("✓ %s\n", message)
+	fmt.Fprintf(os.Stderr, "✓ %s\n", message)
 }
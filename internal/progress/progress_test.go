@@ -48,18 +48,18 @@ func TestProgressBarUpdate(t *testing.T) {
 }
 
 func TestProgressBarDisplay(t *testing.T) {
-	// Capture stdout
-	old := os.Stdout
+	// Capture stderr
+	old := os.Stderr
 	r, w, _ := os.Pipe()
-	os.Stdout = w
+	os.Stderr = w
 
 	pb := NewProgressBar(100, "Test")
 	pb.Update(50)
 	pb.Finish()
 
-	// Restore stdout
+	// Restore stderr
 	w.Close()
-	os.Stdout = old
+	os.Stderr = old
 
 	// Read captured output
 	var buf bytes.Buffer
@@ -143,19 +143,19 @@ func TestSpinner(t *testing.T) {
 }
 
 func TestSpinnerStartStop(t *testing.T) {
-	// Capture stdout
-	old := os.Stdout
+	// Capture stderr
+	old := os.Stderr
 	r, w, _ := os.Pipe()
-	os.Stdout = w
+	os.Stderr = w
 
 	spinner := NewSpinner("Test")
 	spinner.Start()
 	time.Sleep(150 * time.Millisecond) // Let it spin a bit
 	spinner.Stop()
 
-	// Restore stdout
+	// Restore stderr
 	w.Close()
-	os.Stdout = old
+	os.Stderr = old
 
 	// Read captured output
 	var buf bytes.Buffer
@@ -174,17 +174,17 @@ func TestSpinnerStartStop(t *testing.T) {
 // TestFormatBytes has been moved to formatters/bytes_test.go
 
 func TestSimpleProgress(t *testing.T) {
-	// Capture stdout
-	old := os.Stdout
+	// Capture stderr
+	old := os.Stderr
 	r, w, _ := os.Pipe()
-	os.Stdout = w
+	os.Stderr = w
 
 	SimpleProgress("Test message")
 	CompleteProgress("Test completion")
 
-	// Restore stdout
+	// Restore stderr
 	w.Close()
-	os.Stdout = old
+	os.Stderr = old
 
 	// Read captured output
 	var buf bytes.Buffer
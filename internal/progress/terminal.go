@@ -12,33 +12,26 @@ import (
 
 // terminalWriter handles cross-platform terminal output with proper line clearing
 type terminalWriter struct {
-	output      io.Writer // Output destination (default: os.Stdout)
-	isStdout    bool      // True if output is os.Stdout
+	output      io.Writer // Output destination (default: os.Stderr, so progress never mixes with command output)
+	isTerminal  bool      // True if output is a terminal (TTY)
 	lastLineLen int       // Track last line length for proper clearing
 }
 
 // newTerminalWriter creates a new terminal writer
 func newTerminalWriter(output io.Writer) *terminalWriter {
 	if output == nil {
-		output = os.Stdout
+		output = os.Stderr
 	}
 
-	// Check if output is stdout AND is a terminal (TTY)
-	isStdout := false
-	if output == os.Stdout {
-		// Verify it's actually a TTY (not piped/redirected)
-		if term.IsTerminal(int(os.Stdout.Fd())) {
-			isStdout = true
-		}
-	} else if file, ok := output.(*os.File); ok {
-		if file == os.Stdout && term.IsTerminal(int(file.Fd())) {
-			isStdout = true
-		}
+	// Check if output is a terminal (not piped/redirected)
+	isTerminal := false
+	if file, ok := output.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		isTerminal = true
 	}
 
 	return &terminalWriter{
 		output:      output,
-		isStdout:    isStdout,
+		isTerminal:  isTerminal,
 		lastLineLen: 0,
 	}
 }
@@ -59,9 +52,8 @@ func (tw *terminalWriter) clearAndPrint(line string) {
 		tw.printUnixLine(line)
 	}
 
-	// Don't flush for stdout - fmt.Printf auto-flushes
-	// Only flush for other writers (like in tests)
-	if !tw.isStdout {
+	// Terminals are unbuffered; only flush other writers (like in tests)
+	if !tw.isTerminal {
 		tw.flush()
 	}
 }
@@ -76,8 +68,8 @@ func (tw *terminalWriter) printFinal(line string) {
 		fmt.Fprintln(tw.output)
 	}
 
-	// Only flush for non-stdout
-	if !tw.isStdout {
+	// Only flush for non-terminals
+	if !tw.isTerminal {
 		tw.flush()
 	}
 }
@@ -89,13 +81,7 @@ func (tw *terminalWriter) printWindowsLine(line string, maxWidth int) {
 		line = line + strings.Repeat(" ", maxWidth-len(line))
 	}
 
-	// Write directly for stdout, use fmt for other writers
-	if tw.isStdout {
-		// Use fmt.Printf with explicit format - no automatic newline
-		fmt.Printf("\r%s", line)
-	} else {
-		fmt.Fprintf(tw.output, "\r%s", line)
-	}
+	fmt.Fprintf(tw.output, "\r%s", line)
 }
 
 // printUnixLine handles Unix-like system line printing
@@ -107,15 +93,7 @@ func (tw *terminalWriter) printUnixLine(line string) {
 	}
 	tw.lastLineLen = len(line)
 
-	// Write directly for stdout, use fmt for other writers
-	if tw.isStdout {
-		// Use fmt.Printf with explicit format - no automatic newline
-		// Explicitly flush stdout after write
-		fmt.Printf("\r%s%s", line, padding)
-		_ = os.Stdout.Sync() // Best effort - sync errors are rare and typically non-fatal
-	} else {
-		fmt.Fprintf(tw.output, "\r%s%s", line, padding)
-	}
+	fmt.Fprintf(tw.output, "\r%s%s", line, padding)
 }
 
 // clear clears the current line
@@ -133,12 +111,6 @@ func (tw *terminalWriter) clear() {
 
 // flush forces the output to be written immediately
 func (tw *terminalWriter) flush() {
-	// For stdout, sync immediately
-	if tw.isStdout {
-		_ = os.Stdout.Sync() // Best effort - sync errors are rare and typically non-fatal
-		return
-	}
-
 	// Try to sync if the writer supports it
 	if syncer, ok := tw.output.(interface{ Sync() error }); ok {
 		_ = syncer.Sync()
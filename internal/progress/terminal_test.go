@@ -8,7 +8,7 @@ import (
 )
 
 func TestNewTerminalWriter(t *testing.T) {
-	// Test with nil output (should use os.Stdout)
+	// Test with nil output (should use os.Stderr)
 	tw := newTerminalWriter(nil)
 	if tw.output == nil {
 		t.Error("Expected output to be set when nil is provided")
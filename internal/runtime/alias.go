@@ -6,10 +6,13 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/fileutil"
+	"github.com/molmedoz/gopher/internal/log"
 	"github.com/molmedoz/gopher/internal/security"
 )
 
@@ -17,51 +20,116 @@ import (
 // Alias Management - Core Operations
 // ============================================================================
 
-// loadAliasesOnce is the internal function that loads aliases exactly once
-func (am *AliasManager) loadAliasesOnce() {
-	// Validate aliases file path is within safe root
+// safeAliasesFile returns the aliases file path after validating that it is
+// within the safe root (parent of InstallDir, e.g. ~/.gopher or ~/gopher)
+func (am *AliasManager) safeAliasesFile() (string, error) {
 	aliasesFileAbs, err := filepath.Abs(am.aliasesFile)
 	if err != nil {
-		am.loadErr = fmt.Errorf("failed to resolve aliases file path: %w", err)
-		return
+		return "", fmt.Errorf("failed to resolve aliases file path: %w", err)
 	}
 
-	// Get safe root directory (parent of InstallDir, e.g., ~/.gopher or ~/gopher)
 	installDirAbs, err := filepath.Abs(am.config.InstallDir)
 	if err != nil {
-		am.loadErr = fmt.Errorf("failed to resolve install directory: %w", err)
-		return
+		return "", fmt.Errorf("failed to resolve install directory: %w", err)
 	}
 	safeRoot := filepath.Dir(installDirAbs) // Parent of versions directory (e.g., ~/.gopher)
 
 	// Validate aliases file is within safe root to prevent path traversal
 	safeAliasesFile, err := security.ValidatePathWithinRoot(aliasesFileAbs, safeRoot)
 	if err != nil {
-		am.loadErr = fmt.Errorf("invalid aliases file path: %w", err)
-		return
-	}
-
-	// Check if aliases file exists
-	if _, err := os.Stat(safeAliasesFile); os.IsNotExist(err) {
-		// File doesn't exist, create empty aliases map
-		am.mu.Lock()
-		am.aliases = make(map[string]*Alias)
-		am.mu.Unlock()
-		return
+		return "", fmt.Errorf("invalid aliases file path: %w", err)
 	}
+	return safeAliasesFile, nil
+}
 
-	// Read aliases file
+// readAliasesFile reads and parses the aliases file, returning an empty map
+// when the file does not exist yet
+func readAliasesFile(path string) (map[string]*Alias, error) {
 	// #nosec G304 -- path validated and scoped to safeRoot
-	data, err := os.ReadFile(safeAliasesFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return make(map[string]*Alias), nil
+	}
 	if err != nil {
-		am.loadErr = fmt.Errorf("failed to read aliases file: %w", err)
-		return
+		return nil, fmt.Errorf("failed to read aliases file: %w", err)
 	}
 
-	// Parse JSON
 	var aliases map[string]*Alias
 	if err := json.Unmarshal(data, &aliases); err != nil {
-		am.loadErr = fmt.Errorf("failed to parse aliases file: %w", err)
+		return nil, errors.Wrapf(err, errors.ErrCodeAliasFileCorrupt, "aliases file %s is corrupt; run 'gopher alias validate --fix' to back it up and start with no aliases", path)
+	}
+	if aliases == nil {
+		aliases = make(map[string]*Alias)
+	}
+	return aliases, nil
+}
+
+// lockAliasesFile takes the aliases file lock, creating the aliases
+// directory first, and returns the validated file path with the unlock func
+func (am *AliasManager) lockAliasesFile() (string, func(), error) {
+	safeAliasesFile, err := am.safeAliasesFile()
+	if err != nil {
+		return "", nil, errors.Wrapf(err, errors.ErrCodeAliasLoadFailed, "failed to load aliases")
+	}
+
+	// Use 0750 for aliases directory - private user data
+	if err := os.MkdirAll(filepath.Dir(safeAliasesFile), 0750); err != nil {
+		return "", nil, errors.Wrapf(err, errors.ErrCodeAliasSaveFailed, "failed to create aliases directory")
+	}
+
+	unlock, err := acquireFileLock(safeAliasesFile + ".lock")
+	if err != nil {
+		return "", nil, errors.Wrapf(err, errors.ErrCodeAliasSaveFailed, "failed to lock aliases file")
+	}
+	return safeAliasesFile, unlock, nil
+}
+
+// ResetCorruptAliases moves an aliases file that cannot be parsed aside to a
+// timestamped backup and starts over with no aliases. It returns the backup
+// path, or an empty string if the file was not corrupt.
+func (am *AliasManager) ResetCorruptAliases() (string, error) {
+	// Consume the once so a later load does not overwrite the reset state
+	_ = am.LoadAliases()
+
+	safeAliasesFile, unlock, err := am.lockAliasesFile()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	aliases, err := readAliasesFile(safeAliasesFile)
+	if err != nil && !errors.IsErrorCode(err, errors.ErrCodeAliasFileCorrupt) {
+		return "", err
+	}
+
+	backupFile := ""
+	if err != nil {
+		backupFile = fmt.Sprintf("%s.corrupt-%s", safeAliasesFile, time.Now().Format("20060102-150405"))
+		if renameErr := os.Rename(safeAliasesFile, backupFile); renameErr != nil {
+			return "", errors.Wrapf(renameErr, errors.ErrCodeAliasFileCorrupt, "aliases file %s is corrupt and could not be backed up", safeAliasesFile)
+		}
+		log.Info("Aliases file %s was corrupt; the original was saved to %s", safeAliasesFile, backupFile)
+		aliases = make(map[string]*Alias)
+	}
+
+	am.mu.Lock()
+	am.aliases = aliases
+	am.loadErr = nil
+	am.mu.Unlock()
+	return backupFile, nil
+}
+
+// loadAliasesOnce is the internal function that loads aliases exactly once
+func (am *AliasManager) loadAliasesOnce() {
+	safeAliasesFile, err := am.safeAliasesFile()
+	if err != nil {
+		am.loadErr = err
+		return
+	}
+
+	aliases, err := readAliasesFile(safeAliasesFile)
+	if err != nil {
+		am.loadErr = err
 		return
 	}
 
@@ -76,28 +144,17 @@ func (am *AliasManager) LoadAliases() error {
 	return am.loadErr
 }
 
-// SaveAliases saves aliases to the aliases file
+// SaveAliases saves aliases to the aliases file.
+//
+// The file is replaced atomically (write to a temporary file, then rename),
+// so a concurrent reader never sees a partially written file.
 func (am *AliasManager) SaveAliases() error {
 	am.mu.RLock()
 	defer am.mu.RUnlock()
 
-	// Validate aliases file path is within safe root
-	aliasesFileAbs, err := filepath.Abs(am.aliasesFile)
-	if err != nil {
-		return fmt.Errorf("failed to resolve aliases file path: %w", err)
-	}
-
-	// Get safe root directory (parent of InstallDir, e.g., ~/.gopher or ~/gopher)
-	installDirAbs, err := filepath.Abs(am.config.InstallDir)
-	if err != nil {
-		return fmt.Errorf("failed to resolve install directory: %w", err)
-	}
-	safeRoot := filepath.Dir(installDirAbs) // Parent of versions directory (e.g., ~/.gopher)
-
-	// Validate aliases file is within safe root to prevent path traversal
-	safeAliasesFile, err := security.ValidatePathWithinRoot(aliasesFileAbs, safeRoot)
+	safeAliasesFile, err := am.safeAliasesFile()
 	if err != nil {
-		return fmt.Errorf("invalid aliases file path: %w", err)
+		return err
 	}
 
 	// Ensure directory exists
@@ -113,16 +170,52 @@ func (am *AliasManager) SaveAliases() error {
 		return fmt.Errorf("failed to marshal aliases: %w", err)
 	}
 
-	// Write to file
 	// #nosec G306 -- 0644 acceptable for aliases file (user-managed aliases)
-	// #nosec G304 -- path validated and scoped to safeRoot
-	if err := os.WriteFile(safeAliasesFile, data, 0644); err != nil {
+	if err := fileutil.WriteAtomic(safeAliasesFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write aliases file: %w", err)
 	}
 
 	return nil
 }
 
+// mutateAliases runs a load-modify-save cycle while holding the aliases file
+// lock, so concurrent gopher processes do not lose each other's updates.
+//
+// The aliases are re-read from disk after the lock is taken and fn is called
+// with am.mu held; fn must not lock am.mu itself. The result is saved only if
+// fn succeeds.
+func (am *AliasManager) mutateAliases(fn func() error) error {
+	if err := am.LoadAliases(); err != nil {
+		return errors.Wrapf(err, errors.ErrCodeAliasLoadFailed, "failed to load aliases")
+	}
+
+	safeAliasesFile, unlock, err := am.lockAliasesFile()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Pick up changes made by other processes since we loaded
+	latest, err := readAliasesFile(safeAliasesFile)
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeAliasLoadFailed, "failed to load aliases")
+	}
+
+	am.mu.Lock()
+	am.aliases = latest
+	err = fn()
+	am.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := am.SaveAliases(); err != nil {
+		return errors.Wrapf(err, errors.ErrCodeAliasSaveFailed, "failed to save aliases")
+	}
+
+	return nil
+}
+
 // ValidateAliasName validates an alias name
 func (am *AliasManager) ValidateAliasName(name string) error {
 	// Check if name is empty
@@ -131,7 +224,7 @@ func (am *AliasManager) ValidateAliasName(name string) error {
 	}
 
 	// Check for reserved names
-	reservedNames := []string{"system", "sys", "current", "list", "install", "uninstall", "use", "version", "help", "init", "setup", "status", "debug", "env", "alias", "go", "git", "ls", "cd", "pwd"}
+	reservedNames := []string{"system", "sys", "homebrew", "current", "list", "install", "uninstall", "use", "version", "help", "init", "setup", "status", "debug", "env", "alias", "go", "git", "ls", "cd", "pwd"}
 	for _, reserved := range reservedNames {
 		if strings.EqualFold(name, reserved) {
 			return fmt.Errorf("'%s' is a reserved name and cannot be used as an alias", name)
@@ -211,36 +304,26 @@ func (am *AliasManager) CreateAlias(name, version string) error {
 		return errors.Newf(errors.ErrCodeInvalidVersion, "invalid version: %v", err)
 	}
 
-	am.mu.Lock()
-	// Check if alias already exists
-	if _, exists := am.aliases[name]; exists {
-		am.mu.Unlock()
-		return errors.Newf(errors.ErrCodeAliasAlreadyExists, "alias '%s' already exists (use 'gopher alias remove %s' first)", name, name)
-	}
-
-	// Check if version is installed
-	if !am.isVersionInstalled(version) {
-		am.mu.Unlock()
-		return errors.Newf(errors.ErrCodeVersionNotInstalled, "version %s is not installed (use 'gopher install %s' first)", version, version)
-	}
-
-	// Create new alias
-	alias := &Alias{
-		Name:    name,
-		Version: NormalizeVersion(version),
-		Created: time.Now(),
-		Updated: time.Now(),
-	}
-
-	am.aliases[name] = alias
-	am.mu.Unlock()
+	return am.mutateAliases(func() error {
+		// Check if alias already exists
+		if _, exists := am.aliases[name]; exists {
+			return errors.Newf(errors.ErrCodeAliasAlreadyExists, "alias '%s' already exists (use 'gopher alias remove %s' first)", name, name)
+		}
 
-	// Save aliases
-	if err := am.SaveAliases(); err != nil {
-		return errors.Wrapf(err, errors.ErrCodeAliasSaveFailed, "failed to save aliases")
-	}
+		// Check if version is installed
+		if !am.isVersionInstalled(version) {
+			return errors.Newf(errors.ErrCodeVersionNotInstalled, "version %s is not installed (use 'gopher install %s' first)", version, version)
+		}
 
-	return nil
+		// Create new alias
+		am.aliases[name] = &Alias{
+			Name:    name,
+			Version: NormalizeVersion(version),
+			Created: time.Now(),
+			Updated: time.Now(),
+		}
+		return nil
+	})
 }
 
 // GetAlias gets an alias by name
@@ -287,23 +370,16 @@ func (am *AliasManager) RemoveAlias(name string) error {
 		return errors.Newf(errors.ErrCodeInvalidAliasName, "invalid alias name: %v", err)
 	}
 
-	am.mu.Lock()
-	// Check if alias exists
-	if _, exists := am.aliases[name]; !exists {
-		am.mu.Unlock()
-		return errors.Newf(errors.ErrCodeAliasNotFound, "alias '%s' does not exist", name)
-	}
-
-	// Remove alias
-	delete(am.aliases, name)
-	am.mu.Unlock()
-
-	// Save aliases
-	if err := am.SaveAliases(); err != nil {
-		return errors.Wrapf(err, errors.ErrCodeAliasSaveFailed, "failed to save aliases")
-	}
+	return am.mutateAliases(func() error {
+		// Check if alias exists
+		if _, exists := am.aliases[name]; !exists {
+			return errors.Newf(errors.ErrCodeAliasNotFound, "alias '%s' does not exist", name)
+		}
 
-	return nil
+		// Remove alias
+		delete(am.aliases, name)
+		return nil
+	})
 }
 
 // UpdateAlias updates an existing alias
@@ -323,31 +399,23 @@ func (am *AliasManager) UpdateAlias(name, version string) error {
 		return errors.Newf(errors.ErrCodeInvalidVersion, "invalid version: %v", err)
 	}
 
-	am.mu.Lock()
-	// Check if alias exists
-	alias, exists := am.aliases[name]
-	if !exists {
-		am.mu.Unlock()
-		return errors.Newf(errors.ErrCodeAliasNotFound, "alias '%s' does not exist", name)
-	}
-
-	// Check if version is installed
-	if !am.isVersionInstalled(version) {
-		am.mu.Unlock()
-		return errors.Newf(errors.ErrCodeVersionNotInstalled, "version %s is not installed (use 'gopher install %s' first)", version, version)
-	}
-
-	// Update alias
-	alias.Version = NormalizeVersion(version)
-	alias.Updated = time.Now()
-	am.mu.Unlock()
+	return am.mutateAliases(func() error {
+		// Check if alias exists
+		alias, exists := am.aliases[name]
+		if !exists {
+			return errors.Newf(errors.ErrCodeAliasNotFound, "alias '%s' does not exist", name)
+		}
 
-	// Save aliases
-	if err := am.SaveAliases(); err != nil {
-		return errors.Wrapf(err, errors.ErrCodeAliasSaveFailed, "failed to save aliases")
-	}
+		// Check if version is installed
+		if !am.isVersionInstalled(version) {
+			return errors.Newf(errors.ErrCodeVersionNotInstalled, "version %s is not installed (use 'gopher install %s' first)", version, version)
+		}
 
-	return nil
+		// Update alias
+		alias.Version = NormalizeVersion(version)
+		alias.Updated = time.Now()
+		return nil
+	})
 }
 
 // GetAliasesByVersion returns all aliases pointing to a specific version
@@ -371,6 +439,78 @@ func (am *AliasManager) GetAliasesByVersion(version string) ([]*Alias, error) {
 	return result, nil
 }
 
+// FindDanglingAliases returns the aliases whose version is no longer
+// installed, sorted by name
+func (am *AliasManager) FindDanglingAliases() ([]*Alias, error) {
+	// Load aliases first
+	if err := am.LoadAliases(); err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeAliasLoadFailed, "failed to load aliases")
+	}
+
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
+	return am.danglingAliases(), nil
+}
+
+// danglingAliases returns the aliases pointing at versions that are not
+// installed; the caller must hold am.mu
+func (am *AliasManager) danglingAliases() []*Alias {
+	var result []*Alias
+	for _, alias := range am.aliases {
+		if !am.isVersionInstalled(alias.Version) {
+			result = append(result, alias)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// RepairDanglingAliases fixes aliases whose version is no longer installed.
+// If reassignTo is empty the dangling aliases are removed, otherwise they are
+// pointed at reassignTo, which must be installed. It returns the aliases as
+// they were before the repair.
+func (am *AliasManager) RepairDanglingAliases(reassignTo string) ([]*Alias, error) {
+	// Load aliases first
+	if err := am.LoadAliases(); err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeAliasLoadFailed, "failed to load aliases")
+	}
+
+	if reassignTo != "" {
+		// Validate version for security (path traversal protection)
+		if err := security.ValidatePath(reassignTo); err != nil {
+			return nil, errors.Newf(errors.ErrCodeInvalidVersion, "invalid version: %v", err)
+		}
+		if !am.isVersionInstalled(reassignTo) {
+			return nil, errors.Newf(errors.ErrCodeVersionNotInstalled, "version %s is not installed (use 'gopher install %s' first)", reassignTo, reassignTo)
+		}
+	}
+
+	var repaired []*Alias
+	err := am.mutateAliases(func() error {
+		for _, alias := range am.danglingAliases() {
+			before := *alias
+			repaired = append(repaired, &before)
+
+			if reassignTo == "" {
+				delete(am.aliases, alias.Name)
+				continue
+			}
+			alias.Version = NormalizeVersion(reassignTo)
+			alias.Updated = time.Now()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repaired, nil
+}
+
 // Standard alias patterns for suggestions
 var standardAliasPatterns = []string{
 	"stable", "latest", "dev", "development", "prod", "production",
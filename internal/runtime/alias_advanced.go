@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
@@ -31,46 +32,42 @@ func (am *AliasManager) CreateAliasInteractive(name, version string, allowOverri
 		return fmt.Errorf("version %s is not installed (use 'gopher install %s' first)", version, version)
 	}
 
-	// Check if alias already exists
-	if existing, exists := am.aliases[name]; exists {
-		// Handle conflict resolution
-		if force {
-			// Force mode - update without confirmation
-			existing.Version = NormalizeVersion(version)
-			existing.Updated = time.Now()
-		} else if noOverride {
-			// No override mode - return error
-			return fmt.Errorf("alias '%s' already exists and points to %s (use 'gopher alias remove %s' first)", name, existing.Version, name)
-		} else if allowOverride {
-			// Allow override mode - update without confirmation
-			existing.Version = NormalizeVersion(version)
-			existing.Updated = time.Now()
+	return am.mutateAliases(func() error {
+		// Check if alias already exists
+		if existing, exists := am.aliases[name]; exists {
+			// Handle conflict resolution
+			if force {
+				// Force mode - update without confirmation
+				existing.Version = NormalizeVersion(version)
+				existing.Updated = time.Now()
+			} else if noOverride {
+				// No override mode - return error
+				return fmt.Errorf("alias '%s' already exists and points to %s (use 'gopher alias remove %s' first)", name, existing.Version, name)
+			} else if allowOverride {
+				// Allow override mode - update without confirmation
+				existing.Version = NormalizeVersion(version)
+				existing.Updated = time.Now()
+			} else {
+				// Interactive mode - ask for confirmation
+				if err := am.handleAliasConflict(name, existing.Version, version); err != nil {
+					return err
+				}
+				// If we get here, user confirmed the update
+				existing.Version = NormalizeVersion(version)
+				existing.Updated = time.Now()
+			}
 		} else {
-			// Interactive mode - ask for confirmation
-			if err := am.handleAliasConflict(name, existing.Version, version); err != nil {
-				return err
+			// Create new alias
+			alias := &Alias{
+				Name:    name,
+				Version: NormalizeVersion(version),
+				Created: time.Now(),
+				Updated: time.Now(),
 			}
-			// If we get here, user confirmed the update
-			existing.Version = NormalizeVersion(version)
-			existing.Updated = time.Now()
-		}
-	} else {
-		// Create new alias
-		alias := &Alias{
-			Name:    name,
-			Version: NormalizeVersion(version),
-			Created: time.Now(),
-			Updated: time.Now(),
+			am.aliases[name] = alias
 		}
-		am.aliases[name] = alias
-	}
-
-	// Save aliases
-	if err := am.SaveAliases(); err != nil {
-		return fmt.Errorf("failed to save aliases: %w", err)
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // UpdateAliasInteractive updates an alias with interactive conflict resolution
@@ -80,47 +77,43 @@ func (am *AliasManager) UpdateAliasInteractive(name, version string, allowOverri
 		return fmt.Errorf("failed to load aliases: %w", err)
 	}
 
-	// Check if alias exists
-	existing, exists := am.aliases[name]
-	if !exists {
-		return fmt.Errorf("alias '%s' does not exist", name)
-	}
+	return am.mutateAliases(func() error {
+		// Check if alias exists
+		existing, exists := am.aliases[name]
+		if !exists {
+			return fmt.Errorf("alias '%s' does not exist", name)
+		}
 
-	// Check if version is installed
-	if !am.isVersionInstalled(version) {
-		return fmt.Errorf("version %s is not installed (use 'gopher install %s' first)", version, version)
-	}
+		// Check if version is installed
+		if !am.isVersionInstalled(version) {
+			return fmt.Errorf("version %s is not installed (use 'gopher install %s' first)", version, version)
+		}
 
-	// Handle conflict resolution if version is different
-	if existing.Version != NormalizeVersion(version) {
-		if force {
-			// Force mode - update without confirmation
-			existing.Version = NormalizeVersion(version)
-			existing.Updated = time.Now()
-		} else if noOverride {
-			// No override mode - return error
-			return fmt.Errorf("alias '%s' already points to %s (use 'gopher alias remove %s' first)", name, existing.Version, name)
-		} else if allowOverride {
-			// Allow override mode - update without confirmation
-			existing.Version = NormalizeVersion(version)
-			existing.Updated = time.Now()
-		} else {
-			// Interactive mode - ask for confirmation
-			if err := am.handleAliasConflict(name, existing.Version, version); err != nil {
-				return err
+		// Handle conflict resolution if version is different
+		if existing.Version != NormalizeVersion(version) {
+			if force {
+				// Force mode - update without confirmation
+				existing.Version = NormalizeVersion(version)
+				existing.Updated = time.Now()
+			} else if noOverride {
+				// No override mode - return error
+				return fmt.Errorf("alias '%s' already points to %s (use 'gopher alias remove %s' first)", name, existing.Version, name)
+			} else if allowOverride {
+				// Allow override mode - update without confirmation
+				existing.Version = NormalizeVersion(version)
+				existing.Updated = time.Now()
+			} else {
+				// Interactive mode - ask for confirmation
+				if err := am.handleAliasConflict(name, existing.Version, version); err != nil {
+					return err
+				}
+				// If we get here, user confirmed the update
+				existing.Version = NormalizeVersion(version)
+				existing.Updated = time.Now()
 			}
-			// If we get here, user confirmed the update
-			existing.Version = NormalizeVersion(version)
-			existing.Updated = time.Now()
 		}
-	}
-
-	// Save aliases
-	if err := am.SaveAliases(); err != nil {
-		return fmt.Errorf("failed to save aliases: %w", err)
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // CreateAliasesBulk creates multiple aliases with conflict resolution
@@ -140,59 +133,128 @@ func (am *AliasManager) CreateAliasesBulk(aliases map[string]string, allowOverri
 		}
 	}
 
-	// Process each alias
-	for name, version := range aliases {
-		normalizedVersion := NormalizeVersion(version)
-
-		if existing, exists := am.aliases[name]; exists {
-			// Handle conflict resolution
-			if force {
-				// Force mode - update without confirmation
-				existing.Version = normalizedVersion
-				existing.Updated = time.Now()
-			} else if noOverride {
-				// No override mode - return error
-				return fmt.Errorf("alias '%s' already exists and points to %s (use 'gopher alias remove %s' first)", name, existing.Version, name)
-			} else if allowOverride {
-				// Allow override mode - update without confirmation
-				existing.Version = normalizedVersion
-				existing.Updated = time.Now()
+	return am.mutateAliases(func() error {
+		// Process each alias
+		for name, version := range aliases {
+			normalizedVersion := NormalizeVersion(version)
+
+			if existing, exists := am.aliases[name]; exists {
+				// Handle conflict resolution
+				if force {
+					// Force mode - update without confirmation
+					existing.Version = normalizedVersion
+					existing.Updated = time.Now()
+				} else if noOverride {
+					// No override mode - return error
+					return fmt.Errorf("alias '%s' already exists and points to %s (use 'gopher alias remove %s' first)", name, existing.Version, name)
+				} else if allowOverride {
+					// Allow override mode - update without confirmation
+					existing.Version = normalizedVersion
+					existing.Updated = time.Now()
+				} else {
+					// Interactive mode - ask for confirmation
+					if err := am.handleAliasConflict(name, existing.Version, version); err != nil {
+						return err
+					}
+					// If we get here, user confirmed the update
+					existing.Version = normalizedVersion
+					existing.Updated = time.Now()
+				}
 			} else {
-				// Interactive mode - ask for confirmation
-				if err := am.handleAliasConflict(name, existing.Version, version); err != nil {
-					return err
+				// Create new alias
+				alias := &Alias{
+					Name:    name,
+					Version: normalizedVersion,
+					Created: time.Now(),
+					Updated: time.Now(),
 				}
-				// If we get here, user confirmed the update
-				existing.Version = normalizedVersion
-				existing.Updated = time.Now()
-			}
-		} else {
-			// Create new alias
-			alias := &Alias{
-				Name:    name,
-				Version: normalizedVersion,
-				Created: time.Now(),
-				Updated: time.Now(),
+				am.aliases[name] = alias
 			}
-			am.aliases[name] = alias
 		}
+		return nil
+	})
+}
+
+// BulkAliasResult is the outcome of one alias in CreateAliasesKeepGoing
+type BulkAliasResult struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	OK      bool   `json:"ok"`
+	Updated bool   `json:"updated,omitempty"` // An existing alias was repointed
+	Error   string `json:"error,omitempty"`
+	Err     error  `json:"-"`
+}
+
+// CreateAliasesKeepGoing creates multiple aliases like CreateAliasesBulk, but
+// an alias that cannot be created (invalid name, version not installed, or a
+// conflict that is refused) does not stop the others. It returns one result
+// per alias, sorted by name; the error is only set when the aliases cannot be
+// loaded or saved.
+func (am *AliasManager) CreateAliasesKeepGoing(aliases map[string]string, allowOverride, noOverride, force bool) ([]BulkAliasResult, error) {
+	if err := am.LoadAliases(); err != nil {
+		return nil, fmt.Errorf("failed to load aliases: %w", err)
 	}
 
-	// Save aliases
-	if err := am.SaveAliases(); err != nil {
-		return fmt.Errorf("failed to save aliases: %w", err)
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+
+	results := make([]BulkAliasResult, 0, len(names))
+	err := am.mutateAliases(func() error {
+		for _, name := range names {
+			version := aliases[name]
+			result := BulkAliasResult{Name: name, Version: NormalizeVersion(version)}
+
+			existing, exists := am.aliases[name]
+			nameErr := am.ValidateAliasName(name)
+			switch {
+			case nameErr != nil:
+				result.Err = fmt.Errorf("invalid alias name '%s': %w", name, nameErr)
+			case !am.isVersionInstalled(version):
+				result.Err = fmt.Errorf("version %s is not installed (use 'gopher install %s' first)", version, version)
+			case exists && noOverride && !force:
+				result.Err = fmt.Errorf("alias '%s' already exists and points to %s", name, existing.Version)
+			case exists && !force && !allowOverride:
+				result.Err = am.handleAliasConflict(name, existing.Version, version)
+			}
+			if result.Err != nil {
+				result.Error = result.Err.Error()
+				results = append(results, result)
+				continue
+			}
 
-	return nil
+			if exists {
+				existing.Version = result.Version
+				existing.Updated = time.Now()
+				result.Updated = true
+			} else {
+				am.aliases[name] = &Alias{
+					Name:    name,
+					Version: result.Version,
+					Created: time.Now(),
+					Updated: time.Now(),
+				}
+			}
+			result.OK = true
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
 }
 
 // handleAliasConflict handles interactive conflict resolution
 func (am *AliasManager) handleAliasConflict(name, currentVersion, newVersion string) error {
-	fmt.Printf("\n⚠️  Alias Conflict Detected\n")
-	fmt.Printf("   Alias: %s\n", name)
-	fmt.Printf("   Current: %s\n", currentVersion)
-	fmt.Printf("   New:     %s\n", newVersion)
-	fmt.Print("\nUpdate alias? (y/yes/n/no): ")
+	fmt.Fprintf(os.Stderr, "\n⚠️  Alias Conflict Detected\n")
+	fmt.Fprintf(os.Stderr, "   Alias: %s\n", name)
+	fmt.Fprintf(os.Stderr, "   Current: %s\n", currentVersion)
+	fmt.Fprintf(os.Stderr, "   New:     %s\n", newVersion)
+	fmt.Fprint(os.Stderr, "\nUpdate alias? (y/yes/n/no): ")
 
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
@@ -259,9 +321,93 @@ func (am *AliasManager) ExportAliases(filename string, format string, tags []str
 
 // ImportAliases imports aliases from a file
 func (am *AliasManager) ImportAliases(filename string, allowOverride, noOverride, force bool) error {
+	aliases, err := am.readImportFile(filename)
+	if err != nil {
+		return err
+	}
+
+	// Create aliases using bulk creation
+	return am.CreateAliasesBulk(aliases, allowOverride, noOverride, force)
+}
+
+// ImportPreviewEntry is one alias from an import file, as sorted by
+// PreviewImport
+type ImportPreviewEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`           // Version in the import file
+	Current string `json:"current,omitempty"` // Version the existing alias points to
+	Problem string `json:"problem,omitempty"` // Why the alias cannot be imported
+}
+
+// ImportPreview describes what ImportAliases would do with a file. Each list
+// is sorted by alias name.
+type ImportPreview struct {
+	Create    []ImportPreviewEntry `json:"create"`    // New aliases
+	Conflicts []ImportPreviewEntry `json:"conflicts"` // Existing aliases pointing elsewhere
+	Unchanged []ImportPreviewEntry `json:"unchanged"` // Existing aliases already pointing there
+	Invalid   []ImportPreviewEntry `json:"invalid"`   // Bad names or versions that are not installed
+}
+
+// PreviewImport compares the aliases in an import file with the existing
+// ones without changing anything. ImportAliases fails as a whole while any
+// alias is invalid; conflicts are resolved by its override flags.
+func (am *AliasManager) PreviewImport(filename string) (*ImportPreview, error) {
+	aliases, err := am.readImportFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := am.LoadAliases(); err != nil {
+		return nil, fmt.Errorf("failed to load aliases: %w", err)
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	preview := &ImportPreview{
+		Create:    []ImportPreviewEntry{},
+		Conflicts: []ImportPreviewEntry{},
+		Unchanged: []ImportPreviewEntry{},
+		Invalid:   []ImportPreviewEntry{},
+	}
+	for _, name := range names {
+		entry := ImportPreviewEntry{Name: name, Version: aliases[name]}
+
+		if err := am.ValidateAliasName(name); err != nil {
+			entry.Problem = fmt.Sprintf("invalid alias name: %v", err)
+			preview.Invalid = append(preview.Invalid, entry)
+			continue
+		}
+		if !am.isVersionInstalled(entry.Version) {
+			entry.Problem = fmt.Sprintf("version %s is not installed", entry.Version)
+			preview.Invalid = append(preview.Invalid, entry)
+			continue
+		}
+
+		existing, exists := am.aliases[name]
+		switch {
+		case !exists:
+			preview.Create = append(preview.Create, entry)
+		case existing.Version == NormalizeVersion(entry.Version):
+			entry.Current = existing.Version
+			preview.Unchanged = append(preview.Unchanged, entry)
+		default:
+			entry.Current = existing.Version
+			preview.Conflicts = append(preview.Conflicts, entry)
+		}
+	}
+
+	return preview, nil
+}
+
+// readImportFile reads the alias name to version mapping from an import file
+func (am *AliasManager) readImportFile(filename string) (map[string]string, error) {
 	// Check if file exists
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		return fmt.Errorf("file not found: %s", filename)
+		return nil, fmt.Errorf("file not found: %s", filename)
 	}
 
 	// Determine file format
@@ -281,15 +427,14 @@ func (am *AliasManager) ImportAliases(filename string, allowOverride, noOverride
 	case "json":
 		aliases, err = am.importFromJSON(filename)
 	default:
-		return fmt.Errorf("unsupported file format: %s", ext)
+		return nil, fmt.Errorf("unsupported file format: %s", ext)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to import aliases: %w", err)
+		return nil, fmt.Errorf("failed to import aliases: %w", err)
 	}
 
-	// Create aliases using bulk creation
-	return am.CreateAliasesBulk(aliases, allowOverride, noOverride, force)
+	return aliases, nil
 }
 
 // exportToJSON exports aliases to JSON file
@@ -0,0 +1,104 @@
+package runtime
+
+import (
+	"sort"
+	"time"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/security"
+)
+
+// ============================================================================
+// Alias Management - Groups
+// ============================================================================
+
+// A group is a named set of aliases that are meant to be used together, such
+// as the versions of one environment. Membership is stored in each alias's
+// Group field, so groups are persisted in the aliases file without changing
+// its format.
+
+// CreateAliasGroup creates the aliases in bindings (alias name -> version) as
+// members of group. Either all aliases are created or none are: the group
+// must not exist yet and none of the alias names may be taken.
+func (am *AliasManager) CreateAliasGroup(group string, bindings map[string]string) error {
+	if err := errors.ValidateAliasName(group); err != nil {
+		return errors.Newf(errors.ErrCodeInvalidAliasName, "invalid group name '%s': %v", group, err)
+	}
+	if err := security.ValidatePath(group); err != nil {
+		return errors.Newf(errors.ErrCodeInvalidAliasName, "invalid group name: %v", err)
+	}
+	if len(bindings) == 0 {
+		return errors.Newf(errors.ErrCodeInvalidArgument, "group '%s' needs at least one alias (e.g., 'stable=1.22.0')", group)
+	}
+
+	for name, version := range bindings {
+		if err := errors.ValidateAliasName(name); err != nil {
+			return err
+		}
+		if err := security.ValidatePath(name); err != nil {
+			return errors.Newf(errors.ErrCodeInvalidAliasName, "invalid alias name: %v", err)
+		}
+		if err := errors.ValidateVersion(version); err != nil {
+			return err
+		}
+		if err := security.ValidatePath(version); err != nil {
+			return errors.Newf(errors.ErrCodeInvalidVersion, "invalid version: %v", err)
+		}
+	}
+
+	return am.mutateAliases(func() error {
+		for _, alias := range am.aliases {
+			if alias.Group == group {
+				return errors.Newf(errors.ErrCodeAliasAlreadyExists, "alias group '%s' already exists (use 'gopher alias group show %s' to see it)", group, group)
+			}
+		}
+
+		for name, version := range bindings {
+			if _, exists := am.aliases[name]; exists {
+				return errors.Newf(errors.ErrCodeAliasAlreadyExists, "alias '%s' already exists (use 'gopher alias remove %s' first)", name, name)
+			}
+			if !am.isVersionInstalled(NormalizeVersion(version)) {
+				return errors.Newf(errors.ErrCodeVersionNotInstalled, "version %s is not installed for alias '%s' (use 'gopher install %s' first)", version, name, version)
+			}
+		}
+
+		now := time.Now()
+		for name, version := range bindings {
+			am.aliases[name] = &Alias{
+				Name:    name,
+				Version: NormalizeVersion(version),
+				Created: now,
+				Updated: now,
+				Group:   group,
+			}
+		}
+		return nil
+	})
+}
+
+// GetAliasGroup returns the aliases that belong to group, sorted by name
+func (am *AliasManager) GetAliasGroup(group string) ([]*Alias, error) {
+	// Load aliases first
+	if err := am.LoadAliases(); err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeAliasLoadFailed, "failed to load aliases")
+	}
+
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
+	var result []*Alias
+	for _, alias := range am.aliases {
+		if alias.Group == group {
+			result = append(result, alias)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, errors.Newf(errors.ErrCodeAliasNotFound, "alias group '%s' does not exist", group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
@@ -1,8 +1,11 @@
 package runtime
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -374,3 +377,332 @@ func TestAliasManager_IsVersionInstalled(t *testing.T) {
 		t.Error("expected isVersionInstalled to return true with nil manager")
 	}
 }
+
+func TestAliasManager_ConcurrentCreateAlias(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := &config.Config{
+		InstallDir: filepath.Join(tmp, "install"),
+	}
+
+	// Each goroutine uses its own AliasManager to simulate separate gopher
+	// processes that only share the aliases file on disk
+	const workers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			am := NewAliasManager(cfg)
+			errs <- am.CreateAlias(fmt.Sprintf("alias%d", i), "go1.21.0")
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("CreateAlias error: %v", err)
+		}
+	}
+
+	aliases, err := NewAliasManager(cfg).ListAliases()
+	if err != nil {
+		t.Fatalf("ListAliases error: %v", err)
+	}
+	if len(aliases) != workers {
+		t.Fatalf("expected %d aliases to survive concurrent creation, got %d", workers, len(aliases))
+	}
+
+	// The lock file must be released and no temporary files left behind
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != "aliases.json" && entry.Name() != "install" {
+			t.Errorf("unexpected file left in aliases directory: %s", entry.Name())
+		}
+	}
+}
+
+func TestAliasManager_CorruptAliasesFile(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := &config.Config{
+		InstallDir: filepath.Join(tmp, "install"),
+	}
+	aliasesFile := filepath.Join(tmp, "aliases.json")
+	// #nosec G306 -- 0644 acceptable for test files
+	if err := os.WriteFile(aliasesFile, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reads and writes report the corruption and leave the file alone
+	am := NewAliasManager(cfg)
+	if _, err := am.ListAliases(); err == nil || !strings.Contains(err.Error(), "corrupt") {
+		t.Fatalf("expected a corrupt aliases file error, got %v", err)
+	}
+	if err := am.CreateAlias("stable", "go1.21.0"); err == nil {
+		t.Fatal("CreateAlias succeeded with a corrupt aliases file")
+	}
+	if data, err := os.ReadFile(aliasesFile); err != nil || string(data) != "{not json" {
+		t.Fatalf("aliases file was changed: %q, %v", data, err)
+	}
+
+	backup, err := am.ResetCorruptAliases()
+	if err != nil {
+		t.Fatalf("ResetCorruptAliases failed: %v", err)
+	}
+	if backup == "" {
+		t.Fatal("expected a backup of the corrupt aliases file")
+	}
+	data, err := os.ReadFile(backup)
+	if err != nil {
+		t.Fatalf("failed to read backup: %v", err)
+	}
+	if string(data) != "{not json" {
+		t.Errorf("backup content = %q, want original content", string(data))
+	}
+
+	// Subsequent writes must work again
+	if err := am.CreateAlias("stable", "go1.21.0"); err != nil {
+		t.Fatalf("CreateAlias after reset failed: %v", err)
+	}
+
+	// A valid file is left as it is
+	if backup, err := am.ResetCorruptAliases(); err != nil || backup != "" {
+		t.Errorf("ResetCorruptAliases on a valid file = %q, %v", backup, err)
+	}
+}
+
+func TestAliasManager_DanglingAliases(t *testing.T) {
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, "versions")
+	manager := createTestManager(t, installDir)
+	writeMetadata(t, installDir, "go1.21.0")
+	writeMetadata(t, installDir, "go1.22.0")
+
+	am := manager.AliasManager()
+	if err := am.CreateAlias("stable", "go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := am.CreateAlias("old", "go1.22.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Uninstall go1.22.0 behind the alias manager's back
+	if err := os.RemoveAll(filepath.Join(installDir, "go1.22.0")); err != nil {
+		t.Fatal(err)
+	}
+
+	dangling, err := am.FindDanglingAliases()
+	if err != nil {
+		t.Fatalf("FindDanglingAliases error: %v", err)
+	}
+	if len(dangling) != 1 || dangling[0].Name != "old" {
+		t.Fatalf("expected only 'old' to be dangling, got %v", dangling)
+	}
+
+	t.Run("reassign", func(t *testing.T) {
+		repaired, err := am.RepairDanglingAliases("go1.21.0")
+		if err != nil {
+			t.Fatalf("RepairDanglingAliases error: %v", err)
+		}
+		if len(repaired) != 1 || repaired[0].Version != "go1.22.0" {
+			t.Fatalf("expected old alias state to be reported, got %v", repaired)
+		}
+		alias, ok := am.GetAlias("old")
+		if !ok || alias.Version != "go1.21.0" {
+			t.Errorf("expected 'old' to be reassigned to go1.21.0, got %v", alias)
+		}
+	})
+
+	t.Run("reassign to uninstalled version", func(t *testing.T) {
+		if _, err := am.RepairDanglingAliases("go1.99.0"); err == nil {
+			t.Error("expected error when reassigning to an uninstalled version")
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		if err := am.UpdateAlias("old", "go1.21.0"); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.RemoveAll(filepath.Join(installDir, "go1.21.0")); err != nil {
+			t.Fatal(err)
+		}
+		repaired, err := am.RepairDanglingAliases("")
+		if err != nil {
+			t.Fatalf("RepairDanglingAliases error: %v", err)
+		}
+		if len(repaired) != 2 {
+			t.Fatalf("expected 2 aliases to be removed, got %d", len(repaired))
+		}
+		aliases, err := am.ListAliases()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(aliases) != 0 {
+			t.Errorf("expected no aliases left, got %d", len(aliases))
+		}
+	})
+}
+
+func TestAliasManager_PreviewImport(t *testing.T) {
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, "versions")
+	manager := createTestManager(t, installDir)
+	writeMetadata(t, installDir, "go1.21.0")
+	writeMetadata(t, installDir, "go1.22.0")
+
+	am := manager.AliasManager()
+	if err := am.CreateAlias("stable", "go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := am.CreateAlias("prod", "go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	importFile := filepath.Join(tmp, "team.json")
+	data := `{
+  "stable": {"name": "stable", "version": "go1.22.0"},
+  "prod": {"name": "prod", "version": "go1.21.0"},
+  "dev": {"name": "dev", "version": "go1.22.0"},
+  "old": {"name": "old", "version": "go1.19.0"},
+  "bad name": {"name": "bad name", "version": "go1.21.0"}
+}`
+	if err := os.WriteFile(importFile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	preview, err := am.PreviewImport(importFile)
+	if err != nil {
+		t.Fatalf("PreviewImport error: %v", err)
+	}
+
+	if len(preview.Create) != 1 || preview.Create[0].Name != "dev" {
+		t.Errorf("Create = %v, want [dev]", preview.Create)
+	}
+	if len(preview.Conflicts) != 1 || preview.Conflicts[0].Name != "stable" ||
+		preview.Conflicts[0].Current != "go1.21.0" || preview.Conflicts[0].Version != "go1.22.0" {
+		t.Errorf("Conflicts = %v, want stable go1.21.0 -> go1.22.0", preview.Conflicts)
+	}
+	if len(preview.Unchanged) != 1 || preview.Unchanged[0].Name != "prod" {
+		t.Errorf("Unchanged = %v, want [prod]", preview.Unchanged)
+	}
+	if len(preview.Invalid) != 2 || preview.Invalid[0].Name != "bad name" || preview.Invalid[1].Name != "old" {
+		t.Errorf("Invalid = %v, want [bad name, old]", preview.Invalid)
+	}
+
+	// Nothing was written
+	if alias, _ := am.GetAlias("stable"); alias.Version != "go1.21.0" {
+		t.Errorf("stable changed to %s by preview", alias.Version)
+	}
+	if _, exists := am.GetAlias("dev"); exists {
+		t.Error("dev was created by preview")
+	}
+}
+
+func TestAliasManager_CreateAliasesKeepGoing(t *testing.T) {
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, "versions")
+	manager := createTestManager(t, installDir)
+	writeMetadata(t, installDir, "go1.21.0")
+	writeMetadata(t, installDir, "go1.22.0")
+
+	am := manager.AliasManager()
+	if err := am.CreateAlias("stable", "go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := am.CreateAliasesKeepGoing(map[string]string{
+		"stable":   "go1.22.0",
+		"dev":      "go1.22.0",
+		"old":      "go1.19.0",
+		"bad name": "go1.21.0",
+		"prod":     "go1.21.0",
+	}, false, true, false)
+	if err != nil {
+		t.Fatalf("CreateAliasesKeepGoing error: %v", err)
+	}
+
+	want := []struct {
+		name string
+		ok   bool
+	}{{"bad name", false}, {"dev", true}, {"old", false}, {"prod", true}, {"stable", false}}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i, w := range want {
+		if results[i].Name != w.name || results[i].OK != w.ok || (results[i].Error == "") != w.ok {
+			t.Errorf("results[%d] = %+v, want %s ok=%v", i, results[i], w.name, w.ok)
+		}
+	}
+
+	// The aliases that could be created were saved; the conflict was kept
+	if alias, exists := am.GetAlias("dev"); !exists || alias.Version != "go1.22.0" {
+		t.Errorf("dev = %+v, want go1.22.0", alias)
+	}
+	if alias, exists := am.GetAlias("prod"); !exists || alias.Version != "go1.21.0" {
+		t.Errorf("prod = %+v, want go1.21.0", alias)
+	}
+	if alias, _ := am.GetAlias("stable"); alias.Version != "go1.21.0" {
+		t.Errorf("stable changed to %s despite --no-override", alias.Version)
+	}
+}
+
+func TestAliasManager_AliasGroups(t *testing.T) {
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, "versions")
+	manager := createTestManager(t, installDir)
+	writeMetadata(t, installDir, "go1.21.0")
+	writeMetadata(t, installDir, "go1.22.0")
+
+	am := manager.AliasManager()
+	if err := am.CreateAlias("prod", "go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := am.CreateAliasGroup("dev", map[string]string{"stable": "1.22.0", "tools": "1.21.0"}); err != nil {
+		t.Fatalf("CreateAliasGroup error: %v", err)
+	}
+
+	aliases, err := am.GetAliasGroup("dev")
+	if err != nil {
+		t.Fatalf("GetAliasGroup error: %v", err)
+	}
+	if len(aliases) != 2 || aliases[0].Name != "stable" || aliases[1].Name != "tools" {
+		t.Fatalf("expected stable and tools in group, got %v", aliases)
+	}
+	if aliases[0].Version != "go1.22.0" {
+		t.Errorf("expected normalized version go1.22.0, got %s", aliases[0].Version)
+	}
+
+	// Groups survive a reload from disk
+	reloaded := NewAliasManager(manager.config)
+	if aliases, err := reloaded.GetAliasGroup("dev"); err != nil || len(aliases) != 2 {
+		t.Errorf("expected group to be persisted, got %v, %v", aliases, err)
+	}
+
+	tests := []struct {
+		name     string
+		group    string
+		bindings map[string]string
+	}{
+		{"existing group", "dev", map[string]string{"other": "1.21.0"}},
+		{"alias already exists", "ops", map[string]string{"prod": "1.22.0"}},
+		{"version not installed", "ops", map[string]string{"edge": "1.99.0"}},
+		{"no aliases", "ops", map[string]string{}},
+		{"invalid group name", "bad/name", map[string]string{"edge": "1.21.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := am.CreateAliasGroup(tt.group, tt.bindings); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if _, err := am.GetAliasGroup("ops"); err == nil {
+		t.Error("expected failed creations to leave no 'ops' group")
+	}
+}
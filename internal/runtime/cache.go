@@ -0,0 +1,154 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/security"
+)
+
+// ============================================================================
+// Download Cache
+// ============================================================================
+
+// archiveNameRegex matches the file names of Go release archives
+// (e.g. go1.21.0.linux-amd64.tar.gz), and of the checksums saved with them,
+// and captures the version
+var archiveNameRegex = regexp.MustCompile(`^(go[0-9]+(?:\.[0-9]+)*(?:(?:rc|beta)[0-9]+)?)\.[a-z0-9]+-[a-z0-9]+\.(?:tar\.gz|tar\.xz|tar\.zst|zip|msi)(?:\.sha256)?$`)
+
+// DownloadsPageFile is the name of the cached copy of the downloads page in
+// the download directory. It holds the version list and checksums used in
+// offline mode.
+const DownloadsPageFile = "downloads.html"
+
+// CachedArchive is a file in the download cache
+type CachedArchive struct {
+	Version  string    `json:"version,omitempty"` // Empty if the file is not a Go archive
+	File     string    `json:"file"`
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// CacheDir returns the download cache directory
+func (m *Manager) CacheDir() string {
+	return m.config.DownloadDir
+}
+
+// ListCache returns the files in the download cache, sorted by version with
+// unrecognized files last. A missing cache directory is an empty cache.
+//
+// Example:
+//
+//	archives, err := manager.ListCache()
+//	for _, archive := range archives {
+//	    fmt.Println(archive.Version, archive.Size)
+//	}
+func (m *Manager) ListCache() ([]CachedArchive, error) {
+	downloadDir := m.config.DownloadDir
+
+	entries, err := os.ReadDir(downloadDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []CachedArchive{}, nil
+		}
+		return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to read download directory")
+	}
+
+	archives := make([]CachedArchive, 0, len(entries))
+	for _, entry := range entries {
+		path := filepath.Join(downloadDir, entry.Name())
+		size, err := dirSize(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to measure %s", path)
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to stat %s", path)
+		}
+
+		archive := CachedArchive{File: entry.Name(), Path: path, Size: size, Modified: info.ModTime()}
+		if match := archiveNameRegex.FindStringSubmatch(entry.Name()); match != nil {
+			archive.Version = match[1]
+		}
+		archives = append(archives, archive)
+	}
+
+	sort.SliceStable(archives, func(i, j int) bool {
+		a, b := archives[i], archives[j]
+		if (a.Version == "") != (b.Version == "") {
+			return b.Version == ""
+		}
+		if a.Version != b.Version && a.Version != "" {
+			return CompareVersions(a.Version, b.Version) < 0
+		}
+		return a.File < b.File
+	})
+	return archives, nil
+}
+
+// CacheSize returns the total size of the download cache in bytes
+func (m *Manager) CacheSize() (int64, error) {
+	size, err := dirSize(m.config.DownloadDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to calculate download cache size")
+	}
+	return size, nil
+}
+
+// CleanCache removes the cached archives of one version and returns the
+// bytes freed. An empty version removes the whole cache, like Clean.
+//
+// Returns an error if no archive of the version is cached.
+//
+// Example:
+//
+//	freed, err := manager.CleanCache("1.21.0")
+func (m *Manager) CleanCache(version string) (int64, error) {
+	if version == "" {
+		return m.Clean()
+	}
+
+	// Validate version format
+	if err := ValidateVersion(version); err != nil {
+		return 0, errors.Wrapf(err, errors.ErrCodeInvalidVersion, "invalid version")
+	}
+
+	// Validate version for security (path traversal protection)
+	if err := security.ValidatePath(version); err != nil {
+		return 0, errors.Wrapf(err, errors.ErrCodeInvalidVersion, "invalid version")
+	}
+
+	// Normalize version
+	version = NormalizeVersion(version)
+
+	archives, err := m.ListCache()
+	if err != nil {
+		return 0, err
+	}
+
+	var freed int64
+	removed := 0
+	for _, archive := range archives {
+		if archive.Version != version {
+			continue
+		}
+		if err := os.RemoveAll(archive.Path); err != nil {
+			return freed, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to remove %s", archive.Path)
+		}
+		freed += archive.Size
+		removed++
+	}
+
+	if removed == 0 {
+		return 0, errors.Newf(errors.ErrCodeFileNotFound, "no cached archive for %s (use 'gopher cache list' to see the cache)", version)
+	}
+	return freed, nil
+}
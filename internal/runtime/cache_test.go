@@ -0,0 +1,122 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/env"
+	"github.com/molmedoz/gopher/internal/errors"
+)
+
+// newCacheTestManager returns a manager whose download cache holds the given
+// files, each with the given size
+func newCacheTestManager(t *testing.T, files map[string]int) (*Manager, string) {
+	t.Helper()
+	tmp := t.TempDir()
+	downloadDir := filepath.Join(tmp, "downloads")
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, size := range files {
+		if err := os.WriteFile(filepath.Join(downloadDir, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		InstallDir:  filepath.Join(tmp, "versions"),
+		DownloadDir: downloadDir,
+	}
+	return NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": "/usr/bin:/bin"})), downloadDir
+}
+
+func TestManager_ListCache(t *testing.T) {
+	m, downloadDir := newCacheTestManager(t, map[string]int{
+		"go1.22.0.linux-amd64.tar.gz": 300,
+		"go1.9.7.linux-amd64.tar.gz":  100,
+		"go1.21rc2.windows-amd64.zip": 200,
+		"notes.txt":                   10,
+	})
+
+	archives, err := m.ListCache()
+	if err != nil {
+		t.Fatalf("ListCache() error = %v", err)
+	}
+
+	want := []struct {
+		version string
+		size    int64
+	}{
+		{"go1.9.7", 100},
+		{"go1.21rc2", 200},
+		{"go1.22.0", 300},
+		{"", 10},
+	}
+	if len(archives) != len(want) {
+		t.Fatalf("ListCache() returned %d archives, want %d: %+v", len(archives), len(want), archives)
+	}
+	for i, w := range want {
+		if archives[i].Version != w.version || archives[i].Size != w.size {
+			t.Errorf("archive %d = %s (%d bytes), want %s (%d bytes)", i, archives[i].Version, archives[i].Size, w.version, w.size)
+		}
+		if filepath.Dir(archives[i].Path) != downloadDir {
+			t.Errorf("archive %d path %s is not in %s", i, archives[i].Path, downloadDir)
+		}
+	}
+
+	size, err := m.CacheSize()
+	if err != nil || size != 610 {
+		t.Errorf("CacheSize() = %d, %v; want 610", size, err)
+	}
+}
+
+func TestManager_ListCache_Missing(t *testing.T) {
+	m := NewManager(&config.Config{DownloadDir: filepath.Join(t.TempDir(), "missing")}, env.NewMockProvider(nil))
+
+	archives, err := m.ListCache()
+	if err != nil || len(archives) != 0 {
+		t.Errorf("ListCache() = %v, %v; want an empty cache", archives, err)
+	}
+	if size, err := m.CacheSize(); err != nil || size != 0 {
+		t.Errorf("CacheSize() = %d, %v; want 0", size, err)
+	}
+}
+
+func TestManager_CleanCache(t *testing.T) {
+	m, downloadDir := newCacheTestManager(t, map[string]int{
+		"go1.21.0.linux-amd64.tar.gz":  100,
+		"go1.21.0.darwin-arm64.tar.gz": 50,
+		"go1.22.0.linux-amd64.tar.gz":  300,
+	})
+
+	freed, err := m.CleanCache("1.21.0")
+	if err != nil {
+		t.Fatalf("CleanCache() error = %v", err)
+	}
+	if freed != 150 {
+		t.Errorf("CleanCache() freed %d bytes, want 150", freed)
+	}
+
+	entries, err := os.ReadDir(downloadDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "go1.22.0.linux-amd64.tar.gz" {
+		t.Errorf("expected only the go1.22.0 archive to remain, got %v", entries)
+	}
+
+	if _, err := m.CleanCache("1.21.0"); !errors.IsErrorCode(err, errors.ErrCodeFileNotFound) {
+		t.Errorf("CleanCache() of an uncached version error = %v, want FILE_NOT_FOUND", err)
+	}
+	if _, err := m.CleanCache("../etc"); err == nil {
+		t.Error("CleanCache() should reject an invalid version")
+	}
+
+	// An empty version cleans everything
+	freed, err = m.CleanCache("")
+	if err != nil || freed != 300 {
+		t.Errorf("CleanCache(\"\") = %d, %v; want 300", freed, err)
+	}
+}
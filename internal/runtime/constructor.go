@@ -33,7 +33,7 @@ import (
 //
 //	cfg := config.Load("/path/to/config.json")
 //	manager := NewManager(cfg, envProvider)
-//	err := manager.Install("1.21.0")
+//	_, err := manager.Install(context.Background(), "1.21.0")
 func NewManager(cfg *config.Config, envProvider env.Provider) *Manager {
 	manager := &Manager{
 		config:       cfg,
@@ -43,6 +43,9 @@ func NewManager(cfg *config.Config, envProvider env.Provider) *Manager {
 		envProvider:  envProvider,
 	}
 
+	// Keep a copy of the downloads page for offline use
+	manager.downloader.SetPageCache(filepath.Join(cfg.DownloadDir, DownloadsPageFile))
+
 	// Create alias manager with manager reference
 	manager.aliasManager = NewAliasManagerWithManager(cfg, manager)
 
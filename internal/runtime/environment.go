@@ -5,8 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/molmedoz/gopher/internal/config"
 	"github.com/molmedoz/gopher/internal/security"
 )
 
@@ -26,12 +30,12 @@ func (m *Manager) setupEnvironment(version string) error {
 	}
 
 	// Display instructions to user
-	fmt.Printf("✓ Environment variables configured for Go %s\n", version)
-	fmt.Printf("  To activate this environment, run:\n")
-	fmt.Printf("  source %s\n", scriptPath)
-	fmt.Printf("  Or add the following to your shell profile:\n")
+	fmt.Fprintf(os.Stderr, "✓ Environment variables configured for Go %s\n", version)
+	fmt.Fprintf(os.Stderr, "  To activate this environment, run:\n")
+	fmt.Fprintf(os.Stderr, "  source %s\n", scriptPath)
+	fmt.Fprintf(os.Stderr, "  Or add the following to your shell profile:\n")
 	for key, value := range envVars {
-		fmt.Printf("  export %s=%s\n", key, value)
+		fmt.Fprintf(os.Stderr, "  export %s=%s\n", key, value)
 	}
 
 	return nil
@@ -50,26 +54,40 @@ func (m *Manager) setupSystemEnvironment() error {
 		return fmt.Errorf("failed to get system Go info: %w", err)
 	}
 
-	// Create environment variables for system Go
-	envVars := make(map[string]string)
-	envVars["GOROOT"] = systemInfo.GOROOT
-	envVars["GOPATH"] = systemInfo.GOPATH
-	envVars["PATH"] = filepath.Join(systemInfo.GOROOT, "bin") + string(os.PathListSeparator) + m.envProvider.Getenv("PATH")
+	return m.setupExternalEnvironment("system", systemInfo)
+}
+
+// setupExternalEnvironment sets up environment variables for a Go that
+// Gopher does not manage, such as the system or Homebrew Go
+func (m *Manager) setupExternalEnvironment(name string, info *SystemGoInfo) error {
+	if !m.config.SetEnvironment {
+		return nil
+	}
 
 	// Create environment setup script
-	scriptPath, err := m.createEnvironmentScript("system", envVars)
+	scriptPath, err := m.createEnvironmentScript(name, m.externalEnvironment(info))
 	if err != nil {
 		return fmt.Errorf("failed to create environment script: %w", err)
 	}
 
 	// Display instructions to user
-	fmt.Printf("✓ Environment variables configured for system Go\n")
-	fmt.Printf("  To activate this environment, run:\n")
-	fmt.Printf("  source %s\n", scriptPath)
+	fmt.Fprintf(os.Stderr, "✓ Environment variables configured for %s Go\n", name)
+	fmt.Fprintf(os.Stderr, "  To activate this environment, run:\n")
+	fmt.Fprintf(os.Stderr, "  source %s\n", scriptPath)
 
 	return nil
 }
 
+// externalEnvironment returns the environment variables for a Go that
+// Gopher does not manage
+func (m *Manager) externalEnvironment(info *SystemGoInfo) map[string]string {
+	return map[string]string{
+		"GOROOT": info.GOROOT,
+		"GOPATH": info.GOPATH,
+		"PATH":   filepath.Join(info.GOROOT, "bin") + string(os.PathListSeparator) + m.envProvider.Getenv("PATH"),
+	}
+}
+
 // createEnvironmentScript creates a shell script to set up environment variables
 func (m *Manager) createEnvironmentScript(version string, envVars map[string]string) (string, error) {
 	// Create script directory
@@ -102,32 +120,43 @@ func (m *Manager) createEnvironmentScript(version string, envVars map[string]str
 	return scriptPath, nil
 }
 
-// saveActiveVersion saves the currently active version to a state file
-func (m *Manager) saveActiveVersion(version string) error {
+// stateDir returns the validated state directory (e.g. ~/.gopher/state),
+// which sits next to the install directory. It is not created.
+func (m *Manager) stateDir() (string, error) {
 	// Get safe root directory (parent of InstallDir, e.g., ~/.gopher or ~/gopher)
 	// This avoids path traversal via ".."
 	installDirAbs, err := filepath.Abs(m.config.InstallDir)
 	if err != nil {
-		return fmt.Errorf("failed to resolve install directory: %w", err)
+		return "", fmt.Errorf("failed to resolve install directory: %w", err)
 	}
 	safeRoot := filepath.Dir(installDirAbs) // Parent of versions directory (e.g., ~/.gopher)
 
 	// Validate install directory is within expected structure
 	if err := security.ValidatePath(installDirAbs); err != nil {
-		return fmt.Errorf("invalid install directory: %w", err)
+		return "", fmt.Errorf("invalid install directory: %w", err)
 	}
 
 	// State directory is within safe root (e.g., ~/.gopher/state)
 	stateDir := filepath.Join(safeRoot, "state")
 	stateDirAbs, err := filepath.Abs(stateDir)
 	if err != nil {
-		return fmt.Errorf("failed to resolve state directory: %w", err)
+		return "", fmt.Errorf("failed to resolve state directory: %w", err)
 	}
 
 	// Validate state directory is within safe root to prevent path traversal
 	safeStateDir, err := security.ValidatePathWithinRoot(stateDirAbs, safeRoot)
 	if err != nil {
-		return fmt.Errorf("invalid state directory path: %w", err)
+		return "", fmt.Errorf("invalid state directory path: %w", err)
+	}
+	return safeStateDir, nil
+}
+
+// saveActiveVersion saves the currently active version to a state file and
+// records the switch in the history when the version changed
+func (m *Manager) saveActiveVersion(version string) error {
+	safeStateDir, err := m.stateDir()
+	if err != nil {
+		return err
 	}
 
 	// Use 0750 for state directory - private user data
@@ -135,73 +164,46 @@ func (m *Manager) saveActiveVersion(version string) error {
 		return fmt.Errorf("failed to create state directory: %w", err)
 	}
 
-	stateFile := filepath.Join(safeStateDir, "active-version")
-	// Validate state file is within state directory
-	safeStateFile, err := security.ValidatePathWithinRoot(stateFile, safeStateDir)
+	safeStateFile, err := m.stateFile()
 	if err != nil {
-		return fmt.Errorf("invalid state file path: %w", err)
-	}
-
-	content := fmt.Sprintf("active_version=%s\n", version)
-
-	// #nosec G306 -- 0644 acceptable for state file (non-sensitive metadata)
-	// #nosec G304 -- path validated and scoped to safeStateDir
-	if err := os.WriteFile(safeStateFile, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write state file: %w", err)
+		return err
 	}
 
-	return nil
-}
-
-// getActiveVersionFromState retrieves the active version from the state file
-func (m *Manager) getActiveVersionFromState() (string, error) {
-	// Get safe root directory (parent of InstallDir, e.g., ~/.gopher or ~/gopher)
-	installDirAbs, err := filepath.Abs(m.config.InstallDir)
+	// A missing or unreadable state file just means there is no previous version
+	state, err := ReadStateFile(safeStateFile)
 	if err != nil {
-		return "", fmt.Errorf("failed to resolve install directory: %w", err)
+		state = &State{}
 	}
-	safeRoot := filepath.Dir(installDirAbs) // Parent of versions directory (e.g., ~/.gopher)
-
-	// Validate install directory is within expected structure
-	if err := security.ValidatePath(installDirAbs); err != nil {
-		return "", fmt.Errorf("invalid install directory: %w", err)
+	previous := state.ActiveVersion
+	if previous != version {
+		state.PreviousVersion = previous
 	}
+	state.ActiveVersion = version
+	state.UpdatedAt = time.Now()
 
-	// State directory is within safe root (e.g., ~/.gopher/state)
-	stateDir := filepath.Join(safeRoot, "state")
-	stateDirAbs, err := filepath.Abs(stateDir)
-	if err != nil {
-		return "", fmt.Errorf("failed to resolve state directory: %w", err)
+	if err := WriteStateFile(safeStateFile, state); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
 	}
 
-	// Validate state directory is within safe root to prevent path traversal
-	safeStateDir, err := security.ValidatePathWithinRoot(stateDirAbs, safeRoot)
-	if err != nil {
-		return "", fmt.Errorf("invalid state directory path: %w", err)
+	if previous != version {
+		if err := m.recordSwitch(previous, version); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record version switch: %v\n", err)
+		}
 	}
 
-	stateFile := filepath.Join(safeStateDir, "active-version")
-	// Validate state file is within state directory
-	safeStateFile, err := security.ValidatePathWithinRoot(stateFile, safeStateDir)
-	if err != nil {
-		return "", fmt.Errorf("invalid state file path: %w", err)
-	}
+	return nil
+}
 
-	// #nosec G304 -- path validated and scoped to safeStateDir
-	content, err := os.ReadFile(safeStateFile)
+// getActiveVersionFromState retrieves the active version from the state file
+func (m *Manager) getActiveVersionFromState() (string, error) {
+	state, err := m.ReadState()
 	if err != nil {
 		return "", err
 	}
-
-	// Parse the content to extract the version
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "active_version=") {
-			return strings.TrimPrefix(line, "active_version="), nil
-		}
+	if state.ActiveVersion == "" {
+		return "", fmt.Errorf("active version not found in state file")
 	}
-
-	return "", fmt.Errorf("active version not found in state file")
+	return state.ActiveVersion, nil
 }
 
 // setupShellIntegration sets up shell integration for persistent Go version switching
@@ -229,12 +231,34 @@ func (m *Manager) setupShellIntegration() error {
 		return fmt.Errorf("failed to add to shell profile: %w", err)
 	}
 
-	fmt.Printf("✓ Shell integration configured for %s\n", shell)
-	fmt.Printf("  Restart your terminal or run: source %s\n", profilePath)
+	fmt.Fprintf(os.Stderr, "✓ Shell integration configured for %s\n", shell)
+	fmt.Fprintf(os.Stderr, "  Restart your terminal or run: source %s\n", profilePath)
 
 	return nil
 }
 
+// GoSettingsScript returns the init script lines that export the GOFLAGS and
+// GOTOOLCHAIN configured in cfg, in name order
+func GoSettingsScript(cfg *config.Config) string {
+	settings := cfg.GoSettings()
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var script strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&script, "    export %s=%s\n", key, ShellQuote(settings[key]))
+	}
+	return script.String()
+}
+
+// ShellQuote quotes s as a single POSIX shell word
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // createGopherInitScript creates the gopher initialization script
 func (m *Manager) createGopherInitScript() (string, error) {
 	// Create scripts directory
@@ -247,16 +271,18 @@ func (m *Manager) createGopherInitScript() (string, error) {
 	// Create gopher init script
 	initScriptPath := filepath.Join(scriptsDir, "gopher-init.sh")
 
-	// Generate script content
+	// Generate script content. Paths come from the config, as the gopher
+	// directories depend on the platform and the XDG variables.
+	dataDir := filepath.Dir(m.config.InstallDir)
 	scriptContent := `#!/bin/bash
 # Gopher shell integration
 # This script is automatically generated by gopher
 
 # Function to get the active Go version
 gopher_get_active_version() {
-    local state_file="$HOME/.gopher/state/active-version"
+    local state_file=` + ShellQuote(filepath.Join(dataDir, "state", StateFileName)) + `
     if [ -f "$state_file" ]; then
-        local active_version=$(grep "active_version=" "$state_file" | cut -d'=' -f2)
+        local active_version=$(` + StateShellReadCommand(`"$state_file"`) + `)
         if [ -n "$active_version" ]; then
             echo "$active_version"
             return 0
@@ -265,25 +291,42 @@ gopher_get_active_version() {
     return 1
 }
 
+# Function to get the GOBIN for a version from the gobin_mode setting
+gopher_gobin() {
+    local config_file=` + ShellQuote(config.GetConfigPath()) + `
+    local mode=""
+    local custom=""
+    if [ -f "$config_file" ]; then
+        mode=$(grep -o '"gobin_mode": *"[^"]*"' "$config_file" | cut -d'"' -f4)
+        custom=$(grep -o '"custom_gobin": *"[^"]*"' "$config_file" | cut -d'"' -f4)
+    fi
+    case "$mode" in
+        version-specific) echo ` + ShellQuote(filepath.Join(dataDir, "gobin")) + `"/$1" ;;
+        custom) echo "${custom:-$GOPATH/bin}" ;;
+        *) echo "$GOPATH/bin" ;;
+    esac
+}
+
 # Function to setup Go environment
 gopher_setup_environment() {
     local version="$1"
-    
-    if [ "$version" = "system" ]; then
-        # Use system Go
+` + GoSettingsScript(m.config) + `    
+    if [ "$version" = "system" ] || [ "$version" = "homebrew" ]; then
+        # Use system or Homebrew Go (found through the gopher symlink)
         unset GOROOT
         export GOPATH="$HOME/go"
+        export GOBIN="$(gopher_gobin "$version")"
         return 0
     fi
     
     # Set up gopher-managed Go version
-    local gopher_dir="$HOME/.gopher"
-    local version_dir="$gopher_dir/versions/$version"
+    local version_dir=` + ShellQuote(m.config.InstallDir) + `"/$version"
     
     if [ -d "$version_dir" ]; then
         export GOROOT="$version_dir"
         export GOPATH="$HOME/go"
-        export PATH="$version_dir/bin:$PATH"
+        export GOBIN="$(gopher_gobin "$version")"
+        export PATH="$version_dir/bin:$GOBIN:$PATH"
     fi
 }
 
@@ -426,23 +469,77 @@ func (m *Manager) createSymlink(binaryPath string) error {
 	}
 
 	symlinkDir := filepath.Dir(symlinkPath)
-	fmt.Printf("✓ Created symlink in %s\n", symlinkPath)
-	fmt.Printf("  Add %s to your PATH to use this Go version\n", symlinkDir)
+	m.linkExtraBinaries(filepath.Dir(binaryPath), symlinkDir)
+	fmt.Fprintf(os.Stderr, "✓ Created symlink in %s\n", symlinkPath)
+	fmt.Fprintf(os.Stderr, "  Add %s to your PATH to use this Go version\n", symlinkDir)
 
 	// Check if the directory is already in PATH
 	if !m.isDirectoryInPath(symlinkDir) {
-		fmt.Printf("  ⚠️  Directory not in PATH - you may need to restart your terminal\n")
-		fmt.Printf("  Or run: export PATH=\"%s:$PATH\"\n", symlinkDir)
+		fmt.Fprintf(os.Stderr, "  ⚠️  Directory not in PATH - you may need to restart your terminal\n")
+		fmt.Fprintf(os.Stderr, "  Or run: export PATH=\"%s:$PATH\"\n", symlinkDir)
 		if runtime.GOOS == "windows" {
-			fmt.Printf("  Windows: Add %s to your PATH environment variable\n", symlinkDir)
+			fmt.Fprintf(os.Stderr, "  Windows: Add %s to your PATH environment variable\n", symlinkDir)
 		}
 	} else {
-		fmt.Printf("  ✓ Directory is in PATH\n")
+		fmt.Fprintf(os.Stderr, "  ✓ Directory is in PATH\n")
 	}
 
 	return nil
 }
 
+// linkedBinaries returns the names of the binaries linked into the symlink
+// directory: go, followed by the configured linked_binaries
+func (m *Manager) linkedBinaries() []string {
+	names := []string{"go"}
+	if m.config != nil {
+		for _, name := range m.config.LinkedBinaries {
+			name = strings.TrimSpace(name)
+			if name == "" || name != filepath.Base(name) || slices.Contains(names, name) {
+				continue
+			}
+			names = append(names, name)
+		}
+	}
+	if runtime.GOOS == "windows" {
+		for i, name := range names {
+			names[i] = name + ".exe"
+		}
+	}
+	return names
+}
+
+// linkExtraBinaries links the linked binaries other than go from binDir into
+// symlinkDir. A binary the version does not ship has its stale link from a
+// previous version removed, so it does not run a different version than go.
+// Failures are reported as warnings, since the go symlink is already in place.
+func (m *Manager) linkExtraBinaries(binDir, symlinkDir string) {
+	for _, name := range m.linkedBinaries()[1:] {
+		binaryPath := filepath.Join(binDir, name)
+		symlinkPath := filepath.Join(symlinkDir, name)
+
+		if _, err := os.Stat(binaryPath); err != nil {
+			if target, err := os.Readlink(symlinkPath); err == nil && m.extractVersionFromPath(target) != "" {
+				if err := os.Remove(symlinkPath); err != nil {
+					fmt.Fprintf(os.Stderr, "  Warning: failed to remove stale symlink %s: %v\n", symlinkPath, err)
+				}
+			}
+			continue
+		}
+
+		// Never replace a file gopher did not create
+		if info, err := os.Lstat(symlinkPath); err == nil && info.Mode()&os.ModeSymlink == 0 {
+			fmt.Fprintf(os.Stderr, "  Warning: %s exists and is not a symlink, not linking %s\n", symlinkPath, name)
+			continue
+		}
+
+		if err := m.tryCreateSymlink(binaryPath, symlinkPath); err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: failed to link %s: %v\n", name, err)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "✓ Created symlink in %s\n", symlinkPath)
+	}
+}
+
 // tryCreateSymlink attempts to create a symlink
 func (m *Manager) tryCreateSymlink(binaryPath, symlinkPath string) error {
 	// Remove existing symlink if it exists
@@ -536,26 +633,24 @@ func (m *Manager) addSymlinkToPath(binaryPath string) error {
 	return os.Setenv("PATH", newPath)
 }
 
-// removeGopherSymlinks removes all gopher-created symlinks
+// removeGopherSymlinks removes all gopher-created symlinks from the symlink
+// directories and PATH
 func (m *Manager) removeGopherSymlinks() error {
-	pathEnv := m.envProvider.Getenv("PATH")
-	paths := strings.Split(pathEnv, string(os.PathListSeparator))
 	removedCount := 0
 
-	for _, path := range paths {
-		goPath := filepath.Join(path, "go")
-		if runtime.GOOS == "windows" {
-			goPath = filepath.Join(path, "go.exe")
-		}
-
-		if _, err := os.Lstat(goPath); err == nil {
-			if target, err := os.Readlink(goPath); err == nil {
-				if m.extractVersionFromPath(target) != "" {
-					if err := os.Remove(goPath); err == nil {
-						removedCount++
-						fmt.Printf("  Removed symlink: %s\n", goPath)
-					} else {
-						fmt.Printf("  Warning: failed to remove symlink %s: %v\n", goPath, err)
+	for _, path := range m.symlinkDirs() {
+		for _, name := range m.linkedBinaries() {
+			linkPath := filepath.Join(path, name)
+
+			if _, err := os.Lstat(linkPath); err == nil {
+				if target, err := os.Readlink(linkPath); err == nil {
+					if m.extractVersionFromPath(target) != "" {
+						if err := os.Remove(linkPath); err == nil {
+							removedCount++
+							fmt.Fprintf(os.Stderr, "  Removed symlink: %s\n", linkPath)
+						} else {
+							fmt.Fprintf(os.Stderr, "  Warning: failed to remove symlink %s: %v\n", linkPath, err)
+						}
 					}
 				}
 			}
@@ -563,39 +658,91 @@ func (m *Manager) removeGopherSymlinks() error {
 	}
 
 	if removedCount > 0 {
-		fmt.Printf("✓ Removed %d gopher symlinks\n", removedCount)
+		fmt.Fprintf(os.Stderr, "✓ Removed %d gopher symlinks\n", removedCount)
 	}
 
 	return nil
 }
 
-// getGopherSymlinkPath returns the standard gopher symlink path
+// getGopherSymlinkPath returns the gopher symlink path, creating its
+// directory if needed and checking that gopher can write to it
 func (m *Manager) getGopherSymlinkPath() (string, error) {
+	symlinkPath, err := m.gopherSymlinkPath()
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkWritableDir(filepath.Dir(symlinkPath)); err != nil {
+		return "", err
+	}
+
+	return symlinkPath, nil
+}
+
+// gopherSymlinkPath returns the gopher symlink path without touching the
+// filesystem
+func (m *Manager) gopherSymlinkPath() (string, error) {
+	dir, err := m.SymlinkDir()
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(dir, "go.exe"), nil
+	}
+	return filepath.Join(dir, "go"), nil
+}
+
+// SymlinkDir returns the directory that holds the go symlink: symlink_dir
+// (or --symlink-dir) when set, otherwise the platform's standard directory
+func (m *Manager) SymlinkDir() (string, error) {
+	if m.config != nil && m.config.SymlinkDir != "" {
+		return m.config.SymlinkDir, nil
+	}
+	return defaultSymlinkDir()
+}
+
+// defaultSymlinkDir returns the platform's standard symlink directory
+func defaultSymlinkDir() (string, error) {
 	userHome, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get user home directory: %w", err)
 	}
 
-	var symlinkPath string
-	switch runtime.GOOS {
-	case "windows":
-		localBinDir := filepath.Join(userHome, "AppData", "Local", "bin")
-		// #nosec G301 -- 0755 required for executable bin directory
-		if err := os.MkdirAll(localBinDir, 0755); err != nil {
-			return "", fmt.Errorf("failed to create local bin directory: %w", err)
-		}
-		symlinkPath = filepath.Join(localBinDir, "go.exe")
-	default:
-		// Use ~/.local/bin as the standard gopher symlink location
-		localBinDir := filepath.Join(userHome, ".local", "bin")
-		// #nosec G301 -- 0755 required for executable bin directory
-		if err := os.MkdirAll(localBinDir, 0755); err != nil {
-			return "", fmt.Errorf("failed to create local bin directory: %w", err)
-		}
-		symlinkPath = filepath.Join(localBinDir, "go")
+	if runtime.GOOS == "windows" {
+		return filepath.Join(userHome, "AppData", "Local", "bin"), nil
 	}
+	// Use ~/.local/bin as the standard gopher symlink location
+	return filepath.Join(userHome, ".local", "bin"), nil
+}
 
-	return symlinkPath, nil
+// CheckSymlinkDir creates the symlink directory if needed and checks that
+// gopher can write to it. It also reports whether the directory is in PATH,
+// since the symlink has no effect otherwise.
+func (m *Manager) CheckSymlinkDir() (inPath bool, err error) {
+	dir, err := m.SymlinkDir()
+	if err != nil {
+		return false, err
+	}
+	if err := checkWritableDir(dir); err != nil {
+		return false, err
+	}
+	return m.isDirectoryInPath(dir), nil
+}
+
+// checkWritableDir creates dir if needed and checks that files can be
+// created in it
+func checkWritableDir(dir string) error {
+	// #nosec G301 -- 0755 required for executable bin directory
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create symlink directory %s: %w", dir, err)
+	}
+	probe, err := os.CreateTemp(dir, ".gopher-write-check-*")
+	if err != nil {
+		return fmt.Errorf("symlink directory %s is not writable (set symlink_dir or --symlink-dir to a directory you can write to): %w", dir, err)
+	}
+	_ = probe.Close()
+	_ = os.Remove(probe.Name())
+	return nil
 }
 
 // checkWindowsPathOrder checks if Gopher's bin directory is before system Go in PATH.
@@ -680,7 +827,7 @@ After fixing, verify with: where go
 
 	// Gopher comes first or system Go not found - all good!
 	if gopherIndex != -1 && (systemIndex == -1 || gopherIndex < systemIndex) {
-		fmt.Printf("✓ PATH order correct (Gopher before system Go)\n")
+		fmt.Fprintf(os.Stderr, "✓ PATH order correct (Gopher before system Go)\n")
 	}
 
 	return nil
@@ -702,6 +849,12 @@ func (m *Manager) checkGOPATHInPath(version string) {
 			return
 		}
 		gopath = systemInfo.GOPATH
+	} else if version == HomebrewVersion {
+		homebrewInfo, err := NewSystemDetector().GetHomebrewGoInfo()
+		if err != nil {
+			return
+		}
+		gopath = homebrewInfo.GOPATH
 	} else {
 		// Get GOPATH for this version
 		gopath = m.config.GetGOPATHWithEnv(version, m.envProvider)
@@ -716,23 +869,23 @@ func (m *Manager) checkGOPATHInPath(version string) {
 	// Get current PATH
 	currentPath := m.envProvider.Getenv("PATH")
 	if currentPath == "" {
-		fmt.Printf("\n⚠️  WARNING: PATH environment variable is not set!\n")
-		fmt.Printf("  Installed Go packages/tools will not be accessible.\n")
-		fmt.Printf("  GOPATH/bin location: %s\n", gopathBin)
-		fmt.Printf("  Please add GOPATH/bin to your PATH:\n")
+		fmt.Fprintf(os.Stderr, "\n⚠️  WARNING: PATH environment variable is not set!\n")
+		fmt.Fprintf(os.Stderr, "  Installed Go packages/tools will not be accessible.\n")
+		fmt.Fprintf(os.Stderr, "  GOPATH/bin location: %s\n", gopathBin)
+		fmt.Fprintf(os.Stderr, "  Please add GOPATH/bin to your PATH:\n")
 		if runtime.GOOS == "windows" {
-			fmt.Printf("    set PATH=%s;%%PATH%%\n", gopathBin)
-			fmt.Printf("  Or permanently via PowerShell:\n")
-			fmt.Printf("    [Environment]::SetEnvironmentVariable(\"PATH\", \"%s;\" + [Environment]::GetEnvironmentVariable(\"PATH\", \"User\"), \"User\")\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "    set PATH=%s;%%PATH%%\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "  Or permanently via PowerShell:\n")
+			fmt.Fprintf(os.Stderr, "    [Environment]::SetEnvironmentVariable(\"PATH\", \"%s;\" + [Environment]::GetEnvironmentVariable(\"PATH\", \"User\"), \"User\")\n", gopathBin)
 		} else if runtime.GOOS == "darwin" {
-			fmt.Printf("    export PATH=\"%s:$PATH\"\n", gopathBin)
-			fmt.Printf("  Or add to your shell profile (~/.zshrc for zsh, ~/.bash_profile for bash):\n")
-			fmt.Printf("    echo 'export PATH=\"%s:$PATH\"' >> ~/.zshrc\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "    export PATH=\"%s:$PATH\"\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "  Or add to your shell profile (~/.zshrc for zsh, ~/.bash_profile for bash):\n")
+			fmt.Fprintf(os.Stderr, "    echo 'export PATH=\"%s:$PATH\"' >> ~/.zshrc\n", gopathBin)
 		} else {
 			// Linux and other Unix-like systems
-			fmt.Printf("    export PATH=\"%s:$PATH\"\n", gopathBin)
-			fmt.Printf("  Or add to your shell profile (~/.bashrc for bash, ~/.zshrc for zsh):\n")
-			fmt.Printf("    echo 'export PATH=\"%s:$PATH\"' >> ~/.bashrc\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "    export PATH=\"%s:$PATH\"\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "  Or add to your shell profile (~/.bashrc for bash, ~/.zshrc for zsh):\n")
+			fmt.Fprintf(os.Stderr, "    echo 'export PATH=\"%s:$PATH\"' >> ~/.bashrc\n", gopathBin)
 		}
 		return
 	}
@@ -748,45 +901,102 @@ func (m *Manager) checkGOPATHInPath(version string) {
 	}
 
 	if !gopathBinInPath {
-		fmt.Printf("\n⚠️  WARNING: GOPATH/bin is not in your PATH!\n")
-		fmt.Printf("  Installed Go packages/tools will not work.\n")
-		fmt.Printf("  GOPATH/bin location: %s\n", gopathBin)
-		fmt.Printf("\n  To fix this, add GOPATH/bin to your PATH:\n")
+		fmt.Fprintf(os.Stderr, "\n⚠️  WARNING: GOPATH/bin is not in your PATH!\n")
+		fmt.Fprintf(os.Stderr, "  Installed Go packages/tools will not work.\n")
+		fmt.Fprintf(os.Stderr, "  GOPATH/bin location: %s\n", gopathBin)
+		fmt.Fprintf(os.Stderr, "\n  To fix this, add GOPATH/bin to your PATH:\n")
 
 		switch runtime.GOOS {
 		case "windows":
 			// Windows: Use semicolon separator and Windows-specific commands
-			fmt.Printf("  For current session (Command Prompt):\n")
-			fmt.Printf("    set PATH=%s;%%PATH%%\n", gopathBin)
-			fmt.Printf("\n  For current session (PowerShell):\n")
-			fmt.Printf("    $env:PATH = \"%s;\" + $env:PATH\n", gopathBin)
-			fmt.Printf("\n  Permanently via PowerShell:\n")
-			fmt.Printf("    [Environment]::SetEnvironmentVariable(\"PATH\", \"%s;\" + [Environment]::GetEnvironmentVariable(\"PATH\", \"User\"), \"User\")\n", gopathBin)
-			fmt.Printf("\n  After adding, restart your terminal or run:\n")
-			fmt.Printf("    refreshenv  (if using Chocolatey)\n")
-			fmt.Printf("    Or restart PowerShell/Command Prompt\n")
+			fmt.Fprintf(os.Stderr, "  For current session (Command Prompt):\n")
+			fmt.Fprintf(os.Stderr, "    set PATH=%s;%%PATH%%\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "\n  For current session (PowerShell):\n")
+			fmt.Fprintf(os.Stderr, "    $env:PATH = \"%s;\" + $env:PATH\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "\n  Permanently via PowerShell:\n")
+			fmt.Fprintf(os.Stderr, "    [Environment]::SetEnvironmentVariable(\"PATH\", \"%s;\" + [Environment]::GetEnvironmentVariable(\"PATH\", \"User\"), \"User\")\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "\n  After adding, restart your terminal or run:\n")
+			fmt.Fprintf(os.Stderr, "    refreshenv  (if using Chocolatey)\n")
+			fmt.Fprintf(os.Stderr, "    Or restart PowerShell/Command Prompt\n")
 		case "darwin":
 			// macOS: Default to zsh (macOS default since Catalina)
-			fmt.Printf("  For current session:\n")
-			fmt.Printf("    export PATH=\"%s:$PATH\"\n", gopathBin)
-			fmt.Printf("\n  Permanently (add to ~/.zshrc for zsh, or ~/.bash_profile for bash):\n")
-			fmt.Printf("    echo 'export PATH=\"%s:$PATH\"' >> ~/.zshrc\n", gopathBin)
-			fmt.Printf("  Or for bash:\n")
-			fmt.Printf("    echo 'export PATH=\"%s:$PATH\"' >> ~/.bash_profile\n", gopathBin)
-			fmt.Printf("\n  After adding, restart your terminal or run:\n")
-			fmt.Printf("    source ~/.zshrc  (for zsh)\n")
-			fmt.Printf("    Or: source ~/.bash_profile  (for bash)\n")
+			fmt.Fprintf(os.Stderr, "  For current session:\n")
+			fmt.Fprintf(os.Stderr, "    export PATH=\"%s:$PATH\"\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "\n  Permanently (add to ~/.zshrc for zsh, or ~/.bash_profile for bash):\n")
+			fmt.Fprintf(os.Stderr, "    echo 'export PATH=\"%s:$PATH\"' >> ~/.zshrc\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "  Or for bash:\n")
+			fmt.Fprintf(os.Stderr, "    echo 'export PATH=\"%s:$PATH\"' >> ~/.bash_profile\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "\n  After adding, restart your terminal or run:\n")
+			fmt.Fprintf(os.Stderr, "    source ~/.zshrc  (for zsh)\n")
+			fmt.Fprintf(os.Stderr, "    Or: source ~/.bash_profile  (for bash)\n")
 		default:
 			// Linux and other Unix-like systems
-			fmt.Printf("  For current session:\n")
-			fmt.Printf("    export PATH=\"%s:$PATH\"\n", gopathBin)
-			fmt.Printf("\n  Permanently (add to ~/.bashrc for bash, or ~/.zshrc for zsh):\n")
-			fmt.Printf("    echo 'export PATH=\"%s:$PATH\"' >> ~/.bashrc\n", gopathBin)
-			fmt.Printf("  Or for zsh:\n")
-			fmt.Printf("    echo 'export PATH=\"%s:$PATH\"' >> ~/.zshrc\n", gopathBin)
-			fmt.Printf("\n  After adding, restart your terminal or run:\n")
-			fmt.Printf("    source ~/.bashrc  (for bash)\n")
-			fmt.Printf("    Or: source ~/.zshrc  (for zsh)\n")
+			fmt.Fprintf(os.Stderr, "  For current session:\n")
+			fmt.Fprintf(os.Stderr, "    export PATH=\"%s:$PATH\"\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "\n  Permanently (add to ~/.bashrc for bash, or ~/.zshrc for zsh):\n")
+			fmt.Fprintf(os.Stderr, "    echo 'export PATH=\"%s:$PATH\"' >> ~/.bashrc\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "  Or for zsh:\n")
+			fmt.Fprintf(os.Stderr, "    echo 'export PATH=\"%s:$PATH\"' >> ~/.zshrc\n", gopathBin)
+			fmt.Fprintf(os.Stderr, "\n  After adding, restart your terminal or run:\n")
+			fmt.Fprintf(os.Stderr, "    source ~/.bashrc  (for bash)\n")
+			fmt.Fprintf(os.Stderr, "    Or: source ~/.zshrc  (for zsh)\n")
 		}
 	}
 }
+
+// EffectiveGOTOOLCHAIN returns the GOTOOLCHAIN value go commands will see:
+// the process environment first, then the value gopher's environment setup
+// exports, and otherwise Go's own default of "auto".
+func (m *Manager) EffectiveGOTOOLCHAIN() string {
+	if value := m.envProvider.Getenv("GOTOOLCHAIN"); value != "" {
+		return value
+	}
+	if m.config.SetEnvironment && m.config.GOTOOLCHAIN != "" {
+		return m.config.GOTOOLCHAIN
+	}
+	return "auto"
+}
+
+// ToolchainSwitchingAllowed reports whether a GOTOOLCHAIN value lets the go
+// command run a toolchain other than the one on PATH. Only "local" and
+// "path" pin it to the version gopher selected.
+func ToolchainSwitchingAllowed(value string) bool {
+	return value != "local" && value != "path"
+}
+
+// checkToolchainSetting warns when GOTOOLCHAIN allows Go to download and run
+// a different toolchain than the active version.
+func (m *Manager) checkToolchainSetting() {
+	value := m.EffectiveGOTOOLCHAIN()
+	if !ToolchainSwitchingAllowed(value) {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "\n⚠️  WARNING: GOTOOLCHAIN=%s lets Go run a different toolchain than the active version.\n", value)
+	fmt.Fprintf(os.Stderr, "  A go.mod 'go' or 'toolchain' line newer than this version will make Go download and use it.\n")
+	fmt.Fprintf(os.Stderr, "\n  To always use the version selected with gopher:\n")
+	fmt.Fprintf(os.Stderr, "    %s\n", m.ToolchainFix())
+}
+
+// ToolchainFix returns how to keep go commands on the active version, based
+// on where the effective GOTOOLCHAIN comes from: a variable in the
+// environment overrides gopher's setting, and a configured gotoolchain only
+// applies with set_environment.
+func (m *Manager) ToolchainFix() string {
+	setCommand, unsetCommand := "export GOTOOLCHAIN=local", "unset GOTOOLCHAIN"
+	if runtime.GOOS == "windows" {
+		setCommand, unsetCommand = `$env:GOTOOLCHAIN = "local"`, "Remove-Item Env:GOTOOLCHAIN"
+	}
+	configured := !ToolchainSwitchingAllowed(m.config.GOTOOLCHAIN)
+
+	if value := m.envProvider.Getenv("GOTOOLCHAIN"); value != "" {
+		if configured && m.config.SetEnvironment {
+			return fmt.Sprintf("GOTOOLCHAIN=%s in your environment overrides gotoolchain=%s from gopher's config; remove it from your shell profile, or run: %s", value, m.config.GOTOOLCHAIN, unsetCommand)
+		}
+		return fmt.Sprintf("GOTOOLCHAIN=%s is set in your environment; remove it from your shell profile, or run: %s", value, setCommand)
+	}
+	if configured {
+		return fmt.Sprintf("gotoolchain=%s is configured but only exported with set_environment; run: gopher env set set_environment=true", m.config.GOTOOLCHAIN)
+	}
+	return fmt.Sprintf("gopher env set gotoolchain=local (or %s)", setCommand)
+}
@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/security"
+)
+
+// ============================================================================
+// Per-Command Version Activation
+// ============================================================================
+
+// ExecEnvironment returns the environment variables that activate a Go
+// version for a child process, without changing the active version.
+//
+// The version may be an alias. The returned variables are the ones from
+// Config.GetEnvironmentVariables (GOROOT, GOPATH, GOPROXY, ...) when
+// set_environment is enabled; PATH always starts with the version's bin
+// directory.
+//
+// Returns the resolved version and the variables to set, or an error if the
+// version is invalid or not installed.
+//
+// Example:
+//
+//	version, vars, err := manager.ExecEnvironment("stable")
+//	cmd := exec.Command("go", "test", "./...")
+//	for key, value := range vars {
+//	    cmd.Env = append(cmd.Env, key+"="+value)
+//	}
+func (m *Manager) ExecEnvironment(version string) (string, map[string]string, error) {
+	// Check if version is an alias
+	if alias, exists := m.aliasManager.GetAlias(version); exists {
+		version = alias.Version
+	}
+
+	// Validate version format
+	if err := ValidateVersion(version); err != nil {
+		return "", nil, errors.Wrapf(err, errors.ErrCodeInvalidVersion, "invalid version")
+	}
+
+	// Validate version for security (path traversal protection)
+	if err := security.ValidatePath(version); err != nil {
+		return "", nil, errors.Wrapf(err, errors.ErrCodeInvalidVersion, "invalid version")
+	}
+
+	// Normalize version
+	version = NormalizeVersion(version)
+
+	binaryPath, err := m.installer.GetGoBinaryPath(version)
+	if err != nil {
+		if !m.installer.IsInstalled(version) {
+			return "", nil, errors.NewVersionNotInstalled(version)
+		}
+		return "", nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to get go binary path")
+	}
+	if err := m.checkPlatform(version); err != nil {
+		return "", nil, err
+	}
+
+	vars := m.config.GetEnvironmentVariablesWithEnv(version, m.envProvider)
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+
+	// Make sure this version's go is found first, even when environment
+	// management is disabled in the config
+	goBin := filepath.Dir(binaryPath)
+	path := vars["PATH"]
+	if path == "" {
+		path = m.envProvider.Getenv("PATH")
+	}
+	if !strings.HasPrefix(path, goBin+string(os.PathListSeparator)) && path != goBin {
+		if path == "" {
+			path = goBin
+		} else {
+			path = goBin + string(os.PathListSeparator) + path
+		}
+	}
+	vars["PATH"] = path
+
+	return version, vars, nil
+}
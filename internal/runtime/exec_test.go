@@ -0,0 +1,71 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecEnvironment(t *testing.T) {
+	tmp := t.TempDir()
+	m := createTestManager(t, tmp)
+	m.config.SetEnvironment = true
+	writeMetadata(t, tmp, "go1.21.0")
+	writeFakeGoBinary(t, tmp, "go1.21.0", "go1.21.0")
+	goBin := filepath.Join(tmp, "go1.21.0", "bin")
+
+	if err := m.AliasManager().CreateAlias("stable", "go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, spec := range []string{"1.21.0", "go1.21.0", "stable"} {
+		t.Run(spec, func(t *testing.T) {
+			version, vars, err := m.ExecEnvironment(spec)
+			if err != nil {
+				t.Fatalf("ExecEnvironment error: %v", err)
+			}
+			if version != "go1.21.0" {
+				t.Errorf("version = %q, want go1.21.0", version)
+			}
+			if !strings.HasPrefix(vars["PATH"], goBin+string(os.PathListSeparator)) {
+				t.Errorf("PATH should start with %s, got %q", goBin, vars["PATH"])
+			}
+			if strings.Count(vars["PATH"], goBin) != 1 {
+				t.Errorf("bin directory should appear once in PATH, got %q", vars["PATH"])
+			}
+			if vars["GOROOT"] != filepath.Join(tmp, "go1.21.0") {
+				t.Errorf("GOROOT = %q, want %q", vars["GOROOT"], filepath.Join(tmp, "go1.21.0"))
+			}
+		})
+	}
+}
+
+func TestExecEnvironment_EnvironmentDisabled(t *testing.T) {
+	tmp := t.TempDir()
+	m := createTestManager(t, tmp)
+	m.config.SetEnvironment = false
+	writeMetadata(t, tmp, "go1.21.0")
+	writeFakeGoBinary(t, tmp, "go1.21.0", "go1.21.0")
+
+	_, vars, err := m.ExecEnvironment("go1.21.0")
+	if err != nil {
+		t.Fatalf("ExecEnvironment error: %v", err)
+	}
+	if len(vars) != 1 {
+		t.Errorf("expected only PATH to be set, got %v", vars)
+	}
+	want := filepath.Join(tmp, "go1.21.0", "bin") + string(os.PathListSeparator) + "/usr/local/bin:/usr/bin:/bin"
+	if vars["PATH"] != want {
+		t.Errorf("PATH = %q, want %q", vars["PATH"], want)
+	}
+}
+
+func TestExecEnvironment_NotInstalled(t *testing.T) {
+	tmp := t.TempDir()
+	m := createTestManager(t, tmp)
+
+	if _, _, err := m.ExecEnvironment("go1.21.0"); err == nil {
+		t.Error("expected error for a version that is not installed")
+	}
+}
@@ -0,0 +1,161 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ============================================================================
+// File Locking
+// ============================================================================
+
+const (
+	// fileLockTimeout is how long to wait for a lock held by another process
+	fileLockTimeout = 10 * time.Second
+
+	// fileLockStaleAfter is the age after which a lock file that names no
+	// owner is considered abandoned. Owners write their PID right after
+	// creating the lock, so this only happens when one crashed in between.
+	fileLockStaleAfter = 2 * time.Minute
+
+	// fileLockRetryInterval is the delay between lock attempts
+	fileLockRetryInterval = 10 * time.Millisecond
+)
+
+// errLockHeld is returned by tryFileLock when another process holds the lock
+var errLockHeld = fmt.Errorf("lock is held by another process")
+
+// acquireFileLock takes an exclusive lock by creating lockPath with O_EXCL.
+//
+// It retries until fileLockTimeout elapses, breaking locks whose owner has
+// exited. The returned function releases the lock.
+func acquireFileLock(lockPath string) (func(), error) {
+	deadline := time.Now().Add(fileLockTimeout)
+
+	for {
+		unlock, err := tryFileLock(lockPath)
+		if err != errLockHeld {
+			return unlock, err
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s (remove it if no other gopher process is running)", lockPath)
+		}
+		time.Sleep(fileLockRetryInterval)
+	}
+}
+
+// tryFileLock makes a single attempt to take the lock at lockPath, breaking
+// it first if its owner has exited. It returns errLockHeld if another process
+// holds the lock.
+func tryFileLock(lockPath string) (func(), error) {
+	for {
+		// #nosec G304 -- lockPath is derived from validated gopher paths
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file %s: %w", lockPath, err)
+		}
+
+		if !breakAbandonedLock(lockPath) {
+			return nil, errLockHeld
+		}
+	}
+}
+
+// breakAbandonedLock removes the lock at lockPath if it is abandoned and
+// reports whether it did.
+//
+// The check and the removal run under a second lock, the break lock, so
+// two processes that both find the lock abandoned cannot remove one that the
+// other has just taken in its place. The break lock is held only for that
+// moment; one left behind by a crash is removed once it is older than
+// fileLockStaleAfter.
+func breakAbandonedLock(lockPath string) bool {
+	// Named like a lock file, so migrate and version listings skip it
+	breakPath := strings.TrimSuffix(lockPath, ".lock") + ".break.lock"
+	// #nosec G304 -- breakPath is derived from validated gopher paths
+	f, err := os.OpenFile(breakPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		if info, statErr := os.Stat(breakPath); statErr == nil && time.Since(info.ModTime()) > fileLockStaleAfter {
+			_ = os.Remove(breakPath)
+		}
+		return false
+	}
+	_ = f.Close()
+	defer func() { _ = os.Remove(breakPath) }()
+
+	if !lockAbandoned(lockPath) {
+		return false
+	}
+	return os.Remove(lockPath) == nil
+}
+
+// lockHeld reports whether the lock at lockPath exists and is not abandoned
+func lockHeld(lockPath string) bool {
+	if _, err := os.Stat(lockPath); err != nil {
+		return false
+	}
+	return !lockAbandoned(lockPath)
+}
+
+// lockAbandoned reports whether the lock at lockPath was left behind: the
+// process that wrote it has exited, or it names no owner and is older than
+// fileLockStaleAfter. A lock that cannot be read is not abandoned.
+func lockAbandoned(lockPath string) bool {
+	// #nosec G304 -- lockPath is derived from validated gopher paths
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return false
+	}
+	if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid > 0 {
+		return !processAlive(pid)
+	}
+	info, err := os.Stat(lockPath)
+	return err == nil && time.Since(info.ModTime()) > fileLockStaleAfter
+}
+
+// processAlive reports whether a process with the given PID is running
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		// Windows opens the process here and fails if it does not exist
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	// Signal 0 checks that the process exists without affecting it; EPERM
+	// means it exists but belongs to another user
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || os.IsPermission(err)
+}
+
+// dirSize returns the total size of the regular files under path
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,107 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireFileLock(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "test.lock")
+
+	unlock, err := acquireFileLock(lockPath)
+	if err != nil {
+		t.Fatalf("acquireFileLock error: %v", err)
+	}
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Fatalf("lock file not created: %v", err)
+	}
+
+	unlock()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatal("lock file not removed on unlock")
+	}
+}
+
+// exitedPID returns the PID of a process that has exited
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestAcquireFileLock_BreaksStaleLock(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "test.lock")
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n", exitedPID(t))), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := acquireFileLock(lockPath)
+	if err != nil {
+		t.Fatalf("expected stale lock to be broken, got: %v", err)
+	}
+	unlock()
+}
+
+func TestTryFileLock_Owner(t *testing.T) {
+	old := time.Now().Add(-2 * fileLockStaleAfter)
+	tests := []struct {
+		name      string
+		content   string
+		modified  time.Time
+		breaking  bool // another process holds the break lock
+		abandoned bool
+	}{
+		{"running owner", fmt.Sprintf("%d\n", os.Getpid()), time.Now(), false, false},
+		{"running owner of an old lock", fmt.Sprintf("%d\n", os.Getpid()), old, false, false},
+		{"exited owner", fmt.Sprintf("%d\n", exitedPID(t)), time.Now(), false, true},
+		{"exited owner while another process breaks it", fmt.Sprintf("%d\n", exitedPID(t)), time.Now(), true, true},
+		{"no owner yet", "", time.Now(), false, false},
+		{"no owner, old", "", old, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lockPath := filepath.Join(t.TempDir(), ".go1.21.0.lock")
+			if err := os.WriteFile(lockPath, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(lockPath, tt.modified, tt.modified); err != nil {
+				t.Fatal(err)
+			}
+			breakPath := filepath.Join(filepath.Dir(lockPath), ".go1.21.0.break.lock")
+			if tt.breaking {
+				if err := os.WriteFile(breakPath, nil, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := lockHeld(lockPath); got == tt.abandoned {
+				t.Errorf("lockHeld() = %v, want %v", got, !tt.abandoned)
+			}
+			unlock, err := tryFileLock(lockPath)
+			if !tt.abandoned || tt.breaking {
+				if err != errLockHeld {
+					t.Fatalf("tryFileLock() error = %v, want errLockHeld", err)
+				}
+				data, _ := os.ReadFile(lockPath)
+				if string(data) != tt.content {
+					t.Errorf("held lock was replaced: %q", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("tryFileLock() error = %v", err)
+			}
+			unlock()
+			if _, err := os.Stat(breakPath); !os.IsNotExist(err) {
+				t.Error("break lock was not removed")
+			}
+		})
+	}
+}
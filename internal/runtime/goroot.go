@@ -0,0 +1,137 @@
+package runtime
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ============================================================================
+// GOROOT Override Detection
+// ============================================================================
+
+// goRootProfiles are the shell startup files, relative to the home
+// directory, searched for a line that sets GOROOT
+var goRootProfiles = []string{
+	".profile",
+	".bash_profile",
+	".bashrc",
+	".zshenv",
+	".zprofile",
+	".zshrc",
+	filepath.Join(".config", "fish", "config.fish"),
+}
+
+// GOROOTOverride describes a GOROOT in the environment that does not belong
+// to the active version. Go trusts GOROOT over its own location, so 'go'
+// then builds with another version's standard library.
+type GOROOTOverride struct {
+	Value    string `json:"value"`            // GOROOT in the environment
+	Expected string `json:"expected"`         // GOROOT of the active version
+	Version  string `json:"version"`          // Active version
+	Source   string `json:"source,omitempty"` // Profile line that sets it, when found
+	Stale    bool   `json:"stale"`            // Set by gopher for another version in this shell
+	Fix      string `json:"fix"`
+}
+
+// CheckGOROOT reports whether GOROOT is set to something other than the
+// active version's directory.
+//
+// Returns nil when GOROOT is unset, when it matches, or when no gopher
+// version is active (system Go does not need GOROOT).
+func (m *Manager) CheckGOROOT() *GOROOTOverride {
+	value := m.envProvider.Getenv("GOROOT")
+	if value == "" {
+		return nil
+	}
+	version, err := m.getActiveVersionFromState()
+	if err != nil || version == "system" || version == "homebrew" {
+		return nil
+	}
+	version = NormalizeVersion(version)
+	expected := m.config.GetGOROOT(version)
+	if sameFile(value, expected) {
+		return nil
+	}
+
+	override := &GOROOTOverride{
+		Value:    value,
+		Expected: expected,
+		Version:  version,
+		Stale:    m.isManagedGo(value),
+	}
+	if override.Stale {
+		override.Fix = fmt.Sprintf(`eval "$(gopher env activate %s)"`, version)
+		return override
+	}
+	override.Source = goRootSource()
+	override.Fix = "remove the GOROOT setting and restart your shell"
+	if override.Source == "" {
+		override.Fix = "unset GOROOT, and remove it wherever your environment sets it"
+	}
+	return override
+}
+
+// Warning returns a user-facing description of the problem and its fix
+func (o *GOROOTOverride) Warning() string {
+	var b strings.Builder
+	if o.Stale {
+		fmt.Fprintf(&b, "⚠️  WARNING: GOROOT=%s is left over from another version in this shell\n", o.Value)
+	} else {
+		fmt.Fprintf(&b, "⚠️  WARNING: GOROOT=%s overrides gopher; 'go' will use that standard library\n", o.Value)
+	}
+	fmt.Fprintf(&b, "  Active version %s expects GOROOT=%s\n", o.Version, o.Expected)
+	if o.Source != "" {
+		fmt.Fprintf(&b, "  Set in %s\n", o.Source)
+	} else if !o.Stale {
+		fmt.Fprintf(&b, "  Not set in a shell profile; check /etc/profile, your terminal, IDE or CI settings\n")
+	}
+	fmt.Fprintf(&b, "  To fix: %s\n", o.Fix)
+	return b.String()
+}
+
+// goRootSource returns the first profile line that sets GOROOT, as
+// "path:line", or "" when none does
+func goRootSource() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range goRootProfiles {
+		path := filepath.Join(home, name)
+		if line := goRootLine(path); line > 0 {
+			return fmt.Sprintf("%s:%d", path, line)
+		}
+	}
+	return ""
+}
+
+// goRootLine returns the number of the first line in path that sets
+// GOROOT, or 0. Comments and unset lines are ignored.
+func goRootLine(path string) int {
+	// #nosec G304 -- path is one of the user's shell profiles
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			// export GOROOT=..., GOROOT=..., set -gx GOROOT ..., setenv GOROOT ...
+			if strings.HasPrefix(field, "GOROOT=") ||
+				(field == "GOROOT" && i > 0 && (fields[0] == "set" || fields[0] == "setenv") && i+1 < len(fields)) {
+				return n
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/env"
+)
+
+func TestCheckGOROOT(t *testing.T) {
+	tmp := t.TempDir()
+	home := filepath.Join(tmp, "home")
+	setTestHome(t, home)
+	if err := os.MkdirAll(home, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	installDir := filepath.Join(tmp, "versions")
+	m := createTestManager(t, installDir)
+	writeMetadata(t, installDir, "go1.21.0")
+	writeMetadata(t, installDir, "go1.22.0")
+	mockEnv := env.NewMockProvider(map[string]string{})
+	m.envProvider = mockEnv
+
+	stateFile, err := m.stateFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteStateFile(stateFile, &State{ActiveVersion: "go1.22.0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unset, or set by gopher for the active version
+	if override := m.CheckGOROOT(); override != nil {
+		t.Errorf("expected no override without GOROOT, got %+v", override)
+	}
+	mockEnv.Setenv("GOROOT", filepath.Join(installDir, "go1.22.0"))
+	if override := m.CheckGOROOT(); override != nil {
+		t.Errorf("expected no override for the active version, got %+v", override)
+	}
+
+	// Left over from the previous version in this shell
+	mockEnv.Setenv("GOROOT", filepath.Join(installDir, "go1.21.0"))
+	if override := m.CheckGOROOT(); override == nil || !override.Stale || !strings.Contains(override.Fix, "env activate go1.22.0") {
+		t.Errorf("expected a stale GOROOT, got %+v", override)
+	}
+
+	// Exported by the user's profile
+	profile := "# export GOROOT=/old\nexport PATH=$PATH:/usr/local/go/bin\nexport GOROOT=/usr/local/go\n"
+	if err := os.WriteFile(filepath.Join(home, ".zshrc"), []byte(profile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mockEnv.Setenv("GOROOT", "/usr/local/go")
+	override := m.CheckGOROOT()
+	if override == nil || override.Stale || override.Expected != filepath.Join(installDir, "go1.22.0") {
+		t.Fatalf("expected an external override, got %+v", override)
+	}
+	if want := filepath.Join(home, ".zshrc") + ":3"; override.Source != want {
+		t.Errorf("source = %q, want %q", override.Source, want)
+	}
+	if !strings.Contains(override.Warning(), "GOROOT=/usr/local/go") {
+		t.Errorf("warning should name the offending value: %s", override.Warning())
+	}
+
+	// System Go does not use gopher's GOROOT
+	if err := WriteStateFile(stateFile, &State{ActiveVersion: "system"}); err != nil {
+		t.Fatal(err)
+	}
+	if override := m.CheckGOROOT(); override != nil {
+		t.Errorf("expected no check for system Go, got %+v", override)
+	}
+}
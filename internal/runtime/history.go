@@ -0,0 +1,152 @@
+package runtime
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/fileutil"
+)
+
+// ============================================================================
+// Switch History
+// ============================================================================
+
+// historyFile is the name of the switch history log in the state directory
+const historyFile = "history"
+
+// maxHistoryEntries is how many switches the history keeps; older entries
+// are dropped when a new one is recorded
+const maxHistoryEntries = 100
+
+// SwitchRecord is one version switch in the history
+type SwitchRecord struct {
+	Time time.Time `json:"time"`
+	From string    `json:"from,omitempty"` // Empty for the first recorded switch
+	To   string    `json:"to"`
+}
+
+// historyPath returns the path of the switch history log
+func (m *Manager) historyPath() (string, error) {
+	stateDir, err := m.stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, historyFile), nil
+}
+
+// History returns the most recent version switches, newest first. limit <= 0
+// returns the whole history. A missing history log is an empty history.
+//
+// Example:
+//
+//	records, err := manager.History(10)
+//	for _, r := range records {
+//	    fmt.Println(r.Time, r.From, "->", r.To)
+//	}
+func (m *Manager) History(limit int) ([]SwitchRecord, error) {
+	records, err := m.readHistory()
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to read switch history")
+	}
+
+	// Newest first
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// PreviousVersion returns the version that was active before the current
+// one, for 'gopher use -'. It is taken from the newest switch in the history,
+// so switching back records a new switch and a second 'use -' toggles again.
+func (m *Manager) PreviousVersion() (string, error) {
+	records, err := m.History(1)
+	if err != nil {
+		return "", err
+	}
+	if len(records) == 0 || records[0].From == "" {
+		return "", errors.New(errors.ErrCodeInvalidArgument, "no previous version to switch back to (see 'gopher history')")
+	}
+	return records[0].From, nil
+}
+
+// ClearHistory removes the switch history. Clearing an empty history is not
+// an error.
+func (m *Manager) ClearHistory() error {
+	path, err := m.historyPath()
+	if err != nil {
+		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to clear switch history")
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to clear switch history")
+	}
+	return nil
+}
+
+// readHistory returns the recorded switches, oldest first. Lines that cannot
+// be parsed are skipped.
+func (m *Manager) readHistory() ([]SwitchRecord, error) {
+	path, err := m.historyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G304 -- path validated and scoped to the state directory
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []SwitchRecord{}, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	records := []SwitchRecord{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil || fields[2] == "" {
+			continue
+		}
+		records = append(records, SwitchRecord{Time: at, From: fields[1], To: fields[2]})
+	}
+	return records, scanner.Err()
+}
+
+// recordSwitch appends a switch from one version to another to the history,
+// keeping the newest maxHistoryEntries. Each line holds the time, the
+// previous version and the new one, separated by tabs.
+func (m *Manager) recordSwitch(from, to string) error {
+	records, err := m.readHistory()
+	if err != nil {
+		return err
+	}
+	records = append(records, SwitchRecord{Time: time.Now().UTC(), From: from, To: to})
+	if len(records) > maxHistoryEntries {
+		records = records[len(records)-maxHistoryEntries:]
+	}
+
+	var b strings.Builder
+	for _, r := range records {
+		fmt.Fprintf(&b, "%s\t%s\t%s\n", r.Time.Format(time.RFC3339), r.From, r.To)
+	}
+
+	path, err := m.historyPath()
+	if err != nil {
+		return err
+	}
+	// #nosec G306 -- 0644 acceptable for state file (non-sensitive metadata)
+	return fileutil.WriteAtomic(path, []byte(b.String()), 0644)
+}
@@ -0,0 +1,103 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManager_History(t *testing.T) {
+	manager := createTestManager(t, filepath.Join(t.TempDir(), "versions"))
+
+	records, err := manager.History(0)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("History() with no log = %v, %v; want empty", records, err)
+	}
+
+	// Saving the version that is already active is not a switch
+	for _, version := range []string{"go1.21.0", "go1.22.0", "go1.22.0", "system"} {
+		if err := manager.saveActiveVersion(version); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err = manager.History(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SwitchRecord{{From: "go1.22.0", To: "system"}, {From: "go1.21.0", To: "go1.22.0"}, {From: "", To: "go1.21.0"}}
+	if len(records) != len(want) {
+		t.Fatalf("History() = %v, want %d records", records, len(want))
+	}
+	for i, r := range records {
+		if r.From != want[i].From || r.To != want[i].To || r.Time.IsZero() {
+			t.Errorf("record %d = %+v, want %s -> %s", i, r, want[i].From, want[i].To)
+		}
+	}
+
+	if records, _ := manager.History(1); len(records) != 1 || records[0].To != "system" {
+		t.Errorf("History(1) = %v, want the newest switch only", records)
+	}
+
+	if err := manager.ClearHistory(); err != nil {
+		t.Fatal(err)
+	}
+	if records, _ := manager.History(0); len(records) != 0 {
+		t.Errorf("History() after ClearHistory = %v, want empty", records)
+	}
+	if err := manager.ClearHistory(); err != nil {
+		t.Errorf("ClearHistory() on an empty history = %v", err)
+	}
+}
+
+func TestManager_HistoryLimit(t *testing.T) {
+	manager := createTestManager(t, filepath.Join(t.TempDir(), "versions"))
+	stateDir, err := manager.stateDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(stateDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < maxHistoryEntries+5; i++ {
+		if err := manager.recordSwitch("go1.21.0", "go1.22.0"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err := manager.History(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != maxHistoryEntries {
+		t.Errorf("History() kept %d records, want %d", len(records), maxHistoryEntries)
+	}
+}
+
+func TestManager_PreviousVersion(t *testing.T) {
+	manager := createTestManager(t, filepath.Join(t.TempDir(), "versions"))
+
+	if _, err := manager.PreviousVersion(); err == nil {
+		t.Error("PreviousVersion() with no history should fail")
+	}
+	if err := manager.saveActiveVersion("go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.PreviousVersion(); err == nil {
+		t.Error("PreviousVersion() after the first switch should fail")
+	}
+
+	if err := manager.saveActiveVersion("go1.22.0"); err != nil {
+		t.Fatal(err)
+	}
+	// Switching back and forth toggles between the last two versions
+	for _, want := range []string{"go1.21.0", "go1.22.0", "go1.21.0"} {
+		previous, err := manager.PreviousVersion()
+		if err != nil || previous != want {
+			t.Fatalf("PreviousVersion() = %q, %v; want %q", previous, err, want)
+		}
+		if err := manager.saveActiveVersion(previous); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/molmedoz/gopher/internal/errors"
+)
+
+// ============================================================================
+// Homebrew Go Detection
+// ============================================================================
+
+// HomebrewVersion is the pseudo-version that selects the Go installed by
+// Homebrew, like "system" selects the system Go
+const HomebrewVersion = "homebrew"
+
+// homebrewGoPaths lists where Homebrew links its go binary, in lookup order
+var homebrewGoPaths = []string{
+	"/opt/homebrew/bin/go",              // Apple Silicon Homebrew
+	"/usr/local/opt/go/libexec/bin/go",  // Intel Homebrew
+	"/home/linuxbrew/.linuxbrew/bin/go", // Linuxbrew
+}
+
+// FindHomebrewGo returns the path to the Homebrew-managed go binary.
+//
+// Returns an error if Homebrew Go is not installed.
+func FindHomebrewGo() (string, error) {
+	for _, path := range homebrewGoPaths {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", errors.Newf(errors.ErrCodeSystemGoNotAvailable,
+		"Homebrew Go not found (looked in %s); install it with 'brew install go'",
+		strings.Join(homebrewGoPaths, ", "))
+}
+
+// isHomebrewGoPath reports whether path is one of the Homebrew go binaries
+func isHomebrewGoPath(path string) bool {
+	for _, homebrewPath := range homebrewGoPaths {
+		if filepath.Clean(path) == homebrewPath {
+			return true
+		}
+	}
+	return false
+}
+
+// GetHomebrewGoInfo returns detailed information about Homebrew Go
+func (sd *SystemDetectorImpl) GetHomebrewGoInfo() (*SystemGoInfo, error) {
+	goPath, err := FindHomebrewGo()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := sd.runner.Run(goPath, "version")
+	if err != nil {
+		return nil, detectionError("failed to get go version", err)
+	}
+
+	gorootOutput, err := sd.runner.Run(goPath, "env", "GOROOT")
+	if err != nil {
+		return nil, detectionError("failed to get GOROOT", err)
+	}
+
+	gopathOutput, err := sd.runner.Run(goPath, "env", "GOPATH")
+	if err != nil {
+		return nil, detectionError("failed to get GOPATH", err)
+	}
+
+	return &SystemGoInfo{
+		Version:    strings.TrimSpace(string(output)),
+		GOROOT:     strings.TrimSpace(string(gorootOutput)),
+		GOPATH:     strings.TrimSpace(string(gopathOutput)),
+		Executable: goPath,
+		IsValid:    true,
+		Source:     "homebrew",
+	}, nil
+}
+
+// homebrewVersion returns Homebrew Go as an active Version
+func (m *Manager) homebrewVersion() (*Version, error) {
+	info, err := NewSystemDetector().GetHomebrewGoInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	installedAt := time.Now()
+	if fileInfo, err := os.Stat(info.Executable); err == nil {
+		installedAt = fileInfo.ModTime()
+	}
+
+	return &Version{
+		Version:     parseGoVersionOutput(info.Version),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		InstalledAt: installedAt,
+		IsActive:    true,
+		IsSystem:    true,
+		Path:        info.Executable,
+	}, nil
+}
+
+// useHomebrewVersion switches to the Homebrew Go version and returns the go
+// binary it switched to.
+//
+// This is called internally when Use("homebrew") is invoked.
+func (m *Manager) useHomebrewVersion() (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", errors.New(errors.ErrCodeSystemGoNotAvailable, "Homebrew Go is not available on Windows")
+	}
+
+	info, err := NewSystemDetector().GetHomebrewGoInfo()
+	if err != nil {
+		return "", err
+	}
+
+	if err := m.createSymlink(info.Executable); err != nil {
+		return "", errors.NewSymlinkFailed(info.Executable, "", err)
+	}
+
+	if err := m.setupExternalEnvironment(HomebrewVersion, info); err != nil {
+		return "", errors.Wrapf(err, errors.ErrCodeEnvironmentSetupFailed, "failed to setup environment")
+	}
+
+	m.checkGOPATHInPath(HomebrewVersion)
+	m.checkToolchainSetting()
+	m.warnIfPathShadowed()
+
+	if err := m.saveActiveVersion(HomebrewVersion); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save active version: %v\n", err)
+	}
+
+	if err := m.setupShellIntegration(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to setup shell integration: %v\n", err)
+	}
+
+	return info.Executable, nil
+}
@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakeHomebrewGo points homebrewGoPaths at a fake go script for the test
+func fakeHomebrewGo(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake go binary is a shell script")
+	}
+
+	dir := t.TempDir()
+	goroot := filepath.Join(dir, "Cellar", "go", "1.22.1", "libexec")
+	goPath := filepath.Join(dir, "bin", "go")
+	// #nosec G301 -- 0755 acceptable for test directory
+	if err := os.MkdirAll(filepath.Dir(goPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := `#!/bin/sh
+case "$1 $2" in
+  "version ") echo "go version go1.22.1 darwin/arm64" ;;
+  "env GOROOT") echo "` + goroot + `" ;;
+  "env GOPATH") echo "/Users/me/go" ;;
+esac
+`
+	// #nosec G306 -- test binary must be executable
+	if err := os.WriteFile(goPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := homebrewGoPaths
+	homebrewGoPaths = []string{filepath.Join(dir, "missing", "go"), goPath}
+	t.Cleanup(func() { homebrewGoPaths = saved })
+	return goPath
+}
+
+func TestFindHomebrewGo(t *testing.T) {
+	goPath := fakeHomebrewGo(t)
+
+	found, err := FindHomebrewGo()
+	if err != nil {
+		t.Fatalf("FindHomebrewGo error: %v", err)
+	}
+	if found != goPath {
+		t.Errorf("FindHomebrewGo = %q, want %q", found, goPath)
+	}
+	if !isHomebrewGoPath(goPath) {
+		t.Error("isHomebrewGoPath should match the Homebrew binary")
+	}
+	if isHomebrewGoPath("/usr/local/go/bin/go") {
+		t.Error("isHomebrewGoPath should not match a non-Homebrew binary")
+	}
+
+	homebrewGoPaths = []string{filepath.Join(t.TempDir(), "go")}
+	if _, err := FindHomebrewGo(); err == nil {
+		t.Error("expected error when Homebrew Go is not installed")
+	}
+}
+
+func TestHomebrewVersion(t *testing.T) {
+	goPath := fakeHomebrewGo(t)
+	m := createTestManager(t, t.TempDir())
+
+	info, err := NewSystemDetector().GetHomebrewGoInfo()
+	if err != nil {
+		t.Fatalf("GetHomebrewGoInfo error: %v", err)
+	}
+	if info.Executable != goPath || info.GOPATH != "/Users/me/go" || filepath.Base(info.GOROOT) != "libexec" {
+		t.Errorf("unexpected Homebrew info: %+v", info)
+	}
+
+	version, err := m.homebrewVersion()
+	if err != nil {
+		t.Fatalf("homebrewVersion error: %v", err)
+	}
+	if version.Version != "go1.22.1" || !version.IsSystem || version.Path != goPath {
+		t.Errorf("unexpected Homebrew version: %+v", version)
+	}
+}
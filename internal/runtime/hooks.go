@@ -0,0 +1,81 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ============================================================================
+// Hooks
+// ============================================================================
+
+// HookVersionVar is the environment variable that holds the version a hook
+// runs for
+const HookVersionVar = "GOPHER_VERSION"
+
+// HookPreviousVersionVar is the environment variable that holds the version
+// that was active before a post_use hook's switch; empty on the first switch
+const HookPreviousVersionVar = "GOPHER_PREVIOUS_VERSION"
+
+// runHook runs command, a hook from the config file, through the shell with
+// the version's environment (GOROOT, GOPATH, PATH, ...) and GOPHER_VERSION
+// set. extra adds more variables. Its output goes to stderr, so it never
+// mixes with --json output on stdout.
+//
+// "system" and "homebrew" are not managed by gopher, so their hooks get the
+// caller's environment unchanged apart from GOPHER_VERSION and extra.
+//
+// An empty command, or hooks disabled with SetNoHooks, does nothing. The
+// error reports the hook's exit status.
+func (m *Manager) runHook(ctx context.Context, name, command, version string, extra map[string]string) error {
+	if m.noHooks || strings.TrimSpace(command) == "" {
+		return nil
+	}
+
+	cmd := hookCommand(ctx, command)
+	cmd.Env = os.Environ()
+	if version != "system" && version != HomebrewVersion {
+		for key, value := range m.config.GetEnvironmentVariablesWithEnv(version, m.envProvider) {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
+	cmd.Env = append(cmd.Env, HookVersionVar+"="+version)
+	for key, value := range extra {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	fmt.Fprintf(os.Stderr, "Running %s hook for %s: %s\n", name, version, command)
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("%s hook exited with status %d", name, exitErr.ExitCode())
+		}
+		return fmt.Errorf("%s hook failed: %w", name, err)
+	}
+	return nil
+}
+
+// runPostUseHook runs the post_use hook after a switch to result.Version. A
+// failure is only a warning: the switch has already happened.
+func (m *Manager) runPostUseHook(result *UseResult) {
+	extra := map[string]string{HookPreviousVersionVar: result.Previous}
+	if err := m.runHook(context.Background(), "post_use", m.config.PostUse, result.Version, extra); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; switched to %s\n", err, result.Version)
+		result.PostUseError = err.Error()
+	}
+}
+
+// hookCommand returns the command that runs a hook through the shell
+func hookCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		// #nosec G204 -- hooks are commands the user put in their own config file
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	// #nosec G204 -- hooks are commands the user put in their own config file
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
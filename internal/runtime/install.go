@@ -1,9 +1,20 @@
 package runtime
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/molmedoz/gopher/internal/downloader"
 	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/installer"
+	"github.com/molmedoz/gopher/internal/log"
+	"github.com/molmedoz/gopher/internal/progress"
 	"github.com/molmedoz/gopher/internal/security"
 )
 
@@ -23,71 +34,475 @@ import (
 //   - Auto-cleanup of old versions (if enabled)
 //
 // Parameters:
+//   - ctx: Cancels the download when done, e.g. on Ctrl-C
 //   - version: The Go version to install (e.g., "1.21.0", "go1.21.0")
 //
-// Returns an error if the installation fails at any step.
+// Returns where the version was installed and whether its archive was
+// downloaded or taken from the download cache, or an error if the
+// installation fails at any step.
 //
 // Example:
 //
-//	err := manager.Install("1.21.0")
+//	result, err := manager.Install(ctx, "1.21.0")
 //	if err != nil {
 //	    log.Fatal("Installation failed:", err)
 //	}
-func (m *Manager) Install(version string) error {
+//	fmt.Println(result.Path, result.FromCache)
+func (m *Manager) Install(ctx context.Context, version string) (*InstallResult, error) {
+	result, err := m.installOne(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Auto-cleanup if enabled, never removing the version just installed
+	if m.config.AutoCleanup {
+		if err := m.autoCleanup([]string{result.Version}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to auto-cleanup: %v\n", err)
+		}
+	}
+
+	return &result, nil
+}
+
+// InstallFromReader installs a Go version from an archive read from r, for
+// embedding gopher in tools that already have the archive bytes (e.g. from an
+// object store or their own cache) instead of downloading it.
+//
+// The version is validated, locked and checked like Install, the pre_install
+// and post_install hooks run around it (with strict_hooks removing the version
+// again when post_install fails), and auto-cleanup runs afterwards if
+// enabled. A post_install failure that keeps the version is only reported as
+// a warning. The archive is not verified against a published
+// checksum, as it did not come from the mirror; its SHA256 is recorded in the
+// version metadata so 'gopher verify' can still identify it.
+//
+// Parameters:
+//   - ctx: Context for the hooks
+//   - version: The Go version the archive holds (e.g., "1.21.0", "go1.21.0")
+//   - r: The archive contents
+//   - archiveType: installer.ArchiveTarGz, ArchiveTarXz, ArchiveTarZst or ArchiveZip
+//
+// Example:
+//
+//	f, err := os.Open("go1.21.0.linux-amd64.tar.gz")
+//	defer f.Close()
+//	err = manager.InstallFromReader(ctx, "1.21.0", f, installer.ArchiveTarGz)
+func (m *Manager) InstallFromReader(ctx context.Context, version string, r io.Reader, archiveType installer.ArchiveType) error {
+	_, err := m.installFromReader(ctx, version, r, archiveType)
+	return err
+}
+
+// installFromReader implements InstallFromReader, returning the result so
+// InstallFromFile can report a post_install failure that kept the version
+func (m *Manager) installFromReader(ctx context.Context, version string, r io.Reader, archiveType installer.ArchiveType) (InstallResult, error) {
+	if err := ValidateVersion(version); err != nil {
+		return InstallResult{Version: version}, fmt.Errorf("invalid version: %w", err)
+	}
+	if err := security.ValidatePath(version); err != nil {
+		return InstallResult{Version: version}, fmt.Errorf("invalid version: %w", err)
+	}
+	version = NormalizeVersion(version)
+
+	unlock, err := m.lockInstall(version)
+	if err != nil {
+		return InstallResult{Version: version}, err
+	}
+	defer unlock()
+
+	installed, err := m.IsInstalled(version)
+	if err != nil {
+		return InstallResult{Version: version}, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to check if version is installed")
+	}
+	if installed {
+		return InstallResult{Version: version}, errors.NewVersionAlreadyInstalled(version)
+	}
+
+	if err := m.runHook(ctx, "pre_install", m.config.PreInstall, version, nil); err != nil {
+		return InstallResult{Version: version}, errors.NewInstallationFailed(version, err)
+	}
+
+	if err := m.installer.InstallFromReader(version, r, archiveType); err != nil {
+		return InstallResult{Version: version}, errors.NewInstallationFailed(version, err)
+	}
+
+	result := InstallResult{Version: version, OK: true, Path: filepath.Join(m.config.InstallDir, version)}
+	if result.PostInstallError, err = m.runPostInstall(ctx, version); err != nil {
+		return InstallResult{Version: version}, err
+	}
+
+	// Auto-cleanup if enabled
+	if m.config.AutoCleanup {
+		if err := m.autoCleanup([]string{version}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to auto-cleanup: %v\n", err)
+		}
+	}
+
+	return result, nil
+}
+
+// InstallFromFile installs a Go version from a local archive with
+// InstallFromReader, for archives copied by hand, e.g. onto a machine without
+// network access. An empty version is taken from the archive's file name
+// (e.g. go1.21.0.linux-amd64.tar.gz), and the archive type from its extension.
+//
+// Example:
+//
+//	result, err := manager.InstallFromFile(ctx, "/media/usb/go1.21.0.linux-amd64.tar.gz", "")
+func (m *Manager) InstallFromFile(ctx context.Context, path, version string) (*InstallResult, error) {
+	if version == "" {
+		match := archiveNameRegex.FindStringSubmatch(filepath.Base(path))
+		if match == nil || strings.HasSuffix(path, downloader.ChecksumSuffix) {
+			return nil, errors.Newf(errors.ErrCodeInvalidArgument, "cannot tell the Go version from the file name %s; give it after the archive (e.g., 'gopher install --from-file %s 1.21.0')", filepath.Base(path), path)
+		}
+		version = match[1]
+	}
+
+	archiveType, ok := installer.ArchiveTypeOf(path)
+	if !ok {
+		return nil, errors.Newf(errors.ErrCodeInvalidArgument, "unsupported archive %s (expected .tar.gz, .tar.xz, .tar.zst or .zip)", filepath.Base(path))
+	}
+
+	// #nosec G304 -- the archive is chosen by the user
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeFileNotFound, "failed to open archive")
+	}
+	defer file.Close()
+
+	result, err := m.installFromReader(ctx, version, file, archiveType)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// installOne validates and installs a version without running auto-cleanup.
+// The result holds the normalized version even on error.
+func (m *Manager) installOne(ctx context.Context, version string) (InstallResult, error) {
 	// Validate version format
 	if err := ValidateVersion(version); err != nil {
-		return fmt.Errorf("invalid version: %w", err)
+		return InstallResult{Version: version}, fmt.Errorf("invalid version: %w", err)
 	}
 
 	// Validate version for security (path traversal protection)
 	if err := security.ValidatePath(version); err != nil {
-		return fmt.Errorf("invalid version: %w", err)
+		return InstallResult{Version: version}, fmt.Errorf("invalid version: %w", err)
 	}
 
 	// Normalize version
 	version = NormalizeVersion(version)
 
+	// Hold the install lock across the check, so a concurrent install of the
+	// same version is reported instead of racing on its directory
+	unlock, err := m.lockInstall(version)
+	if err != nil {
+		return InstallResult{Version: version}, err
+	}
+	defer unlock()
+
 	// Check if already installed
 	installed, err := m.IsInstalled(version)
 	if err != nil {
-		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to check if version is installed")
+		return InstallResult{Version: version}, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to check if version is installed")
 	}
 	if installed {
-		return errors.NewVersionAlreadyInstalled(version)
+		return InstallResult{Version: version}, errors.NewVersionAlreadyInstalled(version)
+	}
+
+	if err := m.runHook(ctx, "pre_install", m.config.PreInstall, version, nil); err != nil {
+		return InstallResult{Version: version}, errors.NewInstallationFailed(version, err)
+	}
+
+	result, err := m.downloadAndInstall(ctx, version)
+	if err != nil {
+		return result, err
+	}
+
+	if result.PostInstallError, err = m.runPostInstall(ctx, version); err != nil {
+		return InstallResult{Version: version}, err
+	}
+	return result, nil
+}
+
+// runPostInstall runs the post_install hook for a version that was just
+// installed. The version works without whatever the hook sets up, so by
+// default a failure is only reported and its message returned for the
+// install result; with strict_hooks the version is removed again and the
+// install fails.
+func (m *Manager) runPostInstall(ctx context.Context, version string) (string, error) {
+	err := m.runHook(ctx, "post_install", m.config.PostInstall, version, nil)
+	if err == nil {
+		return "", nil
+	}
+	if m.config.StrictHooks {
+		if uninstallErr := m.installer.Uninstall(version); uninstallErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove %s after the post_install hook failed: %v\n", version, uninstallErr)
+		}
+		return "", errors.NewInstallationFailed(version, err)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %v; %s is installed\n", err, version)
+	return err.Error(), nil
+}
+
+// DefaultInstallWorkers is the number of versions InstallMany installs at
+// once when asked to run concurrently
+const DefaultInstallWorkers = 3
+
+// InstallResult is the outcome of installing one version, alone with
+// Install or as part of a batch with InstallMany
+type InstallResult struct {
+	Version          string `json:"version"`
+	OK               bool   `json:"ok"`
+	AlreadyInstalled bool   `json:"already_installed,omitempty"`
+	Skipped          bool   `json:"skipped,omitempty"`            // Not attempted because an earlier version failed
+	Path             string `json:"path,omitempty"`               // Installation directory
+	FromCache        bool   `json:"from_cache,omitempty"`         // Archive reused from the download cache
+	Downloaded       bool   `json:"downloaded,omitempty"`         // Archive fetched from the mirror
+	BytesDownloaded  int64  `json:"bytes_downloaded,omitempty"`   // Size of the fetched archive
+	PostInstallError string `json:"post_install_error,omitempty"` // Failure of the post_install hook, which kept the install
+	Error            string `json:"error,omitempty"`
+	Err              error  `json:"-"`
+}
+
+// InstallMany installs several versions with at most workers installs
+// running at once. Versions that are already installed count as successful.
+//
+// Auto-cleanup runs once after the whole batch, and never removes a version
+// installed by it. onDone, if not nil, is called as each version finishes;
+// calls are serialized. Once ctx is canceled, versions not started yet fail
+// without being downloaded. Unless keepGoing is set, versions not started
+// when one fails are skipped; installs already running still finish.
+//
+// Returns one result per distinct version, in the order given.
+//
+// Example:
+//
+//	results := manager.InstallMany(ctx, []string{"1.21.0", "1.22.0"}, runtime.DefaultInstallWorkers, false, nil)
+func (m *Manager) InstallMany(ctx context.Context, versions []string, workers int, keepGoing bool, onDone func(InstallResult)) []InstallResult {
+	// Installing the same version twice at once would race on its directory
+	seen := make(map[string]bool, len(versions))
+	var unique []string
+	for _, version := range versions {
+		if key := NormalizeVersion(version); !seen[key] {
+			seen[key] = true
+			unique = append(unique, version)
+		}
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]InstallResult, len(unique))
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	stopped := false
+	for w := 0; w < workers && w < len(unique); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				mu.Lock()
+				if stopped {
+					results[i] = InstallResult{Version: unique[i], Skipped: true, Error: "not attempted after an earlier failure"}
+					mu.Unlock()
+					continue
+				}
+				mu.Unlock()
+
+				result, err := m.installOne(ctx, unique[i])
+				result.OK, result.Err = err == nil, err
+				if errors.IsErrorCode(err, errors.ErrCodeVersionAlreadyInstalled) {
+					result.OK, result.AlreadyInstalled, result.Err = true, true, nil
+				}
+				if result.Err != nil {
+					result.Error = result.Err.Error()
+				}
+
+				mu.Lock()
+				results[i] = result
+				if !result.OK && !keepGoing {
+					stopped = true
+				}
+				if onDone != nil {
+					onDone(result)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range unique {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if m.config.AutoCleanup {
+		var keep []string
+		for _, result := range results {
+			if result.OK {
+				keep = append(keep, result.Version)
+			}
+		}
+		if err := m.autoCleanup(keep); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to auto-cleanup: %v\n", err)
+		}
 	}
 
+	return results
+}
+
+// Reinstall downloads and installs a Go version again, replacing any existing
+// installation of it.
+//
+// A valid archive already in the download directory is reused instead of
+// being downloaded again. The existing installation is only replaced once the
+// archive has been verified, and aliases pointing at the version are kept.
+//
+// Parameters:
+//   - ctx: Cancels the download when done
+//   - version: The Go version to reinstall (e.g., "1.21.0", "go1.21.0")
+//
+// Returns the normalized version, or an error if the reinstallation fails.
+//
+// Example:
+//
+//	version, err := manager.Reinstall(ctx, "1.21.0")
+func (m *Manager) Reinstall(ctx context.Context, version string) (string, error) {
+	// Validate version format
+	if err := ValidateVersion(version); err != nil {
+		return "", fmt.Errorf("invalid version: %w", err)
+	}
+
+	// Validate version for security (path traversal protection)
+	if err := security.ValidatePath(version); err != nil {
+		return "", fmt.Errorf("invalid version: %w", err)
+	}
+
+	// Normalize version
+	version = NormalizeVersion(version)
+
+	unlock, err := m.lockInstall(version)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	if _, err := m.downloadAndInstall(ctx, version); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
+
+// lockInstall takes the install lock for a version, a lock file next to its
+// directory in the install dir. It fails at once, rather than waiting, if
+// another gopher process is installing the same version or migrating the
+// install dir.
+func (m *Manager) lockInstall(version string) (func(), error) {
+	// #nosec G301 -- 0755 required for Go installation directory (needs to be executable)
+	if err := os.MkdirAll(m.config.InstallDir, 0755); err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeInstallationFailed, "failed to create install directory")
+	}
+
+	lockPath := m.installLockPath(version)
+	unlock, err := tryFileLock(lockPath)
+	if err == errLockHeld {
+		return nil, errors.Newf(errors.ErrCodeInstallationFailed,
+			"an install of %s is already in progress (remove %s if no other gopher process is running)", version, lockPath)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeInstallationFailed, "failed to lock install of %s", version)
+	}
+
+	// Migrate checks for install locks after taking its own, so one of the
+	// two always sees the other
+	migrateLock := filepath.Join(m.config.InstallDir, migrateLockFile)
+	if lockHeld(migrateLock) {
+		unlock()
+		return nil, errors.Newf(errors.ErrCodeInstallationFailed,
+			"the install directory is being migrated; try again when 'gopher migrate' finishes (remove %s if no other gopher process is running)", migrateLock)
+	}
+	return unlock, nil
+}
+
+// installLockPath returns the path of a version's install lock
+func (m *Manager) installLockPath(version string) string {
+	return filepath.Join(m.config.InstallDir, "."+version+".lock")
+}
+
+// downloadAndInstall downloads a version (reusing a valid cached archive) and
+// extracts it over any existing installation, recording the verified archive
+// checksum in the version metadata. The archive is removed afterwards unless
+// KeepDownloads is set. Callers hold the version's install lock.
+func (m *Manager) downloadAndInstall(ctx context.Context, version string) (InstallResult, error) {
+	result := InstallResult{Version: version}
+
 	// Ensure directories exist
 	if err := m.config.EnsureDirectories(); err != nil {
-		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to ensure directories")
+		return result, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to ensure directories")
+	}
+
+	// Do not start a download, or reuse a cached archive, once canceled
+	if err := ctx.Err(); err != nil {
+		return result, errors.NewDownloadFailed(version, err)
+	}
+
+	// Events from the downloader carry the version they belong to
+	if handler := m.onProgress; handler != nil {
+		ctx = progress.WithEvents(ctx, func(event progress.ProgressEvent) {
+			event.Version = version
+			handler(event)
+		})
 	}
 
 	// Download the version
-	filePath, err := m.downloader.Download(version, m.config.DownloadDir)
+	progress.Emit(ctx, progress.ProgressEvent{Stage: progress.StageResolving})
+	filePath, info, err := m.downloader.DownloadWithInfo(ctx, version, m.config.DownloadDir)
 	if err != nil {
-		return errors.NewDownloadFailed(version, err)
+		return result, errors.NewDownloadFailed(version, err)
+	}
+	result.FromCache, result.Downloaded = info.FromCache, !info.FromCache
+	if result.Downloaded {
+		if stat, err := os.Stat(filePath); err == nil {
+			result.BytesDownloaded = stat.Size()
+		}
 	}
 
-	// Install the version
-	if err := m.installer.Install(version, filePath); err != nil {
-		// Clean up downloaded file on failure (ignore errors on cleanup)
-		_ = m.downloader.Cleanup(filePath)
-		return errors.NewInstallationFailed(version, err)
+	// Note when an existing installation came from a different upstream archive
+	if metadata, err := m.installer.GetVersionMetadata(version); err == nil {
+		if previous := metadata["sha256"]; previous != "" && info.SHA256 != "" && previous != info.SHA256 {
+			log.Info("Note: upstream checksum for %s changed (was %s, now %s)", version, previous, info.SHA256)
+		}
 	}
 
-	// Clean up downloaded file
-	if err := m.downloader.Cleanup(filePath); err != nil {
-		// Log warning but don't fail the installation
-		fmt.Printf("Warning: failed to clean up downloaded file: %v\n", err)
+	// Install the version, recording the verified archive checksum
+	progress.Emit(ctx, progress.ProgressEvent{Stage: progress.StageExtracting})
+	if err := m.installer.InstallWithSHA256(version, filePath, info.SHA256); err != nil {
+		// Clean up downloaded file on failure (ignore errors on cleanup)
+		_ = m.downloader.Cleanup(filePath)
+		return result, errors.NewInstallationFailed(version, err)
 	}
+	result.OK = true
+	result.Path = filepath.Join(m.config.InstallDir, version)
 
-	// Auto-cleanup if enabled
-	if m.config.AutoCleanup {
-		if err := m.autoCleanup(); err != nil {
-			fmt.Printf("Warning: failed to auto-cleanup: %v\n", err)
+	// Keep the verified archive for reinstalls unless downloads are not kept
+	if !m.config.KeepDownloads {
+		if err := m.downloader.Cleanup(filePath); err != nil {
+			// Log warning but don't fail the installation
+			fmt.Fprintf(os.Stderr, "Warning: failed to clean up downloaded file: %v\n", err)
 		}
 	}
 
-	return nil
+	progress.Emit(ctx, progress.ProgressEvent{Stage: progress.StageDone})
+	return result, nil
+}
+
+// UninstallResult is the outcome of removing one version
+type UninstallResult struct {
+	Version    string `json:"version"`
+	Path       string `json:"path"`        // Removed installation directory
+	BytesFreed int64  `json:"bytes_freed"` // Size of the removed directory
 }
 
 // Uninstall removes a specific Go version.
@@ -98,20 +513,22 @@ func (m *Manager) Install(version string) error {
 // Parameters:
 //   - version: The Go version to uninstall (e.g., "1.21.0", "go1.21.0")
 //
-// Returns an error if the uninstallation fails.
+// Returns what was removed and how much space it freed, or an error if the
+// uninstallation fails.
 //
 // Example:
 //
-//	err := manager.Uninstall("1.21.0")
-func (m *Manager) Uninstall(version string) error {
+//	result, err := manager.Uninstall("1.21.0")
+//	fmt.Printf("freed %d bytes\n", result.BytesFreed)
+func (m *Manager) Uninstall(version string) (*UninstallResult, error) {
 	// Validate version format
 	if err := ValidateVersion(version); err != nil {
-		return fmt.Errorf("invalid version: %w", err)
+		return nil, fmt.Errorf("invalid version: %w", err)
 	}
 
 	// Validate version for security (path traversal protection)
 	if err := security.ValidatePath(version); err != nil {
-		return fmt.Errorf("invalid version: %w", err)
+		return nil, fmt.Errorf("invalid version: %w", err)
 	}
 
 	// Normalize version
@@ -120,18 +537,85 @@ func (m *Manager) Uninstall(version string) error {
 	// Check if installed
 	installed, err := m.IsInstalled(version)
 	if err != nil {
-		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to check if version is installed")
+		return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to check if version is installed")
 	}
 	if !installed {
-		return errors.NewVersionNotInstalled(version)
+		return nil, errors.NewVersionNotInstalled(version)
 	}
 
+	// Measure before removing; a size that cannot be read is reported as 0
+	size, _ := m.InstalledSize(version)
+
 	// Uninstall the version
 	if err := m.installer.Uninstall(version); err != nil {
-		return errors.Wrapf(err, errors.ErrCodeUninstallationFailed, "failed to uninstall version %s", version)
+		return nil, errors.Wrapf(err, errors.ErrCodeUninstallationFailed, "failed to uninstall version %s", version)
+	}
+
+	return &UninstallResult{
+		Version:    version,
+		Path:       filepath.Join(m.config.InstallDir, version),
+		BytesFreed: size,
+	}, nil
+}
+
+// UnusedVersions returns the installed versions that can be removed without
+// affecting the user: those that are not active (per the state file or the
+// go symlink) and that no alias points at. The result is sorted newest first.
+//
+// Example:
+//
+//	unused, err := manager.UnusedVersions()
+//	for _, version := range unused {
+//	    fmt.Println("unused:", version)
+//	}
+func (m *Manager) UnusedVersions() ([]string, error) {
+	versions, err := m.installer.ListInstalled()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installed versions: %w", err)
+	}
+
+	inUse := make(map[string]bool)
+	if active, err := m.getActiveVersionFromState(); err == nil {
+		inUse[active] = true
+	}
+	if active, err := m.getCurrentActiveVersion(); err == nil {
+		inUse[active] = true
+	}
+
+	aliases, err := m.aliasManager.ListAliases()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	for _, alias := range aliases {
+		inUse[alias.Version] = true
+	}
+
+	var unused []string
+	for _, version := range versions {
+		if !inUse[version] {
+			unused = append(unused, version)
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool {
+		return CompareVersions(unused[i], unused[j]) > 0
+	})
+
+	return unused, nil
+}
+
+// InstalledSize returns the disk space used by an installed version in bytes
+func (m *Manager) InstalledSize(version string) (int64, error) {
+	version = NormalizeVersion(version)
+	if !m.installer.IsInstalled(version) {
+		return 0, errors.NewVersionNotInstalled(version)
+	}
+
+	size, err := dirSize(filepath.Join(m.config.InstallDir, version))
+	if err != nil {
+		return 0, fmt.Errorf("failed to calculate size of %s: %w", version, err)
 	}
 
-	return nil
+	return size, nil
 }
 
 // IsInstalled checks if a Go version is currently installed.
@@ -1,9 +1,27 @@
 package runtime
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/downloader"
+	"github.com/molmedoz/gopher/internal/env"
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/installer"
+	"github.com/molmedoz/gopher/internal/progress"
 )
 
 func TestManager_Install_AlreadyInstalled(t *testing.T) {
@@ -20,7 +38,7 @@ func TestManager_Install_AlreadyInstalled(t *testing.T) {
 	writeMetadata(t, tmp, "go1.21.0")
 
 	// Try to install the same version
-	err := m.Install("go1.21.0")
+	_, err := m.Install(context.Background(), "go1.21.0")
 	if err == nil {
 		t.Fatal("expected error for already installed version")
 	}
@@ -31,12 +49,72 @@ func TestManager_Install_AlreadyInstalled(t *testing.T) {
 	}
 }
 
+func TestManager_Install_LockHeld(t *testing.T) {
+	tmp := t.TempDir()
+	m := createTestManager(t, tmp)
+
+	// Another process is installing the version
+	lockPath := m.installLockPath("go1.21.0")
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n", os.Getpid())), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, install := range map[string]func() error{
+		"install":   func() error { _, err := m.Install(context.Background(), "1.21.0"); return err },
+		"reinstall": func() error { _, err := m.Reinstall(context.Background(), "1.21.0"); return err },
+	} {
+		err := install()
+		if err == nil {
+			t.Fatalf("%s: expected an error while the install lock is held", name)
+		}
+		if !errors.IsErrorCode(err, errors.ErrCodeInstallationFailed) || !strings.Contains(err.Error(), "already in progress") {
+			t.Errorf("%s: expected an install in progress error, got %v", name, err)
+		}
+	}
+
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Errorf("the other process's lock should be left alone: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "go1.21.0")); !os.IsNotExist(err) {
+		t.Error("no version directory should be created while the lock is held")
+	}
+}
+
+func TestManager_Install_StaleLockAndRelease(t *testing.T) {
+	tmp := t.TempDir()
+	m := createTestManager(t, tmp)
+	writeMetadata(t, tmp, "go1.21.0")
+
+	// A lock left behind by a crashed install is broken
+	lockPath := m.installLockPath("go1.21.0")
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n", exitedPID(t))), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := m.Install(context.Background(), "1.21.0")
+	if !errors.IsErrorCode(err, errors.ErrCodeVersionAlreadyInstalled) {
+		t.Fatalf("expected the stale lock to be broken, got %v", err)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Error("install lock should be released when Install returns")
+	}
+
+	// The lock file is not mistaken for an installed version
+	versions, err := m.installer.ListInstalled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0] != "go1.21.0" {
+		t.Errorf("ListInstalled() = %v, want [go1.21.0]", versions)
+	}
+}
+
 func TestManager_Uninstall_NotInstalled(t *testing.T) {
 	tmp := t.TempDir()
 	m := createTestManager(t, tmp)
 
 	// Try to uninstall a version that's not installed
-	err := m.Uninstall("go1.21.0")
+	_, err := m.Uninstall("go1.21.0")
 	if err == nil {
 		t.Fatal("expected error for not installed version")
 	}
@@ -52,7 +130,7 @@ func TestManager_Uninstall_Flows(t *testing.T) {
 	m := createTestManager(t, tmp)
 
 	// Not installed path
-	if err := m.Uninstall("go1.2.3"); err == nil {
+	if _, err := m.Uninstall("go1.2.3"); err == nil {
 		t.Fatal("expected error for uninstalling non-existent version")
 	}
 
@@ -68,10 +146,14 @@ func TestManager_Uninstall_Flows(t *testing.T) {
 		t.Fatal("expected version to be installed")
 	}
 
-	// Uninstall should work
-	if err := m.Uninstall("go1.21.0"); err != nil {
+	// Uninstall should work and report what it removed
+	result, err := m.Uninstall("go1.21.0")
+	if err != nil {
 		t.Fatalf("uninstall failed: %v", err)
 	}
+	if result.Version != "go1.21.0" || result.Path != filepath.Join(tmp, "go1.21.0") || result.BytesFreed == 0 {
+		t.Errorf("unexpected uninstall result: %+v", result)
+	}
 
 	// Should no longer be installed
 	installed, err = m.IsInstalled("go1.21.0")
@@ -108,3 +190,590 @@ func TestManager_IsInstalled(t *testing.T) {
 		t.Fatal("expected version to be installed")
 	}
 }
+
+// writeCachedArchive writes a tar.gz for version into downloadDir under the
+// name the downloader looks for, and returns its SHA256
+func writeCachedArchive(t *testing.T, downloadDir, version string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	files := map[string]string{
+		"go/bin/go":  "#!/bin/sh\necho \"go version " + version + " linux/amd64\"\n",
+		"go/VERSION": version + "\n",
+	}
+	for name, data := range files {
+		hdr := &tar.Header{Name: name, Mode: 0755, Size: int64(len(data))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// #nosec G301 -- 0755 acceptable for test directory
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	filename := fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+	// #nosec G306 -- 0644 acceptable for test files
+	if err := os.WriteFile(filepath.Join(downloadDir, filename), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+func TestManager_InstallFromReader(t *testing.T) {
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, "versions")
+	m := createTestManager(t, installDir)
+	m.config.AutoCleanup = false
+
+	version := "go1.21.0"
+	sum := writeCachedArchive(t, tmp, version)
+	archive, err := os.Open(filepath.Join(tmp, fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+
+	if err := m.InstallFromReader(context.Background(), "1.21.0", archive, installer.ArchiveTarGz); err != nil {
+		t.Fatalf("InstallFromReader() error = %v", err)
+	}
+	if installed, _ := m.IsInstalled(version); !installed {
+		t.Fatal("version not installed")
+	}
+	if metadata, err := m.installer.GetVersionMetadata(version); err != nil || metadata["sha256"] != sum {
+		t.Errorf("recorded sha256 = %q, %v; want %q", metadata["sha256"], err, sum)
+	}
+	if _, err := os.Stat(m.installLockPath(version)); !os.IsNotExist(err) {
+		t.Error("install lock was not released")
+	}
+
+	err = m.InstallFromReader(context.Background(), version, strings.NewReader(""), installer.ArchiveTarGz)
+	if !errors.IsErrorCode(err, errors.ErrCodeVersionAlreadyInstalled) {
+		t.Errorf("InstallFromReader() of an installed version = %v, want VERSION_ALREADY_INSTALLED", err)
+	}
+}
+
+func TestManager_InstallFromFile(t *testing.T) {
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, "versions")
+	m := createTestManager(t, installDir)
+	m.config.AutoCleanup = false
+
+	// The version comes from the file name
+	sum := writeCachedArchive(t, tmp, "go1.21.0")
+	archive := filepath.Join(tmp, fmt.Sprintf("go1.21.0.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH))
+	result, err := m.InstallFromFile(context.Background(), archive, "")
+	if err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+	if result.Version != "go1.21.0" || !result.OK || result.Path != filepath.Join(installDir, "go1.21.0") {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if metadata, err := m.installer.GetVersionMetadata("go1.21.0"); err != nil || metadata["sha256"] != sum {
+		t.Errorf("recorded sha256 = %q, %v; want %q", metadata["sha256"], err, sum)
+	}
+
+	// A renamed archive needs the version
+	renamed := filepath.Join(tmp, "go.tar.gz")
+	if err := os.Rename(archive, renamed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.InstallFromFile(context.Background(), renamed, ""); !errors.IsErrorCode(err, errors.ErrCodeInvalidArgument) {
+		t.Errorf("InstallFromFile() without a version in the name = %v, want INVALID_ARGUMENT", err)
+	}
+	if _, err := m.InstallFromFile(context.Background(), renamed, "1.22.0"); err != nil {
+		t.Errorf("InstallFromFile() with a version = %v", err)
+	}
+	if installed, _ := m.IsInstalled("go1.22.0"); !installed {
+		t.Error("go1.22.0 not installed")
+	}
+}
+
+func TestManager_Reinstall_ReusesCachedArchiveAndKeepsAliases(t *testing.T) {
+	if runtime.GOOS == "windows" || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" && runtime.GOARCH != "386") {
+		t.Skip("test archive is a tar.gz for a standard platform")
+	}
+
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, "versions")
+	downloadDir := filepath.Join(tmp, "downloads")
+	version := "go1.21.0"
+	sum := writeCachedArchive(t, downloadDir, version)
+	filename := fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+
+	// The downloads page is the only request expected; the archive itself
+	// must come from the download cache
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			t.Errorf("unexpected request for %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `<table><tr><td><a class="download" href="/dl/%s">%s</a></td><td>0.0MB</td><td><tt>%s</tt></td></tr></table>`, filename, filename, sum)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		InstallDir:  installDir,
+		DownloadDir: downloadDir,
+		MirrorURL:   server.URL,
+	}
+	m := NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": "/usr/bin:/bin"}))
+
+	// Simulate a corrupt installation with an alias pointing at it
+	writeMetadata(t, installDir, version)
+	if err := m.AliasManager().CreateAlias("stable", version); err != nil {
+		t.Fatal(err)
+	}
+
+	reinstalled, err := m.Reinstall(context.Background(), "1.21.0")
+	if err != nil {
+		t.Fatalf("Reinstall error: %v", err)
+	}
+	if reinstalled != version {
+		t.Errorf("Reinstall returned %q, want %q", reinstalled, version)
+	}
+
+	result, err := m.Verify(version)
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	if !result.OK || result.SHA256 != sum {
+		t.Errorf("expected a verified reinstall recording the archive checksum, got %+v", result)
+	}
+
+	alias, ok := m.AliasManager().GetAlias("stable")
+	if !ok || alias.Version != version {
+		t.Errorf("expected alias 'stable' to still point at %s, got %v", version, alias)
+	}
+}
+
+func TestManager_Reinstall_KeepDownloads(t *testing.T) {
+	if runtime.GOOS == "windows" || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" && runtime.GOARCH != "386") {
+		t.Skip("test archive is a tar.gz for a standard platform")
+	}
+
+	for _, keep := range []bool{true, false} {
+		t.Run(fmt.Sprintf("keep=%t", keep), func(t *testing.T) {
+			tmp := t.TempDir()
+			downloadDir := filepath.Join(tmp, "downloads")
+			version := "go1.21.0"
+			sum := writeCachedArchive(t, downloadDir, version)
+			filename := fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `<table><tr><td><a class="download" href="/dl/%s">%s</a></td><td>0.0MB</td><td><tt>%s</tt></td></tr></table>`, filename, filename, sum)
+			}))
+			defer server.Close()
+
+			cfg := &config.Config{
+				InstallDir:    filepath.Join(tmp, "versions"),
+				DownloadDir:   downloadDir,
+				MirrorURL:     server.URL,
+				KeepDownloads: keep,
+			}
+			m := NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": "/usr/bin:/bin"}))
+			if _, err := m.Reinstall(context.Background(), version); err != nil {
+				t.Fatalf("Reinstall error: %v", err)
+			}
+
+			_, err := os.Stat(filepath.Join(downloadDir, filename))
+			if kept := err == nil; kept != keep {
+				t.Errorf("archive kept = %t, want %t", kept, keep)
+			}
+		})
+	}
+}
+
+func TestManager_Install_ReportsCachedArchive(t *testing.T) {
+	if runtime.GOOS == "windows" || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" && runtime.GOARCH != "386") {
+		t.Skip("test archive is a tar.gz for a standard platform")
+	}
+
+	tmp := t.TempDir()
+	downloadDir := filepath.Join(tmp, "downloads")
+	version := "go1.21.0"
+	sum := writeCachedArchive(t, downloadDir, version)
+	filename := fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<table><tr><td><a class="download" href="/dl/%s">%s</a></td><td>0.0MB</td><td><tt>%s</tt></td></tr></table>`, filename, filename, sum)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		InstallDir:  filepath.Join(tmp, "versions"),
+		DownloadDir: downloadDir,
+		MirrorURL:   server.URL,
+	}
+	m := NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": "/usr/bin:/bin"}))
+
+	result, err := m.Install(context.Background(), "1.21.0")
+	if err != nil {
+		t.Fatalf("Install error: %v", err)
+	}
+	if result.Version != version || result.Path != filepath.Join(cfg.InstallDir, version) {
+		t.Errorf("unexpected install result: %+v", result)
+	}
+	if !result.FromCache || result.Downloaded || result.BytesDownloaded != 0 {
+		t.Errorf("expected the archive to be reported as cached, got %+v", result)
+	}
+}
+
+func TestManager_Install_Hooks(t *testing.T) {
+	if runtime.GOOS == "windows" || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" && runtime.GOARCH != "386") {
+		t.Skip("test archive is a tar.gz for a standard platform and hooks are sh commands")
+	}
+
+	tests := []struct {
+		name          string
+		preInstall    string
+		postInstall   string
+		strict        bool
+		wantErr       bool
+		wantInstalled bool
+		wantLog       string
+	}{
+		{
+			name:          "both hooks run",
+			preInstall:    `echo "pre $GOPHER_VERSION" >> "$HOOK_LOG"`,
+			postInstall:   `echo "post $GOPHER_VERSION $GOROOT" >> "$HOOK_LOG"`,
+			wantInstalled: true,
+			wantLog:       "pre go1.21.0\npost go1.21.0 {root}\n",
+		},
+		{
+			name:          "failing post_install keeps the version",
+			postInstall:   "exit 3",
+			wantInstalled: true,
+		},
+		{
+			name:        "failing post_install with strict_hooks removes the version",
+			postInstall: "exit 3",
+			strict:      true,
+			wantErr:     true,
+		},
+		{
+			name:        "failing pre_install aborts",
+			preInstall:  "exit 1",
+			postInstall: `echo post >> "$HOOK_LOG"`,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			downloadDir := filepath.Join(tmp, "downloads")
+			version := "go1.21.0"
+			sum := writeCachedArchive(t, downloadDir, version)
+			filename := fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `<table><tr><td><a class="download" href="/dl/%s">%s</a></td><td>0.0MB</td><td><tt>%s</tt></td></tr></table>`, filename, filename, sum)
+			}))
+			defer server.Close()
+
+			hookLog := filepath.Join(tmp, "hooks.log")
+			t.Setenv("HOOK_LOG", hookLog)
+			cfg := &config.Config{
+				InstallDir:     filepath.Join(tmp, "versions"),
+				DownloadDir:    downloadDir,
+				MirrorURL:      server.URL,
+				SetEnvironment: true,
+				GOPATHMode:     "shared",
+				GOBINMode:      "gopath-bin",
+				PreInstall:     tt.preInstall,
+				PostInstall:    tt.postInstall,
+				StrictHooks:    tt.strict,
+			}
+			m := NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": "/usr/bin:/bin", "HOME": tmp}))
+
+			result, err := m.Install(context.Background(), "1.21.0")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Install error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if installed, _ := m.IsInstalled(version); installed != tt.wantInstalled {
+				t.Errorf("installed = %v, want %v", installed, tt.wantInstalled)
+			}
+			if err == nil && (result.PostInstallError != "") != (tt.postInstall == "exit 3") {
+				t.Errorf("unexpected post_install error %q", result.PostInstallError)
+			}
+			if err == nil && tt.postInstall == "exit 3" && !strings.Contains(result.PostInstallError, "status 3") {
+				t.Errorf("post_install error %q should report the exit status", result.PostInstallError)
+			}
+
+			logged, _ := os.ReadFile(hookLog)
+			want := strings.ReplaceAll(tt.wantLog, "{root}", filepath.Join(cfg.InstallDir, version))
+			if string(logged) != want {
+				t.Errorf("hook log = %q, want %q", logged, want)
+			}
+		})
+	}
+}
+
+func TestManager_InstallFromFile_Hooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are sh commands")
+	}
+	tmp := t.TempDir()
+	m := createTestManager(t, filepath.Join(tmp, "versions"))
+	m.config.AutoCleanup = false
+	hookLog := filepath.Join(tmp, "hooks.log")
+	t.Setenv("HOOK_LOG", hookLog)
+	m.config.PreInstall = `echo "pre $GOPHER_VERSION" >> "$HOOK_LOG"`
+	m.config.PostInstall = `echo "post $GOPHER_VERSION" >> "$HOOK_LOG"; exit 3`
+
+	writeCachedArchive(t, tmp, "go1.21.0")
+	archive := filepath.Join(tmp, fmt.Sprintf("go1.21.0.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH))
+
+	// A failing post_install keeps the version and is reported
+	result, err := m.InstallFromFile(context.Background(), archive, "")
+	if err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+	if !strings.Contains(result.PostInstallError, "status 3") {
+		t.Errorf("PostInstallError = %q, want the hook's exit status", result.PostInstallError)
+	}
+	if logged, _ := os.ReadFile(hookLog); string(logged) != "pre go1.21.0\npost go1.21.0\n" {
+		t.Errorf("hook log = %q", logged)
+	}
+
+	// With strict_hooks it removes the version again
+	m.config.StrictHooks = true
+	if _, err := m.InstallFromFile(context.Background(), archive, "1.22.0"); !errors.IsErrorCode(err, errors.ErrCodeInstallationFailed) {
+		t.Errorf("InstallFromFile() with a failing strict post_install = %v, want INSTALLATION_FAILED", err)
+	}
+	if installed, _ := m.IsInstalled("go1.22.0"); installed {
+		t.Error("go1.22.0 kept after its strict post_install hook failed")
+	}
+
+	// A failing pre_install aborts before anything is extracted
+	m.config.PreInstall = "exit 1"
+	if _, err := m.InstallFromFile(context.Background(), archive, "1.23.0"); err == nil {
+		t.Error("InstallFromFile() should fail when pre_install fails")
+	}
+	if _, err := os.Stat(filepath.Join(m.config.InstallDir, "go1.23.0")); !os.IsNotExist(err) {
+		t.Errorf("go1.23.0 extracted despite the failing pre_install (err: %v)", err)
+	}
+}
+
+func TestManager_Install_ProgressEvents(t *testing.T) {
+	if runtime.GOOS == "windows" || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" && runtime.GOARCH != "386") {
+		t.Skip("test archive is a tar.gz for a standard platform")
+	}
+
+	tmp := t.TempDir()
+	version := "go1.21.0"
+	archiveDir := filepath.Join(tmp, "archive")
+	sum := writeCachedArchive(t, archiveDir, version)
+	filename := fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+	archive, err := os.ReadFile(filepath.Join(archiveDir, filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `<table><tr><td><a class="download" href="/dl/%s">%s</a></td><td>0.0MB</td><td><tt>%s</tt></td></tr></table>`, filename, filename, sum)
+		case "/" + filename:
+			_, _ = w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		InstallDir:  filepath.Join(tmp, "versions"),
+		DownloadDir: filepath.Join(tmp, "downloads"),
+		MirrorURL:   server.URL,
+	}
+	m := NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": "/usr/bin:/bin"}))
+	m.SetProgressSink(downloader.NoopProgressSink{})
+
+	var stages []progress.Stage
+	var downloaded int64
+	m.SetProgressHandler(func(event progress.ProgressEvent) {
+		if event.Version != version {
+			t.Errorf("event for %q, want %q", event.Version, version)
+		}
+		if event.Stage == progress.StageDownloading {
+			downloaded = event.Done
+			if len(stages) > 0 && stages[len(stages)-1] == progress.StageDownloading {
+				return
+			}
+		}
+		stages = append(stages, event.Stage)
+	})
+
+	if _, err := m.Install(context.Background(), "1.21.0"); err != nil {
+		t.Fatalf("Install error: %v", err)
+	}
+
+	want := []progress.Stage{progress.StageResolving, progress.StageDownloading, progress.StageVerifying, progress.StageExtracting, progress.StageDone}
+	if fmt.Sprint(stages) != fmt.Sprint(want) {
+		t.Errorf("stages = %v, want %v", stages, want)
+	}
+	if downloaded != int64(len(archive)) {
+		t.Errorf("last download event reported %d bytes, want %d", downloaded, len(archive))
+	}
+}
+
+func TestManager_UnusedVersions(t *testing.T) {
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, "versions")
+	m := createTestManager(t, installDir)
+
+	for _, v := range []string{"go1.9.0", "go1.20.0", "go1.21.0", "go1.22.0"} {
+		writeMetadata(t, installDir, v)
+	}
+	if err := m.AliasManager().CreateAlias("stable", "go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.saveActiveVersion("go1.22.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	unused, err := m.UnusedVersions()
+	if err != nil {
+		t.Fatalf("UnusedVersions error: %v", err)
+	}
+	if len(unused) != 2 || unused[0] != "go1.20.0" || unused[1] != "go1.9.0" {
+		t.Fatalf("UnusedVersions = %v, want [go1.20.0 go1.9.0]", unused)
+	}
+
+	size, err := m.InstalledSize("go1.20.0")
+	if err != nil {
+		t.Fatalf("InstalledSize error: %v", err)
+	}
+	if size <= 0 {
+		t.Errorf("InstalledSize = %d, want > 0", size)
+	}
+	if _, err := m.InstalledSize("go1.1.0"); err == nil {
+		t.Error("expected error for a version that is not installed")
+	}
+}
+
+func TestManager_Install_AutoCleanupKeepsInstalledVersion(t *testing.T) {
+	if runtime.GOOS == "windows" || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" && runtime.GOARCH != "386") {
+		t.Skip("test archive is a tar.gz for a standard platform")
+	}
+
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, "versions")
+	downloadDir := filepath.Join(tmp, "downloads")
+	version := "go1.20.0"
+	sum := writeCachedArchive(t, downloadDir, version)
+	filename := fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<table><tr><td><a class="download" href="/dl/%s">%s</a></td><td>0.0MB</td><td><tt>%s</tt></td></tr></table>`, filename, filename, sum)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		InstallDir:  installDir,
+		DownloadDir: downloadDir,
+		MirrorURL:   server.URL,
+		AutoCleanup: true,
+		MaxVersions: 1,
+	}
+	m := NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": "/usr/bin:/bin"}))
+	writeMetadata(t, installDir, "go1.21.0")
+
+	// go1.20.0 sorts before go1.21.0, so it would be the first to go
+	if _, err := m.Install(context.Background(), "1.20.0"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if ok, _ := m.IsInstalled(version); !ok {
+		t.Error("auto-cleanup removed the version that was just installed")
+	}
+	if ok, _ := m.IsInstalled("go1.21.0"); ok {
+		t.Error("go1.21.0 should have been removed by auto-cleanup")
+	}
+}
+
+func TestManager_InstallMany(t *testing.T) {
+	if runtime.GOOS == "windows" || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" && runtime.GOARCH != "386") {
+		t.Skip("test archive is a tar.gz for a standard platform")
+	}
+
+	tmp := t.TempDir()
+	installDir := filepath.Join(tmp, "versions")
+	downloadDir := filepath.Join(tmp, "downloads")
+
+	var rows bytes.Buffer
+	for _, version := range []string{"go1.21.0", "go1.22.0"} {
+		sum := writeCachedArchive(t, downloadDir, version)
+		filename := fmt.Sprintf("%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+		fmt.Fprintf(&rows, `<tr><td><a class="download" href="/dl/%s">%s</a></td><td>0.0MB</td><td><tt>%s</tt></td></tr>`, filename, filename, sum)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<table>%s</table>", rows.String())
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		InstallDir:  installDir,
+		DownloadDir: downloadDir,
+		MirrorURL:   server.URL,
+		AutoCleanup: true,
+		MaxVersions: 2,
+	}
+	m := NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": "/usr/bin:/bin"}))
+	writeMetadata(t, installDir, "go1.19.0")
+	writeMetadata(t, installDir, "go1.20.0")
+
+	var done []string
+	results := m.InstallMany(context.Background(), []string{"1.21.0", "1.22.0", "go1.21.0", "1.20.0", "not-a-version"}, 2, true, func(r InstallResult) {
+		done = append(done, r.Version)
+	})
+
+	if len(results) != 4 || len(done) != 4 {
+		t.Fatalf("expected 4 results (duplicate dropped), got %d results, %d callbacks: %+v", len(results), len(done), results)
+	}
+	for i, want := range []string{"go1.21.0", "go1.22.0", "go1.20.0"} {
+		if results[i].Version != want || !results[i].OK {
+			t.Errorf("result %d = %+v, want %s installed", i, results[i], want)
+		}
+	}
+	if !results[2].AlreadyInstalled {
+		t.Errorf("go1.20.0 should be reported as already installed: %+v", results[2])
+	}
+	if results[3].OK || results[3].Error == "" {
+		t.Errorf("invalid version should fail: %+v", results[3])
+	}
+
+	// Auto-cleanup ran once and kept every version of the batch
+	for _, version := range []string{"go1.20.0", "go1.21.0", "go1.22.0"} {
+		if ok, _ := m.IsInstalled(version); !ok {
+			t.Errorf("%s should still be installed", version)
+		}
+	}
+	if ok, _ := m.IsInstalled("go1.19.0"); ok {
+		t.Error("go1.19.0 should have been removed by auto-cleanup")
+	}
+
+	// Without keepGoing, the versions after a failure are skipped
+	if _, err := m.Uninstall("go1.22.0"); err != nil {
+		t.Fatal(err)
+	}
+	results = m.InstallMany(context.Background(), []string{"not-a-version", "1.22.0"}, 1, false, nil)
+	if len(results) != 2 || results[0].OK || !results[1].Skipped || results[1].OK {
+		t.Fatalf("expected the failure to skip go1.22.0, got %+v", results)
+	}
+	if ok, _ := m.IsInstalled("go1.22.0"); ok {
+		t.Error("go1.22.0 should not have been installed after the failure")
+	}
+}
@@ -92,9 +92,37 @@ func (m *Manager) ListInstalled() ([]Version, error) {
 		seenVersions[version.Version] = true
 	}
 
+	Versions(result).Sort()
 	return result, nil
 }
 
+// AddDiskUsage sets Size on each Gopher-managed version to the disk space
+// its installation directory uses, and returns the total. System versions
+// are not managed by Gopher and are left at zero.
+//
+// Each directory is walked, so this is only done when asked for (e.g.
+// 'gopher list --size').
+//
+// Example:
+//
+//	versions, _ := manager.ListInstalled()
+//	total, err := manager.AddDiskUsage(versions)
+func (m *Manager) AddDiskUsage(versions []Version) (int64, error) {
+	var total int64
+	for i := range versions {
+		if versions[i].IsSystem {
+			continue
+		}
+		size, err := dirSize(filepath.Join(m.config.InstallDir, versions[i].Version))
+		if err != nil {
+			return 0, fmt.Errorf("failed to measure %s: %w", versions[i].Version, err)
+		}
+		versions[i].Size = size
+		total += size
+	}
+	return total, nil
+}
+
 // detectSystemVersionRobust tries multiple methods to detect system Go version
 func (m *Manager) detectSystemVersionRobust() *Version {
 	// Method 1: Check common system Go locations directly (bypass PATH entirely)
@@ -108,6 +136,8 @@ func (m *Manager) detectSystemVersionRobust() *Version {
 			"/opt/go/bin/go",
 			"/usr/bin/go",
 			"/usr/local/bin/go", // Common on some Linux distros
+			"/snap/bin/go",
+			"/run/current-system/sw/bin/go", // NixOS
 		}
 	case "darwin":
 		systemGoPaths = []string{
@@ -256,14 +286,17 @@ func findGoInPath() (string, error) {
 
 // runGoVersionAtPath runs '<path> version' with a short timeout and returns stdout.
 func runGoVersionAtPath(goPath string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	cmd := exec.CommandContext(ctx, goPath, "version")
-	return cmd.Output()
+	return runGoAtPath(goPath, "version")
+}
+
+// runGoAtPath runs the go binary at goPath with args and a short timeout
+func runGoAtPath(goPath string, args ...string) ([]byte, error) {
+	return runDetectionCommand(goPath, args...)
 }
 
-// ListAvailable returns all available Go versions from official releases
-func (m *Manager) ListAvailable() ([]downloader.VersionInfo, error) {
+// ListAvailable returns all available Go versions from official releases.
+// The request is canceled with ctx.
+func (m *Manager) ListAvailable(ctx context.Context) ([]downloader.VersionInfo, error) {
 	// Fetch from the Go releases API
-	return m.downloader.ListAvailableVersions()
+	return m.downloader.ListAvailableVersions(ctx)
 }
@@ -1,8 +1,10 @@
 package runtime
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -125,7 +127,7 @@ func TestManager_ListAvailable(t *testing.T) {
 
 	// This test may fail if there's no internet connection
 	// We'll just check that it doesn't panic
-	versions, err := m.ListAvailable()
+	versions, err := m.ListAvailable(context.Background())
 	if err != nil {
 		t.Logf("ListAvailable failed (expected if no internet): %v", err)
 		return
@@ -137,3 +139,97 @@ func TestManager_ListAvailable(t *testing.T) {
 		t.Logf("Found %d available versions", len(versions))
 	}
 }
+
+func TestManager_ListInstalled_SortedNewestFirst(t *testing.T) {
+	tmp := t.TempDir()
+	m := createTestManager(t, tmp)
+
+	for _, v := range []string{"go1.9.0", "go1.21.0", "go1.21rc2", "go1.10.8", "go1.21.10"} {
+		writeMetadata(t, tmp, v)
+	}
+
+	got, err := m.ListInstalled()
+	if err != nil {
+		t.Fatalf("ListInstalled error: %v", err)
+	}
+
+	var managed []string
+	for _, v := range got {
+		if !v.IsSystem {
+			managed = append(managed, v.Version)
+		}
+	}
+	want := []string{"go1.21.10", "go1.21.0", "go1.21rc2", "go1.10.8", "go1.9.0"}
+	if strings.Join(managed, ",") != strings.Join(want, ",") {
+		t.Errorf("ListInstalled order = %v, want %v", managed, want)
+	}
+}
+
+func TestVersions_Sort(t *testing.T) {
+	versions := Versions{
+		{Version: "go1.9.0"},
+		{Version: "go1.21.0"},
+		{Version: "go1.22.1", IsSystem: true},
+		{Version: "go1.23.0"},
+	}
+	versions.Sort()
+
+	want := []string{"go1.22.1", "go1.23.0", "go1.21.0", "go1.9.0"}
+	for i, v := range versions {
+		if v.Version != want[i] {
+			t.Fatalf("position %d = %s, want %s (system first, then newest first)", i, v.Version, want[i])
+		}
+	}
+
+	if CompareVersions("go1.9.0", "go1.21.0") >= 0 {
+		t.Error("go1.9.0 should sort before go1.21.0")
+	}
+}
+
+func TestManager_AddDiskUsage(t *testing.T) {
+	tmp := t.TempDir()
+	m := createTestManager(t, tmp)
+
+	writeMetadata(t, tmp, "go1.21.0")
+	if err := os.MkdirAll(filepath.Join(tmp, "go1.21.0", "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "go1.21.0", "bin", "go"), make([]byte, 1000), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeMetadata(t, tmp, "go1.22.0")
+
+	versions := []Version{
+		{Version: "go1.20.0", IsSystem: true, Path: "/usr/local/go/bin/go"},
+		{Version: "go1.21.0"},
+		{Version: "go1.22.0"},
+	}
+	total, err := m.AddDiskUsage(versions)
+	if err != nil {
+		t.Fatalf("AddDiskUsage() error = %v", err)
+	}
+
+	metadataSize, err := dirSize(filepath.Join(tmp, "go1.22.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if versions[0].Size != 0 {
+		t.Errorf("system version size = %d, want 0", versions[0].Size)
+	}
+	if versions[1].Size != 1000+metadataSize {
+		t.Errorf("go1.21.0 size = %d, want %d", versions[1].Size, 1000+metadataSize)
+	}
+	if versions[2].Size != metadataSize {
+		t.Errorf("go1.22.0 size = %d, want %d", versions[2].Size, metadataSize)
+	}
+	if total != versions[1].Size+versions[2].Size {
+		t.Errorf("total = %d, want %d", total, versions[1].Size+versions[2].Size)
+	}
+}
+
+func TestManager_AddDiskUsage_MissingDirectory(t *testing.T) {
+	m := createTestManager(t, t.TempDir())
+	if _, err := m.AddDiskUsage([]Version{{Version: "go1.21.0"}}); err == nil {
+		t.Error("AddDiskUsage() should fail for a version without a directory")
+	}
+}
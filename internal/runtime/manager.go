@@ -27,10 +27,10 @@
 //	manager := NewManager(cfg, envProvider)
 //
 //	// Install a Go version
-//	err := manager.Install("1.21.0")
+//	_, err := manager.Install(context.Background(), "1.21.0")
 //
 //	// Switch to the installed version
-//	err = manager.Use("1.21.0")
+//	_, err = manager.Use("1.21.0")
 //
 //	// Create an alias
 //	err = manager.AliasManager().CreateAlias("stable", "1.21.0")
@@ -39,6 +39,7 @@
 package runtime
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -47,6 +48,9 @@ import (
 	"time"
 
 	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/downloader"
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/progress"
 )
 
 // ============================================================================
@@ -73,31 +77,98 @@ func (m *Manager) AliasManager() *AliasManager {
 	return m.aliasManager
 }
 
+// SetProgressSink sets where download progress is reported. Pass
+// downloader.NoopProgressSink{} to keep output free of progress bars.
+func (m *Manager) SetProgressSink(sink downloader.ProgressSink) {
+	m.downloader.SetProgressSink(sink)
+}
+
+// SetProgressHandler sets a function that receives typed events as versions
+// are installed: resolving, downloading (with bytes), verifying, extracting
+// and done. It is meant for embedders that render progress themselves; the
+// terminal output is unchanged, so combine it with SetProgressSink to silence
+// the progress bar. The handler is called from the installing goroutine and
+// must be safe for concurrent use with InstallMany. Pass nil to stop events.
+//
+// Example:
+//
+//	events := make(chan progress.ProgressEvent, 64)
+//	manager.SetProgressHandler(func(event progress.ProgressEvent) { events <- event })
+func (m *Manager) SetProgressHandler(handler func(progress.ProgressEvent)) {
+	m.onProgress = handler
+}
+
+// SetChecksum sets the SHA256 that the next downloads are verified against
+// instead of the published checksum; see downloader.Downloader.SetChecksum.
+func (m *Manager) SetChecksum(sha256 string) error {
+	if err := m.downloader.SetChecksum(sha256); err != nil {
+		return errors.Wrapf(err, errors.ErrCodeInvalidArgument, "invalid --checksum")
+	}
+	return nil
+}
+
+// SetSkipChecksum allows installing archives that have no checksum to
+// verify against; see downloader.Downloader.SetSkipChecksum.
+func (m *Manager) SetSkipChecksum(skip bool) {
+	m.downloader.SetSkipChecksum(skip)
+}
+
+// SetNoHooks skips the hooks in the config file (pre_install, post_install,
+// post_use) for the operations that follow.
+func (m *Manager) SetNoHooks(noHooks bool) {
+	m.noHooks = noHooks
+}
+
+// SetOffline keeps the manager off the network: the version list comes from
+// the copy cached by the last online listing and installs use cached
+// archives only; see downloader.Downloader.SetOffline.
+func (m *Manager) SetOffline(offline bool) {
+	m.downloader.SetOffline(offline)
+}
+
+// CheckMirror reports whether the configured mirror can be reached; see
+// downloader.Downloader.CheckMirror.
+func (m *Manager) CheckMirror(ctx context.Context) error {
+	if err := m.downloader.CheckMirror(ctx); err != nil {
+		return errors.Wrapf(err, errors.ErrCodeNetworkUnavailable, "download mirror check failed")
+	}
+	return nil
+}
+
 // ============================================================================
 // Utility Methods
 // ============================================================================
 
 // getCurrentActiveVersion determines which version is currently active by checking symlinks.
 func (m *Manager) getCurrentActiveVersion() (string, error) {
-	// Define potential symlink paths
+	// Define potential symlink paths, starting with the configured one
 	var symlinkPaths []string
+	if symlinkPath, err := m.gopherSymlinkPath(); err == nil {
+		symlinkPaths = append(symlinkPaths, symlinkPath)
+	}
 	if runtime.GOOS == "windows" {
-		symlinkPaths = []string{
+		symlinkPaths = append(symlinkPaths,
 			filepath.Join(m.envProvider.Getenv("LOCALAPPDATA"), "gopher", "bin", "go.exe"),
-		}
+		)
 	} else {
-		symlinkPaths = []string{
+		symlinkPaths = append(symlinkPaths,
 			"/usr/local/bin/go",
 			filepath.Join(m.envProvider.Getenv("HOME"), ".local", "bin", "go"),
 			"/opt/gopher/bin/go",
 			"/usr/bin/go",
 			"/opt/gopher/go",
-		}
+		)
 	}
 
 	// Check each symlink path
 	for _, symlinkPath := range symlinkPaths {
 		if target, err := os.Readlink(symlinkPath); err == nil {
+			if isHomebrewGoPath(target) {
+				if m.isDirectoryInPath(filepath.Dir(symlinkPath)) && m.isSymlinkActuallyUsed(symlinkPath) {
+					return HomebrewVersion, nil
+				}
+				continue
+			}
 			if version := m.extractVersionFromPath(target); version != "" {
 				// Verify this is actually a gopher-managed version
 				if m.installer.IsInstalled(version) {
@@ -222,22 +293,52 @@ func (m *Manager) getVersionInfo(version string) (*Version, error) {
 		}
 	}
 
-	// Use metadata from file
+	// Versions are installed for the native platform unless the metadata
+	// says otherwise
+	goos, goarch := runtime.GOOS, runtime.GOARCH
+	if value := metadata["os"]; value != "" {
+		goos = value
+	}
+	if value := metadata["arch"]; value != "" {
+		goarch = value
+	}
+
+	// Use metadata from file (sha256 is absent for versions installed
+	// before checksums were recorded)
 	return &Version{
 		Version:     version,
-		OS:          runtime.GOOS,
-		Arch:        runtime.GOARCH,
+		OS:          goos,
+		Arch:        goarch,
 		InstalledAt: installedAt,
 		IsActive:    false,
 		IsSystem:    false,
 		Path:        filepath.Join(m.config.InstallDir, version),
+		SHA256:      metadata["sha256"],
 	}, nil
 }
 
+// checkPlatform returns an error if version was installed for a different
+// OS or architecture than the one Gopher is running on, since its go binary
+// could not run here
+func (m *Manager) checkPlatform(version string) error {
+	info, err := m.getVersionInfo(version)
+	if err != nil {
+		// Missing binaries and the like are reported by the caller
+		return nil
+	}
+	if !info.IsCompatible() {
+		return errors.Newf(errors.ErrCodePlatformMismatch,
+			"%s was installed for %s/%s and cannot run on %s/%s; reinstall it with 'gopher reinstall %s'",
+			version, info.OS, info.Arch, runtime.GOOS, runtime.GOARCH, version)
+	}
+	return nil
+}
+
 // autoCleanup removes old versions if the configured limit is exceeded.
 //
-// It keeps only the most recent versions up to the MaxVersions limit.
-func (m *Manager) autoCleanup() error {
+// It keeps only the most recent versions up to the MaxVersions limit. The
+// versions in keep are never removed.
+func (m *Manager) autoCleanup(keep []string) error {
 	versions, err := m.installer.ListInstalled()
 	if err != nil {
 		return fmt.Errorf("failed to list installed versions: %w", err)
@@ -247,12 +348,21 @@ func (m *Manager) autoCleanup() error {
 		return nil
 	}
 
+	kept := make(map[string]bool, len(keep))
+	for _, version := range keep {
+		kept[version] = true
+	}
+
 	// Keep only the most recent versions
 	toRemove := len(versions) - m.config.MaxVersions
-	for i := 0; i < toRemove; i++ {
-		if err := m.Uninstall(versions[i]); err != nil {
+	for i := 0; i < len(versions) && toRemove > 0; i++ {
+		if kept[versions[i]] {
+			continue
+		}
+		if _, err := m.Uninstall(versions[i]); err != nil {
 			return fmt.Errorf("failed to cleanup version %s: %w", versions[i], err)
 		}
+		toRemove--
 	}
 
 	return nil
@@ -284,16 +394,7 @@ func (m *Manager) Clean() (int64, error) {
 	}
 
 	// Calculate total size before cleanup
-	var totalSize int64
-	err := filepath.Walk(downloadDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if !info.IsDir() {
-			totalSize += info.Size()
-		}
-		return nil
-	})
+	totalSize, err := dirSize(downloadDir)
 	if err != nil {
 		return 0, fmt.Errorf("failed to calculate download cache size: %w", err)
 	}
@@ -363,21 +464,24 @@ func (m *Manager) Purge() error {
 // removeSymlinks attempts to remove Gopher-created symlinks (best effort)
 func (m *Manager) removeSymlinks() {
 	var symlinkPaths []string
+	if symlinkPath, err := m.gopherSymlinkPath(); err == nil {
+		symlinkPaths = append(symlinkPaths, symlinkPath)
+	}
 
 	if runtime.GOOS == "windows" {
 		localAppData := m.envProvider.Getenv("LOCALAPPDATA")
 		if localAppData != "" {
-			symlinkPaths = []string{
+			symlinkPaths = append(symlinkPaths,
 				filepath.Join(localAppData, "gopher", "bin", "go.exe"),
-			}
+			)
 		}
 	} else {
 		homeDir := m.envProvider.Getenv("HOME")
-		symlinkPaths = []string{
+		symlinkPaths = append(symlinkPaths,
 			"/usr/local/bin/go",
 			filepath.Join(homeDir, ".local", "bin", "go"),
 			filepath.Join(homeDir, "bin", "go"),
-		}
+		)
 	}
 
 	for _, symlinkPath := range symlinkPaths {
@@ -389,7 +493,7 @@ func (m *Manager) removeSymlinks() {
 					// It's a Gopher symlink, remove it
 					if rerr := os.Remove(symlinkPath); rerr != nil && !os.IsNotExist(rerr) {
 						// Log but don't fail - cleanup is best effort
-						fmt.Printf("Warning: failed to remove symlink %s: %v\n", symlinkPath, rerr)
+						fmt.Fprintf(os.Stderr, "Warning: failed to remove symlink %s: %v\n", symlinkPath, rerr)
 					}
 				}
 			}
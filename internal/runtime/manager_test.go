@@ -1,12 +1,16 @@
 package runtime
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/molmedoz/gopher/internal/config"
 	"github.com/molmedoz/gopher/internal/env"
+	"github.com/molmedoz/gopher/internal/errors"
 )
 
 // TestManager_Install_Comprehensive tests the Install method comprehensively
@@ -22,19 +26,19 @@ func TestManager_Install_Comprehensive(t *testing.T) {
 	manager := NewManager(cfg, envProvider)
 
 	// Test installing invalid version
-	err := manager.Install("invalid-version")
+	_, err := manager.Install(context.Background(), "invalid-version")
 	if err == nil {
 		t.Error("Expected error when installing invalid version")
 	}
 
 	// Test installing empty version
-	err = manager.Install("")
+	_, err = manager.Install(context.Background(), "")
 	if err == nil {
 		t.Error("Expected error when installing empty version")
 	}
 
 	// Test installing version with invalid format
-	err = manager.Install("1.21.0") // Missing 'go' prefix
+	_, err = manager.Install(context.Background(), "1.21.0") // Missing 'go' prefix
 	if err == nil {
 		t.Error("Expected error when installing version without 'go' prefix")
 	}
@@ -53,19 +57,19 @@ func TestManager_Uninstall_Comprehensive(t *testing.T) {
 	manager := NewManager(cfg, envProvider)
 
 	// Test uninstalling invalid version
-	err := manager.Uninstall("invalid-version")
+	_, err := manager.Uninstall("invalid-version")
 	if err == nil {
 		t.Error("Expected error when uninstalling invalid version")
 	}
 
 	// Test uninstalling empty version
-	err = manager.Uninstall("")
+	_, err = manager.Uninstall("")
 	if err == nil {
 		t.Error("Expected error when uninstalling empty version")
 	}
 
 	// Test uninstalling non-existent version
-	err = manager.Uninstall("go1.21.0")
+	_, err = manager.Uninstall("go1.21.0")
 	if err == nil {
 		t.Error("Expected error when uninstalling non-existent version")
 	}
@@ -84,24 +88,150 @@ func TestManager_Use_Comprehensive(t *testing.T) {
 	manager := NewManager(cfg, envProvider)
 
 	// Test using invalid version
-	err := manager.Use("invalid-version")
+	_, err := manager.Use("invalid-version")
 	if err == nil {
 		t.Error("Expected error when using invalid version")
 	}
 
 	// Test using empty version
-	err = manager.Use("")
+	_, err = manager.Use("")
 	if err == nil {
 		t.Error("Expected error when using empty version")
 	}
 
 	// Test using non-existent version
-	err = manager.Use("go1.21.0")
+	_, err = manager.Use("go1.21.0")
 	if err == nil {
 		t.Error("Expected error when using non-existent version")
 	}
 }
 
+// TestManager_Use_PlatformMismatch tests that a version installed for another
+// platform is not activated
+func TestManager_Use_PlatformMismatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	installDir := filepath.Join(tmpDir, "install")
+	manager := createTestManager(t, installDir)
+
+	foreignArch := "arm64"
+	if runtime.GOARCH == "arm64" {
+		foreignArch = "amd64"
+	}
+	versionDir := filepath.Join(installDir, "go1.21.0")
+	writeFakeGoBinary(t, installDir, "go1.21.0", "go1.21.0")
+	content := "version=go1.21.0\nos=" + runtime.GOOS + "\narch=" + foreignArch + "\n"
+	// #nosec G306 -- 0644 acceptable for test files
+	if err := os.WriteFile(filepath.Join(versionDir, ".gopher-metadata"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := manager.Use("go1.21.0")
+	if !errors.IsErrorCode(err, errors.ErrCodePlatformMismatch) {
+		t.Fatalf("expected PLATFORM_MISMATCH error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), foreignArch) {
+		t.Errorf("error should name the installed platform: %v", err)
+	}
+
+	if _, _, err := manager.ExecEnvironment("go1.21.0"); !errors.IsErrorCode(err, errors.ErrCodePlatformMismatch) {
+		t.Errorf("expected PLATFORM_MISMATCH from ExecEnvironment, got %v", err)
+	}
+
+	// Native installs are unaffected
+	writeMetadata(t, installDir, "go1.22.0")
+	if err := manager.checkPlatform("go1.22.0"); err != nil {
+		t.Errorf("native install rejected: %v", err)
+	}
+}
+
+// TestManager_SetDefault tests that SetDefault records the version new shells
+// get
+func TestManager_SetDefault(t *testing.T) {
+	tmpDir := t.TempDir()
+	home := filepath.Join(t.TempDir(), "home")
+	setTestHome(t, home)
+
+	installDir := filepath.Join(tmpDir, "install")
+	manager := createTestManager(t, installDir)
+	writeMetadata(t, installDir, "go1.21.0")
+	writeFakeGoBinary(t, installDir, "go1.21.0", "go1.21.0")
+	if err := manager.AliasManager().CreateAlias("work", "go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := manager.SetDefault("work")
+	if err != nil {
+		t.Fatalf("SetDefault error: %v", err)
+	}
+	if result.Version != "go1.21.0" || result.Alias != "work" {
+		t.Errorf("SetDefault resolved %q (alias %q), want go1.21.0 (alias work)", result.Version, result.Alias)
+	}
+	if state, _ := manager.getActiveVersionFromState(); state != "go1.21.0" {
+		t.Errorf("state file records %q, want go1.21.0", state)
+	}
+	if runtime.GOOS != "windows" {
+		if target, err := os.Readlink(result.Symlink); err != nil || target != result.Target {
+			t.Errorf("symlink %s points to %q (%v), want %q", result.Symlink, target, err, result.Target)
+		}
+	}
+
+	if _, err := manager.SetDefault("go1.99.0"); !errors.IsErrorCode(err, errors.ErrCodeVersionNotInstalled) {
+		t.Errorf("expected VERSION_NOT_INSTALLED, got %v", err)
+	}
+}
+
+// TestManager_SetDefault_PostUseHook tests that the post_use hook sees the
+// new and previous versions, and that SetNoHooks skips it
+func TestManager_SetDefault_PostUseHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are sh commands")
+	}
+	tmpDir := t.TempDir()
+	setTestHome(t, filepath.Join(tmpDir, "home"))
+	hookLog := filepath.Join(tmpDir, "hooks.log")
+	t.Setenv("HOOK_LOG", hookLog)
+
+	installDir := filepath.Join(tmpDir, "install")
+	manager := createTestManager(t, installDir)
+	for _, v := range []string{"go1.21.0", "go1.22.0"} {
+		writeMetadata(t, installDir, v)
+		writeFakeGoBinary(t, installDir, v, v)
+	}
+	manager.config.PostUse = `echo "$GOPHER_PREVIOUS_VERSION -> $GOPHER_VERSION" >> "$HOOK_LOG"`
+
+	if _, err := manager.SetDefault("go1.21.0"); err != nil {
+		t.Fatalf("SetDefault error: %v", err)
+	}
+	if _, err := manager.SetDefault("go1.22.0"); err != nil {
+		t.Fatalf("SetDefault error: %v", err)
+	}
+	manager.SetNoHooks(true)
+	if _, err := manager.SetDefault("go1.21.0"); err != nil {
+		t.Fatalf("SetDefault error: %v", err)
+	}
+	data, err := os.ReadFile(hookLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := " -> go1.21.0\ngo1.21.0 -> go1.22.0\n"; string(data) != want {
+		t.Errorf("hook log = %q, want %q", data, want)
+	}
+
+	// A failing hook is reported but keeps the switch
+	manager.SetNoHooks(false)
+	manager.config.PostUse = "exit 2"
+	result, err := manager.SetDefault("go1.22.0")
+	if err != nil {
+		t.Fatalf("SetDefault error: %v", err)
+	}
+	if !strings.Contains(result.PostUseError, "status 2") {
+		t.Errorf("PostUseError = %q, want the hook's exit status", result.PostUseError)
+	}
+	if state, _ := manager.getActiveVersionFromState(); state != "go1.22.0" {
+		t.Errorf("state file records %q, want go1.22.0", state)
+	}
+}
+
 // TestManager_GetCurrent_Comprehensive tests the GetCurrent method comprehensively
 func TestManager_GetCurrent_Comprehensive(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -157,7 +287,7 @@ func TestManager_UseSystemVersion_Comprehensive(t *testing.T) {
 	manager := NewManager(cfg, envProvider)
 
 	// Test using system version
-	err := manager.useSystemVersion()
+	_, err := manager.useSystemVersion()
 	if err != nil {
 		t.Logf("useSystemVersion failed (expected if no system Go): %v", err)
 	}
@@ -281,6 +411,123 @@ func TestManager_GetActiveVersionFromState_Comprehensive(t *testing.T) {
 	}
 }
 
+func TestManager_PromptVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := createTestManager(t, filepath.Join(tmpDir, "install"))
+
+	if got := manager.PromptVersion(); got != "" {
+		t.Errorf("PromptVersion() with no state = %q, want empty", got)
+	}
+
+	tests := []struct {
+		active string
+		want   string
+	}{
+		{"go1.21.0", "go1.21.0"},
+		{"system", ""},
+		{HomebrewVersion, ""},
+	}
+	for _, tt := range tests {
+		if err := manager.saveActiveVersion(tt.active); err != nil {
+			t.Fatal(err)
+		}
+		if got := manager.PromptVersion(); got != tt.want {
+			t.Errorf("PromptVersion() with %q active = %q, want %q", tt.active, got, tt.want)
+		}
+	}
+}
+
+func TestManager_GetActiveVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := createTestManager(t, filepath.Join(tmpDir, "install"))
+
+	version, err := manager.GetActiveVersion()
+	if err != nil || version != "" {
+		t.Errorf("GetActiveVersion() with no state = %q, %v, want empty", version, err)
+	}
+
+	for _, active := range []string{"go1.21.0", "system"} {
+		if err := manager.saveActiveVersion(active); err != nil {
+			t.Fatal(err)
+		}
+		version, err := manager.GetActiveVersion()
+		if err != nil || version != active {
+			t.Errorf("GetActiveVersion() = %q, %v, want %q", version, err, active)
+		}
+	}
+}
+
+func TestManager_EffectiveGOTOOLCHAIN(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		setEnv   bool
+		config   string
+		want     string
+		switches bool
+	}{
+		{"go default", map[string]string{}, false, "", "auto", true},
+		{"config when gopher sets env", map[string]string{}, true, "local", "local", false},
+		{"config ignored without set_environment", map[string]string{}, false, "local", "auto", true},
+		{"process env wins", map[string]string{"GOTOOLCHAIN": "go1.22.0"}, true, "local", "go1.22.0", true},
+		{"path", map[string]string{"GOTOOLCHAIN": "path"}, false, "", "path", false},
+		{"local+auto", map[string]string{"GOTOOLCHAIN": "local+auto"}, false, "", "local+auto", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				InstallDir:     t.TempDir(),
+				SetEnvironment: tt.setEnv,
+				GOTOOLCHAIN:    tt.config,
+			}
+			manager := NewManager(cfg, env.NewMockProvider(tt.env))
+
+			got := manager.EffectiveGOTOOLCHAIN()
+			if got != tt.want {
+				t.Errorf("EffectiveGOTOOLCHAIN() = %q, want %q", got, tt.want)
+			}
+			if switches := ToolchainSwitchingAllowed(got); switches != tt.switches {
+				t.Errorf("ToolchainSwitchingAllowed(%q) = %v, want %v", got, switches, tt.switches)
+			}
+		})
+	}
+}
+
+func TestManager_ToolchainFix(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		setEnv bool
+		config string
+		want   string
+	}{
+		{"nothing set", map[string]string{}, true, "", "gopher env set gotoolchain=local"},
+		{"config without set_environment", map[string]string{}, false, "local", "gopher env set set_environment=true"},
+		{"environment overrides config", map[string]string{"GOTOOLCHAIN": "auto"}, true, "local", "GOTOOLCHAIN=auto in your environment overrides gotoolchain=local"},
+		{"environment only", map[string]string{"GOTOOLCHAIN": "go1.22.0"}, true, "", "GOTOOLCHAIN=go1.22.0 is set in your environment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				InstallDir:     t.TempDir(),
+				SetEnvironment: tt.setEnv,
+				GOTOOLCHAIN:    tt.config,
+			}
+			manager := NewManager(cfg, env.NewMockProvider(tt.env))
+
+			got := manager.ToolchainFix()
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("ToolchainFix() = %q, want it to contain %q", got, tt.want)
+			}
+			if tt.config != "" && strings.Contains(got, "env set gotoolchain=") {
+				t.Errorf("ToolchainFix() = %q suggests a setting that is already configured", got)
+			}
+		})
+	}
+}
+
 // TestManager_SetupShellIntegration_Comprehensive tests the setupShellIntegration method comprehensively
 func TestManager_SetupShellIntegration_Comprehensive(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -317,6 +564,23 @@ func TestManager_CreateGopherInitScript_Comprehensive(t *testing.T) {
 	if err != nil {
 		t.Logf("createGopherInitScript failed: %v", err)
 	}
+
+	// Configured GOFLAGS and GOTOOLCHAIN are exported, quoted for the shell
+	cfg.GOFLAGS = "-ldflags='-s -w'"
+	cfg.GOTOOLCHAIN = "local"
+	path, err := manager.createGopherInitScript()
+	if err != nil {
+		t.Fatalf("createGopherInitScript failed: %v", err)
+	}
+	script, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`export GOFLAGS='-ldflags='\''-s -w'\'''`, "export GOTOOLCHAIN='local'"} {
+		if !strings.Contains(string(script), want) {
+			t.Errorf("init script does not contain %s", want)
+		}
+	}
 }
 
 // TestManager_DetectShell_Comprehensive tests the detectShell method comprehensively
@@ -453,6 +717,129 @@ func TestManager_CreateSymlink_Comprehensive(t *testing.T) {
 	}
 }
 
+// TestManager_CreateSymlink_LinkedBinaries tests that the configured binaries
+// are linked next to go, and that removeGopherSymlinks removes them again
+func TestManager_CreateSymlink_LinkedBinaries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on Windows")
+	}
+	tmpDir := t.TempDir()
+	home := filepath.Join(tmpDir, "home")
+	setTestHome(t, home)
+
+	binDir := filepath.Join(tmpDir, "install", "go1.21.0", "go", "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"go", "gofmt"} {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		InstallDir:     filepath.Join(tmpDir, "install"),
+		DownloadDir:    filepath.Join(tmpDir, "download"),
+		MaxVersions:    5,
+		LinkedBinaries: []string{"go", "gofmt", "gopls"},
+	}
+	symlinkDir := filepath.Join(home, ".local", "bin")
+	manager := NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": symlinkDir}))
+
+	// A gopls link left by another version must not survive the switch
+	if err := os.MkdirAll(symlinkDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(tmpDir, "install", "go1.20.0", "go", "bin", "gopls"), filepath.Join(symlinkDir, "gopls")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.createSymlink(filepath.Join(binDir, "go")); err != nil {
+		t.Fatalf("createSymlink error: %v", err)
+	}
+	for _, name := range []string{"go", "gofmt"} {
+		if target, err := os.Readlink(filepath.Join(symlinkDir, name)); err != nil || target != filepath.Join(binDir, name) {
+			t.Errorf("%s links to %q (%v), want %q", name, target, err, filepath.Join(binDir, name))
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(symlinkDir, "gopls")); !os.IsNotExist(err) {
+		t.Errorf("stale gopls symlink was not removed (err: %v)", err)
+	}
+
+	if err := manager.removeGopherSymlinks(); err != nil {
+		t.Fatalf("removeGopherSymlinks error: %v", err)
+	}
+	for _, name := range []string{"go", "gofmt"} {
+		if _, err := os.Lstat(filepath.Join(symlinkDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s symlink was not removed (err: %v)", name, err)
+		}
+	}
+}
+
+func TestManager_CreateSymlink_SymlinkDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on Windows")
+	}
+	tmpDir := t.TempDir()
+	home := filepath.Join(tmpDir, "home")
+	setTestHome(t, home)
+
+	binDir := filepath.Join(tmpDir, "install", "go1.21.0", "go", "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "go"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	symlinkDir := filepath.Join(tmpDir, "custom", "bin")
+	cfg := &config.Config{
+		InstallDir:  filepath.Join(tmpDir, "install"),
+		DownloadDir: filepath.Join(tmpDir, "download"),
+		MaxVersions: 5,
+		SymlinkDir:  symlinkDir,
+	}
+	manager := NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": "/usr/bin"}))
+
+	// A link in the standard directory from before symlink_dir was set
+	defaultDir := filepath.Join(home, ".local", "bin")
+	if err := os.MkdirAll(defaultDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(tmpDir, "install", "go1.20.0", "go", "bin", "go"), filepath.Join(defaultDir, "go")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.createSymlink(filepath.Join(binDir, "go")); err != nil {
+		t.Fatalf("createSymlink error: %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(symlinkDir, "go")); err != nil || target != filepath.Join(binDir, "go") {
+		t.Errorf("go links to %q (%v) in %s", target, err, symlinkDir)
+	}
+	if inPath, err := manager.CheckSymlinkDir(); err != nil || inPath {
+		t.Errorf("CheckSymlinkDir = %v, %v; want not in PATH", inPath, err)
+	}
+
+	if err := manager.removeGopherSymlinks(); err != nil {
+		t.Fatalf("removeGopherSymlinks error: %v", err)
+	}
+	for _, dir := range []string{symlinkDir, defaultDir} {
+		if _, err := os.Lstat(filepath.Join(dir, "go")); !os.IsNotExist(err) {
+			t.Errorf("symlink in %s was not removed (err: %v)", dir, err)
+		}
+	}
+
+	// A directory that cannot be created is reported before linking
+	file := filepath.Join(tmpDir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.SymlinkDir = filepath.Join(file, "bin")
+	if err := manager.createSymlink(filepath.Join(binDir, "go")); err == nil {
+		t.Error("expected an error for a symlink directory that cannot be created")
+	}
+}
+
 // TestManager_TryCreateSymlink_Comprehensive tests the tryCreateSymlink method comprehensively
 func TestManager_TryCreateSymlink_Comprehensive(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -557,7 +944,7 @@ func TestManager_AutoCleanup_Comprehensive(t *testing.T) {
 	manager := NewManager(cfg, envProvider)
 
 	// Test auto cleanup
-	err := manager.autoCleanup()
+	err := manager.autoCleanup(nil)
 	if err != nil {
 		t.Logf("autoCleanup failed: %v", err)
 	}
@@ -0,0 +1,450 @@
+package runtime
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/installer"
+)
+
+// ============================================================================
+// Directory Migration
+// ============================================================================
+
+// migratingSuffix marks a copy that Migrate has not finished; it is renamed
+// into place once complete, so an interrupted copy is never mistaken for a
+// finished one
+const migratingSuffix = ".migrating"
+
+// migrateLockFile is the lock Migrate holds in the install directory; while
+// it exists lockInstall refuses to start an install
+const migrateLockFile = ".migrate.lock"
+
+// MigrationMove is one directory or file Migrate moves
+type MigrationMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// MigrationSymlink is one symlink Migrate repoints into the new install
+// directory
+type MigrationSymlink struct {
+	Path   string `json:"path"`
+	Target string `json:"target"` // Where it points afterwards
+}
+
+// MigrationPlan describes what Migrate would change
+type MigrationPlan struct {
+	Root        string             `json:"root"`
+	InstallDir  string             `json:"install_dir"`  // New install directory
+	DownloadDir string             `json:"download_dir"` // New download directory
+	Moves       []MigrationMove    `json:"moves"`        // Only items that exist are moved
+	Symlinks    []MigrationSymlink `json:"symlinks"`     // go and linked_binaries links that point into the install directory
+}
+
+// PlanMigration returns the changes Migrate would make to move gopher's data
+// under root, without touching the filesystem: installed versions,
+// downloads, aliases, state and per-version GOBINs move to root, and the go
+// symlink and the linked_binaries links are repointed if they target an
+// installed version.
+//
+// Example:
+//
+//	plan, err := manager.PlanMigration("/data/gopher")
+//	for _, move := range plan.Moves {
+//	    fmt.Println(move.From, "->", move.To)
+//	}
+func (m *Manager) PlanMigration(root string) (*MigrationPlan, error) {
+	if !filepath.IsAbs(root) {
+		return nil, errors.Newf(errors.ErrCodeInvalidArgument, "migration target %s must be an absolute path", root)
+	}
+	root = filepath.Clean(root)
+
+	installDir, err := filepath.Abs(m.config.InstallDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeInvalidConfigValue, "invalid install directory")
+	}
+	downloadDir, err := filepath.Abs(m.config.DownloadDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeInvalidConfigValue, "invalid download directory")
+	}
+	dataDir := filepath.Dir(installDir)
+
+	plan := &MigrationPlan{
+		Root:        root,
+		InstallDir:  filepath.Join(root, "versions"),
+		DownloadDir: filepath.Join(root, "downloads"),
+		Moves:       []MigrationMove{},
+		Symlinks:    []MigrationSymlink{},
+	}
+
+	candidates := []MigrationMove{
+		{From: installDir, To: plan.InstallDir},
+		{From: downloadDir, To: plan.DownloadDir},
+		{From: filepath.Join(dataDir, "aliases.json"), To: filepath.Join(root, "aliases.json")},
+		{From: filepath.Join(dataDir, "state"), To: filepath.Join(root, "state")},
+		{From: filepath.Join(dataDir, "gobin"), To: filepath.Join(root, "gobin")},
+	}
+	for _, move := range candidates {
+		if move.From == move.To {
+			continue
+		}
+		if isWithin(move.To, move.From) {
+			return nil, errors.Newf(errors.ErrCodeInvalidArgument, "cannot migrate %s into itself (%s)", move.From, move.To)
+		}
+		if _, err := os.Lstat(move.From); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to check %s", move.From)
+		}
+		plan.Moves = append(plan.Moves, move)
+	}
+
+	// The go symlink and the linked_binaries links point into the install
+	// directory and must follow it
+	if symlinkDir, err := m.SymlinkDir(); err == nil {
+		for _, name := range m.linkedBinaries() {
+			symlinkPath := filepath.Join(symlinkDir, name)
+			target, err := os.Readlink(symlinkPath)
+			if err != nil || !isWithin(target, installDir) {
+				continue
+			}
+			rel, _ := filepath.Rel(installDir, target)
+			plan.Symlinks = append(plan.Symlinks, MigrationSymlink{
+				Path:   symlinkPath,
+				Target: filepath.Join(plan.InstallDir, rel),
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// Migrate moves gopher's data under root as described by PlanMigration.
+//
+// Each item is copied, the copy is verified against the original, and only
+// after every item is copied and saveConfig has stored the new install and
+// download directories are the originals removed. If Migrate is interrupted,
+// the old layout stays complete and running it again resumes: copies that
+// match their original are kept, unfinished ones are redone.
+//
+// saveConfig is called with the manager's config already updated; if it
+// fails, the config is restored and nothing is removed. No install may start
+// and the aliases file stays locked until Migrate returns; it fails at once
+// if an install is already running.
+//
+// Example:
+//
+//	plan, err := manager.Migrate("/data/gopher", func() error {
+//	    return manager.GetConfig().Save(config.GetConfigPath())
+//	})
+func (m *Manager) Migrate(root string, saveConfig func() error) (*MigrationPlan, error) {
+	plan, err := m.PlanMigration(root)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := m.lockMigration()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	for _, move := range plan.Moves {
+		if err := migrateCopy(move.From, move.To); err != nil {
+			return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to copy %s to %s", move.From, move.To)
+		}
+	}
+
+	previous := *m.config
+	m.config.InstallDir = plan.InstallDir
+	m.config.DownloadDir = plan.DownloadDir
+	if err := saveConfig(); err != nil {
+		*m.config = previous
+		return nil, err
+	}
+
+	// From here on the new layout is in use; point the manager at it too
+	m.installer = installer.New(m.config.InstallDir)
+	m.aliasManager = NewAliasManagerWithManager(m.config, m)
+	m.downloader.SetPageCache(filepath.Join(m.config.DownloadDir, DownloadsPageFile))
+
+	for _, link := range plan.Symlinks {
+		if err := m.tryCreateSymlink(link.Target, link.Path); err != nil {
+			return plan, errors.Wrapf(err, errors.ErrCodeSymlinkFailed, "failed to repoint %s", link.Path)
+		}
+	}
+
+	for _, move := range plan.Moves {
+		if err := removeTree(move.From); err != nil {
+			return plan, errors.Wrapf(err, errors.ErrCodeUnknown, "migrated, but failed to remove %s", move.From)
+		}
+	}
+
+	return plan, nil
+}
+
+// lockMigration takes the locks Migrate holds throughout: the migration lock,
+// which keeps installs from starting, and the aliases file lock. It fails if
+// an install already holds its lock.
+func (m *Manager) lockMigration() (func(), error) {
+	installDir := m.config.InstallDir
+	// #nosec G301 -- 0755 required for Go installation directory (needs to be executable)
+	if err := os.MkdirAll(installDir, 0755); err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to create install directory")
+	}
+
+	lockPath := filepath.Join(installDir, migrateLockFile)
+	unlockMigrate, err := tryFileLock(lockPath)
+	if err == errLockHeld {
+		return nil, errors.Newf(errors.ErrCodeUnknown, "a migration is already in progress (remove %s if no other gopher process is running)", lockPath)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to lock the install directory")
+	}
+
+	entries, err := os.ReadDir(installDir)
+	if err != nil {
+		unlockMigrate()
+		return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to read install directory")
+	}
+	for _, entry := range entries {
+		if entry.Name() == migrateLockFile || !isLockFile(entry.Name()) {
+			continue
+		}
+		if !lockHeld(filepath.Join(installDir, entry.Name())) {
+			continue
+		}
+		unlockMigrate()
+		return nil, errors.Newf(errors.ErrCodeUnknown, "an install is in progress (%s); try again when it finishes", filepath.Join(installDir, entry.Name()))
+	}
+
+	_, unlockAliases, err := m.aliasManager.lockAliasesFile()
+	if err != nil {
+		unlockMigrate()
+		return nil, err
+	}
+
+	return func() {
+		unlockAliases()
+		unlockMigrate()
+	}, nil
+}
+
+// isLockFile reports whether name is one of the lock files gopher keeps in
+// its directories (e.g. .go1.21.0.lock); they are not migrated
+func isLockFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".lock")
+}
+
+// migrateCopy copies src to dst through a temporary sibling and renames it
+// into place once it is verified. An existing dst is kept if it matches src,
+// so an interrupted migration can be resumed.
+func migrateCopy(src, dst string) error {
+	if _, err := os.Lstat(dst); err == nil {
+		if err := verifyCopy(src, dst); err != nil {
+			return fmt.Errorf("%s already exists and differs from %s: %w", dst, src, err)
+		}
+		return nil
+	}
+
+	tmp := dst + migratingSuffix
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return err
+	}
+	if err := copyTree(src, tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	if err := verifyCopy(src, tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// copyTree copies the file or directory at src to dst, keeping permissions
+// and symlinks. Directories are writable while they are filled and get their
+// own permissions afterwards, as module caches hold read-only directories.
+func copyTree(src, dst string) error {
+	type dirMode struct {
+		path string
+		perm os.FileMode
+	}
+	var dirs []dirMode
+
+	err := filepath.WalkDir(src, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case !info.IsDir() && isLockFile(entry.Name()):
+			return nil
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.IsDir():
+			dirs = append(dirs, dirMode{target, info.Mode().Perm()})
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// Deepest first, so a read-only parent does not block its children
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(dirs[i].path, dirs[i].perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFile copies one regular file and syncs it to disk
+func copyFile(src, dst string, perm os.FileMode) error {
+	// #nosec G304 -- src is inside a gopher directory being migrated
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	// #nosec G304 -- dst is inside the migration target
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// verifyCopy checks that every file under src exists under dst with the same
+// type and content, and every symlink with the same target
+func verifyCopy(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		want, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !want.IsDir() && isLockFile(entry.Name()) {
+			return nil
+		}
+		got, err := os.Lstat(target)
+		if err != nil {
+			return fmt.Errorf("%s is missing from the copy", rel)
+		}
+		if want.Mode().Type() != got.Mode().Type() {
+			return fmt.Errorf("%s has a different type in the copy", rel)
+		}
+		switch {
+		case want.Mode()&os.ModeSymlink != 0:
+			wantLink, _ := os.Readlink(path)
+			gotLink, _ := os.Readlink(target)
+			if wantLink != gotLink {
+				return fmt.Errorf("%s points elsewhere in the copy", rel)
+			}
+		case want.Mode().IsRegular() && want.Size() != got.Size():
+			return fmt.Errorf("%s has a different size in the copy", rel)
+		case want.Mode().IsRegular():
+			same, err := sameContent(path, target)
+			if err != nil {
+				return err
+			}
+			if !same {
+				return fmt.Errorf("%s has different content in the copy", rel)
+			}
+		}
+		return nil
+	})
+}
+
+// sameContent reports whether two files have the same SHA256
+func sameContent(a, b string) (bool, error) {
+	hashA, err := fileDigest(a)
+	if err != nil {
+		return false, err
+	}
+	hashB, err := fileDigest(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(hashA, hashB), nil
+}
+
+// fileDigest returns the SHA256 of the file at path
+func fileDigest(path string) ([]byte, error) {
+	// #nosec G304 -- path is inside a gopher directory being migrated
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// removeTree removes path like os.RemoveAll, first making read-only
+// directories under it writable so their contents can be removed
+func removeTree(path string) error {
+	_ = filepath.WalkDir(path, func(p string, entry os.DirEntry, err error) error {
+		if err == nil && entry.IsDir() {
+			if info, err := entry.Info(); err == nil && info.Mode().Perm()&0200 == 0 {
+				_ = os.Chmod(p, info.Mode().Perm()|0700)
+			}
+		}
+		return nil
+	})
+	return os.RemoveAll(path)
+}
+
+// isWithin reports whether path is dir or inside it
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,248 @@
+package runtime
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/env"
+)
+
+// createMigrationManager returns a manager whose data lives in old, with an
+// installed version, a cached archive, aliases and state
+func createMigrationManager(t *testing.T, old string) *Manager {
+	t.Helper()
+	cfg := &config.Config{
+		InstallDir:  filepath.Join(old, "versions"),
+		DownloadDir: filepath.Join(old, "downloads"),
+		MirrorURL:   "https://go.dev/dl/",
+	}
+	manager := NewManager(cfg, env.NewMockProvider(map[string]string{"PATH": "/usr/bin"}))
+
+	writeMetadata(t, cfg.InstallDir, "go1.21.0")
+	files := map[string]string{
+		filepath.Join(cfg.InstallDir, "go1.21.0", "bin", "go"):        "binary",
+		filepath.Join(cfg.DownloadDir, "go1.21.0.linux-amd64.tar.gz"): "archive",
+		filepath.Join(old, "aliases.json"):                            "{}",
+		filepath.Join(old, "state", "active-version"):                 "active_version=go1.21.0\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return manager
+}
+
+func TestManager_Migrate(t *testing.T) {
+	old := t.TempDir()
+	root := filepath.Join(t.TempDir(), "new")
+	manager := createMigrationManager(t, old)
+
+	// A read-only directory, like those in a module cache
+	readOnly := filepath.Join(old, "versions", "go1.21.0", "pkg", "mod")
+	if err := os.MkdirAll(readOnly, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(readOnly, "go.mod"), []byte("module m\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(readOnly, 0500); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chmod(readOnly, 0750)
+		_ = os.Chmod(filepath.Join(root, "versions", "go1.21.0", "pkg", "mod"), 0750)
+	})
+
+	plan, err := manager.PlanMigration(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Moves) != 4 {
+		t.Fatalf("PlanMigration() moves = %v, want versions, downloads, aliases and state", plan.Moves)
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Fatal("PlanMigration() must not touch the filesystem")
+	}
+
+	saves := 0
+	if _, err := manager.Migrate(root, func() error { saves++; return nil }); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if saves != 1 {
+		t.Errorf("saveConfig called %d times, want 1", saves)
+	}
+
+	cfg := manager.GetConfig()
+	if cfg.InstallDir != filepath.Join(root, "versions") || cfg.DownloadDir != filepath.Join(root, "downloads") {
+		t.Errorf("config not updated: install_dir=%s download_dir=%s", cfg.InstallDir, cfg.DownloadDir)
+	}
+	for _, rel := range []string{"versions/go1.21.0/bin/go", "versions/go1.21.0/pkg/mod/go.mod", "downloads/go1.21.0.linux-amd64.tar.gz", "aliases.json", "state/active-version"} {
+		if _, err := os.Stat(filepath.Join(root, rel)); err != nil {
+			t.Errorf("%s was not migrated: %v", rel, err)
+		}
+		if _, err := os.Lstat(filepath.Join(old, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed from the old root", rel)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(root, "versions", "go1.21.0", "pkg", "mod")); err != nil || info.Mode().Perm() != 0500 {
+		t.Errorf("read-only directory permissions not kept: %v, %v", info, err)
+	}
+	if installed, _ := manager.IsInstalled("go1.21.0"); !installed {
+		t.Error("manager does not see the migrated version")
+	}
+}
+
+func TestManager_MigrateRepointsLinkedBinaries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on Windows")
+	}
+	old := t.TempDir()
+	root := filepath.Join(t.TempDir(), "new")
+	manager := createMigrationManager(t, old)
+	cfg := manager.GetConfig()
+	cfg.SymlinkDir = t.TempDir()
+	cfg.LinkedBinaries = []string{"gofmt"}
+
+	binDir := filepath.Join(cfg.InstallDir, "go1.21.0", "bin")
+	if err := os.WriteFile(filepath.Join(binDir, "gofmt"), []byte("binary"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"go", "gofmt"} {
+		if err := os.Symlink(filepath.Join(binDir, name), filepath.Join(cfg.SymlinkDir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A link gopher does not manage is left alone
+	if err := os.Symlink("/usr/bin/true", filepath.Join(cfg.SymlinkDir, "other")); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := manager.PlanMigration(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Symlinks) != 2 {
+		t.Fatalf("PlanMigration() symlinks = %v, want go and gofmt", plan.Symlinks)
+	}
+
+	if _, err := manager.Migrate(root, func() error { return nil }); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	newBinDir := filepath.Join(root, "versions", "go1.21.0", "bin")
+	for _, name := range []string{"go", "gofmt"} {
+		target, err := os.Readlink(filepath.Join(cfg.SymlinkDir, name))
+		if err != nil || target != filepath.Join(newBinDir, name) {
+			t.Errorf("%s symlink = %q, %v; want %s", name, target, err, filepath.Join(newBinDir, name))
+		}
+		if _, err := os.Stat(filepath.Join(cfg.SymlinkDir, name)); err != nil {
+			t.Errorf("%s symlink dangles after the migration: %v", name, err)
+		}
+	}
+}
+
+func TestManager_MigrateSaveFailureKeepsOldLayout(t *testing.T) {
+	old := t.TempDir()
+	root := filepath.Join(t.TempDir(), "new")
+	manager := createMigrationManager(t, old)
+	installDir := manager.GetConfig().InstallDir
+
+	if _, err := manager.Migrate(root, func() error { return errors.New("disk full") }); err == nil {
+		t.Fatal("Migrate() should fail when the config cannot be saved")
+	}
+	if manager.GetConfig().InstallDir != installDir {
+		t.Errorf("InstallDir = %s, want it restored to %s", manager.GetConfig().InstallDir, installDir)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "go1.21.0", "bin", "go")); err != nil {
+		t.Errorf("original version removed after a failed migration: %v", err)
+	}
+
+	// Running again resumes with the copies already made
+	if _, err := manager.Migrate(root, func() error { return nil }); err != nil {
+		t.Fatalf("resumed Migrate() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "versions", "go1.21.0", "bin", "go")); err != nil {
+		t.Errorf("version not migrated on resume: %v", err)
+	}
+}
+
+func TestManager_PlanMigrationRejectsNestedTarget(t *testing.T) {
+	old := t.TempDir()
+	manager := createMigrationManager(t, old)
+
+	if _, err := manager.PlanMigration(filepath.Join(old, "versions", "nested")); err == nil {
+		t.Error("PlanMigration() into the install directory should fail")
+	}
+	if _, err := manager.PlanMigration("relative/dir"); err == nil {
+		t.Error("PlanMigration() with a relative path should fail")
+	}
+}
+
+func TestManager_MigrateRejectsChangedCopy(t *testing.T) {
+	old := t.TempDir()
+	root := filepath.Join(t.TempDir(), "new")
+	manager := createMigrationManager(t, old)
+
+	// A leftover copy whose file has the same size but different content
+	stale := filepath.Join(root, "versions", "go1.21.0", "bin", "go")
+	if err := copyTree(manager.GetConfig().InstallDir, filepath.Join(root, "versions")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("BINARY"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := manager.Migrate(root, func() error { return nil }); err == nil {
+		t.Fatal("Migrate() should fail when an existing copy has different content")
+	}
+	if _, err := os.Stat(filepath.Join(old, "versions", "go1.21.0", "bin", "go")); err != nil {
+		t.Errorf("original version removed after a failed migration: %v", err)
+	}
+}
+
+func TestManager_MigrateLocks(t *testing.T) {
+	old := t.TempDir()
+	root := filepath.Join(t.TempDir(), "new")
+	manager := createMigrationManager(t, old)
+	installDir := manager.GetConfig().InstallDir
+
+	// An install in progress stops the migration
+	unlock, err := manager.lockInstall("go1.22.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.Migrate(root, func() error { return nil }); err == nil {
+		t.Fatal("Migrate() should fail while an install is in progress")
+	}
+	unlock()
+	if _, err := os.Stat(filepath.Join(installDir, migrateLockFile)); !os.IsNotExist(err) {
+		t.Error("migration lock was not released")
+	}
+
+	// A migration in progress stops installs, and lock files are not copied
+	unlockMigration, err := manager.lockMigration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.lockInstall("go1.22.0"); err == nil {
+		t.Error("lockInstall() should fail while a migration is in progress")
+	}
+	if err := copyTree(installDir, filepath.Join(root, "versions")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "versions", migrateLockFile)); !os.IsNotExist(err) {
+		t.Error("the migration lock was copied")
+	}
+	unlockMigration()
+
+	if _, err := manager.Migrate(root, func() error { return nil }); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+}
@@ -1,19 +1,23 @@
 package runtime
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"github.com/molmedoz/gopher/internal/downloader"
 )
 
 // MockDownloader implements minimal downloader interface for testing
 type MockDownloader struct {
-	DownloadFunc              func(version, downloadDir string) (string, error)
+	DownloadFunc              func(ctx context.Context, version, downloadDir string) (string, error)
 	CleanupFunc               func(version string) error
-	ListAvailableVersionsFunc func() ([]downloader.VersionInfo, error)
+	ListAvailableVersionsFunc func(ctx context.Context) ([]downloader.VersionInfo, error)
 }
 
-func (m *MockDownloader) Download(version, downloadDir string) (string, error) {
+func (m *MockDownloader) Download(ctx context.Context, version, downloadDir string) (string, error) {
 	if m.DownloadFunc != nil {
-		return m.DownloadFunc(version, downloadDir)
+		return m.DownloadFunc(ctx, version, downloadDir)
 	}
 	return "/tmp/go" + version + ".tar.gz", nil
 }
@@ -25,9 +29,9 @@ func (m *MockDownloader) Cleanup(version string) error {
 	return nil
 }
 
-func (m *MockDownloader) ListAvailableVersions() ([]downloader.VersionInfo, error) {
+func (m *MockDownloader) ListAvailableVersions(ctx context.Context) ([]downloader.VersionInfo, error) {
 	if m.ListAvailableVersionsFunc != nil {
-		return m.ListAvailableVersionsFunc()
+		return m.ListAvailableVersionsFunc(ctx)
 	}
 	return []downloader.VersionInfo{
 		{Version: "go1.21.0", Stable: true, ReleaseDate: "2023-08-08", Files: []downloader.File{}},
@@ -35,6 +39,32 @@ func (m *MockDownloader) ListAvailableVersions() ([]downloader.VersionInfo, erro
 	}, nil
 }
 
+// MockCommandRunner implements CommandRunner with canned output for testing.
+// Outputs and Errors are keyed by the command line, e.g. "go env GOROOT".
+type MockCommandRunner struct {
+	Paths   map[string]string // LookPath results by executable name
+	Outputs map[string]string
+	Errors  map[string]error
+}
+
+func (m *MockCommandRunner) Run(name string, args ...string) ([]byte, error) {
+	command := strings.Join(append([]string{name}, args...), " ")
+	if err, ok := m.Errors[command]; ok {
+		return nil, err
+	}
+	if output, ok := m.Outputs[command]; ok {
+		return []byte(output), nil
+	}
+	return nil, fmt.Errorf("unexpected command: %s", command)
+}
+
+func (m *MockCommandRunner) LookPath(file string) (string, error) {
+	if path, ok := m.Paths[file]; ok {
+		return path, nil
+	}
+	return "", fmt.Errorf("exec: %q: executable file not found in $PATH", file)
+}
+
 // Note: MockInstaller, MockSystemDetector, MockSymlinkManager, and MockFileSystem
 // have been removed after adapter refactoring. Tests now use real implementations
 // or need to be updated to work without these mocks.
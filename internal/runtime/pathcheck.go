@@ -0,0 +1,212 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ============================================================================
+// PATH Shadowing Detection
+// ============================================================================
+
+// PathShadow describes a PATH where 'go' does not resolve to the gopher
+// symlink, so 'go version' keeps reporting another Go after 'gopher use'
+type PathShadow struct {
+	GoPath          string `json:"go_path,omitempty"`    // go that PATH resolves to; empty when none is found
+	Entry           string `json:"path_entry,omitempty"` // PATH entry holding GoPath
+	Position        int    `json:"position,omitempty"`   // 1-based position of Entry in PATH
+	Symlink         string `json:"symlink"`
+	SymlinkTarget   string `json:"symlink_target"`
+	SymlinkPosition int    `json:"symlink_position,omitempty"` // 1-based position of the symlink directory; 0 when missing
+	Fix             string `json:"fix"`                        // Command that puts the symlink directory first
+}
+
+// CheckPathShadowing reports whether 'go' on PATH resolves to something
+// other than the gopher symlink.
+//
+// Returns nil when the symlink does not exist (nothing has been activated,
+// or system Go is used on Windows) or when PATH finds the symlink's target.
+func (m *Manager) CheckPathShadowing() *PathShadow {
+	symlinkPath, err := m.gopherSymlinkPath()
+	if err != nil {
+		return nil
+	}
+	if _, err := os.Lstat(symlinkPath); err != nil {
+		return nil
+	}
+	target, err := filepath.EvalSymlinks(symlinkPath)
+	if err != nil {
+		return nil
+	}
+
+	pathEnv := m.envProvider.Getenv("PATH")
+	goPath, index := lookPathIn("go", pathEnv)
+	if goPath != "" {
+		if resolved, err := filepath.EvalSymlinks(goPath); err == nil && resolved == target {
+			return nil
+		}
+	}
+
+	symlinkDir := filepath.Dir(symlinkPath)
+	shadow := &PathShadow{
+		GoPath:        goPath,
+		Symlink:       symlinkPath,
+		SymlinkTarget: target,
+		Fix:           pathFixCommand(symlinkDir),
+	}
+	if index >= 0 {
+		shadow.Entry = filepath.Dir(goPath)
+		shadow.Position = index + 1
+	}
+	for i, dir := range filepath.SplitList(pathEnv) {
+		if samePath(dir, symlinkDir) {
+			shadow.SymlinkPosition = i + 1
+			break
+		}
+	}
+	return shadow
+}
+
+// Warning returns a user-facing description of the problem and its fix
+func (s *PathShadow) Warning() string {
+	var b strings.Builder
+	if s.GoPath == "" {
+		fmt.Fprintf(&b, "⚠️  WARNING: 'go' is not found on PATH, so %s will not be used\n", s.Symlink)
+	} else {
+		fmt.Fprintf(&b, "⚠️  WARNING: 'go' resolves to %s, not the version gopher activated\n", s.GoPath)
+		fmt.Fprintf(&b, "  PATH entry %d: %s is searched first\n", s.Position, s.Entry)
+	}
+	if s.SymlinkPosition > 0 {
+		fmt.Fprintf(&b, "  PATH entry %d: %s (gopher, -> %s)\n", s.SymlinkPosition, filepath.Dir(s.Symlink), s.SymlinkTarget)
+	} else {
+		fmt.Fprintf(&b, "  %s (gopher, -> %s) is not in PATH\n", filepath.Dir(s.Symlink), s.SymlinkTarget)
+	}
+	fmt.Fprintf(&b, "  To fix, put gopher's directory first (and add this to your shell profile):\n")
+	fmt.Fprintf(&b, "    %s\n", s.Fix)
+	return b.String()
+}
+
+// warnIfPathShadowed prints a warning after a switch when PATH will not
+// find the version that was just activated
+func (m *Manager) warnIfPathShadowed() {
+	if shadow := m.CheckPathShadowing(); shadow != nil {
+		fmt.Fprintf(os.Stderr, "\n%s", shadow.Warning())
+	}
+}
+
+// lookPathIn finds the first executable named name in pathEnv and returns
+// its path and the index of its PATH entry, or "" and -1
+func lookPathIn(name, pathEnv string) (string, int) {
+	for i, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+		// A name with a separator is checked directly, including PATHEXT on Windows
+		if found, err := exec.LookPath(filepath.Join(dir, name)); err == nil {
+			return found, i
+		}
+	}
+	return "", -1
+}
+
+// samePath compares PATH entries, ignoring case on Windows
+func samePath(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// pathFixCommand returns the shell command that puts dir first on PATH
+func pathFixCommand(dir string) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf(`[Environment]::SetEnvironmentVariable("PATH", "%s;" + [Environment]::GetEnvironmentVariable("PATH", "User"), "User")`, dir)
+	}
+	return fmt.Sprintf(`export PATH="%s:$PATH"`, dir)
+}
+
+// ActivationCheck compares what the state file says is active with what the
+// gopher symlink points to and what 'go version' on PATH reports, so a
+// correctly configured machine can be asserted from one result
+type ActivationCheck struct {
+	ActiveVersion          string `json:"active_version"`        // Version recorded in the state file
+	PathOrderOK            bool   `json:"path_order_ok"`         // No other go shadows the gopher symlink
+	Symlink                string `json:"symlink"`               // Standard gopher symlink path
+	SymlinkTarget          string `json:"symlink_target"`        // Where it points; empty when it does not exist
+	SymlinkMatchesState    bool   `json:"symlink_matches_state"` // It points to the active version's go (or is absent for system)
+	GoPath                 string `json:"go_path"`               // go that PATH resolves to
+	GoVersion              string `json:"go_version"`            // What that go reports
+	ActiveMatchesGoVersion bool   `json:"active_matches_go_version"`
+}
+
+// CheckActivation runs 'go version' from PATH and reports whether it, the
+// gopher symlink and the state file agree on the active version
+func (m *Manager) CheckActivation() *ActivationCheck {
+	check := &ActivationCheck{PathOrderOK: m.CheckPathShadowing() == nil}
+	if state, err := m.ReadState(); err == nil {
+		check.ActiveVersion = state.ActiveVersion
+	}
+
+	symlinkPath, err := m.gopherSymlinkPath()
+	if err == nil {
+		check.Symlink = symlinkPath
+		if target, err := os.Readlink(symlinkPath); err == nil {
+			check.SymlinkTarget = target
+		}
+	}
+
+	goPath, _ := lookPathIn("go", m.envProvider.Getenv("PATH"))
+	check.GoPath = goPath
+	if goPath != "" {
+		if output, err := runGoVersionAtPath(goPath); err == nil {
+			check.GoVersion = parseGoVersionOutput(string(output))
+		}
+	}
+
+	switch check.ActiveVersion {
+	case "":
+		// Nothing is recorded, so nothing can match
+	case "system":
+		// Activating system Go removes the symlink; any go outside the
+		// install directory is the system one
+		check.SymlinkMatchesState = check.SymlinkTarget == ""
+		check.ActiveMatchesGoVersion = check.GoVersion != "" && !m.isManagedGo(goPath)
+	default:
+		version := NormalizeVersion(check.ActiveVersion)
+		if binaryPath, err := m.installer.GetGoBinaryPath(version); err == nil && check.SymlinkTarget != "" {
+			check.SymlinkMatchesState = sameFile(check.Symlink, binaryPath)
+		}
+		check.ActiveMatchesGoVersion = check.GoVersion == version
+	}
+	return check
+}
+
+// isManagedGo reports whether goPath resolves into the gopher install
+// directory
+func (m *Manager) isManagedGo(goPath string) bool {
+	resolved, err := filepath.EvalSymlinks(goPath)
+	if err != nil {
+		return false
+	}
+	installDir, err := filepath.EvalSymlinks(m.config.InstallDir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(installDir, resolved)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// sameFile reports whether two paths name the same file, following symlinks
+func sameFile(a, b string) bool {
+	if samePath(a, b) {
+		return true
+	}
+	resolvedA, errA := filepath.EvalSymlinks(a)
+	resolvedB, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && samePath(resolvedA, resolvedB)
+}
@@ -0,0 +1,130 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/env"
+)
+
+func TestCheckPathShadowing(t *testing.T) {
+	tmp := t.TempDir()
+	home := filepath.Join(tmp, "home")
+	setTestHome(t, home)
+
+	m := createTestManager(t, filepath.Join(tmp, "versions"))
+	writeFakeGoBinary(t, filepath.Join(tmp, "versions"), "go1.21.0", "go1.21.0")
+	writeFakeGoBinary(t, filepath.Join(tmp, "system"), "go1.20.0", "go1.20.0")
+	systemBin := filepath.Join(tmp, "system", "go1.20.0", "bin")
+
+	mockEnv := env.NewMockProvider(map[string]string{})
+	m.envProvider = mockEnv
+
+	// Nothing activated yet
+	if shadow := m.CheckPathShadowing(); shadow != nil {
+		t.Fatalf("expected no shadowing without a symlink, got %+v", shadow)
+	}
+
+	symlinkPath, err := m.getGopherSymlinkPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(tmp, "versions", "go1.21.0", "bin", "go")
+	if err := os.Symlink(target, symlinkPath); err != nil {
+		t.Fatal(err)
+	}
+	symlinkDir := filepath.Dir(symlinkPath)
+
+	// System Go earlier in PATH hides the symlink
+	mockEnv.Setenv("PATH", systemBin+string(os.PathListSeparator)+symlinkDir)
+	shadow := m.CheckPathShadowing()
+	if shadow == nil {
+		t.Fatal("expected shadowing when system Go comes first")
+	}
+	if shadow.Entry != systemBin || shadow.Position != 1 || shadow.SymlinkPosition != 2 {
+		t.Errorf("unexpected shadow: %+v", shadow)
+	}
+	if !strings.Contains(shadow.Fix, symlinkDir) || !strings.Contains(shadow.Warning(), systemBin) {
+		t.Errorf("warning should name the offending entry and the fix: %s", shadow.Warning())
+	}
+
+	// Symlink directory missing from PATH
+	mockEnv.Setenv("PATH", filepath.Join(tmp, "empty"))
+	if shadow := m.CheckPathShadowing(); shadow == nil || shadow.GoPath != "" || shadow.SymlinkPosition != 0 {
+		t.Errorf("expected a missing-from-PATH report, got %+v", shadow)
+	}
+
+	// Symlink first: no problem
+	mockEnv.Setenv("PATH", symlinkDir+string(os.PathListSeparator)+systemBin)
+	if shadow := m.CheckPathShadowing(); shadow != nil {
+		t.Errorf("expected no shadowing with gopher first, got %+v", shadow)
+	}
+
+	// The version's own bin directory first also runs the active version
+	mockEnv.Setenv("PATH", filepath.Dir(target)+string(os.PathListSeparator)+systemBin)
+	if shadow := m.CheckPathShadowing(); shadow != nil {
+		t.Errorf("expected no shadowing when PATH finds the symlink target, got %+v", shadow)
+	}
+}
+
+func TestCheckActivation(t *testing.T) {
+	tmp := t.TempDir()
+	home := filepath.Join(tmp, "home")
+	setTestHome(t, home)
+
+	installDir := filepath.Join(tmp, "versions")
+	m := createTestManager(t, installDir)
+	writeMetadata(t, installDir, "go1.21.0")
+	writeFakeGoBinary(t, installDir, "go1.21.0", "go1.21.0")
+	writeFakeGoBinary(t, filepath.Join(tmp, "system"), "go1.20.0", "go1.20.0")
+	systemBin := filepath.Join(tmp, "system", "go1.20.0", "bin")
+
+	mockEnv := env.NewMockProvider(map[string]string{})
+	m.envProvider = mockEnv
+
+	stateFile, err := m.stateFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteStateFile(stateFile, &State{ActiveVersion: "go1.21.0"}); err != nil {
+		t.Fatal(err)
+	}
+	symlinkPath, err := m.getGopherSymlinkPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(installDir, "go1.21.0", "bin", "go")
+	if err := os.Symlink(target, symlinkPath); err != nil {
+		t.Fatal(err)
+	}
+	symlinkDir := filepath.Dir(symlinkPath)
+
+	// Correctly configured
+	mockEnv.Setenv("PATH", symlinkDir+string(os.PathListSeparator)+systemBin)
+	check := m.CheckActivation()
+	if !check.PathOrderOK || check.SymlinkTarget != target || !check.SymlinkMatchesState ||
+		check.GoVersion != "go1.21.0" || !check.ActiveMatchesGoVersion {
+		t.Errorf("expected a matching activation, got %+v", check)
+	}
+
+	// System Go shadows the symlink
+	mockEnv.Setenv("PATH", systemBin+string(os.PathListSeparator)+symlinkDir)
+	check = m.CheckActivation()
+	if check.PathOrderOK || !check.SymlinkMatchesState || check.GoVersion != "go1.20.0" || check.ActiveMatchesGoVersion {
+		t.Errorf("expected a shadowed activation, got %+v", check)
+	}
+
+	// The state moved on without the symlink
+	if err := WriteStateFile(stateFile, &State{ActiveVersion: "go1.22.0"}); err != nil {
+		t.Fatal(err)
+	}
+	mockEnv.Setenv("PATH", symlinkDir)
+	if check := m.CheckActivation(); check.SymlinkMatchesState || check.ActiveMatchesGoVersion {
+		t.Errorf("expected a stale symlink, got %+v", check)
+	}
+}
@@ -0,0 +1,107 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/security"
+)
+
+// ============================================================================
+// Dry-Run Version Switching
+// ============================================================================
+
+// UsePlan describes what Use would change for a version
+type UsePlan struct {
+	Version       string            `json:"version"`
+	Alias         string            `json:"alias,omitempty"`          // Alias the version was resolved from
+	Symlink       string            `json:"symlink,omitempty"`        // Symlink that would be updated; empty when none is used
+	CurrentTarget string            `json:"current_target,omitempty"` // Where the symlink points now
+	SymlinkInPath bool              `json:"symlink_in_path"`          // Whether the symlink directory is on PATH
+	Target        string            `json:"target"`                   // go binary the symlink would point to
+	Environment   map[string]string `json:"environment,omitempty"`    // Variables that would be set, if set_environment is enabled
+}
+
+// PlanUse resolves a version the same way Use does and returns the changes
+// Use would make, without creating symlinks, writing scripts or saving state.
+//
+// Accepts the same versions as Use: installed versions, aliases, "system"
+// and "homebrew".
+//
+// Example:
+//
+//	plan, err := manager.PlanUse("1.21.0")
+//	fmt.Println(plan.Symlink, "->", plan.Target)
+func (m *Manager) PlanUse(version string) (*UsePlan, error) {
+	plan := &UsePlan{}
+
+	switch version {
+	case "system", "sys":
+		info, err := NewSystemDetector().GetSystemGoInfo()
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.ErrCodeSystemGoNotAvailable, "system Go not available")
+		}
+		plan.Version = "system"
+		plan.Target = info.Executable
+		if m.config.SetEnvironment {
+			plan.Environment = m.externalEnvironment(info)
+		}
+		// On Windows, Use removes the gopher symlink instead of updating it
+		if runtime.GOOS == "windows" {
+			return plan, nil
+		}
+	case HomebrewVersion:
+		info, err := NewSystemDetector().GetHomebrewGoInfo()
+		if err != nil {
+			return nil, err
+		}
+		plan.Version = HomebrewVersion
+		plan.Target = info.Executable
+		if m.config.SetEnvironment {
+			plan.Environment = m.externalEnvironment(info)
+		}
+	default:
+		if alias, exists := m.aliasManager.GetAlias(version); exists {
+			plan.Alias = version
+			version = alias.Version
+		}
+
+		if err := ValidateVersion(version); err != nil {
+			return nil, errors.Wrapf(err, errors.ErrCodeInvalidVersion, "invalid version")
+		}
+		if err := security.ValidatePath(version); err != nil {
+			return nil, errors.Wrapf(err, errors.ErrCodeInvalidVersion, "invalid version")
+		}
+		version = NormalizeVersion(version)
+
+		if !m.installer.IsInstalled(version) {
+			return nil, errors.NewVersionNotInstalled(version)
+		}
+		if err := m.checkPlatform(version); err != nil {
+			return nil, err
+		}
+
+		binaryPath, err := m.installer.GetGoBinaryPath(version)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to get go binary path")
+		}
+		plan.Version = version
+		plan.Target = binaryPath
+		plan.Environment = m.config.GetEnvironmentVariablesWithEnv(version, m.envProvider)
+	}
+
+	symlinkPath, err := m.gopherSymlinkPath()
+	if err != nil {
+		return nil, err
+	}
+	plan.Symlink = symlinkPath
+	if target, err := os.Readlink(symlinkPath); err == nil {
+		plan.CurrentTarget = target
+	}
+
+	plan.SymlinkInPath = m.isDirectoryInPath(filepath.Dir(symlinkPath))
+
+	return plan, nil
+}
@@ -0,0 +1,63 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlanUse(t *testing.T) {
+	tmp := t.TempDir()
+	home := filepath.Join(t.TempDir(), "home")
+	setTestHome(t, home)
+
+	m := createTestManager(t, tmp)
+	m.config.SetEnvironment = true
+	writeMetadata(t, tmp, "go1.21.0")
+	writeFakeGoBinary(t, tmp, "go1.21.0", "go1.21.0")
+	if err := m.AliasManager().CreateAlias("work", "go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := m.PlanUse("work")
+	if err != nil {
+		t.Fatalf("PlanUse error: %v", err)
+	}
+	if plan.Version != "go1.21.0" || plan.Alias != "work" {
+		t.Errorf("plan resolved %q (alias %q), want go1.21.0 (alias work)", plan.Version, plan.Alias)
+	}
+	wantTarget, _ := m.installer.GetGoBinaryPath("go1.21.0")
+	if plan.Target != wantTarget {
+		t.Errorf("Target = %q, want %q", plan.Target, wantTarget)
+	}
+	if plan.Environment["GOROOT"] != filepath.Join(tmp, "go1.21.0") {
+		t.Errorf("GOROOT = %q", plan.Environment["GOROOT"])
+	}
+	if plan.SymlinkInPath {
+		t.Error("symlink directory should not be reported on the mock PATH")
+	}
+
+	// Nothing is created or saved
+	if _, err := os.Lstat(plan.Symlink); !os.IsNotExist(err) {
+		t.Errorf("dry run created %s", plan.Symlink)
+	}
+	if _, err := os.Stat(filepath.Dir(plan.Symlink)); !os.IsNotExist(err) {
+		t.Errorf("dry run created %s", filepath.Dir(plan.Symlink))
+	}
+	if state, _ := m.getActiveVersionFromState(); state != "" {
+		t.Errorf("dry run saved active version %q", state)
+	}
+
+	m.config.SetEnvironment = false
+	plan, err = m.PlanUse("1.21.0")
+	if err != nil {
+		t.Fatalf("PlanUse error: %v", err)
+	}
+	if len(plan.Environment) != 0 {
+		t.Errorf("expected no environment with set_environment disabled, got %v", plan.Environment)
+	}
+
+	if _, err := m.PlanUse("1.22.0"); err == nil {
+		t.Error("expected error for a version that is not installed")
+	}
+}
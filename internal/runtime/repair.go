@@ -0,0 +1,132 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ============================================================================
+// Symlink Repair
+// ============================================================================
+
+// Actions of a SymlinkRepair
+const (
+	SymlinkRepairRepoint = "repoint" // Point the symlink at the active version
+	SymlinkRepairRemove  = "remove"  // Remove the symlink
+)
+
+// SymlinkRepair is a broken gopher symlink and what RepairSymlinks does
+// about it
+type SymlinkRepair struct {
+	Path      string `json:"path"`
+	Target    string `json:"target"`               // Missing file the symlink points to
+	Action    string `json:"action"`               // SymlinkRepairRepoint or SymlinkRepairRemove
+	NewTarget string `json:"new_target,omitempty"` // Set when repointing
+	Error     string `json:"error,omitempty"`      // Set when the repair failed
+}
+
+// PlanSymlinkRepair returns the broken gopher symlinks, without changing
+// them. A symlink is broken when it points into a Go version directory that
+// no longer exists, e.g. after the version was deleted by hand. The gopher
+// symlink directory and every PATH directory are scanned for the linked
+// binaries. Symlinks that point outside the install directory belong to
+// another tool, such as gvm, and are left alone.
+//
+// Broken symlinks are repointed at the binary of the same name in the active
+// version recorded in the state file, when that version is installed and
+// ships the binary, and removed otherwise.
+func (m *Manager) PlanSymlinkRepair() []SymlinkRepair {
+	repairs := []SymlinkRepair{}
+	installDir, err := filepath.Abs(m.config.InstallDir)
+	if err != nil {
+		return repairs
+	}
+	activeBinDir := m.activeBinDir()
+
+	for _, dir := range m.symlinkDirs() {
+		for _, name := range m.linkedBinaries() {
+			path := filepath.Join(dir, name)
+			target, err := os.Readlink(path)
+			if err != nil {
+				continue
+			}
+			resolved := target
+			if !filepath.IsAbs(resolved) {
+				resolved = filepath.Join(dir, resolved)
+			}
+			if !isWithin(resolved, installDir) || m.extractVersionFromPath(resolved) == "" {
+				continue
+			}
+			if _, err := os.Stat(resolved); err == nil {
+				continue
+			}
+
+			repair := SymlinkRepair{Path: path, Target: target, Action: SymlinkRepairRemove}
+			if activeBinDir != "" {
+				newTarget := filepath.Join(activeBinDir, name)
+				if _, err := os.Stat(newTarget); err == nil {
+					repair.Action = SymlinkRepairRepoint
+					repair.NewTarget = newTarget
+				}
+			}
+			repairs = append(repairs, repair)
+		}
+	}
+	return repairs
+}
+
+// RepairSymlinks repairs the symlinks PlanSymlinkRepair reports and returns
+// them. A repair that fails has its Error set; the others are still made.
+func (m *Manager) RepairSymlinks() []SymlinkRepair {
+	repairs := m.PlanSymlinkRepair()
+	for i := range repairs {
+		repair := &repairs[i]
+		var err error
+		switch repair.Action {
+		case SymlinkRepairRepoint:
+			err = m.tryCreateSymlink(repair.NewTarget, repair.Path)
+		default:
+			err = os.Remove(repair.Path)
+		}
+		if err != nil {
+			repair.Error = err.Error()
+		}
+	}
+	return repairs
+}
+
+// symlinkDirs returns the directories that may hold gopher symlinks: the
+// gopher symlink directory, the standard one (which holds the links made
+// before symlink_dir was set), and the PATH directories, without duplicates
+func (m *Manager) symlinkDirs() []string {
+	var dirs []string
+	if dir, err := m.SymlinkDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	if dir, err := defaultSymlinkDir(); err == nil && !slices.Contains(dirs, dir) {
+		dirs = append(dirs, dir)
+	}
+	for _, dir := range strings.Split(m.envProvider.Getenv("PATH"), string(os.PathListSeparator)) {
+		if dir == "" || slices.Contains(dirs, dir) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// activeBinDir returns the bin directory of the active version recorded in
+// the state file, or "" when there is none or it is not installed
+func (m *Manager) activeBinDir() string {
+	version, err := m.getActiveVersionFromState()
+	if err != nil || version == "" || !m.installer.IsInstalled(version) {
+		return ""
+	}
+	binaryPath, err := m.installer.GetGoBinaryPath(version)
+	if err != nil {
+		return ""
+	}
+	return filepath.Dir(binaryPath)
+}
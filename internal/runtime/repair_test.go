@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/molmedoz/gopher/internal/config"
+	"github.com/molmedoz/gopher/internal/env"
+)
+
+func TestRepairSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on Windows")
+	}
+	tmp := t.TempDir()
+	home := filepath.Join(tmp, "home")
+	setTestHome(t, home)
+
+	installDir := filepath.Join(tmp, "versions")
+	symlinkDir := filepath.Join(home, ".local", "bin")
+	gvmBinDir := filepath.Join(tmp, "gvm-bin")
+	cfg := &config.Config{
+		InstallDir:     installDir,
+		DownloadDir:    filepath.Join(tmp, "downloads"),
+		LinkedBinaries: []string{"go", "gofmt"},
+	}
+	m := NewManager(cfg, env.NewMockProvider(map[string]string{
+		"PATH": symlinkDir + string(os.PathListSeparator) + gvmBinDir,
+	}))
+
+	writeMetadata(t, installDir, "go1.22.0")
+	writeFakeGoBinary(t, installDir, "go1.22.0", "go1.22.0")
+	stateFile, err := m.stateFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteStateFile(stateFile, &State{ActiveVersion: "go1.22.0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// go1.21.0 was deleted by hand, leaving its links behind; go1.22.0 has
+	// no gofmt to repoint to
+	if err := os.MkdirAll(symlinkDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"go", "gofmt"} {
+		if err := os.Symlink(filepath.Join(installDir, "go1.21.0", "bin", name), filepath.Join(symlinkDir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A broken gvm link elsewhere in PATH is not gopher's to repair
+	gvmTarget := filepath.Join(home, ".gvm", "gos", "go1.20", "bin", "go")
+	if err := os.MkdirAll(gvmBinDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(gvmTarget, filepath.Join(gvmBinDir, "go")); err != nil {
+		t.Fatal(err)
+	}
+
+	plan := m.PlanSymlinkRepair()
+	if len(plan) != 2 {
+		t.Fatalf("PlanSymlinkRepair found %d symlinks, want 2: %+v", len(plan), plan)
+	}
+	if _, err := os.Lstat(filepath.Join(symlinkDir, "gofmt")); err != nil {
+		t.Errorf("PlanSymlinkRepair changed the filesystem: %v", err)
+	}
+
+	repairs := m.RepairSymlinks()
+	want := map[string]SymlinkRepair{
+		filepath.Join(symlinkDir, "go"): {
+			Action:    SymlinkRepairRepoint,
+			NewTarget: filepath.Join(installDir, "go1.22.0", "bin", "go"),
+		},
+		filepath.Join(symlinkDir, "gofmt"): {Action: SymlinkRepairRemove},
+	}
+	for _, repair := range repairs {
+		w, ok := want[repair.Path]
+		if !ok || repair.Action != w.Action || repair.NewTarget != w.NewTarget || repair.Error != "" {
+			t.Errorf("unexpected repair %+v", repair)
+		}
+	}
+
+	if target, err := os.Readlink(filepath.Join(symlinkDir, "go")); err != nil || target != want[filepath.Join(symlinkDir, "go")].NewTarget {
+		t.Errorf("go links to %q (%v) after repair", target, err)
+	}
+	if _, err := os.Lstat(filepath.Join(symlinkDir, "gofmt")); !os.IsNotExist(err) {
+		t.Errorf("gofmt symlink was not removed (err: %v)", err)
+	}
+	if again := m.PlanSymlinkRepair(); len(again) != 0 {
+		t.Errorf("symlinks still broken after repair: %+v", again)
+	}
+	if target, err := os.Readlink(filepath.Join(gvmBinDir, "go")); err != nil || target != gvmTarget {
+		t.Errorf("gvm link changed to %q (%v)", target, err)
+	}
+}
@@ -0,0 +1,157 @@
+package runtime
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/molmedoz/gopher/internal/fileutil"
+	"github.com/molmedoz/gopher/internal/security"
+)
+
+// ============================================================================
+// State File
+// ============================================================================
+
+// StateFileName is the name of the state file in the state directory
+const StateFileName = "active-version"
+
+// StateSchemaVersion is the version of the state file format written by this
+// release. Bump it when a change is not just a new key.
+const StateSchemaVersion = 1
+
+// State file keys. The shell init scripts read StateKeyActiveVersion with
+// StateShellReadCommand, so it must not change.
+const (
+	StateKeySchemaVersion   = "schema_version"
+	StateKeyActiveVersion   = "active_version"
+	StateKeyPreviousVersion = "previous_version"
+	StateKeyUpdatedAt       = "updated_at"
+)
+
+// State is the content of the state file, which records the version that new
+// shells activate.
+//
+// The file holds one key=value line per field, so that shell scripts can
+// read it with grep and cut:
+//
+//	schema_version=1
+//	active_version=go1.22.0
+//	previous_version=go1.21.0
+//	updated_at=2025-01-02T15:04:05Z
+//
+// Readers ignore blank lines and unknown keys, and leave missing fields
+// empty, so files written by older and newer releases can be read. A file
+// with only an active_version line is the format of releases before the
+// schema was versioned.
+type State struct {
+	SchemaVersion   int       `json:"schema_version"`
+	ActiveVersion   string    `json:"active_version"`
+	PreviousVersion string    `json:"previous_version,omitempty"` // Empty before the first switch
+	UpdatedAt       time.Time `json:"updated_at,omitempty"`       // Zero in files written before it was recorded
+}
+
+// ParseState reads a state file
+func ParseState(r io.Reader) (*State, error) {
+	state := &State{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case StateKeySchemaVersion:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q in state file", key, value)
+			}
+			state.SchemaVersion = n
+		case StateKeyActiveVersion:
+			state.ActiveVersion = value
+		case StateKeyPreviousVersion:
+			state.PreviousVersion = value
+		case StateKeyUpdatedAt:
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q in state file", key, value)
+			}
+			state.UpdatedAt = t
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Format returns the state in the file format described on State. Empty
+// fields are left out; the schema version is always written.
+func (s *State) Format() []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s=%d\n", StateKeySchemaVersion, StateSchemaVersion)
+	fmt.Fprintf(&b, "%s=%s\n", StateKeyActiveVersion, s.ActiveVersion)
+	if s.PreviousVersion != "" {
+		fmt.Fprintf(&b, "%s=%s\n", StateKeyPreviousVersion, s.PreviousVersion)
+	}
+	if !s.UpdatedAt.IsZero() {
+		fmt.Fprintf(&b, "%s=%s\n", StateKeyUpdatedAt, s.UpdatedAt.UTC().Format(time.RFC3339))
+	}
+	return []byte(b.String())
+}
+
+// ReadStateFile reads the state file at path
+func ReadStateFile(path string) (*State, error) {
+	// #nosec G304 -- callers pass the validated state file path
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseState(file)
+}
+
+// WriteStateFile replaces the state file at path with state, atomically so
+// that a shell starting at the same time never reads a partial file
+func WriteStateFile(path string, state *State) error {
+	// #nosec G306 -- 0644 acceptable for state file (non-sensitive metadata)
+	return fileutil.WriteAtomic(path, state.Format(), 0644)
+}
+
+// StateShellReadCommand returns the shell pipeline that prints the active
+// version recorded in the state file at quotedPath, which must already be
+// quoted for the shell. The init scripts use it, so that they read the file
+// the same way ParseState does.
+func StateShellReadCommand(quotedPath string) string {
+	return fmt.Sprintf("grep '^%s=' %s | cut -d'=' -f2-", StateKeyActiveVersion, quotedPath)
+}
+
+// stateFile returns the validated path of the state file
+func (m *Manager) stateFile() (string, error) {
+	safeStateDir, err := m.stateDir()
+	if err != nil {
+		return "", err
+	}
+
+	// Validate state file is within state directory
+	safeStateFile, err := security.ValidatePathWithinRoot(filepath.Join(safeStateDir, StateFileName), safeStateDir)
+	if err != nil {
+		return "", fmt.Errorf("invalid state file path: %w", err)
+	}
+	return safeStateFile, nil
+}
+
+// ReadState returns the content of the state file. The error wraps
+// os.ErrNotExist when no version has been activated yet.
+func (m *Manager) ReadState() (*State, error) {
+	path, err := m.stateFile()
+	if err != nil {
+		return nil, err
+	}
+	return ReadStateFile(path)
+}
@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestState_FormatAndParse(t *testing.T) {
+	updated := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	state := &State{ActiveVersion: "go1.22.0", PreviousVersion: "go1.21.0", UpdatedAt: updated}
+
+	want := "schema_version=1\nactive_version=go1.22.0\nprevious_version=go1.21.0\nupdated_at=2025-01-02T15:04:05Z\n"
+	if got := string(state.Format()); got != want {
+		t.Fatalf("Format() = %q, want %q", got, want)
+	}
+
+	parsed, err := ParseState(strings.NewReader(want))
+	if err != nil {
+		t.Fatalf("ParseState error: %v", err)
+	}
+	if parsed.SchemaVersion != StateSchemaVersion || parsed.ActiveVersion != "go1.22.0" ||
+		parsed.PreviousVersion != "go1.21.0" || !parsed.UpdatedAt.Equal(updated) {
+		t.Errorf("ParseState() = %+v", parsed)
+	}
+}
+
+func TestParseState(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    State
+		wantErr bool
+	}{
+		{"legacy format", "active_version=go1.21.0\n", State{ActiveVersion: "go1.21.0"}, false},
+		{"unknown keys and blank lines", "\nfuture_key=x\nactive_version=system\n", State{ActiveVersion: "system"}, false},
+		{"empty", "", State{}, false},
+		{"invalid schema version", "schema_version=one\n", State{}, true},
+		{"invalid updated_at", "updated_at=yesterday\n", State{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseState(strings.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("ParseState() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStateShellReadCommand checks that the init scripts read the active
+// version from the file saveActiveVersion writes
+func TestStateShellReadCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("init scripts are not used on Windows")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	installDir := filepath.Join(t.TempDir(), "versions")
+	m := createTestManager(t, installDir)
+	if err := m.saveActiveVersion("go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.saveActiveVersion("go1.22.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := m.ReadState()
+	if err != nil {
+		t.Fatalf("ReadState error: %v", err)
+	}
+	if state.ActiveVersion != "go1.22.0" || state.PreviousVersion != "go1.21.0" || state.UpdatedAt.IsZero() {
+		t.Errorf("ReadState() = %+v", state)
+	}
+
+	path, err := m.stateFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// #nosec G204 -- test runs the generated command on a temp file
+	out, err := exec.Command("sh", "-c", StateShellReadCommand(ShellQuote(path))).Output()
+	if err != nil {
+		t.Fatalf("shell read failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "go1.22.0" {
+		t.Errorf("shell read %q, want go1.22.0", got)
+	}
+
+	// Re-activating the same version keeps the previous one
+	if err := m.saveActiveVersion("go1.22.0"); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := m.ReadState(); state.PreviousVersion != "go1.21.0" {
+		t.Errorf("previous version = %q after re-activating, want go1.21.0", state.PreviousVersion)
+	}
+}
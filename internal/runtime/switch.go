@@ -2,16 +2,29 @@ package runtime
 
 import (
 	"fmt"
+	"os"
 	"runtime"
 	"time"
 
 	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/log"
 )
 
 // ============================================================================
 // Version Switching Operations
 // ============================================================================
 
+// UseResult is the outcome of switching versions with Use
+type UseResult struct {
+	Version  string `json:"version"`            // Active version: a normalized version, "system" or "homebrew"
+	Alias    string `json:"alias,omitempty"`    // Alias the version was resolved from
+	Previous string `json:"previous,omitempty"` // Version active before the switch, if one was recorded
+	Symlink  string `json:"symlink,omitempty"`  // go symlink that was updated; empty when none is used
+	Target   string `json:"target"`             // go binary now in use
+
+	PostUseError string `json:"post_use_error,omitempty"` // Why the post_use hook failed; the switch itself succeeded
+}
+
 // Use switches to a specific Go version by creating a symlink.
 //
 // It handles switching between different Go versions by creating a symlink
@@ -20,97 +33,211 @@ import (
 //
 // Special version values:
 //   - "system" or "sys": Switch to system-installed Go
+//   - "homebrew": Switch to the Go installed by Homebrew
 //   - Alias names: Resolve to the actual version
 //   - Version strings: Direct version switching
 //
 // Parameters:
 //   - version: The Go version to switch to (e.g., "1.21.0", "system", "stable")
 //
-// Returns an error if the switching fails at any step.
+// Returns what was switched, or an error if the switching fails at any step.
 //
 // Example:
 //
 //	// Switch to a specific version
-//	err := manager.Use("1.21.0")
+//	result, err := manager.Use("1.21.0")
 //
 //	// Switch to system Go
-//	err := manager.Use("system")
+//	result, err := manager.Use("system")
 //
 //	// Switch using an alias
-//	err := manager.Use("stable")
-func (m *Manager) Use(version string) error {
-	// Handle special case for system version
-	if version == "system" || version == "sys" {
-		return m.useSystemVersion()
-	}
-
-	// Check if version is an alias
-	if alias, exists := m.aliasManager.GetAlias(version); exists {
-		fmt.Printf("Using alias '%s' -> %s\n", version, alias.Version)
-		version = alias.Version
-	}
-
-	// Validate version format
-	if err := ValidateVersion(version); err != nil {
-		return fmt.Errorf("invalid version: %w", err)
+//	result, err := manager.Use("stable")
+//	fmt.Println(result.Alias, "->", result.Version)
+//
+// After a successful switch the post_use hook, if configured, runs with
+// GOPHER_VERSION and GOPHER_PREVIOUS_VERSION set. A failing hook is reported
+// in the result but does not undo the switch.
+func (m *Manager) Use(version string) (*UseResult, error) {
+	result, err := m.use(version)
+	if err != nil {
+		return nil, err
 	}
+	m.runPostUseHook(result)
+	return result, nil
+}
 
-	// Normalize version
-	version = NormalizeVersion(version)
+// use performs the switch for Use, without running the post_use hook
+func (m *Manager) use(version string) (*UseResult, error) {
+	result := &UseResult{}
+	// No previous version is recorded before the first switch
+	result.Previous, _ = m.getActiveVersionFromState()
 
-	// Check if installed
-	installed, err := m.IsInstalled(version)
-	if err != nil {
-		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to check if version is installed")
+	// Handle special case for system version
+	if version == "system" || version == "sys" {
+		target, err := m.useSystemVersion()
+		if err != nil {
+			return nil, err
+		}
+		result.Version, result.Target = "system", target
+		if runtime.GOOS != "windows" {
+			result.Symlink, _ = m.gopherSymlinkPath()
+		}
+		return result, nil
 	}
-	if !installed {
-		return errors.NewVersionNotInstalled(version)
+	if version == HomebrewVersion {
+		target, err := m.useHomebrewVersion()
+		if err != nil {
+			return nil, err
+		}
+		result.Version, result.Target = HomebrewVersion, target
+		result.Symlink, _ = m.gopherSymlinkPath()
+		return result, nil
 	}
 
-	// Get the go binary path
-	binaryPath, err := m.installer.GetGoBinaryPath(version)
+	version, binaryPath, err := m.resolveUseTarget(version, result)
 	if err != nil {
-		return errors.Wrapf(err, errors.ErrCodeUnknown, "failed to get go binary path")
+		return nil, err
 	}
 
 	// Create symlink or update PATH
 	if err := m.createSymlink(binaryPath); err != nil {
-		return errors.NewSymlinkFailed(binaryPath, "", err)
+		return nil, errors.NewSymlinkFailed(binaryPath, "", err)
 	}
+	result.Version, result.Target = version, binaryPath
+	result.Symlink, _ = m.gopherSymlinkPath()
 
 	// Try to add symlink directory to PATH for current session
 	if err := m.addSymlinkToPath(binaryPath); err != nil {
-		fmt.Printf("Warning: failed to add symlink to PATH: %v\n", err)
-		fmt.Printf("  You may need to manually add the symlink directory to your PATH\n")
+		fmt.Fprintf(os.Stderr, "Warning: failed to add symlink to PATH: %v\n", err)
+		fmt.Fprintf(os.Stderr, "  You may need to manually add the symlink directory to your PATH\n")
 	}
 
 	// Set up environment variables
 	if err := m.setupEnvironment(version); err != nil {
-		return errors.Wrapf(err, errors.ErrCodeEnvironmentSetupFailed, "failed to setup environment")
+		return nil, errors.Wrapf(err, errors.ErrCodeEnvironmentSetupFailed, "failed to setup environment")
 	}
 
 	// Check if GOPATH/bin is in PATH and alert user if not
 	m.checkGOPATHInPath(version)
 
+	// Warn if GOTOOLCHAIN lets Go bypass the selected version
+	m.checkToolchainSetting()
+
 	// Save the active version for persistence
 	if err := m.saveActiveVersion(version); err != nil {
-		fmt.Printf("Warning: failed to save active version: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to save active version: %v\n", err)
 	}
 
 	// Set up shell integration for persistence
 	if err := m.setupShellIntegration(); err != nil {
-		fmt.Printf("Warning: failed to setup shell integration: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to setup shell integration: %v\n", err)
 	}
 
 	// On Windows, check PATH order and warn if system Go will take precedence
 	if runtime.GOOS == "windows" {
 		if err := m.checkWindowsPathOrder(); err != nil {
 			// Non-fatal: show warning but don't fail
-			fmt.Printf("\n%v\n", err)
+			fmt.Fprintf(os.Stderr, "\n%v\n", err)
+			return result, nil
 		}
 	}
 
-	return nil
+	// Warn if another go earlier in PATH hides the one just activated
+	m.warnIfPathShadowed()
+
+	return result, nil
+}
+
+// SetDefault makes version the Go version that new shells get, without
+// activating it in the running shell.
+//
+// Like Use, it points the go symlink at the version, records it in the state
+// file and writes the environment script and shell integration that new
+// shells load. Unlike Use, it does not print instructions for activating the
+// version in the current shell, which keeps its environment until the
+// profile is re-sourced or 'gopher env activate' is evaluated in it.
+//
+// "system" and "homebrew" are handled by Use. The post_use hook runs as it
+// does for Use.
+func (m *Manager) SetDefault(version string) (*UseResult, error) {
+	if version == "system" || version == "sys" || version == HomebrewVersion {
+		return m.Use(version)
+	}
+
+	result := &UseResult{}
+	// No previous version is recorded before the first switch
+	result.Previous, _ = m.getActiveVersionFromState()
+
+	version, binaryPath, err := m.resolveUseTarget(version, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.createSymlink(binaryPath); err != nil {
+		return nil, errors.NewSymlinkFailed(binaryPath, "", err)
+	}
+	result.Version, result.Target = version, binaryPath
+	result.Symlink, _ = m.gopherSymlinkPath()
+
+	if m.config.SetEnvironment {
+		if _, err := m.createEnvironmentScript(version, m.config.GetEnvironmentVariables(version)); err != nil {
+			return nil, errors.Wrapf(err, errors.ErrCodeEnvironmentSetupFailed, "failed to setup environment")
+		}
+	}
+
+	// The state file is what new shells read, so failing to write it fails
+	// the whole operation
+	if err := m.saveActiveVersion(version); err != nil {
+		return nil, errors.Wrapf(err, errors.ErrCodeUnknown, "failed to save active version")
+	}
+
+	if err := m.setupShellIntegration(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to setup shell integration: %v\n", err)
+	}
+
+	m.runPostUseHook(result)
+	return result, nil
+}
+
+// resolveUseTarget resolves an alias or version for Use and SetDefault to
+// the installed version and its go binary. An alias is recorded in result.
+func (m *Manager) resolveUseTarget(version string, result *UseResult) (string, string, error) {
+	// Check if version is an alias
+	if alias, exists := m.aliasManager.GetAlias(version); exists {
+		fmt.Fprintf(os.Stderr, "Using alias '%s' -> %s\n", version, alias.Version)
+		result.Alias = version
+		version = alias.Version
+	}
+
+	// Validate version format
+	if err := ValidateVersion(version); err != nil {
+		return "", "", fmt.Errorf("invalid version: %w", err)
+	}
+
+	// Normalize version
+	version = NormalizeVersion(version)
+
+	// Check if installed
+	installed, err := m.IsInstalled(version)
+	if err != nil {
+		return "", "", errors.Wrapf(err, errors.ErrCodeUnknown, "failed to check if version is installed")
+	}
+	if !installed {
+		return "", "", errors.NewVersionNotInstalled(version)
+	}
+
+	// Refuse to activate a version built for another platform
+	if err := m.checkPlatform(version); err != nil {
+		return "", "", err
+	}
+
+	// Get the go binary path
+	binaryPath, err := m.installer.GetGoBinaryPath(version)
+	if err != nil {
+		return "", "", errors.Wrapf(err, errors.ErrCodeUnknown, "failed to get go binary path")
+	}
+
+	return version, binaryPath, nil
 }
 
 // GetCurrent returns the currently active Go version.
@@ -140,6 +267,10 @@ func (m *Manager) GetCurrent() (*Version, error) {
 			if systemDetector.IsSystemGoAvailable() {
 				return systemDetector.DetectSystemGo()
 			}
+		} else if activeVersion == HomebrewVersion {
+			if version, err := m.homebrewVersion(); err == nil {
+				return version, nil
+			}
 		} else {
 			// It's a gopher-managed version, get its info
 			if version, err := m.getVersionInfo(activeVersion); err == nil {
@@ -150,6 +281,12 @@ func (m *Manager) GetCurrent() (*Version, error) {
 
 	// Try to detect from symlinks
 	if activeVersion, err := m.getCurrentActiveVersion(); err == nil {
+		if activeVersion == HomebrewVersion {
+			if version, err := m.homebrewVersion(); err == nil {
+				return version, nil
+			}
+		}
+
 		// Check if it's a system version
 		if systemDetector.IsSystemGoAvailable() {
 			if systemVersion, err := systemDetector.DetectSystemGo(); err == nil {
@@ -180,55 +317,95 @@ func (m *Manager) GetCurrent() (*Version, error) {
 	}, nil
 }
 
-// useSystemVersion switches to the system Go version.
+// GetActiveVersion returns the version recorded as active by 'gopher use':
+// a Gopher-managed version, "system" or HomebrewVersion. It returns "" when no
+// version has been selected.
+//
+// Only the state file is read. The recorded version is not checked against
+// the go symlink or PATH, so it can be stale; GetCurrent does that
+// reconciliation and should be used when correctness matters more than speed.
+//
+// Example:
+//
+//	version, err := manager.GetActiveVersion()
+func (m *Manager) GetActiveVersion() (string, error) {
+	version, err := m.getActiveVersionFromState()
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, errors.ErrCodeUnknown, "failed to read active version")
+	}
+	return version, nil
+}
+
+// PromptVersion returns the Gopher-managed version recorded as active, for
+// shell prompts.
+//
+// Unlike GetCurrent it only reads the state file: no go binary is run and
+// nothing is detected, so it is cheap enough to call on every prompt render.
+// Returns "" when system or Homebrew Go is active or no version has been
+// selected with 'gopher use'.
+func (m *Manager) PromptVersion() string {
+	version, err := m.GetActiveVersion()
+	if err != nil || version == "system" || version == HomebrewVersion {
+		return ""
+	}
+	return version
+}
+
+// useSystemVersion switches to the system Go version and returns the go
+// binary it switched to.
 //
 // This is called internally when Use("system") is invoked.
 // It handles platform-specific switching logic.
-func (m *Manager) useSystemVersion() error {
+func (m *Manager) useSystemVersion() (string, error) {
 	systemDetector := NewSystemDetector()
 	if !systemDetector.IsSystemGoAvailable() {
-		return fmt.Errorf("system Go not available")
+		return "", fmt.Errorf("system Go not available")
 	}
 
 	// Get system Go path
 	systemPath, err := systemDetector.GetSystemGoPath()
 	if err != nil {
-		return fmt.Errorf("failed to get system Go path: %w", err)
+		return "", fmt.Errorf("failed to get system Go path: %w", err)
 	}
 
 	// On Windows, remove gopher symlinks to let system Go be found naturally
 	if runtime.GOOS == "windows" {
 		if err := m.removeGopherSymlinks(); err != nil {
-			fmt.Printf("Warning: failed to remove gopher symlinks: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove gopher symlinks: %v\n", err)
 		}
-		fmt.Printf("✓ Switched to system Go version\n")
-		fmt.Printf("  System Go path: %s\n", systemPath)
+		fmt.Fprintf(os.Stderr, "✓ Switched to system Go version\n")
+		fmt.Fprintf(os.Stderr, "  System Go path: %s\n", systemPath)
 	} else {
 		// On Unix systems, create symlink to system Go
 		if err := m.createSymlink(systemPath); err != nil {
-			return fmt.Errorf("failed to create symlink: %w", err)
+			return "", fmt.Errorf("failed to create symlink: %w", err)
 		}
 	}
 
 	// Set up environment for system Go
 	if err := m.setupSystemEnvironment(); err != nil {
-		return fmt.Errorf("failed to setup system environment: %w", err)
+		return "", fmt.Errorf("failed to setup system environment: %w", err)
 	}
 
 	// Check if GOPATH/bin is in PATH for system Go
 	m.checkGOPATHInPath("system")
+	m.checkToolchainSetting()
+	m.warnIfPathShadowed()
 
 	// Save the system version as active
 	if err := m.saveActiveVersion("system"); err != nil {
-		fmt.Printf("Warning: failed to save active version: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to save active version: %v\n", err)
 	}
 
 	// Set up shell integration for persistence
 	if err := m.setupShellIntegration(); err != nil {
-		fmt.Printf("Warning: failed to setup shell integration: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to setup shell integration: %v\n", err)
 	}
 
-	return nil
+	return systemPath, nil
 }
 
 // GetSystemInfo returns detailed information about system Go.
@@ -248,5 +425,32 @@ func (m *Manager) GetSystemInfo() (*SystemGoInfo, error) {
 	if !systemDetector.IsSystemGoAvailable() {
 		return nil, fmt.Errorf("system Go not available")
 	}
-	return systemDetector.GetSystemGoInfo()
+	info, err := systemDetector.GetSystemGoInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	// Report Homebrew Go alongside, unless it is the system Go itself
+	if homebrewInfo, err := systemDetector.GetHomebrewGoInfo(); err == nil && homebrewInfo.GOROOT != info.GOROOT {
+		info.Homebrew = homebrewInfo
+	}
+	return info, nil
+}
+
+// RefreshSystemInfo re-detects system Go using the PATH a new login shell
+// would have, rather than the PATH gopher inherited.
+//
+// Detection is never cached, but the inherited PATH goes stale when a profile
+// is edited (e.g. by 'gopher setup') and not re-sourced. The refreshed PATH is
+// also applied to this process. On Windows, or when the login shell cannot be
+// run, it falls back to the current PATH.
+func (m *Manager) RefreshSystemInfo() (*SystemGoInfo, error) {
+	if runtime.GOOS != "windows" {
+		if path, err := loginShellPATH(m.envProvider.Getenv("SHELL")); err != nil {
+			log.Debug("keeping current PATH: %v", err)
+		} else if err := os.Setenv("PATH", path); err != nil {
+			return nil, errors.Wrapf(err, errors.ErrCodeEnvironmentSetupFailed, "failed to set PATH")
+		}
+	}
+	return m.GetSystemInfo()
 }
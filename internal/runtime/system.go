@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/molmedoz/gopher/internal/downloader"
 	"github.com/molmedoz/gopher/internal/errors"
 )
 
@@ -17,26 +18,58 @@ import (
 // System Go Detection
 // ============================================================================
 
+// CommandRunner runs the commands system detection relies on, so tests can
+// feed canned 'go version' and 'go env' output instead of needing a Go
+// installation
+type CommandRunner interface {
+	// Run runs name with args and returns its standard output
+	Run(name string, args ...string) ([]byte, error)
+	// LookPath searches PATH for an executable, like exec.LookPath
+	LookPath(file string) (string, error)
+}
+
+// ExecCommandRunner runs real commands, each bounded by the system
+// detection timeout
+type ExecCommandRunner struct{}
+
+// Run implements CommandRunner
+func (ExecCommandRunner) Run(name string, args ...string) ([]byte, error) {
+	return runDetectionCommand(name, args...)
+}
+
+// LookPath implements CommandRunner
+func (ExecCommandRunner) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
+
 // SystemDetectorImpl handles detection of system-installed Go versions
-type SystemDetectorImpl struct{}
+type SystemDetectorImpl struct {
+	runner CommandRunner
+}
 
-// NewSystemDetector creates a new system detector
+// NewSystemDetector creates a new system detector that runs real commands
 func NewSystemDetector() *SystemDetectorImpl {
-	return &SystemDetectorImpl{}
+	return NewSystemDetectorWithRunner(ExecCommandRunner{})
+}
+
+// NewSystemDetectorWithRunner creates a system detector that runs commands
+// through runner, e.g. a MockCommandRunner in tests
+func NewSystemDetectorWithRunner(runner CommandRunner) *SystemDetectorImpl {
+	return &SystemDetectorImpl{runner: runner}
 }
 
 // DetectSystemGo detects the system-installed Go version
 func (sd *SystemDetectorImpl) DetectSystemGo() (*Version, error) {
 	// Try to find go binary in PATH
-	goPath, err := exec.LookPath("go")
+	goPath, err := sd.runner.LookPath("go")
 	if err != nil {
 		return nil, fmt.Errorf("go not found in PATH: %w", err)
 	}
 
 	// Get the version by running 'go version' with controlled context
-	output, err := runGoCommand("version")
+	output, err := sd.runGoCommand("version")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get go version: %w", err)
+		return nil, detectionError("failed to get go version", err)
 	}
 
 	// Parse the version output
@@ -137,12 +170,92 @@ func (sd *SystemDetectorImpl) isSystemInstallation(goPath string) bool {
 		return true
 	}
 
-	return false
+	// Go installed by snap, flatpak or nix lives outside the usual directories
+	return packageSource(goPath) != ""
+}
+
+// packagePaths lists where snap, flatpak and nix put Go. Nix store paths are
+// hashed (/nix/store/<hash>-go-1.21.0/bin/go), so directories are matched as
+// prefixes; entries under a home directory are matched anywhere in the path.
+var packagePaths = []struct {
+	dir    string
+	source string
+	inHome bool
+}{
+	{"/snap/", "snap", false},
+	{"/var/lib/flatpak/", "flatpak", false},
+	{"/.local/share/flatpak/", "flatpak", true},
+	{"/usr/lib/sdk/golang/", "flatpak", false}, // SDK extension inside a flatpak sandbox
+	{"/nix/store/", "nix", false},
+	{"/nix/var/nix/profiles/", "nix", false},
+	{"/.nix-profile/", "nix", true},
+	{"/run/current-system/sw/", "nix", false},
+	{"/etc/profiles/per-user/", "nix", false},
+}
+
+// packageSource returns the package manager ("snap", "flatpak" or "nix")
+// that installed the go binary at goPath, or "" if it is none of them.
+// Symlinks are followed, since these managers link go into a bin directory.
+func packageSource(goPath string) string {
+	for _, candidate := range resolvedPaths(goPath) {
+		for _, entry := range packagePaths {
+			if strings.HasPrefix(candidate, entry.dir) || entry.inHome && strings.Contains(candidate, entry.dir) {
+				return entry.source
+			}
+		}
+	}
+	return ""
+}
+
+// installPaths lists where other installers put Go, after following
+// symlinks (Debian links /usr/bin/go to /usr/lib/go-1.21/bin/go)
+var installPaths = []struct {
+	dir    string
+	source string
+}{
+	{"/opt/homebrew/", "homebrew"},
+	{"/usr/local/opt/go/", "homebrew"},
+	{"/usr/local/Cellar/go/", "homebrew"},
+	{"/home/linuxbrew/.linuxbrew/", "homebrew"},
+	{"/usr/lib/go-", "apt"},
+	{"/usr/lib/golang/", "dnf"},
+	{"/usr/lib/go/", "distro-package"}, // Arch, Alpine and others
+	{"/usr/local/go/", "official-tarball"},
+	{"/opt/go/", "official-tarball"},
+	{"C:/Program Files/Go/", "official-installer"},
+	{"C:/Go/", "official-installer"},
+}
+
+// installSource describes how the go binary at goPath was installed:
+// "homebrew", "apt", "dnf", "distro-package", "official-tarball",
+// "official-installer", "snap", "flatpak", "nix", or "unknown"
+func installSource(goPath string) string {
+	if source := packageSource(goPath); source != "" {
+		return source
+	}
+	for _, candidate := range resolvedPaths(goPath) {
+		for _, entry := range installPaths {
+			if strings.HasPrefix(candidate, entry.dir) {
+				return entry.source
+			}
+		}
+	}
+	return "unknown"
+}
+
+// resolvedPaths returns goPath and, if it is a symlink, its target, both
+// with forward slashes
+func resolvedPaths(goPath string) []string {
+	paths := []string{filepath.ToSlash(goPath)}
+	if resolved, err := filepath.EvalSymlinks(goPath); err == nil && resolved != goPath {
+		paths = append(paths, filepath.ToSlash(resolved))
+	}
+	return paths
 }
 
 // GetSystemGoPath returns the path to the system Go binary
 func (sd *SystemDetectorImpl) GetSystemGoPath() (string, error) {
-	goPath, err := exec.LookPath("go")
+	goPath, err := sd.runner.LookPath("go")
 	if err != nil {
 		return "", fmt.Errorf("go not found in PATH: %w", err)
 	}
@@ -151,7 +264,7 @@ func (sd *SystemDetectorImpl) GetSystemGoPath() (string, error) {
 
 // IsSystemGoAvailable checks if system Go is available
 func (sd *SystemDetectorImpl) IsSystemGoAvailable() bool {
-	_, err := exec.LookPath("go")
+	_, err := sd.runner.LookPath("go")
 	return err == nil
 }
 
@@ -163,27 +276,21 @@ func (sd *SystemDetectorImpl) GetSystemGoInfo() (*SystemGoInfo, error) {
 	}
 
 	// Get version
-	output, err := runGoCommand("version")
+	output, err := sd.runGoCommand("version")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get go version: %w", err)
+		return nil, detectionError("failed to get go version", err)
 	}
 
 	// Get GOROOT
-	gorootOutput, err := runGoCommand("env", "GOROOT")
+	gorootOutput, err := sd.runGoCommand("env", "GOROOT")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get GOROOT: %w", err)
+		return nil, detectionError("failed to get GOROOT", err)
 	}
 
 	// Get GOPATH
-	gopathOutput, err := runGoCommand("env", "GOPATH")
-	if err != nil {
-		return nil, fmt.Errorf("failed to get GOPATH: %w", err)
-	}
-
-	// Get file info
-	_, err = os.Stat(goPath)
+	gopathOutput, err := sd.runGoCommand("env", "GOPATH")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get file info: %w", err)
+		return nil, detectionError("failed to get GOPATH", err)
 	}
 
 	return &SystemGoInfo{
@@ -192,21 +299,83 @@ func (sd *SystemDetectorImpl) GetSystemGoInfo() (*SystemGoInfo, error) {
 		GOPATH:     strings.TrimSpace(string(gopathOutput)),
 		Executable: goPath,
 		IsValid:    true,
+		Source:     installSource(goPath),
 	}, nil
 }
 
 // runGoCommand executes the system 'go' command with a short timeout and fixed binary name
 // to avoid executing arbitrary paths (addresses gosec G204).
-func runGoCommand(args ...string) ([]byte, error) {
+func (sd *SystemDetectorImpl) runGoCommand(args ...string) ([]byte, error) {
 	// Resolve via PATH to ensure 'go' exists, but do not pass a variable path to exec
-	if _, err := exec.LookPath("go"); err != nil {
+	if _, err := sd.runner.LookPath("go"); err != nil {
 		return nil, fmt.Errorf("go not found in PATH: %w", err)
 	}
-	// Use a context with timeout to prevent hanging commands
+	return sd.runner.Run("go", args...)
+}
+
+// systemDetectionTimeout bounds each go command run to detect an
+// installation, so a go binary on a hung network file system or broken
+// mount cannot hang gopher
+var systemDetectionTimeout = 5 * time.Second
+
+// commandOutput runs a command and returns its stdout. Tests replace it to
+// simulate slow or failing go binaries.
+var commandOutput = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+	// #nosec G204 -- name is "go" or a go binary found by Gopher, not user input
+	cmd := exec.CommandContext(ctx, name, args...)
+	// Do not wait on children that keep the output open after a kill
+	cmd.WaitDelay = time.Second
+	return cmd.Output()
+}
+
+// runDetectionCommand runs name with args within systemDetectionTimeout,
+// returning a TIMEOUT_EXCEEDED error if it does not finish in time
+func runDetectionCommand(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), systemDetectionTimeout)
+	defer cancel()
+	output, err := commandOutput(ctx, name, args...)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return nil, errors.Newf(errors.ErrCodeTimeoutExceeded,
+			"'%s %s' did not finish within %s (is it on a hung network file system or broken mount?)",
+			name, strings.Join(args, " "), systemDetectionTimeout)
+	}
+	return output, err
+}
+
+// detectionError adds context to an error from a detection command. A
+// timeout keeps its code, so it reaches the user as a timeout.
+func detectionError(message string, err error) error {
+	if timeout, ok := err.(*errors.GopherError); ok && timeout.Code == errors.ErrCodeTimeoutExceeded {
+		return errors.Newf(errors.ErrCodeTimeoutExceeded, "system Go detection timed out: %s", timeout.Message)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// pathMarker delimits PATH in login shell output, so anything the profile
+// prints is ignored
+const pathMarker = "__GOPHER_PATH__"
+
+// loginShellPATH returns PATH as set up by a fresh login shell, which reads
+// the user's profile. An empty shell means /bin/sh.
+func loginShellPATH(shell string) (string, error) {
+	if shell == "" {
+		shell = "/bin/sh"
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, "go", args...)
-	return cmd.Output()
+	// #nosec G204 -- shell comes from $SHELL, the user's own login shell
+	cmd := exec.CommandContext(ctx, shell, "-l", "-c", `printf '`+pathMarker+`%s`+pathMarker+`' "$PATH"`)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run login shell %s: %w", shell, err)
+	}
+
+	_, rest, found := strings.Cut(string(output), pathMarker)
+	path, _, closed := strings.Cut(rest, pathMarker)
+	if !found || !closed || path == "" {
+		return "", fmt.Errorf("login shell %s did not report PATH", shell)
+	}
+	return path, nil
 }
 
 // ============================================================================
@@ -233,51 +402,9 @@ func NormalizeVersion(version string) string {
 	return "go" + version
 }
 
-// CompareVersions compares two version strings
+// CompareVersions compares two version strings numerically, taking
+// prerelease suffixes (rc, beta, alpha) into account
 // Returns -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
 func CompareVersions(v1, v2 string) int {
-	// Normalize versions
-	v1 = NormalizeVersion(v1)
-	v2 = NormalizeVersion(v2)
-
-	// Remove 'go' prefix for comparison
-	v1 = strings.TrimPrefix(v1, "go")
-	v2 = strings.TrimPrefix(v2, "go")
-
-	// Split into parts
-	parts1 := strings.Split(v1, ".")
-	parts2 := strings.Split(v2, ".")
-
-	// Compare major version
-	if len(parts1) > 0 && len(parts2) > 0 {
-		if parts1[0] != parts2[0] {
-			if parts1[0] < parts2[0] {
-				return -1
-			}
-			return 1
-		}
-	}
-
-	// Compare minor version
-	if len(parts1) > 1 && len(parts2) > 1 {
-		if parts1[1] != parts2[1] {
-			if parts1[1] < parts2[1] {
-				return -1
-			}
-			return 1
-		}
-	}
-
-	// Compare patch version
-	if len(parts1) > 2 && len(parts2) > 2 {
-		if parts1[2] != parts2[2] {
-			if parts1[2] < parts2[2] {
-				return -1
-			}
-			return 1
-		}
-	}
-
-	// If all parts are equal, versions are equal
-	return 0
+	return downloader.CompareVersions(NormalizeVersion(v1), NormalizeVersion(v2))
 }
@@ -1,9 +1,17 @@
 package runtime
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/molmedoz/gopher/internal/errors"
 )
 
 func TestSystemDetector_IsSystemInstallation(t *testing.T) {
@@ -269,6 +277,151 @@ func TestSystemDetector_GetSystemGoInfo(t *testing.T) {
 	}
 }
 
+// TestSystemDetector_CannedOutput feeds recorded 'go version' and 'go env'
+// output through a mock runner, so detection is covered without Go installed
+func TestSystemDetector_CannedOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		goPath      string
+		outputs     map[string]string
+		wantVersion string
+		wantGOROOT  string
+		wantGOPATH  string
+		wantSystem  bool
+	}{
+		{
+			name:   "linux release",
+			goPath: "/usr/local/go/bin/go",
+			outputs: map[string]string{
+				"go version":    "go version go1.21.0 linux/amd64\n",
+				"go env GOROOT": "/usr/local/go\n",
+				"go env GOPATH": "/home/gopher/go\n",
+			},
+			wantVersion: "go1.21.0",
+			wantGOROOT:  "/usr/local/go",
+			wantGOPATH:  "/home/gopher/go",
+			wantSystem:  true,
+		},
+		{
+			name:   "windows paths with CRLF",
+			goPath: "C:\\Program Files\\Go\\bin\\go.exe",
+			outputs: map[string]string{
+				"go version":    "go version go1.21.5 windows/amd64\r\n",
+				"go env GOROOT": "C:\\Program Files\\Go\r\n",
+				"go env GOPATH": "C:\\Users\\gopher\\go\r\n",
+			},
+			wantVersion: "go1.21.5",
+			wantGOROOT:  "C:\\Program Files\\Go",
+			wantGOPATH:  "C:\\Users\\gopher\\go",
+			wantSystem:  true,
+		},
+		{
+			name:   "devel build",
+			goPath: "/home/gopher/sdk/gotip/bin/go",
+			outputs: map[string]string{
+				"go version":    "go version devel go1.22-abc123 Tue Aug 1 12:00:00 2023 +0000 darwin/arm64\n",
+				"go env GOROOT": "/home/gopher/sdk/gotip\n",
+				"go env GOPATH": "/home/gopher/go\n",
+			},
+			wantVersion: "devel go1.22-abc123",
+			wantGOROOT:  "/home/gopher/sdk/gotip",
+			wantGOPATH:  "/home/gopher/go",
+			wantSystem:  false,
+		},
+		{
+			name:   "rc release",
+			goPath: "/usr/bin/go",
+			outputs: map[string]string{
+				"go version":    "go version go1.22rc1 linux/arm64\n",
+				"go env GOROOT": "/usr/lib/go-1.22\n",
+				"go env GOPATH": "/root/go\n",
+			},
+			wantVersion: "go1.22rc1",
+			wantGOROOT:  "/usr/lib/go-1.22",
+			wantGOPATH:  "/root/go",
+			wantSystem:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewSystemDetectorWithRunner(&MockCommandRunner{
+				Paths:   map[string]string{"go": tt.goPath},
+				Outputs: tt.outputs,
+			})
+
+			version, err := detector.DetectSystemGo()
+			if err != nil {
+				t.Fatalf("DetectSystemGo() error = %v", err)
+			}
+			if version.Version != tt.wantVersion || version.Path != tt.goPath || version.IsSystem != tt.wantSystem {
+				t.Errorf("DetectSystemGo() = %+v, want version %q at %q (system %v)", version, tt.wantVersion, tt.goPath, tt.wantSystem)
+			}
+
+			info, err := detector.GetSystemGoInfo()
+			if err != nil {
+				t.Fatalf("GetSystemGoInfo() error = %v", err)
+			}
+			if info.GOROOT != tt.wantGOROOT || info.GOPATH != tt.wantGOPATH || info.Executable != tt.goPath {
+				t.Errorf("GetSystemGoInfo() = %+v, want GOROOT %q and GOPATH %q", info, tt.wantGOROOT, tt.wantGOPATH)
+			}
+			if info.Version != strings.TrimSpace(tt.outputs["go version"]) {
+				t.Errorf("GetSystemGoInfo().Version = %q", info.Version)
+			}
+		})
+	}
+}
+
+func TestSystemDetector_CannedOutputErrors(t *testing.T) {
+	outputs := map[string]string{
+		"go version":    "go version go1.21.0 linux/amd64\n",
+		"go env GOROOT": "/usr/local/go\n",
+	}
+
+	tests := []struct {
+		name    string
+		runner  *MockCommandRunner
+		wantErr string
+	}{
+		{
+			name:    "go not in PATH",
+			runner:  &MockCommandRunner{},
+			wantErr: "go not found in PATH",
+		},
+		{
+			name: "go env fails",
+			runner: &MockCommandRunner{
+				Paths:   map[string]string{"go": "/usr/local/go/bin/go"},
+				Outputs: outputs,
+				Errors:  map[string]error{"go env GOPATH": fmt.Errorf("exit status 1")},
+			},
+			wantErr: "failed to get GOPATH",
+		},
+		{
+			name: "unparsable version",
+			runner: &MockCommandRunner{
+				Paths:   map[string]string{"go": "/usr/local/go/bin/go"},
+				Outputs: map[string]string{"go version": "go: cannot find GOROOT directory\n"},
+			},
+			wantErr: "failed to parse go version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewSystemDetectorWithRunner(tt.runner)
+			_, detectErr := detector.DetectSystemGo()
+			_, infoErr := detector.GetSystemGoInfo()
+			for _, err := range []error{detectErr, infoErr} {
+				if err != nil && strings.Contains(err.Error(), tt.wantErr) {
+					return
+				}
+			}
+			t.Errorf("expected an error containing %q, got %v and %v", tt.wantErr, detectErr, infoErr)
+		})
+	}
+}
+
 // TestVersion_String_SystemVersion tests string representation of system versions
 func TestVersion_String_SystemVersion(t *testing.T) {
 	version := Version{
@@ -395,17 +548,37 @@ func TestSystemDetector_isSystemInstallation_MorePaths(t *testing.T) {
 		{
 			name:     "snap path",
 			goPath:   "/snap/go/current/bin/go",
-			expected: false, // Not in our system paths
+			expected: true,
 		},
 		{
 			name:     "flatpak path",
 			goPath:   "/var/lib/flatpak/app/org.golang.Go/current/active/files/bin/go",
-			expected: false, // Not in our system paths
+			expected: true,
 		},
 		{
 			name:     "nix path",
 			goPath:   "/nix/store/abc123-go-1.21.0/bin/go",
-			expected: false, // Not in our system paths
+			expected: true,
+		},
+		{
+			name:     "snap bin path",
+			goPath:   "/snap/bin/go",
+			expected: true,
+		},
+		{
+			name:     "nix profile path",
+			goPath:   "/home/user/.nix-profile/bin/go",
+			expected: true,
+		},
+		{
+			name:     "nixos system path",
+			goPath:   "/run/current-system/sw/bin/go",
+			expected: true,
+		},
+		{
+			name:     "go path in home",
+			goPath:   "/home/user/sdk/go1.21.0/bin/go",
+			expected: false,
 		},
 	}
 
@@ -419,8 +592,171 @@ func TestSystemDetector_isSystemInstallation_MorePaths(t *testing.T) {
 	}
 }
 
+func TestPackageSource(t *testing.T) {
+	tests := []struct {
+		goPath string
+		want   string
+	}{
+		{"/snap/bin/go", "snap"},
+		{"/snap/go/10455/bin/go", "snap"},
+		{"/var/lib/flatpak/runtime/org.freedesktop.Sdk.Extension.golang/x86_64/23.08/active/files/bin/go", "flatpak"},
+		{"/home/user/.local/share/flatpak/app/org.golang.Go/current/active/files/bin/go", "flatpak"},
+		{"/usr/lib/sdk/golang/bin/go", "flatpak"},
+		{"/nix/store/0c5sdk2gfy0ylad9m4fqmhx6x3jw4kv2-go-1.21.0/bin/go", "nix"},
+		{"/home/user/.nix-profile/bin/go", "nix"},
+		{"/nix/var/nix/profiles/default/bin/go", "nix"},
+		{"/run/current-system/sw/bin/go", "nix"},
+		{"/etc/profiles/per-user/user/bin/go", "nix"},
+		{"/usr/local/go/bin/go", ""},
+		{"/home/user/snap-backup/go/bin/go", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goPath, func(t *testing.T) {
+			if got := packageSource(tt.goPath); got != tt.want {
+				t.Errorf("packageSource(%q) = %q, want %q", tt.goPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallSource(t *testing.T) {
+	tests := []struct {
+		goPath string
+		want   string
+	}{
+		{"/opt/homebrew/bin/go", "homebrew"},
+		{"/usr/local/opt/go/libexec/bin/go", "homebrew"},
+		{"/home/linuxbrew/.linuxbrew/bin/go", "homebrew"},
+		{"/usr/lib/go-1.21/bin/go", "apt"},
+		{"/usr/lib/golang/bin/go", "dnf"},
+		{"/usr/lib/go/bin/go", "distro-package"},
+		{"/usr/local/go/bin/go", "official-tarball"},
+		{`C:\Program Files\Go\bin\go.exe`, "official-installer"},
+		{"/snap/bin/go", "snap"},
+		{"/nix/store/0c5sdk2gfy0ylad9m4fqmhx6x3jw4kv2-go-1.21.0/bin/go", "nix"},
+		{"/home/user/sdk/go1.21.0/bin/go", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goPath, func(t *testing.T) {
+			if runtime.GOOS != "windows" && strings.Contains(tt.goPath, `\`) {
+				t.Skip("Windows path")
+			}
+			if got := installSource(tt.goPath); got != tt.want {
+				t.Errorf("installSource(%q) = %q, want %q", tt.goPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvedPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need Developer Mode on Windows")
+	}
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "nix", "store", "abc-go-1.21.0", "bin", "go")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "bin", "go")
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	paths := resolvedPaths(link)
+	if len(paths) != 2 || !strings.HasSuffix(paths[1], "/nix/store/abc-go-1.21.0/bin/go") {
+		t.Errorf("resolvedPaths(%q) = %v, want the link and its target", link, paths)
+	}
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && s[len(s)-len(substr):] == substr ||
 		len(s) > len(substr) && contains(s[:len(s)-1], substr)
 }
+
+func TestLoginShellPATH(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("login shells are not used on Windows")
+	}
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("/bin/sh not available")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ENV", "")
+	profile := "echo 'welcome'\nexport PATH=/opt/refreshed/bin:/usr/bin:/bin\n"
+	if err := os.WriteFile(filepath.Join(home, ".profile"), []byte(profile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := loginShellPATH("/bin/sh")
+	if err != nil {
+		t.Fatalf("loginShellPATH() error = %v", err)
+	}
+	if path != "/opt/refreshed/bin:/usr/bin:/bin" {
+		t.Errorf("loginShellPATH() = %q, want the PATH from .profile", path)
+	}
+}
+
+func TestLoginShellPATH_MissingShell(t *testing.T) {
+	if _, err := loginShellPATH(filepath.Join(t.TempDir(), "no-such-shell")); err == nil {
+		t.Error("loginShellPATH() should fail for a missing shell")
+	}
+}
+
+// slowCommands makes every detection command hang until it is canceled,
+// like a go binary on a hung network mount
+func slowCommands(t *testing.T) {
+	t.Helper()
+	oldOutput, oldTimeout := commandOutput, systemDetectionTimeout
+	t.Cleanup(func() { commandOutput, systemDetectionTimeout = oldOutput, oldTimeout })
+
+	systemDetectionTimeout = 50 * time.Millisecond
+	commandOutput = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+}
+
+func TestRunDetectionCommand_Timeout(t *testing.T) {
+	slowCommands(t)
+
+	start := time.Now()
+	_, err := runGoAtPath("/opt/slow/bin/go", "version")
+	if !errors.IsErrorCode(err, errors.ErrCodeTimeoutExceeded) {
+		t.Fatalf("expected TIMEOUT_EXCEEDED, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("detection took %s, want it bounded by the timeout", elapsed)
+	}
+	if !strings.Contains(err.Error(), "/opt/slow/bin/go version") {
+		t.Errorf("error should name the command: %v", err)
+	}
+
+	err = detectionError("failed to get go version", err)
+	if !errors.IsErrorCode(err, errors.ErrCodeTimeoutExceeded) || !strings.Contains(err.Error(), "system Go detection timed out") {
+		t.Errorf("expected a system Go detection timeout, got %v", err)
+	}
+}
+
+func TestSystemDetector_GetSystemGoInfo_Timeout(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("no go binary in PATH")
+	}
+	slowCommands(t)
+
+	_, err := NewSystemDetector().GetSystemGoInfo()
+	if !errors.IsErrorCode(err, errors.ErrCodeTimeoutExceeded) || !strings.Contains(err.Error(), "system Go detection timed out") {
+		t.Errorf("expected a system Go detection timeout, got %v", err)
+	}
+}
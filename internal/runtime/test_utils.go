@@ -3,12 +3,24 @@ package runtime
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/molmedoz/gopher/internal/config"
 	"github.com/molmedoz/gopher/internal/env"
 )
 
+// setTestHome points the home directory at home for the rest of the test.
+// A relative XDG_CONFIG_HOME keeps any go binary the test runs from writing
+// telemetry under home from a background process, which makes TempDir
+// cleanup fail; gopher ignores relative XDG directories.
+func setTestHome(t *testing.T, home string) {
+	t.Helper()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", "relative")
+}
+
 // Helper function to write metadata files
 func writeMetadata(t *testing.T, dir, version string) {
 	t.Helper()
@@ -19,8 +31,8 @@ func writeMetadata(t *testing.T, dir, version string) {
 	}
 	f := filepath.Join(vdir, ".gopher-metadata")
 	content := "version=" + version + "\n" +
-		"os=linux\n" +
-		"arch=amd64\n" +
+		"os=" + runtime.GOOS + "\n" +
+		"arch=" + runtime.GOARCH + "\n" +
 		"installed_at=2023-01-01T00:00:00Z\n" +
 		"install_dir=" + vdir + "\n"
 	// #nosec G306 -- 0644 acceptable for test files
@@ -24,10 +24,10 @@
 //	manager := NewManager(cfg, envProvider)
 //
 //	// Install a Go version
-//	err := manager.Install("1.21.0")
+//	_, err := manager.Install(context.Background(), "1.21.0")
 //
 //	// Switch to a version
-//	err := manager.Use("1.21.0")
+//	_, err := manager.Use("1.21.0")
 //
 //	// List installed versions
 //	versions, err := manager.ListInstalled()
@@ -38,6 +38,7 @@ package runtime
 import (
 	"fmt"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -46,6 +47,7 @@ import (
 	"github.com/molmedoz/gopher/internal/downloader"
 	"github.com/molmedoz/gopher/internal/env"
 	"github.com/molmedoz/gopher/internal/installer"
+	"github.com/molmedoz/gopher/internal/progress"
 )
 
 // Manager is the main orchestrator for Go version management.
@@ -66,13 +68,15 @@ import (
 //
 //	cfg := config.Load("/path/to/config.json")
 //	manager := NewManager(cfg, envProvider)
-//	err := manager.Install("1.21.0")
+//	_, err := manager.Install(context.Background(), "1.21.0")
 type Manager struct {
 	config       *config.Config
 	downloader   *downloader.Downloader
 	installer    *installer.Installer
 	aliasManager *AliasManager
 	envProvider  env.Provider
+	onProgress   func(progress.ProgressEvent) // Install events; see SetProgressHandler
+	noHooks      bool                         // Skip the configured hooks; see SetNoHooks
 }
 
 // Alias represents a version alias that provides a shortcut name for a Go version.
@@ -148,6 +152,8 @@ type Version struct {
 	IsActive    bool      `json:"is_active"`
 	IsSystem    bool      `json:"is_system"`
 	Path        string    `json:"path,omitempty"`
+	SHA256      string    `json:"sha256,omitempty"` // Checksum of the archive it was installed from, if recorded
+	Size        int64     `json:"size,omitempty"`   // Disk usage in bytes, set by Manager.AddDiskUsage
 }
 
 // String returns the string representation of the version
@@ -166,6 +172,21 @@ func (v *Version) FullString() string {
 	return fmt.Sprintf("%s (%s/%s)", v.Version, v.OS, v.Arch)
 }
 
+// Versions is a list of Go versions that can be sorted for display
+type Versions []Version
+
+// Sort orders the versions newest first, like list-remote, comparing version
+// numbers numerically so go1.21.0 comes before go1.9.0. System versions are
+// kept ahead of Gopher-managed ones.
+func (vs Versions) Sort() {
+	sort.SliceStable(vs, func(i, j int) bool {
+		if vs[i].IsSystem != vs[j].IsSystem {
+			return vs[i].IsSystem
+		}
+		return CompareVersions(vs[i].Version, vs[j].Version) > 0
+	})
+}
+
 // DisplayString returns the string representation with active indicator and colors
 func (v *Version) DisplayString() string {
 	base := v.FullString()
@@ -229,4 +250,12 @@ type SystemGoInfo struct {
 	GOPATH     string `json:"gopath"`
 	Executable string `json:"executable"`
 	IsValid    bool   `json:"is_valid"`
+
+	// Source is how this Go was installed, worked out from the executable
+	// path: "homebrew", "apt", "dnf", "distro-package", "official-tarball",
+	// "official-installer", "snap", "flatpak", "nix" or "unknown"
+	Source string `json:"source"`
+
+	// Homebrew describes the Homebrew-managed Go, if installed
+	Homebrew *SystemGoInfo `json:"homebrew,omitempty"`
 }
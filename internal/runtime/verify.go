@@ -0,0 +1,149 @@
+package runtime
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/molmedoz/gopher/internal/errors"
+	"github.com/molmedoz/gopher/internal/installer"
+	"github.com/molmedoz/gopher/internal/security"
+)
+
+// ============================================================================
+// Installation Integrity Checks
+// ============================================================================
+
+// sha256Regex matches a hex-encoded SHA256 checksum
+var sha256Regex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// VerifyResult describes the outcome of an integrity check for one installed
+// version. A version is OK when Problems is empty.
+type VerifyResult struct {
+	Version  string   `json:"version"`
+	OK       bool     `json:"ok"`
+	SHA256   string   `json:"sha256,omitempty"` // Recorded archive checksum, if any
+	Problems []string `json:"problems,omitempty"`
+}
+
+// ValidateArchive checks that the archive at path could be installed, without
+// extracting it. The info holds the detected version and size even when the
+// archive is rejected.
+//
+// Example:
+//
+//	info, err := manager.ValidateArchive("go1.21.0.linux-amd64.tar.gz")
+//	if err == nil {
+//	    fmt.Println("installable:", info.Version)
+//	}
+func (m *Manager) ValidateArchive(path string) (*installer.ArchiveInfo, error) {
+	info, err := m.installer.Validate(path)
+	if err != nil {
+		return info, errors.Wrapf(err, errors.ErrCodeExtractionFailed, "archive %s cannot be installed", path)
+	}
+	return info, nil
+}
+
+// Verify checks the integrity of an installed Go version.
+//
+// It confirms that:
+//   - The .gopher-metadata file is present, parseable and names the version
+//   - The recorded archive SHA256, if any, is well-formed
+//   - The go binary exists
+//   - Running the go binary with 'version' reports the expected version
+//
+// Problems found with the installation are reported in the result rather than
+// as an error; an error is returned only if the version is invalid or not
+// installed.
+//
+// Example:
+//
+//	result, err := manager.Verify("1.21.0")
+//	if err == nil && !result.OK {
+//	    fmt.Println("corrupt:", result.Problems)
+//	}
+func (m *Manager) Verify(version string) (*VerifyResult, error) {
+	// Validate version format
+	if err := ValidateVersion(version); err != nil {
+		return nil, fmt.Errorf("invalid version: %w", err)
+	}
+
+	// Validate version for security (path traversal protection)
+	if err := security.ValidatePath(version); err != nil {
+		return nil, fmt.Errorf("invalid version: %w", err)
+	}
+
+	// Normalize version
+	version = NormalizeVersion(version)
+
+	if !m.installer.IsInstalled(version) {
+		return nil, errors.NewVersionNotInstalled(version)
+	}
+
+	return m.verifyInstalled(version), nil
+}
+
+// VerifyAll checks the integrity of every Gopher-managed version, sorted by
+// version. System Go is not included.
+func (m *Manager) VerifyAll() ([]VerifyResult, error) {
+	versions, err := m.installer.ListInstalled()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installed versions: %w", err)
+	}
+
+	sort.Strings(versions)
+	results := make([]VerifyResult, 0, len(versions))
+	for _, version := range versions {
+		results = append(results, *m.verifyInstalled(version))
+	}
+
+	return results, nil
+}
+
+// verifyInstalled runs the integrity checks for an installed version
+func (m *Manager) verifyInstalled(version string) *VerifyResult {
+	result := &VerifyResult{Version: version}
+
+	metadata, err := m.installer.GetVersionMetadata(version)
+	switch {
+	case err != nil:
+		result.Problems = append(result.Problems, fmt.Sprintf("metadata is missing or unreadable: %v", err))
+	case metadata["version"] != version:
+		result.Problems = append(result.Problems, fmt.Sprintf("metadata names version %q", metadata["version"]))
+	}
+
+	// Versions installed before checksums were recorded have no sha256
+	if sum, ok := metadata["sha256"]; ok {
+		result.SHA256 = sum
+		if !sha256Regex.MatchString(sum) {
+			result.Problems = append(result.Problems, fmt.Sprintf("metadata has a malformed sha256 %q", sum))
+		}
+	}
+
+	binaryPath, err := m.installer.GetGoBinaryPath(version)
+	if err != nil {
+		result.Problems = append(result.Problems, "go binary not found")
+		return result
+	}
+
+	output, err := runGoVersionAtPath(binaryPath)
+	if err != nil {
+		result.Problems = append(result.Problems, fmt.Sprintf("go binary failed to run: %v", err))
+	} else if reported := parseGoVersionOutput(string(output)); reported != version {
+		result.Problems = append(result.Problems, fmt.Sprintf("go binary reports version %q", reported))
+	}
+
+	result.OK = len(result.Problems) == 0
+	return result
+}
+
+// parseGoVersionOutput extracts the version from 'go version' output
+// (e.g. "go version go1.21.0 linux/amd64" -> "go1.21.0")
+func parseGoVersionOutput(output string) string {
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != "go" || fields[1] != "version" {
+		return strings.TrimSpace(output)
+	}
+	return fields[2]
+}
@@ -0,0 +1,160 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeFakeGoBinary installs a shell script as bin/go that reports the
+// given version
+func writeFakeGoBinary(t *testing.T, dir, version, reports string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake go binary is a shell script")
+	}
+	binDir := filepath.Join(dir, version, "bin")
+	// #nosec G301 -- 0755 acceptable for test directory
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho \"go version " + reports + " linux/amd64\"\n"
+	// #nosec G306 -- test binary must be executable
+	if err := os.WriteFile(filepath.Join(binDir, "go"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tmp := t.TempDir()
+	m := createTestManager(t, tmp)
+
+	writeMetadata(t, tmp, "go1.21.0")
+	writeFakeGoBinary(t, tmp, "go1.21.0", "go1.21.0")
+
+	// Reports the wrong version
+	writeMetadata(t, tmp, "go1.22.0")
+	writeFakeGoBinary(t, tmp, "go1.22.0", "go1.20.0")
+
+	// Missing go binary
+	writeMetadata(t, tmp, "go1.23.0")
+
+	// Missing metadata
+	writeFakeGoBinary(t, tmp, "go1.24.0", "go1.24.0")
+
+	result, err := m.Verify("1.21.0")
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	if !result.OK || len(result.Problems) != 0 {
+		t.Errorf("expected go1.21.0 to be OK, got %+v", result)
+	}
+
+	results, err := m.VerifyAll()
+	if err != nil {
+		t.Fatalf("VerifyAll error: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+
+	want := map[string]string{
+		"go1.21.0": "",
+		"go1.22.0": "reports version",
+		"go1.23.0": "go binary not found",
+		"go1.24.0": "metadata",
+	}
+	for _, r := range results {
+		problem, ok := want[r.Version]
+		if !ok {
+			t.Errorf("unexpected version in results: %s", r.Version)
+			continue
+		}
+		if problem == "" {
+			if !r.OK {
+				t.Errorf("%s: expected OK, got problems %v", r.Version, r.Problems)
+			}
+			continue
+		}
+		if r.OK || !strings.Contains(strings.Join(r.Problems, "; "), problem) {
+			t.Errorf("%s: expected problem containing %q, got %+v", r.Version, problem, r)
+		}
+	}
+}
+
+func TestVerify_NotInstalled(t *testing.T) {
+	tmp := t.TempDir()
+	m := createTestManager(t, tmp)
+
+	if _, err := m.Verify("1.21.0"); err == nil {
+		t.Error("expected error for a version that is not installed")
+	}
+}
+
+func TestParseGoVersionOutput(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"go version go1.21.0 linux/amd64\n", "go1.21.0"},
+		{"go version go1.22rc1 darwin/arm64", "go1.22rc1"},
+		{"garbage\n", "garbage"},
+	}
+	for _, tt := range tests {
+		if got := parseGoVersionOutput(tt.output); got != tt.want {
+			t.Errorf("parseGoVersionOutput(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestVerify_SHA256(t *testing.T) {
+	tmp := t.TempDir()
+	m := createTestManager(t, tmp)
+	sum := strings.Repeat("ab", 32)
+
+	appendMetadata := func(version, line string) {
+		t.Helper()
+		f, err := os.OpenFile(filepath.Join(tmp, version, ".gopher-metadata"), os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeMetadata(t, tmp, "go1.21.0")
+	writeFakeGoBinary(t, tmp, "go1.21.0", "go1.21.0")
+	appendMetadata("go1.21.0", "sha256="+sum)
+
+	writeMetadata(t, tmp, "go1.22.0")
+	writeFakeGoBinary(t, tmp, "go1.22.0", "go1.22.0")
+	appendMetadata("go1.22.0", "sha256=not-a-checksum")
+
+	result, err := m.Verify("go1.21.0")
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	if !result.OK || result.SHA256 != sum {
+		t.Errorf("expected OK with recorded sha256, got %+v", result)
+	}
+
+	result, err = m.Verify("go1.22.0")
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	if result.OK {
+		t.Errorf("expected malformed sha256 to fail verification, got %+v", result)
+	}
+
+	info, err := m.getVersionInfo("go1.21.0")
+	if err != nil {
+		t.Fatalf("getVersionInfo error: %v", err)
+	}
+	if info.SHA256 != sum {
+		t.Errorf("Version.SHA256 = %q, want %q", info.SHA256, sum)
+	}
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -206,19 +207,19 @@ func TestIntegration_ErrorHandling(t *testing.T) {
 	manager := inruntime.NewManager(cfg, envProvider)
 
 	// Test 1: Install non-existent version
-	err := manager.Install("invalid-version")
+	_, err := manager.Install(context.Background(), "invalid-version")
 	if err == nil {
 		t.Error("Expected error when installing invalid version")
 	}
 
 	// Test 2: Uninstall non-installed version
-	err = manager.Uninstall("go1.21.0")
+	_, err = manager.Uninstall("go1.21.0")
 	if err == nil {
 		t.Error("Expected error when uninstalling non-installed version")
 	}
 
 	// Test 3: Use non-installed version
-	err = manager.Use("go1.21.0")
+	_, err = manager.Use("go1.21.0")
 	if err == nil {
 		t.Error("Expected error when using non-installed version")
 	}
@@ -277,7 +278,7 @@ func TestIntegration_SystemDetection(t *testing.T) {
 
 	// Test using system version
 	if systemInfo != nil {
-		err = manager.Use("system")
+		_, err = manager.Use("system")
 		if err != nil {
 			t.Logf("Failed to use system version: %v", err)
 		}